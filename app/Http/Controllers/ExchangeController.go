@@ -4,13 +4,25 @@ import (
 	"context"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/framework"
 	"github.com/clarkgo/clarkgo/pkg/response"
 	"github.com/clarkgo/clarkgo/pkg/web3"
 	"github.com/cloudwego/hertz/pkg/app"
 )
 
 // ExchangeController 交易所控制器
-type ExchangeController struct{}
+type ExchangeController struct {
+	manager *web3.ExchangeManager
+}
+
+// NewExchangeController 创建交易所控制器，从容器解析 "exchange" 服务，未注册时使用全局管理器
+func NewExchangeController(container *framework.Container) *ExchangeController {
+	manager, err := framework.Resolve[*web3.ExchangeManager](container, "exchange")
+	if err != nil {
+		manager = web3.GetExchangeManager()
+	}
+	return &ExchangeController{manager: manager}
+}
 
 // GetBalance 获取交易所余额
 func (e *ExchangeController) GetBalance(ctx context.Context, c *app.RequestContext) {
@@ -25,7 +37,7 @@ func (e *ExchangeController) GetBalance(ctx context.Context, c *app.RequestConte
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	balance, err := web3.GetExchangeManager().GetBalance(timeoutCtx, exchange, currency)
+	balance, err := e.manager.GetBalance(timeoutCtx, exchange, currency)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -45,7 +57,7 @@ func (e *ExchangeController) GetBalances(ctx context.Context, c *app.RequestCont
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	balances, err := web3.GetExchangeManager().GetBalances(timeoutCtx, exchange)
+	balances, err := e.manager.GetBalances(timeoutCtx, exchange)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -70,7 +82,7 @@ func (e *ExchangeController) GetPrice(ctx context.Context, c *app.RequestContext
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	price, err := web3.GetExchangeManager().GetPrice(timeoutCtx, exchange, pair)
+	price, err := e.manager.GetPrice(timeoutCtx, exchange, pair)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -85,7 +97,7 @@ func (e *ExchangeController) GetPrice(ctx context.Context, c *app.RequestContext
 
 // GetSupportedExchanges 获取支持的交易所
 func (e *ExchangeController) GetSupportedExchanges(ctx context.Context, c *app.RequestContext) {
-	exchanges := web3.GetExchangeManager().GetSupportedExchanges()
+	exchanges := e.manager.GetSupportedExchanges()
 
 	response.Success(c, map[string]interface{}{
 		"exchanges": exchanges,
@@ -4,13 +4,25 @@ import (
 	"context"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/framework"
 	"github.com/clarkgo/clarkgo/pkg/response"
 	"github.com/clarkgo/clarkgo/pkg/web3"
 	"github.com/cloudwego/hertz/pkg/app"
 )
 
 // Web3Controller Web3 控制器
-type Web3Controller struct{}
+type Web3Controller struct {
+	manager *web3.Manager
+}
+
+// NewWeb3Controller 创建 Web3 控制器，从容器解析 "web3" 服务，未注册时使用全局管理器
+func NewWeb3Controller(container *framework.Container) *Web3Controller {
+	manager, err := framework.Resolve[*web3.Manager](container, "web3")
+	if err != nil {
+		manager = web3.GetManager()
+	}
+	return &Web3Controller{manager: manager}
+}
 
 // GetBalance 获取地址余额
 // @Summary 获取区块链地址余额
@@ -30,7 +42,7 @@ func (w *Web3Controller) GetBalance(ctx context.Context, c *app.RequestContext)
 		return
 	}
 
-	balance, err := web3.GetManager().GetBalance(ctx, chain, address)
+	balance, err := w.manager.GetBalance(ctx, chain, address)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -64,7 +76,7 @@ func (w *Web3Controller) GetTransaction(ctx context.Context, c *app.RequestConte
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	tx, err := web3.GetManager().GetTransaction(timeoutCtx, chain, txHash)
+	tx, err := w.manager.GetTransaction(timeoutCtx, chain, txHash)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -84,7 +96,7 @@ func (w *Web3Controller) GetTransaction(ctx context.Context, c *app.RequestConte
 func (w *Web3Controller) GetBlockNumber(ctx context.Context, c *app.RequestContext) {
 	chain := web3.Chain(c.Param("chain"))
 
-	client, err := web3.GetManager().GetClient(chain)
+	client, err := w.manager.GetClient(chain)
 	if err != nil {
 		response.Error(c, 500, "Internal Server Error", err.Error())
 		return
@@ -140,7 +152,7 @@ func (w *Web3Controller) GetWalletInfo(ctx context.Context, c *app.RequestContex
 // @Success 200 {object} map[string]interface{}
 // @Router /api/web3/chains [get]
 func (w *Web3Controller) GetSupportedChains(ctx context.Context, c *app.RequestContext) {
-	chains := web3.GetManager().GetSupportedChains()
+	chains := w.manager.GetSupportedChains()
 
 	response.Success(c, map[string]interface{}{
 		"chains": chains,
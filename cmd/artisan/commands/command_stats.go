@@ -26,10 +26,11 @@ var (
 )
 
 func GetCommandStats() map[string]CommandStat {
-	loadStats()
 	statsMutex.Lock()
 	defer statsMutex.Unlock()
 
+	loadStats()
+
 	copy := make(map[string]CommandStat)
 	for k, v := range stats {
 		copy[k] = v
@@ -38,6 +39,9 @@ func GetCommandStats() map[string]CommandStat {
 }
 
 func RecordCommandUsage(name string, duration time.Duration) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
 	loadStats()
 
 	if stat, exists := stats[name]; exists {
@@ -58,6 +62,9 @@ func RecordCommandUsage(name string, duration time.Duration) {
 }
 
 func ShowStats(args []string) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
 	loadStats()
 
 	if len(stats) == 0 {
@@ -87,12 +94,18 @@ func ShowStats(args []string) {
 }
 
 func ResetStats(args []string) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
 	stats = make(map[string]CommandStat)
 	saveStats()
 	fmt.Println("Command statistics have been reset")
 }
 
 func ExportStats(args []string) {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
 	loadStats()
 	if len(args) < 1 {
 		fmt.Println("Format is required (json or csv)")
@@ -112,6 +125,7 @@ func ExportStats(args []string) {
 	}
 }
 
+// loadStats 从磁盘加载统计数据，调用方需持有 statsMutex
 func loadStats() {
 	stats = make(map[string]CommandStat)
 
@@ -128,6 +142,7 @@ func loadStats() {
 	json.Unmarshal(data, &stats)
 }
 
+// saveStats 将统计数据写入磁盘，调用方需持有 statsMutex
 func saveStats() {
 	filePath := filepath.Join("storage", "stats", "command_stats.json")
 	os.MkdirAll(filepath.Dir(filePath), 0755)
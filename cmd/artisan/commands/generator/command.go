@@ -6,24 +6,31 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 )
 
 type Command struct {
-	RootDir     string
-	TemplateDir string
+	RootDir      string
+	TemplateDir  string
+	MigrationDir string
 }
 
 func NewCommand() *Command {
 	return &Command{
-		RootDir:     "app",
-		TemplateDir: "stubs",
+		RootDir:      "app",
+		TemplateDir:  "stubs",
+		MigrationDir: filepath.Join("database", "migrations"),
 	}
 }
 
-func (c *Command) Handle(args []string) {
+// Make 生成指定类型的文件，kind 为 make:command、make:controller 等
+func (c *Command) Make(kind string, args []string) error {
 	if len(args) < 1 {
-		fmt.Println("Not enough arguments (missing: name)")
-		return
+		return fmt.Errorf("Not enough arguments (missing: name)")
+	}
+
+	if kind == "make:migration" {
+		return c.makeMigration(args)
 	}
 
 	name := args[0]
@@ -37,24 +44,93 @@ func (c *Command) Handle(args []string) {
 	}
 
 	var templateFile string
-	switch os.Args[2] {
+	outputPath := filepath.Join(c.RootDir, name+".go")
+	switch kind {
 	case "make:command":
 		templateFile = "command.stub"
 	case "make:controller":
 		templateFile = "controller.stub"
 	case "make:model":
 		templateFile = "model.stub"
+	case "make:middleware":
+		// 中间件统一生成到 app/Http/Middleware，包名固定为 middleware
+		templateFile = "middleware.stub"
+		outputPath = filepath.Join(c.RootDir, "Http", "Middleware", name+".go")
 	default:
-		fmt.Println("Unsupported make command")
-		return
+		return fmt.Errorf("Unsupported make command")
+	}
+
+	if err := c.generateFile(templateFile, outputPath, data); err != nil {
+		return fmt.Errorf("Error generating file: %w", err)
+	}
+	return nil
+}
+
+// makeMigration 生成带时间戳的迁移文件
+// 用法: make:migration <name> [--table=<table>]
+func (c *Command) makeMigration(args []string) error {
+	name := ""
+	table := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "--table="):
+			table = strings.TrimPrefix(arg, "--table=")
+		case arg == "--table" && i+1 < len(args):
+			table = args[i+1]
+			i++
+		case name == "":
+			name = arg
+		}
+	}
+
+	if name == "" {
+		return fmt.Errorf("Not enough arguments (missing: name)")
+	}
+
+	name = strings.ToLower(name)
+	timestamp := time.Now().Format("20060102150405")
+	version := fmt.Sprintf("%s_%s", timestamp, name)
+	// 函数名和结构体名带上时间戳，同名迁移生成多次时不会出现重复定义
+	structName := studly(name) + timestamp
+	data := map[string]interface{}{
+		"Name":    structName,
+		"Table":   table,
+		"Schema":  lowerFirst(structName) + "Schema",
+		"Version": version,
+	}
+
+	templateFile := "migration.stub"
+	if table != "" {
+		templateFile = "migration.create.stub"
+	}
+
+	if err := c.generateFile(templateFile, filepath.Join(c.MigrationDir, version+".go"), data); err != nil {
+		return fmt.Errorf("Error generating file: %w", err)
 	}
+	return nil
+}
+
+// studly 将 create_posts_table 转换为 CreatePostsTable
+func studly(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
 
-	if err := c.generateFile(templateFile, name, data); err != nil {
-		fmt.Printf("Error generating file: %v\n", err)
+// lowerFirst 将首字母转换为小写
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
 	}
+	return strings.ToLower(s[:1]) + s[1:]
 }
 
-func (c *Command) generateFile(templateFile, name string, data map[string]interface{}) error {
+func (c *Command) generateFile(templateFile, outputPath string, data map[string]interface{}) error {
 	// Read template
 	tplPath := filepath.Join(c.TemplateDir, templateFile)
 	tplContent, err := os.ReadFile(tplPath)
@@ -69,7 +145,6 @@ func (c *Command) generateFile(templateFile, name string, data map[string]interf
 	}
 
 	// Create directory if needed
-	outputPath := filepath.Join(c.RootDir, name+".go")
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestCommand 使用仓库中的模板，将文件生成到临时目录
+func newTestCommand(t *testing.T) *Command {
+	t.Helper()
+	dir := t.TempDir()
+	return &Command{
+		RootDir:      filepath.Join(dir, "app"),
+		TemplateDir:  filepath.Join("..", "..", "..", "..", "stubs"),
+		MigrationDir: filepath.Join(dir, "database", "migrations"),
+	}
+}
+
+func TestMakeMiddleware(t *testing.T) {
+	c := newTestCommand(t)
+	if err := c.Make("make:middleware", []string{"Throttle"}); err != nil {
+		t.Fatalf("make:middleware failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(c.RootDir, "Http", "Middleware", "Throttle.go"))
+	if err != nil {
+		t.Fatalf("Expected middleware file to be generated: %v", err)
+	}
+	if !strings.Contains(string(content), "package middleware") || !strings.Contains(string(content), "func Throttle() framework.HandlerFunc") {
+		t.Errorf("Unexpected middleware content:\n%s", content)
+	}
+}
+
+func TestMakeUnsupported(t *testing.T) {
+	c := newTestCommand(t)
+	if err := c.Make("make:unknown", []string{"Foo"}); err == nil {
+		t.Error("Expected error for unsupported make command")
+	}
+	if err := c.Make("make:middleware", nil); err == nil {
+		t.Error("Expected error when the name is missing")
+	}
+}
+
+func TestMakeMigration(t *testing.T) {
+	c := newTestCommand(t)
+	if err := c.Make("make:migration", []string{"create_posts_table", "--table=posts"}); err != nil {
+		t.Fatalf("make:migration failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(c.MigrationDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one migration file, got %v (%v)", entries, err)
+	}
+	version := strings.TrimSuffix(entries[0].Name(), ".go")
+	timestamp, name, _ := strings.Cut(version, "_")
+	if len(timestamp) != 14 || name != "create_posts_table" {
+		t.Fatalf("Unexpected migration file name %q", entries[0].Name())
+	}
+
+	content, _ := os.ReadFile(filepath.Join(c.MigrationDir, entries[0].Name()))
+	// 函数名包含时间戳，同名迁移不会重复定义
+	for _, want := range []string{
+		`Version: "` + version + `"`,
+		"func UpCreatePostsTable" + timestamp + "(db *gorm.DB) error",
+		"func DownCreatePostsTable" + timestamp + "(db *gorm.DB) error",
+		"type createPostsTable" + timestamp + "Schema struct",
+		`return "posts"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected generated migration to contain %q:\n%s", want, content)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/clarkgo/clarkgo/config"
 	"github.com/clarkgo/clarkgo/database/migrations"
@@ -12,10 +13,63 @@ func Migrate(args []string) {
 	// 使用全局DB实例
 	db := config.DB
 
-	// 执行迁移
-	if err := db.AutoMigrate(&migrations.User{}); err != nil {
+	// 执行待执行的迁移
+	ran, err := migrations.NewMigrator().Up(db)
+	for _, version := range ran {
+		fmt.Printf("Migrated: %s\n", version)
+	}
+	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
 	fmt.Println("Migration completed successfully")
 }
+
+// MigrateRollback 回滚迁移，用法: migrate:rollback [steps]
+func MigrateRollback(args []string) {
+	steps := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("Invalid steps: %v\n", err)
+			return
+		}
+		steps = n
+	}
+
+	rolledBack, err := migrations.NewMigrator().Rollback(config.DB, steps)
+	for _, version := range rolledBack {
+		fmt.Printf("Rolled back: %s\n", version)
+	}
+	if err != nil {
+		log.Fatalf("Rollback failed: %v", err)
+	}
+
+	if len(rolledBack) == 0 {
+		fmt.Println("Nothing to rollback")
+	}
+}
+
+// MigrateStatus 显示每个迁移的执行状态，用法: migrate:status
+func MigrateStatus(args []string) error {
+	statuses, err := migrations.NewMigrator().Status(config.DB)
+	if err != nil {
+		return fmt.Errorf("failed to load migration status: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No migrations registered")
+		return nil
+	}
+
+	fmt.Printf("%-48s %-8s %s\n", "MIGRATION", "BATCH", "STATUS")
+	for _, status := range statuses {
+		batch, state := "-", "Pending"
+		if status.Applied {
+			batch = strconv.Itoa(status.Batch)
+			state = "Ran at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-48s %-8s %s\n", status.Version, batch, state)
+	}
+	return nil
+}
@@ -1,27 +1,88 @@
 package commands
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/framework"
+	"github.com/clarkgo/clarkgo/pkg/log"
 	q "github.com/clarkgo/clarkgo/pkg/queue"
 )
 
-func QueueWork(args []string) {
-	fmt.Println("Starting queue workers...")
+// queueRegistrars 任务处理器注册钩子
+var queueRegistrars = []func(*q.Queue){registerJobHandlers}
 
-	// 创建队列管理器（使用内存驱动，实际应用中应该使用 Redis）
-	driver := q.NewMemoryDriver()
+// OnQueueWork 注册任务处理器注册钩子，queue:work 启动前调用
+func OnQueueWork(fn func(*q.Queue)) {
+	queueRegistrars = append(queueRegistrars, fn)
+}
+
+// QueueWork 启动队列工作进程
+// 用法: queue:work [--queue=high,default] [--workers=3] [--once] [--timeout=30s]
+//
+// 队列驱动与 Web 应用一致，按 queue 配置段创建（见 Application.NewQueueDriver），参数默认值同样取自该配置段：
+//
+//	queue.queues            默认监听的队列，按优先级排列，默认 ["default"]
+//	queue.workers           工作协程数，默认 1
+//	queue.shutdown_timeout  停止时等待运行中任务完成的时间，默认 30s
+func QueueWork(args []string) error {
+	app := framework.NewApplication().SetConfigPath("config").SetQuiet(true)
+	if err := app.Boot(); err != nil {
+		return err
+	}
+	defer app.Close()
+
+	defaultQueues := app.Config.GetStringSlice("queue.queues")
+	if len(defaultQueues) == 0 {
+		defaultQueues = []string{"default"}
+	}
+	defaultTimeout, err := time.ParseDuration(app.Config.GetString("queue.shutdown_timeout", q.DefaultShutdownTimeout.String()))
+	if err != nil {
+		return fmt.Errorf("invalid queue.shutdown_timeout: %w", err)
+	}
+
+	fs := flag.NewFlagSet("queue:work", flag.ContinueOnError)
+	queues := fs.String("queue", strings.Join(defaultQueues, ","), "Comma-separated queues to listen on, in priority order")
+	workers := fs.Int("workers", app.Config.GetInt("queue.workers", 1), "Number of worker goroutines")
+	once := fs.Bool("once", false, "Process a single job then exit")
+	timeout := fs.Duration("timeout", defaultTimeout, "Graceful shutdown timeout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	driver, err := app.NewQueueDriver()
+	if err != nil {
+		return err
+	}
+
+	queueNames := strings.Split(*queues, ",")
 	queueMgr := q.NewQueue(driver)
+	queueMgr.SetWorkers(*workers).SetQueues(queueNames)
 
-	// 配置工作进程
-	queueMgr.SetWorkers(3).SetQueues([]string{"default", "high", "low"})
+	// Redis 驱动回收崩溃工作进程遗留在处理中集合的任务，回收间隔随可见性超时调整
+	if redisDriver, ok := driver.(*q.RedisDriver); ok && !*once {
+		redisDriver.StartReaper(queueNames, redisDriver.ReaperInterval())
+	}
 
 	// 注册任务处理器
-	registerJobHandlers(queueMgr)
+	for _, register := range queueRegistrars {
+		register(queueMgr)
+	}
+
+	if *once {
+		if !queueMgr.WorkOnce() {
+			fmt.Println("No jobs available")
+		}
+		return queueMgr.Shutdown(*timeout)
+	}
+
+	fmt.Printf("Starting queue workers (driver: %s)...\n", app.Config.GetString("queue.driver", framework.DriverMemory))
 
 	// 启动工作进程
 	go func() {
@@ -31,8 +92,8 @@ func QueueWork(args []string) {
 	}()
 
 	fmt.Println("✓ Queue workers started")
-	fmt.Println("  - Workers: 3")
-	fmt.Println("  - Queues: default, high, low")
+	fmt.Printf("  - Workers: %d\n", *workers)
+	fmt.Printf("  - Queues: %s\n", *queues)
 	fmt.Println("\nPress Ctrl+C to stop...")
 
 	// 等待中断信号
@@ -40,30 +101,33 @@ func QueueWork(args []string) {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
-	fmt.Println("\nStopping queue workers...")
-	queueMgr.Stop()
+	fmt.Println("\nStopping queue workers, waiting for running jobs...")
+	if err := queueMgr.Shutdown(*timeout); err != nil {
+		return err
+	}
 	fmt.Println("✓ Queue workers stopped")
+	return nil
 }
 
 // registerJobHandlers 注册任务处理器
 func registerJobHandlers(queueMgr *q.Queue) {
 	// 示例：邮件发送任务
-	queueMgr.Register("EmailSendJob", func(payload []byte) error {
-		fmt.Printf("[Queue] Processing email job: %s\n", string(payload))
+	queueMgr.RegisterWithContext("EmailSendJob", func(ctx context.Context, payload []byte) error {
+		log.FromContext(ctx).Info("processing email job", "payload", string(payload))
 		time.Sleep(1 * time.Second) // 模拟邮件发送
 		return nil
 	})
 
 	// 示例：数据处理任务
-	queueMgr.Register("DataProcessJob", func(payload []byte) error {
-		fmt.Printf("[Queue] Processing data job: %s\n", string(payload))
+	queueMgr.RegisterWithContext("DataProcessJob", func(ctx context.Context, payload []byte) error {
+		log.FromContext(ctx).Info("processing data job", "payload", string(payload))
 		time.Sleep(2 * time.Second) // 模拟数据处理
 		return nil
 	})
 
 	// 示例：图片处理任务
-	queueMgr.Register("ImageProcessJob", func(payload []byte) error {
-		fmt.Printf("[Queue] Processing image job: %s\n", string(payload))
+	queueMgr.RegisterWithContext("ImageProcessJob", func(ctx context.Context, payload []byte) error {
+		log.FromContext(ctx).Info("processing image job", "payload", string(payload))
 		time.Sleep(3 * time.Second) // 模拟图片处理
 		return nil
 	})
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Command Artisan 命令接口
+type Command interface {
+	// Run 执行命令
+	Run(args []string) error
+	// Help 返回命令的简要说明
+	Help() string
+}
+
+// ErrUnknownCommand 未注册的命令
+var ErrUnknownCommand = errors.New("unknown command")
+
+// funcCommand 将普通函数适配为 Command
+type funcCommand struct {
+	help string
+	fn   func(args []string) error
+}
+
+// Run 实现 Command 接口
+func (c *funcCommand) Run(args []string) error {
+	return c.fn(args)
+}
+
+// Help 实现 Command 接口
+func (c *funcCommand) Help() string {
+	return c.help
+}
+
+// Func 将 func(args []string) error 适配为 Command
+func Func(help string, fn func(args []string) error) Command {
+	return &funcCommand{help: help, fn: fn}
+}
+
+// Action 将不返回错误的命令函数（如 ShowStats）适配为 Command
+func Action(help string, fn func(args []string)) Command {
+	return Func(help, func(args []string) error {
+		fn(args)
+		return nil
+	})
+}
+
+// Registry 命令注册表
+type Registry struct {
+	commands   map[string]Command
+	onExecuted []func(name string, duration time.Duration)
+	mu         sync.RWMutex
+}
+
+// NewRegistry 创建命令注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: make(map[string]Command),
+	}
+}
+
+// Register 注册命令，同名命令会被覆盖
+func (r *Registry) Register(name string, cmd Command) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.commands[name] = cmd
+	return r
+}
+
+// OnExecuted 注册命令执行完成回调，Dispatch 会将命令名称与耗时传给回调
+func (r *Registry) OnExecuted(fn func(name string, duration time.Duration)) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onExecuted = append(r.onExecuted, fn)
+	return r
+}
+
+// Get 获取命令
+func (r *Registry) Get(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Names 获取所有已注册的命令名称（已排序）
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dispatch 根据 args[0] 查找并执行命令，没有参数时打印用法
+// 未知命令返回 ErrUnknownCommand，命令的错误原样返回，由调用方负责输出
+func (r *Registry) Dispatch(args []string) error {
+	if len(args) < 1 {
+		r.PrintUsage()
+		return nil
+	}
+
+	name := args[0]
+	cmd, ok := r.Get(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, name)
+	}
+
+	start := time.Now()
+	err := cmd.Run(args[1:])
+	duration := time.Since(start)
+
+	r.mu.RLock()
+	callbacks := r.onExecuted
+	r.mu.RUnlock()
+	for _, fn := range callbacks {
+		fn(name, duration)
+	}
+
+	return err
+}
+
+// PrintUsage 打印已注册命令的用法
+func (r *Registry) PrintUsage() {
+	fmt.Println("\nRegistered commands:")
+	for _, name := range r.Names() {
+		cmd, _ := r.Get(name)
+		fmt.Printf("  %-20s %s\n", name, cmd.Help())
+	}
+}
+
+// defaultRegistry 全局命令注册表
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry 获取全局命令注册表
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register 注册命令到全局注册表
+func Register(name string, cmd Command) {
+	defaultRegistry.Register(name, cmd)
+}
+
+// Dispatch 通过全局注册表分发命令
+func Dispatch(args []string) error {
+	return defaultRegistry.Dispatch(args)
+}
+
+func init() {
+	// 自动记录命令使用统计
+	defaultRegistry.OnExecuted(RecordCommandUsage)
+
+	// 数据库迁移
+	Register("migrate:rollback", Action("Rollback database migrations [steps]", MigrateRollback))
+	Register("migrate:status", Func("Show the status of each migration", MigrateStatus))
+
+	// 路由
+	Register("route:list", Func("List registered routes [--method=] [--path=] [--json]", RouteList))
+
+	// 队列工作进程
+	Register("queue:work", Func("Run queue workers [--queue=] [--workers=] [--once]", QueueWork))
+
+	// 任务调度
+	Register("schedule:work", Action("Start scheduler workers", ScheduleWork))
+	Register("schedule:run", Func("Run due scheduled tasks once [--daemon]", ScheduleRun))
+	Register("schedule:list", Func("List scheduled tasks", ScheduleList))
+
+	// 邮件队列命令
+	Register("queue:process", Action("Process email queue", func(args []string) { ProcessQueue() }))
+	Register("queue:status", Action("Show queue status", ShowQueueStatus))
+	Register("queue:retry", Action("Retry failed jobs", RetryFailedJobs))
+	Register("queue:clean", Action("Clean old jobs", CleanQueue))
+	Register("queue:priority", Action("Set job priority", SetPriority))
+	Register("queue:stats", Action("Show queue statistics", ShowQueueStats))
+	Register("queue:pause", Action("Pause queue processing [duration]", PauseQueue))
+	Register("queue:resume", Action("Resume queue processing [duration]", ResumeQueue))
+
+	// 命令统计
+	Register("stats:show", Action("Show command usage statistics", ShowStats))
+	Register("stats:reset", Action("Reset command statistics", ResetStats))
+	Register("stats:export", Action("Export statistics (json/csv)", ExportStats))
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRegistryDispatchUnknownCommand(t *testing.T) {
+	executed := 0
+	r := NewRegistry().OnExecuted(func(name string, duration time.Duration) {
+		executed++
+	})
+
+	err := r.Dispatch([]string{"missing"})
+	if !errors.Is(err, ErrUnknownCommand) {
+		t.Fatalf("Expected ErrUnknownCommand, got %v", err)
+	}
+	if executed != 0 {
+		t.Errorf("Expected no executed callbacks for unknown command, got %d", executed)
+	}
+}
+
+func TestRegistryDispatchRunsCommand(t *testing.T) {
+	var got []string
+	r := NewRegistry().Register("greet", Func("Greet someone", func(args []string) error {
+		got = args
+		return nil
+	}))
+
+	if err := r.Dispatch([]string{"greet", "alice", "--loud"}); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if want := []string{"alice", "--loud"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected args %v, got %v", want, got)
+	}
+}
+
+func TestRegistryDispatchPropagatesError(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := NewRegistry().Register("fail", Func("Always fails", func(args []string) error {
+		return errBoom
+	}))
+
+	if err := r.Dispatch([]string{"fail"}); !errors.Is(err, errBoom) {
+		t.Errorf("Expected command error to be returned, got %v", err)
+	}
+}
+
+func TestRegistryOnExecuted(t *testing.T) {
+	var names []string
+	var durations []time.Duration
+	r := NewRegistry().
+		Register("ok", Action("Succeeds", func(args []string) {
+			time.Sleep(5 * time.Millisecond)
+		})).
+		Register("fail", Func("Fails", func(args []string) error {
+			return errors.New("boom")
+		})).
+		OnExecuted(func(name string, duration time.Duration) {
+			names = append(names, name)
+			durations = append(durations, duration)
+		})
+
+	if err := r.Dispatch([]string{"ok"}); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if err := r.Dispatch([]string{"fail"}); err == nil {
+		t.Fatal("Expected error from failing command")
+	}
+
+	// 失败的命令同样会触发回调
+	if want := []string{"ok", "fail"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected callbacks for %v, got %v", want, names)
+	}
+	if durations[0] < 5*time.Millisecond {
+		t.Errorf("Expected duration of at least 5ms, got %v", durations[0])
+	}
+}
+
+func TestRegistryDispatchWithoutArgs(t *testing.T) {
+	executed := 0
+	r := NewRegistry().OnExecuted(func(name string, duration time.Duration) {
+		executed++
+	})
+
+	if err := r.Dispatch(nil); err != nil {
+		t.Errorf("Expected nil error without args, got %v", err)
+	}
+	if executed != 0 {
+		t.Errorf("Expected no executed callbacks without args, got %d", executed)
+	}
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/clarkgo/clarkgo/pkg/framework"
+	"github.com/clarkgo/clarkgo/routes"
+)
+
+// RouteList 列出应用注册的路由
+// 用法: route:list [--method=GET] [--path=/api] [--json]
+func RouteList(args []string) error {
+	method := ""
+	path := ""
+	asJSON := false
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--method="):
+			method = strings.ToUpper(strings.TrimPrefix(arg, "--method="))
+		case strings.HasPrefix(arg, "--path="):
+			path = strings.TrimPrefix(arg, "--path=")
+		case arg == "--json":
+			asJSON = true
+		default:
+			return fmt.Errorf("unknown option: %s", arg)
+		}
+	}
+
+	// 启动应用并注册路由，但不启动服务器；静默模式下启动日志写入 stderr，保证 stdout 仅包含路由列表
+	app := framework.NewApplication().SetConfigPath("config").SetQuiet(true)
+	if err := app.Boot(); err != nil {
+		return err
+	}
+	routes.Register(app)
+
+	var filtered []framework.RouteInfo
+	for _, route := range app.Router.GetRoutes() {
+		if method != "" && route.Method != method {
+			continue
+		}
+		if path != "" && !strings.Contains(route.Path, path) {
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+
+	if asJSON {
+		if filtered == nil {
+			filtered = []framework.RouteInfo{}
+		}
+		data, err := json.MarshalIndent(filtered, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	framework.PrintRouteTable(filtered)
+	return nil
+}
@@ -1,10 +1,41 @@
 package commands
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
-func ScheduleList(args []string) {
-	fmt.Println("List of scheduled tasks:")
-	fmt.Println("1. Send daily report - Every day at 08:00")
-	fmt.Println("2. Cleanup old records - Every Sunday at 23:00")
-	fmt.Println("3. Backup database - First day of month at 02:00")
+// ScheduleList 列出已注册的调度任务，最近一次运行结果从调度状态文件读取
+func ScheduleList(args []string) error {
+	scheduler := newScheduler()
+	scheduler.LoadState()
+
+	tasks := scheduler.ListTasks()
+	if len(tasks) == 0 {
+		fmt.Println("No scheduled tasks registered")
+		return nil
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Name < tasks[j].Name
+	})
+
+	fmt.Printf("%-24s %-16s %-20s %s\n", "NAME", "CRON", "NEXT RUN", "LAST RESULT")
+	for _, task := range tasks {
+		lastResult := "never"
+		if !task.LastRunAt.IsZero() {
+			lastResult = "success at " + task.LastRunAt.Format("2006-01-02 15:04:05")
+			if task.LastError != "" {
+				lastResult = "failed at " + task.LastRunAt.Format("2006-01-02 15:04:05") + ": " + task.LastError
+			}
+		}
+
+		fmt.Printf("%-24s %-16s %-20s %s\n",
+			task.Name,
+			task.Schedule,
+			task.NextRunAt.Format("2006-01-02 15:04:05"),
+			lastResult,
+		)
+	}
+	return nil
 }
@@ -1,22 +1,44 @@
 package commands
 
 import (
+	"flag"
 	"fmt"
 	"time"
 )
 
-func ScheduleRun(args []string) {
-	fmt.Println("Running scheduled tasks...")
+// ScheduleRun 运行当前分钟到期的任务后退出，适合由系统 cron 每分钟调用
+// 用法: schedule:run [--daemon]
+func ScheduleRun(args []string) error {
+	fs := flag.NewFlagSet("schedule:run", flag.ContinueOnError)
+	daemon := fs.Bool("daemon", false, "Keep the scheduler resident instead of exiting")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
 
-	// 示例任务
-	fmt.Println("Running task: Send daily report")
-	time.Sleep(1 * time.Second)
-	fmt.Println("Task completed: Send daily report")
+	if *daemon {
+		ScheduleWork(fs.Args())
+		return nil
+	}
 
-	// 示例任务
-	fmt.Println("Running task: Cleanup old records")
-	time.Sleep(1 * time.Second)
-	fmt.Println("Task completed: Cleanup old records")
+	scheduler := newScheduler()
+	logs := scheduler.RunDue(time.Now())
+	if len(logs) == 0 {
+		fmt.Println("No scheduled tasks are due")
+		return nil
+	}
 
-	fmt.Println("All scheduled tasks completed")
+	failed := 0
+	for _, log := range logs {
+		if log.Success {
+			fmt.Printf("✓ %s (%v)\n", log.TaskName, log.Duration)
+		} else {
+			failed++
+			fmt.Printf("✗ %s (%v): %s\n", log.TaskName, log.Duration, log.Error)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scheduled tasks failed", failed, len(logs))
+	}
+	return nil
 }
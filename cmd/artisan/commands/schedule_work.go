@@ -9,15 +9,32 @@ import (
 	"github.com/clarkgo/clarkgo/pkg/schedule"
 )
 
+// scheduleRegistrars 调度任务注册钩子
+var scheduleRegistrars = []func(*schedule.Scheduler){registerTasks}
+
+// OnSchedule 注册调度任务注册钩子，schedule:* 命令创建调度器时调用
+func OnSchedule(fn func(*schedule.Scheduler)) {
+	scheduleRegistrars = append(scheduleRegistrars, fn)
+}
+
+// scheduleStorePath 调度任务状态文件，用于补跑停机期间错过的任务
+const scheduleStorePath = "storage/framework/schedule.json"
+
+// newScheduler 创建调度器并通过注册钩子加载任务
+func newScheduler() *schedule.Scheduler {
+	scheduler := schedule.NewScheduler().WithStore(schedule.NewFileStore(scheduleStorePath))
+	for _, register := range scheduleRegistrars {
+		register(scheduler)
+	}
+	return scheduler
+}
+
 // ScheduleWork 启动调度器工作进程
 func ScheduleWork(args []string) {
 	fmt.Println("Starting scheduler...")
 
-	// 创建调度器
-	scheduler := schedule.NewScheduler()
-
-	// 注册示例任务
-	registerTasks(scheduler)
+	// 创建调度器并加载任务
+	scheduler := newScheduler()
 
 	// 启动调度器
 	scheduler.Start()
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -16,6 +17,51 @@ import (
 	"github.com/cloudwego/hertz/pkg/app/server"
 )
 
+func init() {
+	// 代码生成命令
+	makeCommands := map[string]string{
+		"make:command":    "Create a new Artisan command <name>",
+		"make:controller": "Create a new controller <name>",
+		"make:model":      "Create a new model <name>",
+		"make:middleware": "Create a new middleware <name>",
+		"make:migration":  "Create a new migration <name> [--table=<table>]",
+	}
+	for kind, help := range makeCommands {
+		commands.Register(kind, commands.Func(help, func(args []string) error {
+			return generator.NewCommand().Make(kind, args)
+		}))
+	}
+
+	// stats 包依赖 commands 包，因此在此处注册其命令
+	commands.Register("stats:chart", commands.Action("Generate usage chart", func(args []string) {
+		stats.GenerateChart(commands.GetCommandStats())
+	}))
+	commands.Register("stats:cleanup", commands.Func("Clean up old statistics [duration]", func(args []string) error {
+		threshold := 30 * 24 * time.Hour // Default: 30 days
+		if len(args) > 0 {
+			d, err := time.ParseDuration(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid duration format: %w", err)
+			}
+			threshold = d
+		}
+		stats.CleanupOldStats(commands.GetCommandStats(), time.Now().Add(-threshold))
+		return nil
+	}))
+	commands.Register("stats:check", commands.Func("Check for performance anomalies [threshold]", func(args []string) error {
+		threshold := 5 * time.Second // Default: 5 seconds
+		if len(args) > 0 {
+			d, err := time.ParseDuration(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid duration format: %w", err)
+			}
+			threshold = d
+		}
+		stats.CheckForAnomalies(commands.GetCommandStats(), threshold)
+		return nil
+	}))
+}
+
 func main() {
 	// Initialize database
 	config.InitDB()
@@ -70,76 +116,24 @@ func runArtisan() {
 	command := cmdArgs[0]
 	args := cmdArgs[1:]
 
+	// 注册表中的命令由 Dispatch 自动记录使用统计
+	dispatched := false
+	var cmdErr error
+
 	switch command {
 	case "ai:setup", "ai:chat", "ai:completion", "ai:models", "ai:test", "ai:config":
 		// 将 ai: 前缀的命令传递给AI命令处理器
 		aiArgs := []string{strings.TrimPrefix(command, "ai:")}
 		aiArgs = append(aiArgs, args...)
 		commands.HandleAICommand(aiArgs)
-	case "make:command":
-		generator.NewCommand().Handle(args)
-	case "make:controller":
-		generator.NewCommand().Handle(args)
-	case "make:model":
-		generator.NewCommand().Handle(args)
-	case "make:middleware":
-		generator.NewCommand().Handle(args)
 	case "migrate":
 		commands.Migrate(args)
 	case "help":
 		showHelp()
-	case "stats:show":
-		commands.ShowStats(args)
-	case "stats:reset":
-		commands.ResetStats(args)
-	case "stats:export":
-		commands.ExportStats(args)
-	case "stats:chart":
-		stats.GenerateChart(commands.GetCommandStats())
-	case "stats:cleanup":
-		if len(args) > 0 {
-			threshold, err := time.ParseDuration(args[0])
-			if err != nil {
-				fmt.Printf("Invalid duration format: %v\n", err)
-				return
-			}
-			stats.CleanupOldStats(commands.GetCommandStats(), time.Now().Add(-threshold))
-		} else {
-			stats.CleanupOldStats(commands.GetCommandStats(), time.Now().Add(-30*24*time.Hour)) // Default: 30 days
-		}
-	case "stats:check":
-		if len(args) > 0 {
-			threshold, err := time.ParseDuration(args[0])
-			if err != nil {
-				fmt.Printf("Invalid duration format: %v\n", err)
-				return
-			}
-			stats.CheckForAnomalies(commands.GetCommandStats(), threshold)
-		} else {
-			stats.CheckForAnomalies(commands.GetCommandStats(), 5*time.Second) // Default: 5 seconds
-		}
 	case "alert:setup":
 		commands.SetupEmailAlert(args)
 	case "alert:test":
 		commands.SendTestEmail(args)
-	case "queue:process":
-		commands.ProcessQueue()
-	case "queue:status":
-		commands.ShowQueueStatus(args)
-	case "queue:retry":
-		commands.RetryFailedJobs(args)
-	case "queue:clean":
-		commands.CleanQueue(args)
-	case "queue:priority":
-		commands.SetPriority(args)
-	case "queue:stats":
-		commands.ShowQueueStats(args)
-	case "schedule:work":
-		commands.ScheduleWork(args)
-	case "schedule:run":
-		commands.ScheduleRun(args)
-	case "schedule:list":
-		commands.ScheduleList(args)
 	case "event:test":
 		commands.EventTest(args)
 	case "event:list":
@@ -156,8 +150,9 @@ func runArtisan() {
 		commands.ExchangeCommandWrapper(args)
 
 	default:
-		fmt.Printf("Unknown command: %s\n", command)
-		showHelp()
+		// 其余命令交由命令注册表处理
+		dispatched = true
+		cmdErr = commands.Dispatch(cmdArgs)
 	}
 
 	duration := time.Since(startTime)
@@ -165,8 +160,23 @@ func runArtisan() {
 		time.Now().Format("2006-01-02 15:04:05"),
 		duration)
 
-	// 记录命令使用统计
-	commands.RecordCommandUsage(command, duration)
+	// 记录尚未迁移到注册表的命令的使用统计
+	if !dispatched {
+		commands.RecordCommandUsage(command, duration)
+	}
+
+	// 命令失败时以非零状态退出，cron 和 CI 可以据此判断失败
+	if cmdErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", cmdErr)
+		if errors.Is(cmdErr, commands.ErrUnknownCommand) {
+			commands.DefaultRegistry().PrintUsage()
+		}
+		logger.Printf("[%s] Command failed: %v",
+			time.Now().Format("2006-01-02 15:04:05"),
+			cmdErr)
+		logFile.Close()
+		os.Exit(1)
+	}
 }
 
 func runWebServer() {
@@ -185,10 +195,6 @@ func showHelp() {
 	fmt.Println("ClarkGo Artisan Tool")
 	fmt.Println("Usage: go run . artisan <command> [arguments]")
 	fmt.Println("\nAvailable commands:")
-	fmt.Println("  make:command <name>\tCreate a new Artisan command")
-	fmt.Println("  make:controller <name>\tCreate a new controller")
-	fmt.Println("  make:model <name>\tCreate a new model")
-	fmt.Println("  make:middleware <name>\tCreate a new middleware")
 	fmt.Println("  migrate\t\tRun database migrations")
 	fmt.Println("  help\t\t\tShow this help message")
 	fmt.Println("\nAI commands:")
@@ -198,27 +204,9 @@ func showHelp() {
 	fmt.Println("  ai:models\t\t\tList available models")
 	fmt.Println("  ai:test [model]\t\tTest AI connection")
 	fmt.Println("  ai:config <action>\t\tManage AI configurations")
-	fmt.Println("\nStatistics commands:")
-	fmt.Println("  stats:show\t\tShow command usage statistics")
-	fmt.Println("  stats:reset\t\tReset command statistics")
-	fmt.Println("  stats:export <format>\tExport statistics (json/csv)")
-	fmt.Println("  stats:chart\t\tGenerate usage chart")
-	fmt.Println("  stats:cleanup\tClean up old statistics")
-	fmt.Println("  stats:check\t\tCheck for performance anomalies")
 	fmt.Println("\nAlert commands:")
 	fmt.Println("  alert:setup <file>\tSetup email alert configuration")
 	fmt.Println("  alert:test\t\tSend test email")
-	fmt.Println("\nQueue commands:")
-	fmt.Println("  queue:process\t\tProcess email queue")
-	fmt.Println("  queue:status\t\tShow queue status")
-	fmt.Println("  queue:retry\t\tRetry failed jobs")
-	fmt.Println("  queue:clean\t\tClean old jobs")
-	fmt.Println("  queue:priority\tSet job priority")
-	fmt.Println("  queue:stats\t\tShow queue statistics")
-	fmt.Println("\nSchedule commands:")
-	fmt.Println("  schedule:work\t\tStart scheduler workers")
-	fmt.Println("  schedule:run\t\tRun scheduled tasks once")
-	fmt.Println("  schedule:list\t\tList scheduled tasks")
 	fmt.Println("\nEvent commands:")
 	fmt.Println("  event:test\t\tTest event system")
 	fmt.Println("  event:list\t\tList registered events")
@@ -242,4 +230,6 @@ func showHelp() {
 	fmt.Println("  exchange price <exchange> <pair>\tGet trading pair price")
 	fmt.Println("  exchange compare <pair>\tCompare prices across exchanges")
 	fmt.Println("  exchange balance-all <currency>\tGet balance across all exchanges")
+
+	commands.DefaultRegistry().PrintUsage()
 }
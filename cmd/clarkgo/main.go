@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/clarkgo/clarkgo/database/migrations"
 	"github.com/clarkgo/clarkgo/pkg/framework"
@@ -14,14 +15,72 @@ func main() {
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "migrate":
-			if err := migrations.CreateUsersTable(app.DB); err != nil {
+			boot(app)
+			ran, err := migrations.NewMigrator().Up(app.DB.DB)
+			for _, version := range ran {
+				fmt.Printf("Migrated: %s\n", version)
+			}
+			if err != nil {
 				fmt.Printf("Migration failed: %v\n", err)
 				os.Exit(1)
 			}
+			if len(ran) == 0 {
+				fmt.Println("Nothing to migrate")
+				return
+			}
 			fmt.Println("Migration completed successfully")
 			return
+		case "migrate:rollback":
+			steps := 1
+			if len(os.Args) > 2 {
+				n, err := strconv.Atoi(os.Args[2])
+				if err != nil {
+					fmt.Printf("Invalid steps: %v\n", err)
+					os.Exit(1)
+				}
+				steps = n
+			}
+			boot(app)
+			rolledBack, err := migrations.NewMigrator().Rollback(app.DB.DB, steps)
+			for _, version := range rolledBack {
+				fmt.Printf("Rolled back: %s\n", version)
+			}
+			if err != nil {
+				fmt.Printf("Rollback failed: %v\n", err)
+				os.Exit(1)
+			}
+			if len(rolledBack) == 0 {
+				fmt.Println("Nothing to rollback")
+			}
+			return
+		case "migrate:status":
+			boot(app)
+			statuses, err := migrations.NewMigrator().Status(app.DB.DB)
+			if err != nil {
+				fmt.Printf("Failed to load migration status: %v\n", err)
+				os.Exit(1)
+			}
+			for _, status := range statuses {
+				state := "Pending"
+				if status.Applied {
+					state = fmt.Sprintf("Ran (batch %d)", status.Batch)
+				}
+				fmt.Printf("%-48s %s\n", status.Version, state)
+			}
+			return
 		}
 	}
 
-	app.Boot().Run()
+	if err := app.Run(); err != nil {
+		fmt.Printf("Application exited with error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// boot 启动应用，失败时退出
+func boot(app *framework.Application) {
+	if err := app.Boot(); err != nil {
+		fmt.Printf("Failed to boot application: %v\n", err)
+		os.Exit(1)
+	}
 }
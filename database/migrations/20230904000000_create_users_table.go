@@ -5,6 +5,18 @@ import (
 	"gorm.io/gorm"
 )
 
+func init() {
+	Register(Migration{
+		Version: "20230904000000_create_users_table",
+		Up: func(db *gorm.DB) error {
+			return CreateUsersTable(&database.Database{DB: db})
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(&User{})
+		},
+	})
+}
+
 type User struct {
 	gorm.Model
 	Id       uint   `gorm:"primaryKey;autoIncrement;not null;index"`
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"github.com/clarkgo/clarkgo/internal/app/models"
+	"github.com/clarkgo/clarkgo/pkg/database"
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Migration{
+		Version: "20230905000000_create_cms_tables",
+		Up: func(db *gorm.DB) error {
+			return CreateCMSTables(&database.Database{DB: db})
+		},
+		Down: DropCMSTables,
+	})
+}
+
+// CreateCMSTables 创建CMS相关表
+func CreateCMSTables(db *database.Database) error {
+	// 自动迁移所有模型
+	return db.DB.AutoMigrate(
+		&models.Media{},
+		&models.Role{},
+		&models.Permission{},
+		&models.Category{},
+		&models.Tag{},
+		&models.Post{},
+		&models.RolePermission{},
+		&models.UserRole{},
+		&models.PostTag{},
+	)
+}
+
+// DropCMSTables 删除CMS相关表
+func DropCMSTables(db *gorm.DB) error {
+	return db.Migrator().DropTable(
+		&models.PostTag{},
+		&models.UserRole{},
+		&models.RolePermission{},
+		&models.Post{},
+		&models.Tag{},
+		&models.Category{},
+		&models.Permission{},
+		&models.Role{},
+		&models.Media{},
+	)
+}
@@ -5,11 +5,24 @@ import (
 	"gorm.io/gorm"
 )
 
+func init() {
+	Register(Migration{
+		Version: "20230906000000_create_menus_table",
+		Up:      CreateMenusTable,
+		Down:    DropMenusTable,
+	})
+}
+
 // CreateMenusTable 创建菜单表
 func CreateMenusTable(db *gorm.DB) error {
 	return db.AutoMigrate(&models.Menu{})
 }
 
+// DropMenusTable 删除菜单表
+func DropMenusTable(db *gorm.DB) error {
+	return db.Migrator().DropTable(&models.Menu{})
+}
+
 // SeedDefaultMenus 添加默认菜单
 func SeedDefaultMenus(db *gorm.DB) error {
 	// 检查是否已有菜单
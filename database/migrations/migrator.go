@@ -0,0 +1,242 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 迁移定义
+type Migration struct {
+	Version string // 迁移版本，通常为文件名（不含 .go），按字典序执行
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// MigrationRecord migrations 表记录
+type MigrationRecord struct {
+	ID        uint   `gorm:"primaryKey"`
+	Version   string `gorm:"size:255;uniqueIndex;not null"`
+	Batch     int    `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+// TableName 指定表名
+func (MigrationRecord) TableName() string {
+	return "migrations"
+}
+
+// registered 已注册的迁移
+var registered []Migration
+
+// Register 注册迁移，由各迁移文件的 init 调用
+func Register(m Migration) {
+	registered = append(registered, m)
+}
+
+// Registered 获取已注册的迁移（按版本排序）
+func Registered() []Migration {
+	result := make([]Migration, len(registered))
+	copy(result, registered)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	migrations []Migration
+}
+
+// NewMigrator 创建迁移执行器，migrations 为空时使用已注册的迁移
+func NewMigrator(migrations ...Migration) *Migrator {
+	if len(migrations) == 0 {
+		migrations = Registered()
+	} else {
+		sort.Slice(migrations, func(i, j int) bool {
+			return migrations[i].Version < migrations[j].Version
+		})
+	}
+
+	return &Migrator{migrations: migrations}
+}
+
+// ensureTable 确保 migrations 表存在
+func (m *Migrator) ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&MigrationRecord{})
+}
+
+// applied 获取已执行的迁移记录（按批次、版本排序）
+func (m *Migrator) applied(db *gorm.DB) ([]MigrationRecord, error) {
+	var records []MigrationRecord
+	if err := db.Order("batch asc, version asc").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Pending 获取待执行的迁移
+func (m *Migrator) Pending(db *gorm.DB) ([]Migration, error) {
+	if err := m.ensureTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	records, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool, len(records))
+	for _, record := range records {
+		done[record.Version] = true
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !done[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// MigrationStatus 迁移执行状态
+type MigrationStatus struct {
+	Version   string
+	Applied   bool
+	Batch     int
+	AppliedAt time.Time
+}
+
+// Status 按版本顺序返回所有迁移的执行状态
+// 已执行但未注册的迁移（如对应文件已删除）同样列出，便于排查
+func (m *Migrator) Status(db *gorm.DB) ([]MigrationStatus, error) {
+	if err := m.ensureTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	records, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]MigrationRecord, len(records))
+	for _, record := range records {
+		byVersion[record.Version] = record
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version}
+		if record, ok := byVersion[migration.Version]; ok {
+			status.Applied = true
+			status.Batch = record.Batch
+			status.AppliedAt = record.AppliedAt
+			delete(byVersion, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range byVersion {
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Applied:   true,
+			Batch:     record.Batch,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Up 按版本顺序执行所有待执行的迁移，每个迁移在独立事务中执行
+// 注意：MySQL 的 DDL 会隐式提交事务，失败时可能无法完全回滚
+func (m *Migrator) Up(db *gorm.DB) ([]string, error) {
+	pending, err := m.Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	var batch int
+	if err := db.Model(&MigrationRecord{}).Select("COALESCE(MAX(batch), 0)").Scan(&batch).Error; err != nil {
+		return nil, err
+	}
+	batch++
+
+	var ran []string
+	for _, migration := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if migration.Up != nil {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&MigrationRecord{
+				Version:   migration.Version,
+				Batch:     batch,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migration %s failed: %w", migration.Version, err)
+		}
+		ran = append(ran, migration.Version)
+	}
+
+	return ran, nil
+}
+
+// Rollback 回滚最近执行的 steps 个迁移（steps <= 0 时回滚 1 个）
+func (m *Migrator) Rollback(db *gorm.DB, steps int) ([]string, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	if err := m.ensureTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	records, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var rolledBack []string
+	for i := len(records) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		record := records[i]
+		migration, ok := byVersion[record.Version]
+		if !ok {
+			return rolledBack, fmt.Errorf("migration %s not found", record.Version)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if migration.Down != nil {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&MigrationRecord{}, record.ID).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback %s failed: %w", record.Version, err)
+		}
+		rolledBack = append(rolledBack, record.Version)
+	}
+
+	return rolledBack, nil
+}
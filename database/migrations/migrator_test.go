@@ -0,0 +1,152 @@
+package migrations
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	return db
+}
+
+// tableMigration 创建并删除指定表的测试迁移
+func tableMigration(version, table string) Migration {
+	return Migration{
+		Version: version,
+		Up: func(db *gorm.DB) error {
+			return db.Exec("CREATE TABLE " + table + " (id integer)").Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.Migrator().DropTable(table)
+		},
+	}
+}
+
+func statusVersions(t *testing.T, m *Migrator, db *gorm.DB) map[string]int {
+	t.Helper()
+	statuses, err := m.Status(db)
+	if err != nil {
+		t.Fatalf("Status error: %v", err)
+	}
+	result := make(map[string]int, len(statuses))
+	for _, status := range statuses {
+		if status.Applied {
+			result[status.Version] = status.Batch
+		} else {
+			result[status.Version] = 0
+		}
+	}
+	return result
+}
+
+func TestMigratorUpAndRollback(t *testing.T) {
+	db := openTestDB(t)
+	first := tableMigration("20240101000000_create_alpha", "alpha")
+	second := tableMigration("20240102000000_create_beta", "beta")
+	third := tableMigration("20240103000000_create_gamma", "gamma")
+
+	// 注册顺序不影响执行顺序
+	m := NewMigrator(second, first)
+	if got := statusVersions(t, m, db); !reflect.DeepEqual(got, map[string]int{first.Version: 0, second.Version: 0}) {
+		t.Errorf("Expected all migrations pending, got %v", got)
+	}
+
+	ran, err := m.Up(db)
+	if err != nil {
+		t.Fatalf("Up error: %v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{first.Version, second.Version}) {
+		t.Errorf("Expected migrations in version order, got %v", ran)
+	}
+	if ran, _ := m.Up(db); len(ran) != 0 {
+		t.Errorf("Expected nothing to migrate on second run, got %v", ran)
+	}
+
+	// 新增的迁移属于新的批次
+	m = NewMigrator(first, second, third)
+	if ran, err := m.Up(db); err != nil || !reflect.DeepEqual(ran, []string{third.Version}) {
+		t.Fatalf("Expected only the new migration to run, got %v (%v)", ran, err)
+	}
+	want := map[string]int{first.Version: 1, second.Version: 1, third.Version: 2}
+	if got := statusVersions(t, m, db); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected status: got %v, want %v", got, want)
+	}
+
+	rolledBack, err := m.Rollback(db, 0)
+	if err != nil || !reflect.DeepEqual(rolledBack, []string{third.Version}) {
+		t.Fatalf("Expected rollback of the latest migration, got %v (%v)", rolledBack, err)
+	}
+	if db.Migrator().HasTable("gamma") {
+		t.Error("Expected Down to drop the table")
+	}
+
+	rolledBack, err = m.Rollback(db, 5)
+	if err != nil || !reflect.DeepEqual(rolledBack, []string{second.Version, first.Version}) {
+		t.Fatalf("Expected rollback in reverse order, got %v (%v)", rolledBack, err)
+	}
+	if got := statusVersions(t, m, db); got[first.Version] != 0 || got[second.Version] != 0 || got[third.Version] != 0 {
+		t.Errorf("Expected all migrations pending after rollback, got %v", got)
+	}
+}
+
+func TestMigratorUpFailure(t *testing.T) {
+	db := openTestDB(t)
+	errBoom := errors.New("boom")
+	ok := tableMigration("20240101000000_create_alpha", "alpha")
+	failing := Migration{
+		Version: "20240102000000_broken",
+		Up: func(db *gorm.DB) error {
+			if err := db.Exec("CREATE TABLE broken (id integer)").Error; err != nil {
+				return err
+			}
+			return errBoom
+		},
+	}
+
+	ran, err := NewMigrator(ok, failing).Up(db)
+	if !errors.Is(err, errBoom) || !reflect.DeepEqual(ran, []string{ok.Version}) {
+		t.Fatalf("Expected failure after the first migration, got %v (%v)", ran, err)
+	}
+	// 失败的迁移在事务中回滚，不留下表和记录
+	if db.Migrator().HasTable("broken") {
+		t.Error("Expected failed migration to be rolled back")
+	}
+	if got := statusVersions(t, NewMigrator(ok, failing), db); got[failing.Version] != 0 || got[ok.Version] != 1 {
+		t.Errorf("Unexpected status after failure: %v", got)
+	}
+}
+
+func TestMigratorStatusIncludesUnregistered(t *testing.T) {
+	db := openTestDB(t)
+	menus := tableMigration("20230906000000_create_menus_table", "menus")
+	if _, err := NewMigrator(menus).Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	// 已执行但未注册的迁移同样出现在状态中
+	orphan := tableMigration("20240101000000_create_alpha", "alpha")
+	if _, err := NewMigrator(orphan).Up(db); err != nil {
+		t.Fatal(err)
+	}
+	if got := statusVersions(t, NewMigrator(menus), db); got[orphan.Version] != 2 || got[menus.Version] != 1 {
+		t.Errorf("Unexpected status: %v", got)
+	}
+}
+
+func TestRegisteredVersionsAreTimestamped(t *testing.T) {
+	for _, migration := range Registered() {
+		if len(migration.Version) < 15 || migration.Version[14] != '_' {
+			t.Errorf("Expected timestamped version, got %q", migration.Version)
+		}
+	}
+}
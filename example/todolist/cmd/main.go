@@ -1,8 +1,11 @@
 package main
 
 import (
+	"log"
+
 	"example/todolist/internal/controllers"
-	"example/todolist/pkg/framework"
+
+	"github.com/clarkgo/clarkgo/pkg/framework"
 )
 
 func main() {
@@ -14,15 +17,12 @@ func main() {
 
 	// 注册路由
 	application.RegisterRoutes(func(r *framework.Router) {
-		// TodoList路由
-		todoController := controllers.NewTodoController()
-		r.GET("/todos", todoController.ListTodos)
-		r.POST("/todos", todoController.CreateTodo)
-		r.GET("/todos/:id", todoController.GetTodo)
-		r.PUT("/todos/:id", todoController.UpdateTodo)
-		r.DELETE("/todos/:id", todoController.DeleteTodo)
+		// TodoList路由：GET/POST /todos，GET/PUT/DELETE /todos/:id
+		r.Resource("/todos", controllers.NewTodoController())
 	})
 
 	// 启动应用
-	application.Run()
+	if err := application.Run(); err != nil {
+		log.Fatalf("Application exited with error: %v", err)
+	}
 }
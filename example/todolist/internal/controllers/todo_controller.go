@@ -5,9 +5,10 @@ import (
 	"example/todolist/internal/models"
 	"fmt"
 
-	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/clarkgo/clarkgo/pkg/framework"
 )
 
+// TodoController 实现 framework.ResourceController
 type TodoController struct {
 	// 这里可以注入服务或存储库
 }
@@ -16,7 +17,7 @@ func NewTodoController() *TodoController {
 	return &TodoController{}
 }
 
-func (c *TodoController) ListTodos(ctx context.Context, rc *app.RequestContext) {
+func (c *TodoController) Index(ctx context.Context, rc *framework.RequestContext) {
 	// 获取所有Todo项
 	todos := []models.Todo{
 		{ID: 1, Title: "学习Hertz框架", Completed: false},
@@ -28,7 +29,7 @@ func (c *TodoController) ListTodos(ctx context.Context, rc *app.RequestContext)
 	})
 }
 
-func (c *TodoController) CreateTodo(ctx context.Context, rc *app.RequestContext) {
+func (c *TodoController) Store(ctx context.Context, rc *framework.RequestContext) {
 	var todo models.Todo
 	if err := rc.Bind(&todo); err != nil {
 		rc.JSON(400, map[string]string{"error": "Invalid request"})
@@ -43,10 +44,11 @@ func (c *TodoController) CreateTodo(ctx context.Context, rc *app.RequestContext)
 	})
 }
 
-func (c *TodoController) GetTodo(ctx context.Context, rc *app.RequestContext) {
+func (c *TodoController) Show(ctx context.Context, rc *framework.RequestContext) {
 	id := rc.Param("id")
 
 	// 模拟从数据库获取
+	fmt.Printf("获取Todo ID %s\n", id)
 	todo := models.Todo{
 		ID:        1,
 		Title:     "示例Todo",
@@ -58,7 +60,7 @@ func (c *TodoController) GetTodo(ctx context.Context, rc *app.RequestContext) {
 	})
 }
 
-func (c *TodoController) UpdateTodo(ctx context.Context, rc *app.RequestContext) {
+func (c *TodoController) Update(ctx context.Context, rc *framework.RequestContext) {
 	id := rc.Param("id")
 	var todo models.Todo
 	if err := rc.Bind(&todo); err != nil {
@@ -74,7 +76,7 @@ func (c *TodoController) UpdateTodo(ctx context.Context, rc *app.RequestContext)
 	})
 }
 
-func (c *TodoController) DeleteTodo(ctx context.Context, rc *app.RequestContext) {
+func (c *TodoController) Destroy(ctx context.Context, rc *framework.RequestContext) {
 	id := rc.Param("id")
 
 	// 删除逻辑
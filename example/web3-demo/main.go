@@ -137,7 +137,7 @@ func demoAddressValidation() {
 		{web3.Bitcoin, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
 		{web3.Bitcoin, "invalid-btc-address"},
 		{web3.Solana, "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeK"},
-		{web3.BSC, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0"},
+		{web3.BSC, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0"},
 	}
 
 	for _, tc := range testCases {
@@ -1,11 +1,10 @@
 package main
 
 import (
-	"context"
+	"log"
 
 	_ "github.com/clarkgo/clarkgo/docs" // Swagger docs
 	"github.com/clarkgo/clarkgo/pkg/framework"
-	"github.com/clarkgo/clarkgo/pkg/swagger"
 	"github.com/clarkgo/clarkgo/routes"
 )
 
@@ -34,43 +33,23 @@ func main() {
 	app := framework.NewApplication().
 		SetConfigPath("config").
 		SetEnv("development").
-		SetDebug(true).
-		Boot()
+		SetDebug(true)
+	if err := app.Boot(); err != nil {
+		log.Fatalf("Failed to boot application: %v", err)
+	}
 
-	// 注册API路由
-	routes.APIRoutes(app)
+	// 注册路由
+	routes.Register(app)
 
-	// 注册其他路由
-	app.RegisterRoutes(func(router *framework.Router) {
-		// Swagger文档路由 - 访问 http://localhost:8888/swagger/index.html
-		router.GET("/swagger/*any", swagger.SwaggerHandler())
-
-		// 基本API路由
-		api := router.Group("/api")
-		{
-			api.GET("/ping", func(ctx context.Context, c *framework.RequestContext) {
-				c.JSON(200, map[string]interface{}{
-					"message": "pong",
-				})
-			})
-		}
-
-		// Web路由
-		router.GET("/", func(ctx context.Context, c *framework.RequestContext) {
-			c.String(200, "Welcome to ClarkGo with AI capabilities! See /doc/ai.md for AI integration guide.")
-		})
-	})
-
-	// 注册中间件
-	app.RegisterMiddleware(
-		framework.Cors(),
-		framework.Recovery(),
-		framework.Logger(),
-	)
-
-	// 注册静态文件目录
-	app.Static("/public", app.GetPublicPath())
+	// 注册中间件，按优先级执行：恢复 -> 请求 ID -> 日志 -> CORS
+	app.
+		UseMiddleware("recovery", framework.MiddlewarePriorityRecovery, framework.RecoveryWithConfig(framework.RecoveryConfig{Debug: app.Debug})).
+		UseMiddleware("request_id", framework.MiddlewarePriorityRequestID, framework.RequestID()).
+		UseMiddleware("logger", framework.MiddlewarePriorityLogger, framework.Logger()).
+		UseMiddleware("cors", framework.MiddlewarePriorityDefault, framework.Cors())
 
 	// 运行应用
-	app.Run()
+	if err := app.Run(); err != nil {
+		log.Fatalf("Application exited with error: %v", err)
+	}
 }
@@ -15,9 +15,37 @@ type Driver interface {
 	Clear() error
 }
 
+// closer 持有后台资源、需要关闭的驱动
+type closer interface {
+	Close() error
+}
+
+// PrefixClearer 支持按前缀清除缓存的驱动
+type PrefixClearer interface {
+	ClearPrefix(prefix string) error
+}
+
+// TTLReader 支持查询剩余过期时间的驱动，永不过期时返回 0
+type TTLReader interface {
+	TTL(key string) (time.Duration, error)
+}
+
+// Normalizer 存储时会改变值类型的驱动（如以 JSON 编码的 Redis 驱动），
+// Normalize 返回值写入后再读取得到的形式
+type Normalizer interface {
+	Normalize(value interface{}) (interface{}, error)
+}
+
+// ErrPrefixClearNotSupported 驱动不支持按前缀清除
+var ErrPrefixClearNotSupported = errors.New("driver does not support prefix clearing")
+
+// NamespaceSeparator 命名空间与键之间的分隔符
+const NamespaceSeparator = ":"
+
 // Cache 缓存管理器
 type Cache struct {
 	driver Driver
+	prefix string
 	mu     sync.RWMutex
 }
 
@@ -28,11 +56,39 @@ func NewCache(driver Driver) *Cache {
 	}
 }
 
+// Namespace 返回命名空间视图，所有键自动添加 "prefix:" 前缀
+// 视图与原缓存共享驱动，可嵌套使用
+func (c *Cache) Namespace(prefix string) *Cache {
+	return &Cache{
+		driver: c.driver,
+		prefix: c.key(prefix) + NamespaceSeparator,
+	}
+}
+
+// Close 关闭驱动释放后台资源（如内存驱动的过期清理 goroutine），驱动不需要关闭时不做任何事
+// 命名空间视图共享驱动，关闭任一视图都会关闭驱动
+func (c *Cache) Close() error {
+	if cl, ok := c.driver.(closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// Prefix 获取命名空间前缀，未使用命名空间时为空
+func (c *Cache) Prefix() string {
+	return c.prefix
+}
+
+// key 添加命名空间前缀
+func (c *Cache) key(key string) string {
+	return c.prefix + key
+}
+
 // Get 获取缓存
 func (c *Cache) Get(key string) (interface{}, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.driver.Get(key)
+	return c.driver.Get(c.key(key))
 }
 
 // GetString 获取字符串缓存
@@ -86,26 +142,35 @@ func (c *Cache) GetBool(key string) (bool, error) {
 func (c *Cache) Set(key string, value interface{}, ttl time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.driver.Set(key, value, ttl)
+	return c.driver.Set(c.key(key), value, ttl)
 }
 
 // Delete 删除缓存
 func (c *Cache) Delete(key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.driver.Delete(key)
+	return c.driver.Delete(c.key(key))
 }
 
 // Exists 检查缓存是否存在
 func (c *Cache) Exists(key string) bool {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.driver.Exists(key)
+	return c.driver.Exists(c.key(key))
 }
 
-// Clear 清空缓存
+// Clear 清空缓存，命名空间视图只清除该前缀下的键
 func (c *Cache) Clear() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.driver.Clear()
+
+	if c.prefix == "" {
+		return c.driver.Clear()
+	}
+
+	clearer, ok := c.driver.(PrefixClearer)
+	if !ok {
+		return ErrPrefixClearNotSupported
+	}
+	return clearer.ClearPrefix(c.prefix)
 }
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryDriverWithLimit(t *testing.T) {
+	driver := NewMemoryDriverWithLimit(2)
+
+	driver.Set("a", 1, 0)
+	driver.Set("b", 2, 0)
+
+	// 访问 a 使 b 成为最近最少使用
+	if _, err := driver.Get("a"); err != nil {
+		t.Fatalf("Get(a) error: %v", err)
+	}
+
+	driver.Set("c", 3, 0)
+
+	if driver.Exists("b") {
+		t.Error("expected b to be evicted")
+	}
+	if !driver.Exists("a") || !driver.Exists("c") {
+		t.Error("expected a and c to remain")
+	}
+	if driver.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", driver.Len())
+	}
+
+	// 过期清理同时移除访问记录
+	driver.Set("d", 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	driver.deleteExpired()
+
+	if driver.Len() != 1 || driver.order.Len() != 1 {
+		t.Errorf("expected 1 entry after GC, got %d items and %d order entries", driver.Len(), driver.order.Len())
+	}
+}
+
+func TestCacheNamespace(t *testing.T) {
+	c := NewCache(NewMemoryDriver())
+	users := c.Namespace("users")
+	sessions := c.Namespace("sessions")
+
+	users.Set("1", "alice", 0)
+	sessions.Set("1", "token", 0)
+
+	if v, _ := users.GetString("1"); v != "alice" {
+		t.Errorf("users:1 = %q, want alice", v)
+	}
+	if v, _ := c.GetString("sessions:1"); v != "token" {
+		t.Errorf("sessions:1 = %q, want token", v)
+	}
+
+	if err := users.Clear(); err != nil {
+		t.Fatalf("Clear() error: %v", err)
+	}
+	if users.Exists("1") {
+		t.Error("expected users namespace to be cleared")
+	}
+	if !sessions.Exists("1") {
+		t.Error("expected sessions namespace to be kept")
+	}
+}
+
+func TestTieredDriver(t *testing.T) {
+	l1 := NewMemoryDriver()
+	l2 := NewMemoryDriver()
+	driver := NewTieredDriver(l1, l2).SetL1TTL(time.Second)
+
+	driver.Set("a", 1, time.Hour)
+	if !l1.Exists("a") || !l2.Exists("a") {
+		t.Fatal("expected Set to write both levels")
+	}
+
+	// 一级缓存未命中时从二级缓存回填
+	l2.Set("b", 2, 0)
+	if v, err := driver.Get("b"); err != nil || v != 2 {
+		t.Fatalf("Get(b) = %v, %v", v, err)
+	}
+	if !l1.Exists("b") {
+		t.Error("expected L1 to be populated on L2 hit")
+	}
+	if ttl, _ := l1.TTL("b"); ttl <= 0 || ttl > time.Second {
+		t.Errorf("expected backfill to use the L1 TTL, got %v", ttl)
+	}
+
+	// 回填不超过二级缓存的剩余过期时间
+	l2.Set("c", 3, 100*time.Millisecond)
+	driver.Get("c")
+	if ttl, _ := l1.TTL("c"); ttl <= 0 || ttl > 100*time.Millisecond {
+		t.Errorf("expected backfill to keep the L2 expiration, got %v", ttl)
+	}
+	driver.SetL1TTL(0)
+	l1.Delete("c")
+	driver.Get("c")
+	if ttl, _ := l1.TTL("c"); ttl <= 0 || ttl > 100*time.Millisecond {
+		t.Errorf("expected backfill without L1 TTL to keep the L2 expiration, got %v", ttl)
+	}
+	driver.SetL1TTL(time.Second)
+
+	if err := driver.Delete("a"); err != nil {
+		t.Fatalf("Delete(a) error: %v", err)
+	}
+	if l1.Exists("a") || l2.Exists("a") {
+		t.Error("expected Delete to clear both levels")
+	}
+
+	if got := driver.l1Expiration(time.Hour); got != time.Second {
+		t.Errorf("l1Expiration(1h) = %v, want 1s", got)
+	}
+	if got := driver.l1Expiration(0); got != time.Second {
+		t.Errorf("l1Expiration(0) = %v, want 1s", got)
+	}
+}
+
+func TestCacheClose(t *testing.T) {
+	l1 := NewMemoryDriver()
+	l2 := NewMemoryDriver()
+	c := NewCache(NewTieredDriver(l1, l2)).Namespace("app")
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	for _, driver := range []*MemoryDriver{l1, l2} {
+		select {
+		case <-driver.stop:
+		default:
+			t.Error("expected Close to stop the GC goroutine of both levels")
+		}
+	}
+
+	// 重复关闭安全，关闭后仍可读写
+	if err := l1.Close(); err != nil {
+		t.Fatalf("second Close error: %v", err)
+	}
+	if err := c.Set("k", "v", time.Minute); err != nil {
+		t.Fatalf("Set after Close error: %v", err)
+	}
+}
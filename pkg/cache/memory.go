@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"container/list"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 )
@@ -16,12 +18,23 @@ type MemoryItem struct {
 type MemoryDriver struct {
 	items map[string]MemoryItem
 	mu    sync.RWMutex
+
+	// maxEntries 最大缓存条目数，0 表示不限制
+	maxEntries int
+	// order 访问顺序，表头为最近使用的键
+	order    *list.List
+	elements map[string]*list.Element
+
+	// stop 关闭时停止过期清理 goroutine
+	stop      chan struct{}
+	closeOnce sync.Once
 }
 
 // NewMemoryDriver 创建一个新的内存缓存驱动
 func NewMemoryDriver() *MemoryDriver {
 	driver := &MemoryDriver{
 		items: make(map[string]MemoryItem),
+		stop:  make(chan struct{}),
 	}
 
 	// 启动过期清理
@@ -30,10 +43,28 @@ func NewMemoryDriver() *MemoryDriver {
 	return driver
 }
 
+// NewMemoryDriverWithLimit 创建有最大条目数限制的内存缓存驱动
+// 超出限制时淘汰最近最少使用（LRU）的缓存，maxEntries <= 0 时不限制
+func NewMemoryDriverWithLimit(maxEntries int) *MemoryDriver {
+	driver := NewMemoryDriver()
+	if maxEntries > 0 {
+		driver.maxEntries = maxEntries
+		driver.order = list.New()
+		driver.elements = make(map[string]*list.Element)
+	}
+	return driver
+}
+
 // Get 获取缓存
 func (d *MemoryDriver) Get(key string) (interface{}, error) {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+	// LRU 模式下读取会更新访问顺序，需要写锁
+	if d.order != nil {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	} else {
+		d.mu.RLock()
+		defer d.mu.RUnlock()
+	}
 
 	item, found := d.items[key]
 	if !found {
@@ -45,6 +76,7 @@ func (d *MemoryDriver) Get(key string) (interface{}, error) {
 		return nil, errors.New("key expired")
 	}
 
+	d.touch(key)
 	return item.Value, nil
 }
 
@@ -63,6 +95,8 @@ func (d *MemoryDriver) Set(key string, value interface{}, ttl time.Duration) err
 		Expiration: expiration,
 	}
 
+	d.touch(key)
+	d.evict()
 	return nil
 }
 
@@ -75,7 +109,7 @@ func (d *MemoryDriver) Delete(key string) error {
 		return errors.New("key not found")
 	}
 
-	delete(d.items, key)
+	d.remove(key)
 	return nil
 }
 
@@ -97,26 +131,123 @@ func (d *MemoryDriver) Exists(key string) bool {
 	return true
 }
 
+// TTL 获取剩余过期时间，永不过期时返回 0
+func (d *MemoryDriver) TTL(key string) (time.Duration, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	item, found := d.items[key]
+	if !found {
+		return 0, errors.New("key not found")
+	}
+	if item.Expiration == 0 {
+		return 0, nil
+	}
+
+	remaining := time.Until(time.Unix(0, item.Expiration))
+	if remaining <= 0 {
+		return 0, errors.New("key expired")
+	}
+	return remaining, nil
+}
+
 // Clear 清空缓存
 func (d *MemoryDriver) Clear() error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
 	d.items = make(map[string]MemoryItem)
+	if d.order != nil {
+		d.order.Init()
+		d.elements = make(map[string]*list.Element)
+	}
 	return nil
 }
 
+// ClearPrefix 清除指定前缀的缓存
+func (d *MemoryDriver) ClearPrefix(prefix string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key := range d.items {
+		if strings.HasPrefix(key, prefix) {
+			d.remove(key)
+		}
+	}
+	return nil
+}
+
+// Len 获取缓存条目数（包含尚未被清理的过期条目）
+func (d *MemoryDriver) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.items)
+}
+
+// touch 将键标记为最近使用，调用方需持有写锁
+func (d *MemoryDriver) touch(key string) {
+	if d.order == nil {
+		return
+	}
+
+	if elem, found := d.elements[key]; found {
+		d.order.MoveToFront(elem)
+		return
+	}
+	d.elements[key] = d.order.PushFront(key)
+}
+
+// evict 淘汰超出限制的最近最少使用缓存，调用方需持有写锁
+func (d *MemoryDriver) evict() {
+	if d.order == nil {
+		return
+	}
+
+	for len(d.items) > d.maxEntries {
+		oldest := d.order.Back()
+		if oldest == nil {
+			return
+		}
+		d.remove(oldest.Value.(string))
+	}
+}
+
+// remove 删除缓存及其访问记录，调用方需持有写锁
+func (d *MemoryDriver) remove(key string) {
+	delete(d.items, key)
+	if d.order == nil {
+		return
+	}
+
+	if elem, found := d.elements[key]; found {
+		d.order.Remove(elem)
+		delete(d.elements, key)
+	}
+}
+
 // startGC 启动垃圾回收
 func (d *MemoryDriver) startGC() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
 	for {
-		<-ticker.C
-		d.deleteExpired()
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			d.deleteExpired()
+		}
 	}
 }
 
+// Close 停止过期清理 goroutine，关闭后缓存仍可读写，过期项在读取时判定
+func (d *MemoryDriver) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.stop)
+	})
+	return nil
+}
+
 // deleteExpired 删除过期缓存
 func (d *MemoryDriver) deleteExpired() {
 	now := time.Now().UnixNano()
@@ -126,7 +257,7 @@ func (d *MemoryDriver) deleteExpired() {
 
 	for key, item := range d.items {
 		if item.Expiration > 0 && item.Expiration < now {
-			delete(d.items, key)
+			d.remove(key)
 		}
 	}
 }
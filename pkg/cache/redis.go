@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisScanCount 按前缀清除时每次 SCAN 的数量
+const redisScanCount = 100
+
+// RedisDriver Redis 缓存驱动
+// 值以 JSON 编码存储，读取时数字解码为 float64，结构体解码为 map[string]interface{}
+type RedisDriver struct {
+	client *redis.Client
+	prefix string
+	ctx    context.Context
+}
+
+// NewRedisDriver 创建 Redis 缓存驱动，所有键添加 "prefix:" 前缀，Clear 只清除该前缀下的键
+func NewRedisDriver(client *redis.Client, prefix string) *RedisDriver {
+	if prefix == "" {
+		prefix = "cache"
+	}
+	return &RedisDriver{
+		client: client,
+		prefix: prefix + NamespaceSeparator,
+		ctx:    context.Background(),
+	}
+}
+
+// key 添加驱动前缀
+func (d *RedisDriver) key(key string) string {
+	return d.prefix + key
+}
+
+// Get 获取缓存
+func (d *RedisDriver) Get(key string) (interface{}, error) {
+	data, err := d.client.Get(d.ctx, d.key(key)).Bytes()
+	if err == redis.Nil {
+		return nil, errors.New("key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Set 设置缓存，ttl <= 0 时永不过期
+func (d *RedisDriver) Set(key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if ttl < 0 {
+		ttl = 0
+	}
+	return d.client.Set(d.ctx, d.key(key), data, ttl).Err()
+}
+
+// Normalize 实现 Normalizer，返回值经 JSON 编码再解码后的形式，与 Get 的结果一致
+func (d *RedisDriver) Normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// Delete 删除缓存
+func (d *RedisDriver) Delete(key string) error {
+	deleted, err := d.client.Del(d.ctx, d.key(key)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("key not found")
+	}
+	return nil
+}
+
+// Exists 检查缓存是否存在
+func (d *RedisDriver) Exists(key string) bool {
+	count, err := d.client.Exists(d.ctx, d.key(key)).Result()
+	return err == nil && count > 0
+}
+
+// TTL 获取剩余过期时间，永不过期时返回 0
+func (d *RedisDriver) TTL(key string) (time.Duration, error) {
+	ttl, err := d.client.PTTL(d.ctx, d.key(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// go-redis 对不存在的键返回 -2，对永不过期的键返回 -1（不按精度换算）
+	switch {
+	case ttl == -2:
+		return 0, errors.New("key not found")
+	case ttl < 0:
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// Clear 清空驱动前缀下的所有缓存
+func (d *RedisDriver) Clear() error {
+	return d.ClearPrefix("")
+}
+
+// ClearPrefix 清除指定前缀的缓存
+func (d *RedisDriver) ClearPrefix(prefix string) error {
+	iter := d.client.Scan(d.ctx, 0, escapeRedisPattern(d.key(prefix))+"*", redisScanCount).Iterator()
+
+	keys := make([]string, 0, redisScanCount)
+	for iter.Next(d.ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == redisScanCount {
+			if err := d.client.Del(d.ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(keys) > 0 {
+		return d.client.Del(d.ctx, keys...).Err()
+	}
+	return nil
+}
+
+// escapeRedisPattern 转义 SCAN MATCH 模式中的通配字符
+func escapeRedisPattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
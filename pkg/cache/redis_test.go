@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisDriver(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	driver := NewRedisDriver(client, "app")
+	c := NewCache(driver)
+
+	if err := c.Set("name", "clark", time.Minute); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if !server.Exists("app:name") {
+		t.Error("expected key to be stored with the driver prefix")
+	}
+	if name, err := c.GetString("name"); err != nil || name != "clark" {
+		t.Errorf("GetString() = %q, %v", name, err)
+	}
+
+	// 数字经 JSON 解码为 float64，GetInt 仍可读取
+	c.Set("count", 3, 0)
+	if count, err := c.GetInt("count"); err != nil || count != 3 {
+		t.Errorf("GetInt() = %d, %v", count, err)
+	}
+
+	if ttl, err := driver.TTL("name"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL(name) = %v, %v", ttl, err)
+	}
+	if ttl, err := driver.TTL("count"); err != nil || ttl != 0 {
+		t.Errorf("TTL(count) = %v, %v, want 0 for no expiration", ttl, err)
+	}
+	if _, err := driver.TTL("missing"); err == nil {
+		t.Error("expected error for missing key")
+	}
+
+	server.FastForward(2 * time.Minute)
+	if c.Exists("name") {
+		t.Error("expected key to expire")
+	}
+	if _, err := c.Get("name"); err == nil {
+		t.Error("expected error for expired key")
+	}
+
+	// Clear 只清除驱动前缀下的键，命名空间视图只清除自己的前缀
+	server.Set("other:key", "kept")
+	users := c.Namespace("users")
+	users.Set("1", "a", 0)
+	users.Set("2", "b", 0)
+	if err := users.Clear(); err != nil {
+		t.Fatalf("namespace Clear error: %v", err)
+	}
+	if users.Exists("1") || !c.Exists("count") {
+		t.Error("expected namespace Clear to only remove its own keys")
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if c.Exists("count") || !server.Exists("other:key") {
+		t.Error("expected Clear to only remove keys under the driver prefix")
+	}
+
+	if err := c.Delete("count"); err == nil {
+		t.Error("expected error deleting missing key")
+	}
+}
+
+func TestTieredDriverConsistentTypes(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	type profile struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	l1 := NewMemoryDriver()
+	driver := NewTieredDriver(l1, NewRedisDriver(client, "app"))
+	if err := driver.Set("profile", profile{Name: "clark", Age: 30}, time.Minute); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	fromL1, err := driver.Get("profile")
+	if err != nil {
+		t.Fatalf("Get from L1 error: %v", err)
+	}
+
+	// 一级缓存未命中时从二级缓存读取，类型与一级缓存命中时相同
+	l1.Delete("profile")
+	fromL2, err := driver.Get("profile")
+	if err != nil {
+		t.Fatalf("Get from L2 error: %v", err)
+	}
+
+	want := map[string]interface{}{"name": "clark", "age": float64(30)}
+	if !reflect.DeepEqual(fromL1, want) {
+		t.Errorf("L1 hit = %#v, want %#v", fromL1, want)
+	}
+	if !reflect.DeepEqual(fromL2, want) {
+		t.Errorf("L2 hit = %#v, want %#v", fromL2, want)
+	}
+
+	// 无法编码的值不会写入一级缓存
+	if err := driver.Set("bad", make(chan int), time.Minute); err == nil || l1.Exists("bad") {
+		t.Errorf("expected Set to fail without writing L1, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultL1TTL 一级缓存默认过期时间
+const DefaultL1TTL = time.Minute
+
+// TieredDriver 两级缓存驱动
+// 一级缓存（通常为内存）位于二级缓存（通常为 Redis）之前，用于加速热点键。
+// 二级缓存实现 Normalizer 时一级缓存保存归一化后的值，无论命中哪一级，读取到的类型都相同
+type TieredDriver struct {
+	l1    Driver
+	l2    Driver
+	l1TTL time.Duration
+}
+
+// NewTieredDriver 创建两级缓存驱动
+func NewTieredDriver(l1, l2 Driver) *TieredDriver {
+	return &TieredDriver{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: DefaultL1TTL,
+	}
+}
+
+// SetL1TTL 设置一级缓存过期时间，用于限制一级缓存数据的陈旧程度
+func (d *TieredDriver) SetL1TTL(ttl time.Duration) *TieredDriver {
+	d.l1TTL = ttl
+	return d
+}
+
+// Get 获取缓存，一级缓存未命中时读取二级缓存并回填一级缓存
+// 二级缓存支持 TTLReader 时回填的过期时间不超过二级缓存的剩余过期时间
+func (d *TieredDriver) Get(key string) (interface{}, error) {
+	if value, err := d.l1.Get(key); err == nil {
+		return value, nil
+	}
+
+	value, err := d.l2.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var ttl time.Duration
+	if reader, ok := d.l2.(TTLReader); ok {
+		if remaining, err := reader.TTL(key); err == nil {
+			ttl = remaining
+		}
+	}
+
+	d.l1.Set(key, value, d.l1Expiration(ttl))
+	return value, nil
+}
+
+// Set 设置缓存，同时写入两级缓存
+// 一级缓存的过期时间取 ttl 与一级缓存过期时间中较短者
+func (d *TieredDriver) Set(key string, value interface{}, ttl time.Duration) error {
+	if err := d.l2.Set(key, value, ttl); err != nil {
+		return err
+	}
+
+	if normalizer, ok := d.l2.(Normalizer); ok {
+		normalized, err := normalizer.Normalize(value)
+		if err != nil {
+			return err
+		}
+		value = normalized
+	}
+	return d.l1.Set(key, value, d.l1Expiration(ttl))
+}
+
+// Delete 删除缓存，同时删除两级缓存
+func (d *TieredDriver) Delete(key string) error {
+	l1Err := d.l1.Delete(key)
+	l2Err := d.l2.Delete(key)
+
+	if l1Err == nil || l2Err == nil {
+		return nil
+	}
+	return l2Err
+}
+
+// Exists 检查缓存是否存在
+func (d *TieredDriver) Exists(key string) bool {
+	return d.l1.Exists(key) || d.l2.Exists(key)
+}
+
+// Clear 清空两级缓存
+func (d *TieredDriver) Clear() error {
+	if err := d.l1.Clear(); err != nil {
+		return err
+	}
+	return d.l2.Clear()
+}
+
+// ClearPrefix 清除两级缓存中指定前缀的缓存
+func (d *TieredDriver) ClearPrefix(prefix string) error {
+	l1, ok1 := d.l1.(PrefixClearer)
+	l2, ok2 := d.l2.(PrefixClearer)
+	if !ok1 || !ok2 {
+		return ErrPrefixClearNotSupported
+	}
+
+	if err := l1.ClearPrefix(prefix); err != nil {
+		return err
+	}
+	return l2.ClearPrefix(prefix)
+}
+
+// l1Expiration 计算一级缓存过期时间
+func (d *TieredDriver) l1Expiration(ttl time.Duration) time.Duration {
+	if d.l1TTL <= 0 {
+		return ttl
+	}
+	if ttl <= 0 || ttl > d.l1TTL {
+		return d.l1TTL
+	}
+	return ttl
+}
+
+// Close 关闭两级缓存中需要关闭的驱动
+func (d *TieredDriver) Close() error {
+	var errs []error
+	for _, driver := range []Driver{d.l1, d.l2} {
+		if cl, ok := driver.(closer); ok {
+			if err := cl.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
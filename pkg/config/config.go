@@ -5,27 +5,78 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// 配置文件格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// formatExtensions 文件扩展名对应的配置格式
+var formatExtensions = map[string]string{
+	".json": FormatJSON,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".toml": FormatTOML,
+}
+
+// EnvVar 未调用 SetEnv 时读取运行环境的环境变量
+const EnvVar = "APP_ENV"
+
+// source 顶级配置的来源文件
+type source struct {
+	path   string
+	format string
+}
+
 // Config 配置管理器
 type Config struct {
-	items map[string]interface{}
-	paths []string
+	items   map[string]interface{}
+	paths   []string
+	sources map[string]source
+	key     []byte // 加密配置值的解密密钥
+	env     string // 运行环境，决定加载哪些环境覆盖文件
 }
 
 // NewConfig 创建一个新的配置管理器
 func NewConfig(paths []string) *Config {
 	return &Config{
-		items: make(map[string]interface{}),
-		paths: paths,
+		items:   make(map[string]interface{}),
+		paths:   paths,
+		sources: make(map[string]source),
+	}
+}
+
+// SetEnv 设置运行环境，未设置时使用环境变量 APP_ENV
+func (c *Config) SetEnv(env string) *Config {
+	c.env = env
+	return c
+}
+
+// Env 返回加载环境覆盖文件使用的运行环境
+func (c *Config) Env() string {
+	if c.env != "" {
+		return c.env
 	}
+	return os.Getenv(EnvVar)
 }
 
 // Load 加载配置文件
+// 每个目录先加载基础文件（如 database.json），再将当前运行环境的覆盖文件（如 database.production.json）
+// 深度合并到基础配置上：映射逐键合并，其他值（包括数组）整体替换。
+// 优先级从低到高依次为：前面目录的基础文件、前面目录的覆盖文件、后面目录的基础文件（整体替换）、后面目录的覆盖文件。
+// 文件名中带 "." 的文件只作为环境覆盖文件，不属于当前运行环境时忽略
 func (c *Config) Load() error {
+	env := c.Env()
+
 	for _, path := range c.paths {
 		files, err := os.ReadDir(path)
 		if err != nil {
@@ -33,31 +84,173 @@ func (c *Config) Load() error {
 			continue
 		}
 
+		var overlays []string
 		for _, file := range files {
-			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			ext := filepath.Ext(file.Name())
+			format, ok := formatExtensions[ext]
+			if file.IsDir() || !ok {
 				continue
 			}
 
-			configName := strings.TrimSuffix(file.Name(), ".json")
-			configPath := filepath.Join(path, file.Name())
+			configName := strings.TrimSuffix(file.Name(), ext)
+			if strings.Contains(configName, ".") {
+				if env != "" && strings.HasSuffix(configName, "."+env) {
+					overlays = append(overlays, file.Name())
+				}
+				continue
+			}
 
-			data, err := os.ReadFile(configPath)
+			configPath := filepath.Join(path, file.Name())
+			configData, err := readConfigFile(configPath, format)
 			if err != nil {
-				return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+				return err
 			}
 
-			var configData interface{}
-			if err := json.Unmarshal(data, &configData); err != nil {
-				return fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+			c.items[configName] = configData
+			c.sources[configName] = source{path: configPath, format: format}
+		}
+
+		for _, name := range overlays {
+			ext := filepath.Ext(name)
+			configName := strings.TrimSuffix(strings.TrimSuffix(name, ext), "."+env)
+			configPath := filepath.Join(path, name)
+			configData, err := readConfigFile(configPath, formatExtensions[ext])
+			if err != nil {
+				return err
 			}
 
-			c.items[configName] = configData
+			// 有覆盖文件的配置保存到覆盖文件，避免将环境配置写入基础文件
+			c.items[configName] = mergeConfig(c.items[configName], configData)
+			c.sources[configName] = source{path: configPath, format: formatExtensions[ext]}
 		}
 	}
 
 	return nil
 }
 
+// readConfigFile 读取并解析配置文件
+func readConfigFile(configPath, format string) (interface{}, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	configData, err := decode(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	}
+	return configData, nil
+}
+
+// mergeConfig 将 overlay 深度合并到 base 上：两侧都是映射时逐键合并，否则 overlay 替换 base
+// 不修改 base，返回合并后的新值
+func mergeConfig(base, overlay interface{}) interface{} {
+	baseMap, ok := base.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+	overlayMap, ok := overlay.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+
+	merged := make(map[string]interface{}, len(baseMap)+len(overlayMap))
+	for k, v := range baseMap {
+		merged[k] = v
+	}
+	for k, v := range overlayMap {
+		merged[k] = mergeConfig(baseMap[k], v)
+	}
+	return merged
+}
+
+// LoadEncrypted 设置解密密钥并加载配置文件
+// 之后 Get 读取到 "enc:<base64>" 格式的字符串时会使用 AES-GCM 透明解密，未加密的值原样返回
+func (c *Config) LoadEncrypted(key []byte) error {
+	if _, err := newGCM(key); err != nil {
+		return err
+	}
+
+	c.key = key
+	return c.Load()
+}
+
+// Save 将顶级配置写回其来源文件，保持原有格式
+// 加载了环境覆盖文件的配置将合并后的完整配置写入覆盖文件，基础文件保持不变；
+// 运行时新增的配置写入第一个配置目录下的 <section>.json
+func (c *Config) Save(section string) error {
+	config, ok := c.items[section]
+	if !ok {
+		return fmt.Errorf("config section %s not found", section)
+	}
+
+	src, ok := c.sources[section]
+	if !ok {
+		if len(c.paths) == 0 {
+			return fmt.Errorf("no config path to save section %s", section)
+		}
+		src = source{path: filepath.Join(c.paths[0], section+".json"), format: FormatJSON}
+	}
+
+	data, err := encode(src.format, config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config section %s: %w", section, err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(src.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	// 先写临时文件再重命名，避免写入中断导致配置文件损坏
+	tmpPath := src.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", src.path, err)
+	}
+	if err := os.Rename(tmpPath, src.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file %s: %w", src.path, err)
+	}
+
+	c.sources[section] = src
+	return nil
+}
+
+// decode 按格式解析配置内容
+func decode(format string, data []byte) (interface{}, error) {
+	var configData interface{}
+	var err error
+
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &configData)
+	case FormatTOML:
+		var m map[string]interface{}
+		err = toml.Unmarshal(data, &m)
+		configData = m
+	default:
+		err = json.Unmarshal(data, &configData)
+	}
+
+	return configData, err
+}
+
+// encode 按格式序列化配置，JSON 使用缩进格式
+func encode(format string, config interface{}) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(config)
+	case FormatTOML:
+		return toml.Marshal(config)
+	default:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+}
+
 // Get 获取配置项
 func (c *Config) Get(key string, defaultValue ...interface{}) interface{} {
 	keys := strings.Split(key, ".")
@@ -80,24 +273,40 @@ func (c *Config) Get(key string, defaultValue ...interface{}) interface{} {
 		return config
 	}
 
-	// 递归获取嵌套配置
+	// 递归获取嵌套配置，数字键用于访问数组元素，如 servers.0.host
 	current := config
 	for _, k := range keys[1:] {
-		m, ok := current.(map[string]interface{})
+		var ok bool
+		switch node := current.(type) {
+		case map[string]interface{}:
+			current, ok = node[k]
+		case []interface{}:
+			var index int
+			index, ok = sliceIndex(k, len(node))
+			if ok {
+				current = node[index]
+			}
+		}
+
 		if !ok {
 			if len(defaultValue) > 0 {
 				return defaultValue[0]
 			}
 			return nil
 		}
+	}
 
-		current, ok = m[k]
-		if !ok {
+	// 解密加密配置值，解密失败时视为配置不存在，避免将密文当作明文使用
+	if str, ok := current.(string); ok && c.key != nil && IsEncrypted(str) {
+		plaintext, err := Decrypt(c.key, str)
+		if err != nil {
+			hlog.Warnf("Failed to decrypt config %s: %v", key, err)
 			if len(defaultValue) > 0 {
 				return defaultValue[0]
 			}
 			return nil
 		}
+		return plaintext
 	}
 
 	return current
@@ -121,6 +330,39 @@ func (c *Config) GetString(key string, defaultValue ...string) string {
 	return ""
 }
 
+// GetSlice 获取数组配置，不是数组时返回 nil
+func (c *Config) GetSlice(key string) []interface{} {
+	if items, ok := c.Get(key).([]interface{}); ok {
+		return items
+	}
+	return nil
+}
+
+// GetStringSlice 获取字符串数组配置，跳过非字符串元素，加密的元素会被解密
+func (c *Config) GetStringSlice(key string) []string {
+	items := c.GetSlice(key)
+	if items == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(items))
+	for i := range items {
+		if str, ok := c.Get(fmt.Sprintf("%s.%d", key, i)).(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// sliceIndex 将路径中的数字键转换为数组下标，超出范围时返回 false
+func sliceIndex(key string, length int) (int, bool) {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || index >= length {
+		return 0, false
+	}
+	return index, true
+}
+
 // GetInt 获取整数配置
 func (c *Config) GetInt(key string, defaultValue ...int) int {
 	value := c.Get(key)
@@ -131,6 +373,8 @@ func (c *Config) GetInt(key string, defaultValue ...int) int {
 	switch v := value.(type) {
 	case int:
 		return v
+	case int64:
+		return int(v)
 	case float64:
 		return int(v)
 	}
@@ -142,6 +386,28 @@ func (c *Config) GetInt(key string, defaultValue ...int) int {
 	return 0
 }
 
+// GetIntMap 获取值为整数的映射配置，如端点权重表，跳过非数字的值
+// 映射的键直接使用，不按 "." 拆分
+func (c *Config) GetIntMap(key string) map[string]int {
+	items, ok := c.Get(key).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]int, len(items))
+	for k, value := range items {
+		switch v := value.(type) {
+		case int:
+			result[k] = v
+		case int64:
+			result[k] = int(v)
+		case float64:
+			result[k] = int(v)
+		}
+	}
+	return result
+}
+
 // GetBool 获取布尔配置
 func (c *Config) GetBool(key string, defaultValue ...bool) bool {
 	value := c.Get(key)
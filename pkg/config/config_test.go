@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMergesEnvOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "database.json", `{
+		"default": "mysql",
+		"connections": {
+			"mysql": {"host": "127.0.0.1", "port": 3306, "database": "app"},
+			"redis": {"host": "127.0.0.1"}
+		},
+		"replicas": ["a", "b"]
+	}`)
+	writeConfigFile(t, dir, "database.production.json", `{
+		"connections": {"mysql": {"host": "db.internal"}},
+		"replicas": ["c"]
+	}`)
+	writeConfigFile(t, dir, "database.testing.json", `{"default": "sqlite"}`)
+	writeConfigFile(t, dir, "cache.production.json", `{"driver": "redis"}`)
+
+	c := NewConfig([]string{dir}).SetEnv("production")
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if host := c.GetString("database.connections.mysql.host"); host != "db.internal" {
+		t.Errorf("expected overlay host db.internal, got %q", host)
+	}
+	// 覆盖文件未设置的键保留基础值
+	if port := c.GetInt("database.connections.mysql.port"); port != 3306 {
+		t.Errorf("expected base port 3306, got %d", port)
+	}
+	if host := c.GetString("database.connections.redis.host"); host != "127.0.0.1" {
+		t.Errorf("expected untouched redis host, got %q", host)
+	}
+	// 数组整体替换
+	if replicas := c.GetStringSlice("database.replicas"); len(replicas) != 1 || replicas[0] != "c" {
+		t.Errorf("expected overlay to replace replicas, got %v", replicas)
+	}
+	// 其他环境的覆盖文件被忽略
+	if def := c.GetString("database.default"); def != "mysql" {
+		t.Errorf("expected testing overlay to be ignored, got %q", def)
+	}
+	// 没有基础文件的覆盖文件单独生效
+	if driver := c.GetString("cache.driver"); driver != "redis" {
+		t.Errorf("expected overlay-only section, got %q", driver)
+	}
+}
+
+func TestLoadEnvFromEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "app.json", `{"debug": true}`)
+	writeConfigFile(t, dir, "app.staging.json", `{"debug": false}`)
+
+	t.Setenv(EnvVar, "staging")
+
+	c := NewConfig([]string{dir})
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.Env() != "staging" || c.GetBool("app.debug", true) {
+		t.Errorf("expected APP_ENV overlay to apply, env=%q debug=%v", c.Env(), c.GetBool("app.debug", true))
+	}
+
+	// SetEnv 优先于 APP_ENV
+	c = NewConfig([]string{dir}).SetEnv("development")
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !c.GetBool("app.debug") {
+		t.Error("expected base config when SetEnv overrides APP_ENV")
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"app.json": `{"name": "demo", "server": {"port": 8080}}`,
+		"app.yaml": "name: demo\nserver:\n  port: 8080\n",
+		"app.toml": "name = \"demo\"\n\n[server]\nport = 8080\n",
+	}
+
+	for name, content := range files {
+		t.Run(filepath.Ext(name), func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFile(t, dir, name, content)
+
+			c := NewConfig([]string{dir})
+			if err := c.Load(); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			c.Set("app.name", "renamed")
+			c.Set("app.server.port", 9090)
+			c.Set("app.features", []interface{}{"a", "b"})
+			if err := c.Save("app"); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			// 写回原文件，不生成其他格式的文件
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 || entries[0].Name() != name {
+				t.Fatalf("expected only %s after save, got %v", name, entries)
+			}
+
+			reloaded := NewConfig([]string{dir})
+			if err := reloaded.Load(); err != nil {
+				t.Fatalf("reload failed: %v", err)
+			}
+			if got := reloaded.GetString("app.name"); got != "renamed" {
+				t.Errorf("expected saved name, got %q", got)
+			}
+			if got := reloaded.GetInt("app.server.port"); got != 9090 {
+				t.Errorf("expected saved port, got %d", got)
+			}
+			if got := reloaded.GetStringSlice("app.features"); len(got) != 2 || got[1] != "b" {
+				t.Errorf("expected saved slice, got %v", got)
+			}
+		})
+	}
+}
+
+func TestSaveNewSection(t *testing.T) {
+	dir := t.TempDir()
+	c := NewConfig([]string{dir})
+	if err := c.Save("missing"); err == nil {
+		t.Error("expected error for unknown section")
+	}
+
+	c.Set("cache.driver", "redis")
+	if err := c.Save("cache"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cache.json")); err != nil {
+		t.Errorf("expected new section to be written as JSON: %v", err)
+	}
+}
+
+func TestGetSliceIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "cluster.json", `{
+		"name": "primary",
+		"servers": [
+			{"host": "10.0.0.1", "port": 6379},
+			{"host": "10.0.0.2", "tags": ["replica", 1, "eu"]}
+		]
+	}`)
+
+	c := NewConfig([]string{dir})
+	if err := c.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if host := c.GetString("cluster.servers.0.host"); host != "10.0.0.1" {
+		t.Errorf("expected first server host, got %q", host)
+	}
+	if port := c.GetInt("cluster.servers.0.port"); port != 6379 {
+		t.Errorf("expected first server port, got %d", port)
+	}
+	if servers := c.GetSlice("cluster.servers"); len(servers) != 2 {
+		t.Errorf("expected 2 servers, got %v", servers)
+	}
+	// 非字符串元素被跳过
+	if tags := c.GetStringSlice("cluster.servers.1.tags"); len(tags) != 2 || tags[1] != "eu" {
+		t.Errorf("expected string tags, got %v", tags)
+	}
+
+	for _, key := range []string{
+		"cluster.servers.2.host",  // 下标越界
+		"cluster.servers.-1.host", // 负数下标
+		"cluster.servers.x.host",  // 非数字键
+		"cluster.name.0",          // 父节点不是数组
+	} {
+		if value := c.Get(key, "default"); value != "default" {
+			t.Errorf("%s: expected default, got %v", key, value)
+		}
+	}
+	if c.GetSlice("cluster.name") != nil || c.GetStringSlice("cluster.missing") != nil {
+		t.Error("expected nil for non-slice values")
+	}
+}
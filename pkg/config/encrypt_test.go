@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt(testKey, "s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(encrypted) || strings.Contains(encrypted, "s3cret") {
+		t.Fatalf("expected enc: value without plaintext, got %q", encrypted)
+	}
+
+	// 每次加密使用随机 nonce
+	if again, _ := Encrypt(testKey, "s3cret"); again == encrypted {
+		t.Error("expected different ciphertext for repeated encryption")
+	}
+
+	plaintext, err := Decrypt(testKey, encrypted)
+	if err != nil || plaintext != "s3cret" {
+		t.Errorf("expected round trip to s3cret, got %q, %v", plaintext, err)
+	}
+
+	if _, err := Encrypt([]byte("short"), "s3cret"); err == nil {
+		t.Error("expected error for invalid key length")
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	encrypted, _ := Encrypt(testKey, "s3cret")
+
+	if _, err := Decrypt([]byte("fedcba9876543210fedcba9876543210"), encrypted); err == nil {
+		t.Error("expected wrong key to fail")
+	}
+	if _, err := Decrypt(testKey, "s3cret"); !errors.Is(err, ErrInvalidEncryptedValue) {
+		t.Errorf("expected ErrInvalidEncryptedValue for plaintext, got %v", err)
+	}
+	if _, err := Decrypt(testKey, EncryptedPrefix+"not-base64!"); !errors.Is(err, ErrInvalidEncryptedValue) {
+		t.Errorf("expected ErrInvalidEncryptedValue for malformed value, got %v", err)
+	}
+}
+
+func TestLoadEncrypted(t *testing.T) {
+	encrypted, _ := Encrypt(testKey, "db-pass")
+	wrongKey, _ := Encrypt([]byte("fedcba9876543210fedcba9876543210"), "other")
+
+	dir := t.TempDir()
+	writeConfigFile(t, dir, "database.json", `{
+		"password": "`+encrypted+`",
+		"username": "app",
+		"broken": "`+wrongKey+`",
+		"hosts": ["plain", "`+encrypted+`"]
+	}`)
+
+	c := NewConfig([]string{dir})
+	if err := c.LoadEncrypted([]byte("short")); err == nil {
+		t.Error("expected LoadEncrypted to reject an invalid key")
+	}
+	if err := c.LoadEncrypted(testKey); err != nil {
+		t.Fatalf("LoadEncrypted failed: %v", err)
+	}
+
+	if password := c.GetString("database.password"); password != "db-pass" {
+		t.Errorf("expected decrypted password, got %q", password)
+	}
+	// 未加密的值原样返回
+	if username := c.GetString("database.username"); username != "app" {
+		t.Errorf("expected plaintext passthrough, got %q", username)
+	}
+	// 解密失败时视为配置不存在
+	if broken := c.GetString("database.broken", "fallback"); broken != "fallback" {
+		t.Errorf("expected default for undecryptable value, got %q", broken)
+	}
+	if hosts := c.GetStringSlice("database.hosts"); len(hosts) != 2 || hosts[0] != "plain" || hosts[1] != "db-pass" {
+		t.Errorf("expected decrypted slice elements, got %v", hosts)
+	}
+}
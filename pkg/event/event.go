@@ -2,10 +2,15 @@ package event
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/log"
 )
 
 // Event 事件接口
@@ -19,24 +24,70 @@ type Listener func(ctx context.Context, event Event) error
 
 // ListenerWrapper 监听器包装器
 type ListenerWrapper struct {
-	Name     string
-	Handler  Listener
-	Priority int  // 优先级，数字越小优先级越高
-	Async    bool // 是否异步执行
+	Name       string
+	Handler    Listener
+	Priority   int  // 优先级，数字越小优先级越高
+	Async      bool // 是否异步执行
+	Sequential bool // 是否在事件专用队列中按顺序执行（仅异步）
 }
 
 // Dispatcher 事件分发器
 type Dispatcher struct {
-	listeners map[string][]*ListenerWrapper
-	mu        sync.RWMutex
-	queue     chan *eventJob
-	workers   int
-	ctx       context.Context
-	cancel    context.CancelFunc
-	wg        sync.WaitGroup
-	logs      []EventLog
-	logsMu    sync.RWMutex
-	maxLogs   int
+	listeners  map[string][]*ListenerWrapper
+	mu         sync.RWMutex
+	queue      chan *eventJob
+	queueSize  int
+	sequential map[string]chan *eventJob // 顺序监听器的事件专用队列
+	workers    int
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+	logs       []EventLog
+	logsMu     sync.RWMutex
+	maxLogs    int
+	replayBuf  []Event // 重放缓冲区，保留原始事件
+	replaySize int     // 重放缓冲区大小，0 表示未启用
+	replayMu   sync.RWMutex
+	onFailure  []func(EventLog) // 监听器失败回调
+	logger     log.StructuredLogger
+	webhooks   WebhookSender // webhook 投递器
+	endpoints  [][2]string   // ListenWebhook 注册的 url 和密钥，设置投递器时登记
+
+	overflowPolicy  OverflowPolicy // 异步队列已满时的处理策略
+	overflowTimeout time.Duration  // Block 策略的最长等待时间
+	dropped         atomic.Int64   // 因队列已满未执行的异步监听器数量
+}
+
+// OverflowPolicy 异步队列已满时的处理策略
+type OverflowPolicy int
+
+const (
+	// DropNewest 丢弃新分发的任务并记录警告（默认）
+	DropNewest OverflowPolicy = iota
+	// DropOldest 丢弃队列中最旧的任务，为新任务腾出位置
+	DropOldest
+	// Block 阻塞分发方直到队列有空位，超过等待时间后返回 ErrQueueFull
+	Block
+	// Reject 不入队，分发方收到 ErrQueueFull
+	Reject
+)
+
+// ErrQueueFull 异步队列已满，监听器未执行
+var ErrQueueFull = errors.New("event queue full")
+
+// ErrDispatcherStopped 分发器已停止，异步监听器未执行
+var ErrDispatcherStopped = errors.New("event dispatcher stopped")
+
+// WebhookSender webhook 投递接口，由 webhook.Deliverer 实现
+type WebhookSender interface {
+	Send(ctx context.Context, eventName string, data interface{}, url, secret string) error
+}
+
+// WebhookRegistrar 支持预先登记端点的投递器，由 webhook.Deliverer 实现
+// 队列工作进程不分发事件，需要在注册监听器时登记端点才能在投递时解析密钥
+type WebhookRegistrar interface {
+	RegisterEndpoint(url, secret string) string
 }
 
 // eventJob 事件任务
@@ -58,26 +109,98 @@ type EventLog struct {
 	Async        bool
 }
 
-// NewDispatcher 创建新的事件分发器
+// ListenerStat 监听器统计
+type ListenerStat struct {
+	Executions  int
+	Failures    int
+	AvgDuration time.Duration
+	LastError   string
+}
+
+// 分发器默认配置
+const (
+	DefaultWorkers   = 10
+	DefaultQueueSize = 1000
+	DefaultMaxLogs   = 1000
+)
+
+// Config 分发器配置
+type Config struct {
+	Workers int // 异步工作协程数，<= 0 时使用 DefaultWorkers
+	// QueueSize 异步队列容量，每个事件的顺序监听器队列使用相同容量，必须大于 0
+	// 队列创建后容量不可变，调整容量需要 Stop 排空旧分发器后用新配置创建
+	QueueSize int
+	MaxLogs   int // 保留的执行日志条数，<= 0 时使用 DefaultMaxLogs，运行时可通过 SetMaxLogs 调整
+}
+
+// NewDispatcher 创建新的事件分发器，队列容量和日志条数使用默认值
 func NewDispatcher(workers int) *Dispatcher {
-	if workers <= 0 {
-		workers = 10
+	d, _ := NewDispatcherWithConfig(Config{Workers: workers, QueueSize: DefaultQueueSize})
+	return d
+}
+
+// NewDispatcherWithConfig 按配置创建事件分发器
+func NewDispatcherWithConfig(config Config) (*Dispatcher, error) {
+	if config.QueueSize <= 0 {
+		return nil, fmt.Errorf("event queue size must be greater than 0, got %d", config.QueueSize)
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultWorkers
+	}
+	if config.MaxLogs <= 0 {
+		config.MaxLogs = DefaultMaxLogs
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	d := &Dispatcher{
-		listeners: make(map[string][]*ListenerWrapper),
-		queue:     make(chan *eventJob, 1000),
-		workers:   workers,
-		ctx:       ctx,
-		cancel:    cancel,
-		logs:      make([]EventLog, 0),
-		maxLogs:   1000,
+		listeners:  make(map[string][]*ListenerWrapper),
+		queue:      make(chan *eventJob, config.QueueSize),
+		queueSize:  config.QueueSize,
+		sequential: make(map[string]chan *eventJob),
+		workers:    config.Workers,
+		ctx:        ctx,
+		cancel:     cancel,
+		logs:       make([]EventLog, 0),
+		maxLogs:    config.MaxLogs,
+		logger:     log.Default(),
 	}
 
 	// 启动工作进程
 	d.startWorkers()
 
+	return d, nil
+}
+
+// SetMaxLogs 设置保留的执行日志条数，缩小时立即丢弃最旧的日志
+func (d *Dispatcher) SetMaxLogs(maxLogs int) *Dispatcher {
+	if maxLogs <= 0 {
+		maxLogs = DefaultMaxLogs
+	}
+
+	d.logsMu.Lock()
+	defer d.logsMu.Unlock()
+
+	d.maxLogs = maxLogs
+	if len(d.logs) > d.maxLogs {
+		d.logs = d.logs[len(d.logs)-d.maxLogs:]
+	}
+	return d
+}
+
+// SetOverflowPolicy 设置异步队列（包括顺序监听器的事件队列）已满时的处理策略
+// timeout 仅用于 Block 策略，<= 0 时一直等待，直到分发的 ctx 取消或分发器停止
+func (d *Dispatcher) SetOverflowPolicy(policy OverflowPolicy, timeout time.Duration) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.overflowPolicy = policy
+	d.overflowTimeout = timeout
+	return d
+}
+
+// SetLogger 设置日志记录器
+func (d *Dispatcher) SetLogger(logger log.StructuredLogger) *Dispatcher {
+	d.logger = logger
 	return d
 }
 
@@ -96,20 +219,76 @@ func (d *Dispatcher) ListenWithPriority(eventName string, listener Listener, pri
 	return d.ListenWithOptions(eventName, "", listener, priority, false)
 }
 
+// ListenSequential 注册顺序异步监听器
+// 同一事件的顺序监听器在该事件专用的单消费者队列中按注册顺序依次执行，
+// 不占用分发调用方的协程，适用于"先写审计日志再发送通知"这类有先后依赖的场景
+func (d *Dispatcher) ListenSequential(eventName string, listener Listener) *Dispatcher {
+	return d.addListener(eventName, &ListenerWrapper{
+		Handler:    listener,
+		Async:      true,
+		Sequential: true,
+	})
+}
+
 // ListenWithOptions 注册监听器（完整选项）
 func (d *Dispatcher) ListenWithOptions(eventName, name string, listener Listener, priority int, async bool) *Dispatcher {
+	return d.addListener(eventName, &ListenerWrapper{
+		Name:     name,
+		Handler:  listener,
+		Priority: priority,
+		Async:    async,
+	})
+}
+
+// SetWebhookSender 设置 ListenWebhook 使用的投递器
+func (d *Dispatcher) SetWebhookSender(sender WebhookSender) *Dispatcher {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	d.webhooks = sender
+	if registrar, ok := sender.(WebhookRegistrar); ok {
+		for _, endpoint := range d.endpoints {
+			registrar.RegisterEndpoint(endpoint[0], endpoint[1])
+		}
+	}
+	return d
+}
 
-	if name == "" {
-		name = fmt.Sprintf("listener_%d", time.Now().UnixNano())
+// ListenWebhook 注册转发监听器，将事件以签名 JSON 投递到外部 HTTP 端点
+// 监听器异步执行，失败重试由投递器的队列负责，需先通过 SetWebhookSender 设置投递器
+func (d *Dispatcher) ListenWebhook(eventName, url, secret string) *Dispatcher {
+	d.mu.Lock()
+	d.endpoints = append(d.endpoints, [2]string{url, secret})
+	if registrar, ok := d.webhooks.(WebhookRegistrar); ok {
+		registrar.RegisterEndpoint(url, secret)
 	}
+	d.mu.Unlock()
 
-	wrapper := &ListenerWrapper{
-		Name:     name,
-		Handler:  listener,
-		Priority: priority,
-		Async:    async,
+	return d.ListenWithOptions(eventName, "webhook:"+url, func(ctx context.Context, event Event) error {
+		d.mu.RLock()
+		sender := d.webhooks
+		d.mu.RUnlock()
+
+		if sender == nil {
+			return errors.New("webhook sender not configured")
+		}
+		return sender.Send(ctx, event.EventName(), event, url, secret)
+	}, 0, true)
+}
+
+// addListener 添加监听器
+func (d *Dispatcher) addListener(eventName string, wrapper *ListenerWrapper) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if wrapper.Name == "" {
+		wrapper.Name = fmt.Sprintf("listener_%d", time.Now().UnixNano())
+	}
+
+	if wrapper.Sequential && d.sequential[eventName] == nil {
+		ch := make(chan *eventJob, d.queueSize)
+		d.sequential[eventName] = ch
+		d.wg.Add(1)
+		go d.sequentialWorker(ch)
 	}
 
 	if d.listeners[eventName] == nil {
@@ -124,16 +303,12 @@ func (d *Dispatcher) ListenWithOptions(eventName, name string, listener Listener
 	return d
 }
 
-// sortListeners 排序监听器
+// sortListeners 排序监听器（稳定排序，同优先级保持注册顺序）
 func (d *Dispatcher) sortListeners(eventName string) {
 	listeners := d.listeners[eventName]
-	for i := 0; i < len(listeners)-1; i++ {
-		for j := i + 1; j < len(listeners); j++ {
-			if listeners[i].Priority > listeners[j].Priority {
-				listeners[i], listeners[j] = listeners[j], listeners[i]
-			}
-		}
-	}
+	sort.SliceStable(listeners, func(i, j int) bool {
+		return listeners[i].Priority < listeners[j].Priority
+	})
 }
 
 // Dispatch 分发事件
@@ -143,43 +318,169 @@ func (d *Dispatcher) Dispatch(event Event) error {
 
 // DispatchWithContext 使用自定义 context 分发事件
 func (d *Dispatcher) DispatchWithContext(ctx context.Context, event Event) error {
+	d.recordReplay(event)
+	return d.dispatch(ctx, event)
+}
+
+// DispatchBatch 批量分发事件，见 DispatchBatchWithContext
+func (d *Dispatcher) DispatchBatch(events []Event) error {
+	return d.DispatchBatchWithContext(context.Background(), events)
+}
+
+// DispatchBatchWithContext 批量分发事件，所有事件共用一次加锁获取的监听器快照，
+// 适合导入数据等需要连续分发大量事件的场景；分发期间新注册或移除的监听器对本批事件不生效
+// 事件按顺序分发，同步监听器依次执行，异步监听器的任务全部入队，返回所有事件的合并错误
+func (d *Dispatcher) DispatchBatchWithContext(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	d.recordReplay(events...)
+
 	d.mu.RLock()
-	listeners := d.listeners[event.EventName()]
+	targets := make(map[string]dispatchTarget)
+	for _, event := range events {
+		name := event.EventName()
+		if _, ok := targets[name]; !ok {
+			targets[name] = dispatchTarget{listeners: d.listeners[name], sequential: d.sequential[name]}
+		}
+	}
+	policy, timeout := d.overflowPolicy, d.overflowTimeout
 	d.mu.RUnlock()
 
-	if len(listeners) == 0 {
-		return nil
+	var errs []error
+	for _, event := range events {
+		for _, err := range d.fire(ctx, event, targets[event.EventName()], policy, timeout) {
+			errs = append(errs, fmt.Errorf("%s: %w", event.EventName(), err))
+		}
 	}
 
-	var syncErrors []error
+	if len(errs) > 0 {
+		return fmt.Errorf("listeners failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
 
-	for _, listener := range listeners {
-		if listener.Async {
-			// 异步执行
-			select {
-			case d.queue <- &eventJob{
+// dispatchTarget 事件的监听器快照
+type dispatchTarget struct {
+	listeners  []*ListenerWrapper
+	sequential chan *eventJob
+}
+
+// dispatch 将事件分发给当前监听器
+func (d *Dispatcher) dispatch(ctx context.Context, event Event) error {
+	d.mu.RLock()
+	target := dispatchTarget{
+		listeners:  d.listeners[event.EventName()],
+		sequential: d.sequential[event.EventName()],
+	}
+	policy, timeout := d.overflowPolicy, d.overflowTimeout
+	d.mu.RUnlock()
+
+	if errs := d.fire(ctx, event, target, policy, timeout); len(errs) > 0 {
+		return fmt.Errorf("listeners failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// fire 执行同步监听器并将异步监听器入队，返回同步监听器和入队失败的错误
+func (d *Dispatcher) fire(ctx context.Context, event Event, target dispatchTarget, policy OverflowPolicy, timeout time.Duration) []error {
+	var errs []error
+
+	for _, listener := range target.listeners {
+		if listener.Sequential || listener.Async {
+			// 异步执行，顺序监听器进入事件专用队列
+			queue := d.queue
+			if listener.Sequential {
+				queue = target.sequential
+			}
+			job := &eventJob{
 				event:    event,
 				listener: listener,
 				ctx:      ctx,
-			}:
-			default:
-				// 队列满了，记录警告
-				fmt.Printf("Warning: event queue full, dropping async listener %s for event %s\n",
-					listener.Name, event.EventName())
+			}
+			if err := d.enqueue(ctx, queue, job, policy, timeout); err != nil {
+				errs = append(errs, err)
 			}
 		} else {
 			// 同步执行
 			if err := d.executeListener(ctx, event, listener); err != nil {
-				syncErrors = append(syncErrors, err)
+				errs = append(errs, err)
 			}
 		}
 	}
 
-	if len(syncErrors) > 0 {
-		return fmt.Errorf("listeners failed: %v", syncErrors)
+	return errs
+}
+
+// enqueue 将异步任务放入队列，队列已满时按策略处理
+func (d *Dispatcher) enqueue(ctx context.Context, queue chan *eventJob, job *eventJob, policy OverflowPolicy, timeout time.Duration) error {
+	if d.ctx.Err() != nil {
+		d.dropped.Add(1)
+		d.logger.Warn("event dispatcher stopped, dropped async listener",
+			"listener", job.listener.Name, "event", job.event.EventName())
+		return fmt.Errorf("%w: listener %s", ErrDispatcherStopped, job.listener.Name)
 	}
 
-	return nil
+	select {
+	case queue <- job:
+		return nil
+	default:
+	}
+
+	switch policy {
+	case DropOldest:
+		// 先取出最旧的任务再入队，两步之间的空位可能被其他分发方占用，因此循环重试
+		for {
+			select {
+			case oldest := <-queue:
+				d.drop(oldest, "dropped oldest job")
+			default:
+			}
+
+			select {
+			case queue <- job:
+				return nil
+			default:
+			}
+		}
+
+	case Block:
+		var expired <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+
+		select {
+		case queue <- job:
+			return nil
+		case <-expired:
+			d.drop(job, "timed out waiting for queue")
+			return fmt.Errorf("%w: listener %s", ErrQueueFull, job.listener.Name)
+		case <-ctx.Done():
+			d.drop(job, "dispatch canceled while waiting for queue")
+			return ctx.Err()
+		case <-d.ctx.Done():
+			d.drop(job, "dispatcher stopped while waiting for queue")
+			return fmt.Errorf("%w: listener %s", ErrDispatcherStopped, job.listener.Name)
+		}
+
+	case Reject:
+		d.drop(job, "rejected job")
+		return fmt.Errorf("%w: listener %s", ErrQueueFull, job.listener.Name)
+
+	default:
+		d.drop(job, "dropped async listener")
+		return nil
+	}
+}
+
+// drop 记录因队列已满未执行的任务
+func (d *Dispatcher) drop(job *eventJob, reason string) {
+	d.dropped.Add(1)
+	d.logger.Warn("event queue full, "+reason,
+		"listener", job.listener.Name, "event", job.event.EventName())
 }
 
 // executeListener 执行监听器
@@ -205,6 +506,16 @@ func (d *Dispatcher) executeListener(ctx context.Context, event Event, listener
 
 	d.addLog(log)
 
+	if err != nil {
+		d.mu.RLock()
+		hooks := d.onFailure
+		d.mu.RUnlock()
+
+		for _, hook := range hooks {
+			hook(log)
+		}
+	}
+
 	return err
 }
 
@@ -230,11 +541,27 @@ func (d *Dispatcher) worker(id int) {
 	}
 }
 
-// Stop 停止事件分发器
+// sequentialWorker 顺序队列工作进程
+func (d *Dispatcher) sequentialWorker(ch chan *eventJob) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case job := <-ch:
+			d.executeListener(job.ctx, job.event, job.listener)
+		}
+	}
+}
+
+// Stop 停止事件分发器，等待工作协程退出，重复调用是安全的
+// 停止后同步监听器照常执行，异步监听器不再入队，分发方收到 ErrDispatcherStopped
 func (d *Dispatcher) Stop() {
-	d.cancel()
-	d.wg.Wait()
-	close(d.queue)
+	d.stopOnce.Do(func() {
+		d.cancel()
+		d.wg.Wait()
+	})
 }
 
 // Forget 移除事件监听器
@@ -359,10 +686,52 @@ func (d *Dispatcher) GetStats() map[string]interface{} {
 		"fail_count":       totalExecutions - successCount,
 		"success_rate":     successRate,
 		"queue_size":       len(d.queue),
+		"queue_capacity":   d.queueSize,
+		"dropped_events":   d.dropped.Load(),
 		"workers":          d.workers,
 	}
 }
 
+// GetListenerStats 获取事件各监听器的统计信息（基于日志缓冲区计算）
+func (d *Dispatcher) GetListenerStats(eventName string) map[string]ListenerStat {
+	d.logsMu.RLock()
+	defer d.logsMu.RUnlock()
+
+	stats := make(map[string]ListenerStat)
+	totals := make(map[string]time.Duration)
+
+	for _, log := range d.logs {
+		if log.EventName != eventName {
+			continue
+		}
+
+		stat := stats[log.ListenerName]
+		stat.Executions++
+		if !log.Success {
+			stat.Failures++
+			stat.LastError = log.Error
+		}
+		stats[log.ListenerName] = stat
+		totals[log.ListenerName] += log.Duration
+	}
+
+	for name, stat := range stats {
+		stat.AvgDuration = totals[name] / time.Duration(stat.Executions)
+		stats[name] = stat
+	}
+
+	return stats
+}
+
+// OnListenerFailure 注册监听器失败回调，可用于告警
+func (d *Dispatcher) OnListenerFailure(callback func(EventLog)) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.onFailure = append(d.onFailure, callback)
+	return d
+}
+
 // Subscribe 订阅多个事件到同一个监听器
 func (d *Dispatcher) Subscribe(events []string, listener Listener) *Dispatcher {
 	for _, eventName := range events {
@@ -388,6 +757,70 @@ func (d *Dispatcher) Until(event Event) (interface{}, error) {
 	return nil, nil
 }
 
+// EnableReplay 启用事件重放，保留最近 bufferSize 个原始事件
+// 默认不启用，以避免内存无限增长；bufferSize <= 0 时关闭并清空缓冲区
+func (d *Dispatcher) EnableReplay(bufferSize int) {
+	d.replayMu.Lock()
+	defer d.replayMu.Unlock()
+
+	if bufferSize <= 0 {
+		d.replaySize = 0
+		d.replayBuf = nil
+		return
+	}
+
+	d.replaySize = bufferSize
+	if len(d.replayBuf) > bufferSize {
+		d.replayBuf = d.replayBuf[len(d.replayBuf)-bufferSize:]
+	}
+}
+
+// recordReplay 记录事件到重放缓冲区
+func (d *Dispatcher) recordReplay(events ...Event) {
+	d.replayMu.Lock()
+	defer d.replayMu.Unlock()
+
+	if d.replaySize == 0 {
+		return
+	}
+
+	d.replayBuf = append(d.replayBuf, events...)
+	if len(d.replayBuf) > d.replaySize {
+		d.replayBuf = d.replayBuf[len(d.replayBuf)-d.replaySize:]
+	}
+}
+
+// Replay 将最近 n 个匹配的事件按原始顺序重新分发给当前监听器
+// eventName 为空时匹配所有事件；重放的事件不会再次写入缓冲区
+func (d *Dispatcher) Replay(eventName string, n int) error {
+	d.replayMu.RLock()
+	if d.replaySize == 0 {
+		d.replayMu.RUnlock()
+		return fmt.Errorf("event replay is not enabled")
+	}
+
+	var events []Event
+	for i := len(d.replayBuf) - 1; i >= 0 && len(events) < n; i-- {
+		if eventName == "" || d.replayBuf[i].EventName() == eventName {
+			events = append(events, d.replayBuf[i])
+		}
+	}
+	d.replayMu.RUnlock()
+
+	var errs []error
+	for i := len(events) - 1; i >= 0; i-- {
+		if err := d.dispatch(context.Background(), events[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("replay failed: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
 // GetEventType 获取事件类型名称
 func GetEventType(event Event) string {
 	t := reflect.TypeOf(event)
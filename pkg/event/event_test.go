@@ -2,6 +2,10 @@ package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -56,6 +60,256 @@ func TestAsyncListener(t *testing.T) {
 	}
 }
 
+func TestDispatcherStop(t *testing.T) {
+	dispatcher := NewDispatcher(1)
+
+	synced := 0
+	dispatcher.Listen("test.stop", func(ctx context.Context, event Event) error {
+		synced++
+		return nil
+	})
+	dispatcher.ListenAsync("test.stop.async", func(ctx context.Context, event Event) error {
+		t.Error("Async listener should not run after Stop")
+		return nil
+	})
+
+	dispatcher.Stop()
+	dispatcher.Stop()
+
+	if err := dispatcher.Dispatch(&BaseEvent{Name: "test.stop"}); err != nil || synced != 1 {
+		t.Errorf("Expected sync listener to run after Stop, got %v (%d calls)", err, synced)
+	}
+	if err := dispatcher.Dispatch(&BaseEvent{Name: "test.stop.async"}); !errors.Is(err, ErrDispatcherStopped) {
+		t.Errorf("Expected ErrDispatcherStopped, got %v", err)
+	}
+}
+
+func TestSequentialListener(t *testing.T) {
+	dispatcher := NewDispatcher(4)
+	defer dispatcher.Stop()
+
+	var mu sync.Mutex
+	var order []string
+	done := make(chan bool, 1)
+
+	// 第一个监听器较慢，但仍需先于第二个完成
+	dispatcher.ListenSequential("test.sequential", func(ctx context.Context, event Event) error {
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		order = append(order, "audit")
+		mu.Unlock()
+		return nil
+	})
+
+	dispatcher.ListenSequential("test.sequential", func(ctx context.Context, event Event) error {
+		mu.Lock()
+		order = append(order, "notify")
+		mu.Unlock()
+		done <- true
+		return nil
+	})
+
+	testEvent := &BaseEvent{Name: "test.sequential"}
+	if err := dispatcher.Dispatch(testEvent); err != nil {
+		t.Fatalf("Dispatch error: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatal("Sequential listeners did not execute in time")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 2 || order[0] != "audit" || order[1] != "notify" {
+		t.Errorf("Sequential listeners executed in wrong order: %v", order)
+	}
+}
+
+func TestOverflowPolicy(t *testing.T) {
+	dispatcher, err := NewDispatcherWithConfig(Config{Workers: 1, QueueSize: 10})
+	if err != nil {
+		t.Fatalf("NewDispatcherWithConfig error: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	dispatcher.ListenSequential("test.overflow", func(ctx context.Context, event Event) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})
+	defer close(release)
+
+	testEvent := &BaseEvent{Name: "test.overflow"}
+	dispatcher.Dispatch(testEvent)
+	<-started
+
+	// 消费者阻塞，填满事件专用队列
+	for i := 0; i < 10; i++ {
+		if err := dispatcher.Dispatch(testEvent); err != nil {
+			t.Fatalf("Dispatch error while filling queue: %v", err)
+		}
+	}
+
+	if err := dispatcher.Dispatch(testEvent); err != nil {
+		t.Errorf("Expected default policy to drop silently, got %v", err)
+	}
+
+	dispatcher.SetOverflowPolicy(Reject, 0)
+	if err := dispatcher.Dispatch(testEvent); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull with Reject, got %v", err)
+	}
+
+	dispatcher.SetOverflowPolicy(Block, 20*time.Millisecond)
+	start := time.Now()
+	if err := dispatcher.Dispatch(testEvent); !errors.Is(err, ErrQueueFull) || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Expected ErrQueueFull after blocking, got %v after %v", err, time.Since(start))
+	}
+
+	dispatcher.SetOverflowPolicy(DropOldest, 0)
+	if err := dispatcher.Dispatch(testEvent); err != nil {
+		t.Errorf("Expected DropOldest to enqueue, got %v", err)
+	}
+
+	if dropped := dispatcher.GetStats()["dropped_events"].(int64); dropped != 4 {
+		t.Errorf("Expected 4 dropped events, got %d", dropped)
+	}
+}
+
+func TestDispatcherConfig(t *testing.T) {
+	if _, err := NewDispatcherWithConfig(Config{Workers: 1}); err == nil {
+		t.Error("Expected error for zero queue size")
+	}
+
+	dispatcher, err := NewDispatcherWithConfig(Config{Workers: 1, QueueSize: 5, MaxLogs: 10})
+	if err != nil {
+		t.Fatalf("NewDispatcherWithConfig error: %v", err)
+	}
+	defer dispatcher.Stop()
+
+	dispatcher.Listen("test.config", func(ctx context.Context, event Event) error {
+		return nil
+	})
+	for i := 0; i < 8; i++ {
+		dispatcher.Dispatch(&BaseEvent{Name: "test.config"})
+	}
+
+	stats := dispatcher.GetStats()
+	if stats["queue_capacity"].(int) != 5 || stats["workers"].(int) != 1 {
+		t.Errorf("Unexpected stats %v", stats)
+	}
+
+	dispatcher.SetMaxLogs(3)
+	if logs := dispatcher.GetLogs("test.config", 100); len(logs) != 3 {
+		t.Errorf("Expected logs trimmed to 3, got %d", len(logs))
+	}
+}
+
+type importedEvent struct {
+	BaseEvent
+	ID string
+}
+
+func TestDispatchBatch(t *testing.T) {
+	dispatcher := NewDispatcher(2)
+	defer dispatcher.Stop()
+
+	var mu sync.Mutex
+	var imported []string
+	dispatcher.Listen("record.imported", func(ctx context.Context, event Event) error {
+		id := event.(*importedEvent).ID
+		if id == "bad" {
+			return errors.New("invalid record")
+		}
+		mu.Lock()
+		imported = append(imported, id)
+		mu.Unlock()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	dispatcher.ListenAsync("record.imported", func(ctx context.Context, event Event) error {
+		wg.Done()
+		return nil
+	})
+
+	events := make([]Event, 0, 101)
+	for i := 0; i < 100; i++ {
+		events = append(events, &importedEvent{BaseEvent: BaseEvent{Name: "record.imported"}, ID: fmt.Sprintf("r%d", i)})
+	}
+	events = append(events, &importedEvent{BaseEvent: BaseEvent{Name: "record.imported"}, ID: "bad"}, &BaseEvent{Name: "no.listeners"})
+	wg.Add(101)
+
+	err := dispatcher.DispatchBatch(events)
+	if err == nil || !strings.Contains(err.Error(), "record.imported: invalid record") {
+		t.Errorf("Expected combined sync listener error, got %v", err)
+	}
+
+	mu.Lock()
+	if len(imported) != 100 || imported[0] != "r0" || imported[99] != "r99" {
+		t.Errorf("Expected sync listeners to run in order for every event, got %d", len(imported))
+	}
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Async listeners did not receive every event in time")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dispatcher := NewDispatcher(2)
+	defer dispatcher.Stop()
+
+	if err := dispatcher.Replay("test.replay", 1); err == nil {
+		t.Error("Expected error when replay is not enabled")
+	}
+
+	dispatcher.EnableReplay(2)
+
+	for i := 0; i < 3; i++ {
+		dispatcher.Dispatch(NewUserRegistered(uint(i+1), "user", "user@example.com"))
+	}
+	dispatcher.Dispatch(&BaseEvent{Name: "test.other"})
+
+	// 重放时才注册监听器
+	var replayed []uint
+	dispatcher.Listen("user.registered", func(ctx context.Context, event Event) error {
+		replayed = append(replayed, event.(*UserRegistered).UserID)
+		return nil
+	})
+
+	if err := dispatcher.Replay("user.registered", 5); err != nil {
+		t.Fatalf("Replay error: %v", err)
+	}
+
+	// 缓冲区大小为 2，仅保留最后两个事件（其中一个是 test.other）
+	if len(replayed) != 1 || replayed[0] != 3 {
+		t.Errorf("Unexpected replayed events: %v", replayed)
+	}
+
+	// 监听器错误保留原始错误链
+	errReplay := errors.New("listener failed")
+	dispatcher.Listen("test.other", func(ctx context.Context, event Event) error {
+		return errReplay
+	})
+	if err := dispatcher.Replay("", 5); !errors.Is(err, errReplay) {
+		t.Errorf("Expected replay error to wrap the listener error, got %v", err)
+	}
+}
+
 func TestListenerPriority(t *testing.T) {
 	dispatcher := NewDispatcher(2)
 	defer dispatcher.Stop()
@@ -168,6 +422,41 @@ func TestGetStats(t *testing.T) {
 	}
 }
 
+func TestListenerStats(t *testing.T) {
+	dispatcher := NewDispatcher(2)
+	defer dispatcher.Stop()
+
+	var failures []EventLog
+	dispatcher.OnListenerFailure(func(log EventLog) {
+		failures = append(failures, log)
+	})
+
+	dispatcher.ListenWithOptions("test.stats", "ok", func(ctx context.Context, event Event) error {
+		return nil
+	}, 0, false)
+	dispatcher.ListenWithOptions("test.stats", "broken", func(ctx context.Context, event Event) error {
+		return errors.New("smtp unavailable")
+	}, 0, false)
+
+	testEvent := &BaseEvent{Name: "test.stats"}
+	dispatcher.Dispatch(testEvent)
+	dispatcher.Dispatch(testEvent)
+
+	stats := dispatcher.GetListenerStats("test.stats")
+
+	if stats["ok"].Executions != 2 || stats["ok"].Failures != 0 {
+		t.Errorf("Unexpected stats for 'ok': %+v", stats["ok"])
+	}
+
+	if stats["broken"].Executions != 2 || stats["broken"].Failures != 2 {
+		t.Errorf("Unexpected stats for 'broken': %+v", stats["broken"])
+	}
+
+	if len(failures) != 2 || failures[0].ListenerName != "broken" {
+		t.Errorf("Expected 2 failure callbacks for 'broken', got %+v", failures)
+	}
+}
+
 func TestGlobalDispatcher(t *testing.T) {
 	executed := false
 
@@ -225,3 +514,94 @@ func TestUserRegisteredEvent(t *testing.T) {
 		t.Errorf("Expected username 'testuser', got '%s'", capturedEvent.Username)
 	}
 }
+
+type orderShipped struct {
+	OrderID string
+}
+
+func (e *orderShipped) EventName() string {
+	return "order.shipped"
+}
+
+func TestTypedListener(t *testing.T) {
+	dispatcher := NewDispatcher(2)
+	defer dispatcher.Stop()
+
+	if name := TypedEventName[*orderShipped](); name != "order.shipped" {
+		t.Errorf("Expected event name 'order.shipped', got '%s'", name)
+	}
+
+	var shipped string
+	On(dispatcher, func(ctx context.Context, event *orderShipped) error {
+		shipped = event.OrderID
+		return nil
+	})
+
+	var username string
+	OnNamed(dispatcher, "user.registered", func(ctx context.Context, event *UserRegistered) error {
+		username = event.Username
+		return nil
+	})
+
+	dispatcher.Dispatch(&orderShipped{OrderID: "A100"})
+	dispatcher.Dispatch(NewUserRegistered(1, "testuser", "test@example.com"))
+
+	if shipped != "A100" {
+		t.Errorf("Expected order 'A100', got '%s'", shipped)
+	}
+
+	if username != "testuser" {
+		t.Errorf("Expected username 'testuser', got '%s'", username)
+	}
+
+	// 名称相同但类型不符时返回错误
+	if err := dispatcher.Dispatch(&BaseEvent{Name: "order.shipped"}); err == nil {
+		t.Error("Expected type mismatch error")
+	}
+}
+
+type fakeWebhookSender struct {
+	sent       chan string
+	registered []string
+}
+
+func (s *fakeWebhookSender) RegisterEndpoint(url, secret string) string {
+	s.registered = append(s.registered, url)
+	return url
+}
+
+func (s *fakeWebhookSender) Send(ctx context.Context, eventName string, data interface{}, url, secret string) error {
+	s.sent <- eventName + " " + url
+	return nil
+}
+
+func TestListenWebhook(t *testing.T) {
+	sender := &fakeWebhookSender{sent: make(chan string, 1)}
+	d := NewDispatcher(1).SetWebhookSender(sender)
+	defer d.Stop()
+
+	d.ListenWebhook("webhook.test", "https://example.com/hook", "secret")
+	if len(sender.registered) != 1 || sender.registered[0] != "https://example.com/hook" {
+		t.Errorf("expected endpoint to be registered on listen, got %v", sender.registered)
+	}
+
+	// 后设置的投递器登记已注册的端点
+	late := &fakeWebhookSender{}
+	lateDispatcher := NewDispatcher(1)
+	defer lateDispatcher.Stop()
+	lateDispatcher.ListenWebhook("webhook.test", "https://example.com/late", "secret").SetWebhookSender(late)
+	if len(late.registered) != 1 || late.registered[0] != "https://example.com/late" {
+		t.Errorf("expected endpoints to be registered with a late sender, got %v", late.registered)
+	}
+
+	d.Dispatch(&BaseEvent{Name: "webhook.test"})
+
+	select {
+	case got := <-sender.sent:
+		if got != "webhook.test https://example.com/hook" {
+			t.Errorf("unexpected delivery %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("webhook was not sent")
+	}
+}
@@ -13,7 +13,7 @@ var (
 // GetDispatcher 获取全局事件分发器
 func GetDispatcher() *Dispatcher {
 	once.Do(func() {
-		globalDispatcher = NewDispatcher(10)
+		globalDispatcher = NewDispatcher(DefaultWorkers)
 	})
 	return globalDispatcher
 }
@@ -28,6 +28,11 @@ func ListenAsync(eventName string, listener Listener) {
 	GetDispatcher().ListenAsync(eventName, listener)
 }
 
+// ListenSequential 注册全局顺序异步监听器
+func ListenSequential(eventName string, listener Listener) {
+	GetDispatcher().ListenSequential(eventName, listener)
+}
+
 // ListenWithPriority 注册全局带优先级的监听器
 func ListenWithPriority(eventName string, listener Listener, priority int) {
 	GetDispatcher().ListenWithPriority(eventName, listener, priority)
@@ -0,0 +1,58 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// On 注册类型化监听器
+// 事件名称由 T 的零值 EventName() 推导，为空时回退为 GetEventType 的类型名称；
+// 监听器内部完成类型断言，handler 直接获得具体类型的事件
+func On[T Event](d *Dispatcher, handler func(ctx context.Context, event T) error) *Dispatcher {
+	return OnNamed(d, TypedEventName[T](), handler)
+}
+
+// OnNamed 以指定事件名称注册类型化监听器
+// 适用于名称在构造函数中设置的事件（如基于 BaseEvent 的 UserRegistered）
+func OnNamed[T Event](d *Dispatcher, eventName string, handler func(ctx context.Context, event T) error) *Dispatcher {
+	return d.Listen(eventName, func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			var zero T
+			return fmt.Errorf("event %s: expected %T, got %T", eventName, zero, event)
+		}
+		return handler(ctx, typed)
+	})
+}
+
+// TypedEventName 推导类型 T 对应的事件名称
+func TypedEventName[T Event]() string {
+	var zero T
+	t := reflect.TypeOf(&zero).Elem()
+
+	// 指针类型使用新分配的零值，避免在 nil 接收者上调用 EventName
+	var instance Event = zero
+	if t.Kind() == reflect.Ptr {
+		instance = reflect.New(t.Elem()).Interface().(Event)
+	}
+
+	if name := safeEventName(instance); name != "" {
+		return name
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// safeEventName 调用 EventName，捕获零值可能引发的 panic
+func safeEventName(event Event) (name string) {
+	defer func() {
+		if recover() != nil {
+			name = ""
+		}
+	}()
+	return event.EventName()
+}
@@ -3,17 +3,23 @@ package framework
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/cache"
 	"github.com/clarkgo/clarkgo/pkg/config"
 	"github.com/clarkgo/clarkgo/pkg/database"
+	"github.com/clarkgo/clarkgo/pkg/event"
 	"github.com/clarkgo/clarkgo/pkg/log"
+	"github.com/clarkgo/clarkgo/pkg/queue"
 	"github.com/clarkgo/clarkgo/pkg/redis"
+	"github.com/clarkgo/clarkgo/pkg/webhook"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/hertz/pkg/common/hlog"
@@ -26,13 +32,24 @@ type Application struct {
 	Config     *config.Config
 	DB         *database.Database
 	Redis      *redis.Client
-	Logger     *log.Logger
+	Logger     *log.Manager
+	Container  *Container
+	Lifecycle  *Lifecycle
 	ConfigPath string
 	AppName    string
 	AppVersion string
 	Env        string
 	Debug      bool
+	Quiet      bool // 静默模式：启动日志写入 stderr 并关闭 SQL 日志，stdout 只留给命令自身的输出
 	booted     bool
+	bootErr    error // 启动钩子返回的首个错误，由 Boot 和 Run 返回
+
+	middleware    *middlewareStack
+	bootHooks     []func() error
+	startHooks    []func()
+	shutdownHooks []func() error
+	closeOnce     sync.Once
+	closeErr      error
 }
 
 // NewApplication 创建一个新的应用实例
@@ -43,16 +60,19 @@ func NewApplication() *Application {
 		Env:        "development",
 		Debug:      true,
 		ConfigPath: "config",
+		Container:  NewContainer(),
+		Lifecycle:  NewLifecycle(),
 		booted:     false,
+		middleware: &middlewareStack{},
 	}
 
 	return app
 }
 
-// Boot 启动应用程序，初始化各种组件
-func (app *Application) Boot() *Application {
+// Boot 启动应用程序，初始化各种组件，返回启动钩子的错误；重复调用不会再次初始化，返回首次启动的错误
+func (app *Application) Boot() error {
 	if app.booted {
-		return app
+		return app.bootErr
 	}
 
 	// 加载配置
@@ -73,13 +93,147 @@ func (app *Application) Boot() *Application {
 	// 初始化Redis
 	app.initRedis()
 
+	// 注册核心服务
+	app.registerServices()
+
 	app.booted = true
+
+	// 执行启动钩子，任一钩子返回错误时中止启动
+	for _, hook := range app.bootHooks {
+		if err := hook(); err != nil {
+			app.bootErr = fmt.Errorf("boot hook failed: %w", err)
+			break
+		}
+	}
+	return app.bootErr
+}
+
+// OnBoot 注册启动钩子，在 Boot 完成核心组件初始化后按注册顺序执行
+// 适合注册路由、执行迁移、预热缓存，钩子返回错误时中止启动，错误由 Boot 返回；
+// Boot 之后注册的钩子立即执行，错误由之后的 Boot 或 Run 返回
+func (app *Application) OnBoot(hook func() error) *Application {
+	if !app.booted {
+		app.bootHooks = append(app.bootHooks, hook)
+		return app
+	}
+	if app.bootErr == nil {
+		if err := hook(); err != nil {
+			app.bootErr = fmt.Errorf("boot hook failed: %w", err)
+		}
+	}
+	return app
+}
+
+// OnStart 注册运行钩子，在 Run 启动服务器后按注册顺序执行，适合启动调度器和队列工作进程
+func (app *Application) OnStart(hook func()) *Application {
+	app.startHooks = append(app.startHooks, hook)
+	return app
+}
+
+// OnShutdown 注册关闭钩子，由 Close 在关闭 Lifecycle 组件和数据库连接前按注册的逆序执行
+// 钩子返回错误不影响其余钩子执行
+func (app *Application) OnShutdown(hook func() error) *Application {
+	app.shutdownHooks = append(app.shutdownHooks, hook)
 	return app
 }
 
+// runStartHooks 按注册顺序执行运行钩子
+func (app *Application) runStartHooks() {
+	for _, hook := range app.startHooks {
+		hook()
+	}
+}
+
+// registerServices 将核心组件注册到服务容器
+func (app *Application) registerServices() {
+	app.Container.
+		Instance("app", app).
+		Instance("config", app.Config).
+		Instance("logger", app.Logger).
+		Instance("router", app.Router).
+		Instance("db", app.DB)
+
+	if app.Redis != nil {
+		app.Container.Instance("redis", app.Redis)
+	}
+
+	// 按配置创建的驱动，首次解析时初始化
+	app.Container.
+		Singleton("cache", func(c *Container) (interface{}, error) {
+			driver, err := app.NewCacheDriver()
+			if err != nil {
+				return nil, err
+			}
+			return cache.NewCache(driver), nil
+		}).
+		Singleton("queue", func(c *Container) (interface{}, error) {
+			driver, err := app.NewQueueDriver()
+			if err != nil {
+				return nil, err
+			}
+			return queue.NewQueue(driver), nil
+		})
+
+	// 事件分发器通过全局投递器转发 webhook
+	dispatcher := event.GetDispatcher().SetWebhookSender(webhook.GetDeliverer())
+	app.Container.
+		Instance("events", dispatcher).
+		Instance("webhooks", webhook.GetDeliverer())
+
+	// 事件分发器最先注册、最后关闭，缓存和队列关闭时仍可能触发事件；未解析的单例不需要关闭
+	// 分发器是全局单例，Stop 可重复调用，停止后异步分发返回 event.ErrDispatcherStopped 而不是 panic
+	app.Lifecycle.
+		Register("events", dispatcher).
+		RegisterFunc("cache", app.closeResolved("cache")).
+		RegisterFunc("queue", app.shutdownQueue)
+}
+
+// shutdownQueue 优雅关闭已解析的队列，等待运行中的任务最多 queue.shutdown_timeout，队列尚未创建时不做任何事
+func (app *Application) shutdownQueue() error {
+	instance, ok := app.Container.Resolved("queue")
+	if !ok {
+		return nil
+	}
+	q, ok := instance.(*queue.Queue)
+	if !ok {
+		return app.closeResolved("queue")()
+	}
+
+	timeout, err := app.configDuration("queue.shutdown_timeout", queue.DefaultShutdownTimeout)
+	if err != nil {
+		// 配置错误时仍需关闭队列，使用默认超时并返回配置错误
+		return errors.Join(err, q.Shutdown(queue.DefaultShutdownTimeout))
+	}
+	return q.Shutdown(timeout)
+}
+
+// closeResolved 返回关闭容器中已解析单例的函数，单例尚未创建时不做任何事
+func (app *Application) closeResolved(name string) func() error {
+	return func() error {
+		instance, ok := app.Container.Resolved(name)
+		if !ok {
+			return nil
+		}
+		if fn, ok := closerFunc(instance); ok {
+			return fn()
+		}
+		return nil
+	}
+}
+
+// Make 从服务容器解析服务
+func (app *Application) Make(name string) (interface{}, error) {
+	return app.Container.Make(name)
+}
+
 // loadConfig 加载配置
+// 环境变量 APP_ENV 优先于 SetEnv，决定加载哪些环境覆盖配置文件（如 database.production.json）
 func (app *Application) loadConfig() {
-	app.Config = config.NewConfig([]string{app.ConfigPath})
+	if env := os.Getenv(config.EnvVar); env != "" {
+		app.Env = env
+	}
+
+	app.Config = config.NewConfig([]string{app.ConfigPath}).SetEnv(app.Env)
 	if err := app.Config.Load(); err != nil {
 		hlog.Fatalf("Failed to load config: %v", err)
 	}
@@ -105,8 +259,11 @@ func (app *Application) initLogger() {
 		OutputFile: app.Config.GetString("log.output_file", "logs/app.log"),
 		TimeFormat: app.Config.GetString("log.time_format", "2006-01-02 15:04:05"),
 	}
+	if app.Quiet && logConfig.Output != "file" {
+		logConfig.Output = "stderr"
+	}
 
-	app.Logger = log.NewLogger(logConfig)
+	app.Logger = log.NewManager(logConfig)
 	if err := app.Logger.Init(); err != nil {
 		hlog.Fatalf("Failed to initialize logger: %v", err)
 	}
@@ -131,6 +288,10 @@ func (app *Application) initRouter() {
 		app.initServer()
 	}
 	app.Router = NewRouter(app.Server)
+	app.Lifecycle.Register("route limiters", app.Router)
+
+	// 全局中间件入口需在所有路由之前安装，之后注册的中间件同样作用于已注册的路由
+	app.Server.Use(app.middleware.handle)
 }
 
 // initDatabase 初始化数据库
@@ -139,7 +300,7 @@ func (app *Application) initDatabase() {
 	dbConfig := &database.Config{
 		Driver:   "sqlite",
 		Database: "storage/database/data.db",
-		Debug:    app.Debug,
+		Debug:    app.Debug && !app.Quiet,
 	}
 
 	// 确保数据库目录存在
@@ -153,13 +314,43 @@ func (app *Application) initDatabase() {
 	}
 }
 
-// Run 运行应用程序
-func (app *Application) Run() {
-	if !app.booted {
-		app.Boot()
+// Close 释放应用资源：按注册的逆序执行关闭钩子，再按注册的逆序关闭 Lifecycle 中的组件，最后关闭数据库连接
+// 任一步骤失败不影响后续步骤，所有错误合并返回；Run 收到退出信号后自动调用，重复调用只执行一次
+func (app *Application) Close() error {
+	app.closeOnce.Do(func() {
+		var errs []error
+
+		// 后注册的钩子先执行，与依赖的初始化顺序相反
+		for i := len(app.shutdownHooks) - 1; i >= 0; i-- {
+			if err := app.shutdownHooks[i](); err != nil {
+				errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+			}
+		}
+
+		if err := app.Lifecycle.Close(); err != nil {
+			errs = append(errs, err)
+		}
+
+		if app.DB != nil {
+			if err := app.DB.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("database: %w", err))
+			}
+		}
+
+		app.closeErr = errors.Join(errs...)
+	})
+	return app.closeErr
+}
+
+// Run 运行应用程序，阻塞直到收到退出信号并完成关闭
+// 启动失败、服务器运行失败或关闭失败时返回错误
+func (app *Application) Run() error {
+	if err := app.Boot(); err != nil {
+		return err
 	}
 
 	// 启动服务器
+	serverErr := make(chan error, 1)
 	go func() {
 		host := app.Config.GetString("server.host", "0.0.0.0")
 		port := app.Config.GetInt("server.port", 8888)
@@ -171,33 +362,38 @@ func (app *Application) Run() {
 		}
 
 		hlog.Infof("Server is running on %s", addr)
-		err := app.Server.Run()
-		if err != nil {
-			hlog.Fatal("Server run error: ", err)
+		if err := app.Server.Run(); err != nil {
+			serverErr <- err
 		}
 	}()
 
+	app.runStartHooks()
+
 	// 优雅关闭
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	defer signal.Stop(quit)
+
+	select {
+	case <-quit:
+	case err := <-serverErr:
+		return errors.Join(fmt.Errorf("server run error: %w", err), app.Close())
+	}
 
 	hlog.Info("Shutting down server...")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	var errs []error
 	if err := app.Server.Shutdown(ctx); err != nil {
-		hlog.Fatal("Server forced to shutdown: ", err)
+		errs = append(errs, fmt.Errorf("server forced to shutdown: %w", err))
 	}
-
-	// 关闭数据库连接
-	if app.DB != nil {
-		if err := app.DB.Close(); err != nil {
-			hlog.Errorf("Failed to close database connection: %v", err)
-		}
+	if err := app.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close application: %w", err))
 	}
 
 	hlog.Info("Server exiting")
+	return errors.Join(errs...)
 }
 
 // SetDebug 设置调试模式
@@ -206,6 +402,12 @@ func (app *Application) SetDebug(debug bool) *Application {
 	return app
 }
 
+// SetQuiet 设置静默模式，需在 Boot 之前调用，供 route:list --json 等需要干净 stdout 的命令使用
+func (app *Application) SetQuiet(quiet bool) *Application {
+	app.Quiet = quiet
+	return app
+}
+
 // SetEnv 设置环境
 func (app *Application) SetEnv(env string) *Application {
 	app.Env = env
@@ -243,9 +445,33 @@ func (app *Application) RegisterRoutes(fn func(*Router)) {
 	fn(app.Router)
 }
 
-// RegisterMiddleware 注册全局中间件
+// RegisterMiddleware 注册全局中间件，使用默认优先级，按注册顺序执行
 func (app *Application) RegisterMiddleware(handlers ...app.HandlerFunc) {
-	app.Server.Use(handlers...)
+	for _, handler := range handlers {
+		app.middleware.add("", MiddlewarePriorityDefault, handler)
+	}
+}
+
+// UseMiddleware 注册带名称和优先级的全局中间件，priority 越小越先执行，相同时按注册顺序
+// 例如: app.UseMiddleware("recovery", framework.MiddlewarePriorityRecovery, framework.Recovery())
+func (app *Application) UseMiddleware(name string, priority int, handler app.HandlerFunc) *Application {
+	app.middleware.add(name, priority, handler)
+	return app
+}
+
+// UseMiddlewareBefore 在名为 target 的中间件之前插入全局中间件
+func (app *Application) UseMiddlewareBefore(target, name string, handler app.HandlerFunc) error {
+	return app.middleware.insert(target, name, handler, false)
+}
+
+// UseMiddlewareAfter 在名为 target 的中间件之后插入全局中间件
+func (app *Application) UseMiddlewareAfter(target, name string, handler app.HandlerFunc) error {
+	return app.middleware.insert(target, name, handler, true)
+}
+
+// MiddlewareNames 返回按执行顺序排列的全局中间件名称，未命名的中间件为空字符串
+func (app *Application) MiddlewareNames() []string {
+	return app.middleware.names()
 }
 
 // Static 注册静态文件目录
@@ -0,0 +1,175 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/queue"
+	"github.com/cloudwego/hertz/pkg/app/server"
+)
+
+// newTestApp 创建未启动的应用，只初始化服务器、路由和全局中间件入口，
+// 配合 ut.PerformRequest(app.Server.Engine, ...) 在测试中发起请求
+func newTestApp() *Application {
+	app := NewApplication()
+	app.Server = server.New()
+	app.initRouter()
+	return app
+}
+
+func TestApplicationHooks(t *testing.T) {
+	// Boot 在工作目录下创建 SQLite 数据库，切换到临时目录避免污染源码树
+	t.Chdir(t.TempDir())
+
+	var order []string
+	record := func(name string) func() error {
+		return func() error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	app := NewApplication()
+	app.ConfigPath = "config"
+	app.OnBoot(func() error {
+		// 启动钩子执行时核心组件已初始化
+		if app.Router == nil || app.DB == nil {
+			t.Error("Expected core components to be ready before boot hooks")
+		}
+		return record("boot1")()
+	}).
+		OnBoot(record("boot2")).
+		OnStart(func() { order = append(order, "start1") }).
+		OnStart(func() { order = append(order, "start2") }).
+		OnShutdown(record("shutdown1")).
+		OnShutdown(func() error {
+			order = append(order, "shutdown2")
+			return errors.New("flush failed")
+		})
+	app.Lifecycle.RegisterFunc("queue", record("lifecycle"))
+
+	if len(order) != 0 {
+		t.Fatalf("Expected hooks to wait for Boot, got %v", order)
+	}
+	if err := app.Boot(); err != nil {
+		t.Fatalf("Boot error: %v", err)
+	}
+
+	// Boot 之后注册的启动钩子立即执行
+	app.OnBoot(record("boot3"))
+	app.runStartHooks()
+
+	err := app.Close()
+	if err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("Expected shutdown hook error to be returned, got %v", err)
+	}
+
+	want := "boot1,boot2,boot3,start1,start2,shutdown2,shutdown1,lifecycle"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("Unexpected hook order:\n got: %s\nwant: %s", got, want)
+	}
+
+	// 重复关闭不会再次执行钩子
+	if app.Close(); len(order) != 8 {
+		t.Errorf("Expected second Close to be a no-op, got %v", order)
+	}
+}
+
+func TestApplicationBootHookError(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var ran []string
+	app := NewApplication().SetConfigPath("config").SetQuiet(true).
+		OnBoot(func() error { return errors.New("migration failed") }).
+		OnBoot(func() error { ran = append(ran, "after"); return nil })
+	defer app.Close()
+
+	// 钩子失败时 Boot 返回错误，后续钩子不再执行
+	err := app.Boot()
+	if err == nil || !strings.Contains(err.Error(), "migration failed") {
+		t.Fatalf("Expected boot hook error, got %v", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("Expected later hooks to be skipped, got %v", ran)
+	}
+
+	// 重复 Boot 和 Run 返回同一错误，不会启动服务器
+	if again := app.Boot(); again != err {
+		t.Errorf("Expected repeated Boot to return the first error, got %v", again)
+	}
+	if runErr := app.Run(); runErr != err {
+		t.Errorf("Expected Run to return the boot error, got %v", runErr)
+	}
+
+	// Boot 之后注册的钩子失败时错误由 Run 返回
+	booted := NewApplication().SetConfigPath("config").SetQuiet(true)
+	defer booted.Close()
+	if err := booted.Boot(); err != nil {
+		t.Fatalf("Boot error: %v", err)
+	}
+	booted.OnBoot(func() error { return errors.New("warmup failed") })
+	if err := booted.Run(); err == nil || !strings.Contains(err.Error(), "warmup failed") {
+		t.Errorf("Expected Run to return the late boot hook error, got %v", err)
+	}
+}
+
+// slowJob 执行时阻塞的任务，用于测试队列优雅关闭
+type slowJob struct {
+	queue.BaseJob
+}
+
+func (j *slowJob) Handle() error { return nil }
+
+func TestApplicationShutdownQueue(t *testing.T) {
+	app := newFactoryApp(t, map[string]interface{}{"queue.shutdown_timeout": "50ms"})
+
+	// 队列尚未创建时不做任何事
+	if err := app.shutdownQueue(); err != nil {
+		t.Fatalf("Expected no error without a resolved queue, got %v", err)
+	}
+
+	q := queue.NewQueue(queue.NewMemoryDriver())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	q.Register(fmt.Sprintf("%T", &slowJob{}), func(payload []byte) error {
+		close(started)
+		<-release
+		return nil
+	})
+	q.Push(&slowJob{BaseJob: queue.BaseJob{ID: "slow", Queue: "default"}})
+	go q.Work()
+	<-started
+	app.Container.Instance("queue", q)
+
+	// 按配置的时间等待运行中的任务，超时后强制关闭并返回错误
+	start := time.Now()
+	err := app.shutdownQueue()
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("Expected shutdown to time out after the configured grace period, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected shutdown to wait for the grace period, took %v", elapsed)
+	}
+}
+
+func TestApplicationQuiet(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := NewApplication().SetConfigPath("config").SetDebug(true).SetQuiet(true)
+	if err := app.Boot(); err != nil {
+		t.Fatalf("Boot error: %v", err)
+	}
+	defer app.Close()
+
+	// 静默模式下日志写入 stderr，调试模式也不输出 SQL 日志
+	if app.Logger.Config.Output != "stderr" {
+		t.Errorf("Expected quiet boot to log to stderr, got %s", app.Logger.Config.Output)
+	}
+	if app.DB.Config.Debug {
+		t.Error("Expected quiet boot to disable SQL logging")
+	}
+}
@@ -0,0 +1,153 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrServiceNotFound 服务未注册
+var ErrServiceNotFound = errors.New("service not found")
+
+// Factory 服务工厂函数，可通过 c 解析依赖的其他服务
+type Factory func(c *Container) (interface{}, error)
+
+// binding 服务绑定
+type binding struct {
+	factory  Factory
+	shared   bool
+	instance interface{}
+	resolved bool
+	mu       sync.Mutex
+}
+
+// Container 服务容器
+type Container struct {
+	bindings map[string]*binding
+	mu       sync.RWMutex
+}
+
+// NewContainer 创建服务容器
+func NewContainer() *Container {
+	return &Container{
+		bindings: make(map[string]*binding),
+	}
+}
+
+// Bind 注册服务，每次 Make 都会调用 factory 创建新实例
+func (c *Container) Bind(name string, factory Factory) *Container {
+	return c.bind(name, &binding{factory: factory})
+}
+
+// Singleton 注册单例服务，首次 Make 时创建并缓存实例
+func (c *Container) Singleton(name string, factory Factory) *Container {
+	return c.bind(name, &binding{factory: factory, shared: true})
+}
+
+// Instance 注册已创建的实例
+func (c *Container) Instance(name string, instance interface{}) *Container {
+	return c.bind(name, &binding{shared: true, instance: instance, resolved: true})
+}
+
+// bind 保存绑定，同名服务会被覆盖
+func (c *Container) bind(name string, b *binding) *Container {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bindings[name] = b
+	return c
+}
+
+// Has 检查服务是否已注册
+func (c *Container) Has(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, exists := c.bindings[name]
+	return exists
+}
+
+// Names 获取所有已注册的服务名称（已排序）
+func (c *Container) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.bindings))
+	for name := range c.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolved 返回已创建的共享实例，未注册、非单例或尚未解析时返回 false，不会触发创建
+func (c *Container) Resolved(name string) (interface{}, bool) {
+	c.mu.RLock()
+	b, exists := c.bindings[name]
+	c.mu.RUnlock()
+
+	if !exists || !b.shared {
+		return nil, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.instance, b.resolved
+}
+
+// Make 解析服务
+func (c *Container) Make(name string) (interface{}, error) {
+	c.mu.RLock()
+	b, exists := c.bindings[name]
+	c.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	if !b.shared {
+		return b.factory(c)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.resolved {
+		return b.instance, nil
+	}
+
+	instance, err := b.factory(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %s: %w", name, err)
+	}
+
+	b.instance = instance
+	b.resolved = true
+	return instance, nil
+}
+
+// MustMake 解析服务，失败时 panic
+func (c *Container) MustMake(name string) interface{} {
+	instance, err := c.Make(name)
+	if err != nil {
+		panic(err)
+	}
+	return instance
+}
+
+// Resolve 解析服务并断言为类型 T
+func Resolve[T any](c *Container, name string) (T, error) {
+	var zero T
+
+	instance, err := c.Make(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("service %s is %T, not %T", name, instance, zero)
+	}
+	return typed, nil
+}
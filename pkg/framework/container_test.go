@@ -0,0 +1,66 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type counterService struct {
+	id int
+}
+
+func TestContainerBindAndSingleton(t *testing.T) {
+	c := NewContainer()
+
+	created := 0
+	factory := func(c *Container) (interface{}, error) {
+		created++
+		return &counterService{id: created}, nil
+	}
+
+	c.Bind("transient", factory).Singleton("shared", factory)
+
+	first, err := Resolve[*counterService](c, "transient")
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	second, _ := Resolve[*counterService](c, "transient")
+	if first.id == second.id {
+		t.Error("Expected Bind to create a new instance on each resolve")
+	}
+
+	if c.MustMake("shared") != c.MustMake("shared") {
+		t.Error("Expected Singleton to return the same instance")
+	}
+	if created != 3 {
+		t.Errorf("Expected 3 instances to be created, got %d", created)
+	}
+}
+
+func TestContainerDependenciesAndErrors(t *testing.T) {
+	c := NewContainer()
+	c.Instance("dsn", "sqlite://memory")
+	c.Singleton("db", func(c *Container) (interface{}, error) {
+		dsn, err := Resolve[string](c, "dsn")
+		return "db(" + dsn + ")", err
+	})
+
+	if db, err := Resolve[string](c, "db"); err != nil || db != "db(sqlite://memory)" {
+		t.Errorf("Expected dependency to be resolved, got %q, %v", db, err)
+	}
+
+	if _, err := c.Make("missing"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Expected ErrServiceNotFound, got %v", err)
+	}
+	if _, err := Resolve[int](c, "dsn"); err == nil {
+		t.Error("Expected type mismatch error")
+	}
+
+	if !c.Has("db") {
+		t.Error("Expected db to be registered")
+	}
+	if names := strings.Join(c.Names(), ","); names != "db,dsn" {
+		t.Errorf("Expected sorted service names, got %s", names)
+	}
+}
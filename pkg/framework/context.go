@@ -2,8 +2,12 @@ package framework
 
 import (
 	"context"
+	"errors"
 
+	"github.com/clarkgo/clarkgo/pkg/response"
+	"github.com/clarkgo/clarkgo/pkg/validator"
 	"github.com/cloudwego/hertz/pkg/app"
+	playground "github.com/go-playground/validator/v10"
 )
 
 // RequestContext 请求上下文
@@ -23,6 +27,11 @@ func (c *RequestContext) JSON(code int, obj interface{}) {
 	c.RequestContext.JSON(code, obj)
 }
 
+// XML 返回XML响应
+func (c *RequestContext) XML(code int, obj interface{}) {
+	c.RequestContext.XML(code, obj)
+}
+
 // String 返回字符串响应
 func (c *RequestContext) String(code int, format string, values ...interface{}) {
 	c.RequestContext.String(code, format, values...)
@@ -88,6 +97,33 @@ func (c *RequestContext) BindForm(obj interface{}) error {
 	return c.RequestContext.BindForm(obj)
 }
 
+// BindAndValidate 按 Content-Type 绑定请求数据，并使用 validate 标签验证
+// 验证失败时返回 *validator.ValidationError，可直接传给 ValidationFailed
+func (c *RequestContext) BindAndValidate(obj interface{}) error {
+	if err := c.RequestContext.Bind(obj); err != nil {
+		return err
+	}
+	return validator.Validate(obj)
+}
+
+// ValidationFailed 根据错误类型返回错误响应：
+// 验证错误返回 422 及字段到错误消息列表的映射 {"errors": {"title": ["title is required"]}}，其余错误返回 400
+func (c *RequestContext) ValidationFailed(err error) {
+	var valErr *validator.ValidationError
+	if errors.As(err, &valErr) {
+		response.ValidationError(c.RequestContext, valErr.Errors)
+		return
+	}
+
+	var fieldErrs playground.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		response.ValidationError(c.RequestContext, validator.NewValidationError(fieldErrs).Errors)
+		return
+	}
+
+	response.BadRequest(c.RequestContext, err.Error())
+}
+
 // ClientIP 获取客户端IP
 func (c *RequestContext) ClientIP() string {
 	return c.RequestContext.ClientIP()
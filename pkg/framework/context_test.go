@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+type createTodoRequest struct {
+	Title    string `json:"title" validate:"required,min=3"`
+	Priority int    `json:"priority" validate:"gte=1,lte=5"`
+}
+
+func TestBindAndValidate(t *testing.T) {
+	testApp := newTestApp()
+	testApp.Router.POST("/todos", func(ctx context.Context, c *RequestContext) {
+		var req createTodoRequest
+		if err := c.BindAndValidate(&req); err != nil {
+			c.ValidationFailed(err)
+			return
+		}
+		c.JSON(201, req)
+	})
+
+	post := func(body string) *ut.ResponseRecorder {
+		return ut.PerformRequest(testApp.Server.Engine, "POST", "/todos",
+			&ut.Body{Body: bytes.NewBufferString(body), Len: len(body)},
+			ut.Header{Key: "Content-Type", Value: "application/json"})
+	}
+
+	if w := post(`{"title":"write tests","priority":2}`); w.Code != 201 {
+		t.Errorf("Expected valid request to pass, got %d %s", w.Code, w.Body.String())
+	}
+
+	w := post(`{"title":"ab","priority":9}`)
+	if w.Code != 422 {
+		t.Fatalf("Expected 422 for invalid input, got %d %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Success bool                `json:"success"`
+		Error   string              `json:"error"`
+		Errors  map[string][]string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid response body: %v", err)
+	}
+	if body.Success || body.Error != "Validation Failed" {
+		t.Errorf("Unexpected error envelope %s", w.Body.String())
+	}
+	if len(body.Errors["title"]) != 1 || len(body.Errors["priority"]) != 1 || len(body.Errors) != 2 {
+		t.Errorf("Expected one message per invalid field, got %v", body.Errors)
+	}
+
+	// 无法解析的请求体返回 400
+	if w := post(`{"title":`); w.Code != 400 {
+		t.Errorf("Expected 400 for malformed JSON, got %d %s", w.Code, w.Body.String())
+	}
+}
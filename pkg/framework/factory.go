@@ -0,0 +1,165 @@
+package framework
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/cache"
+	"github.com/clarkgo/clarkgo/pkg/queue"
+	"github.com/clarkgo/clarkgo/pkg/ratelimit"
+	"github.com/clarkgo/clarkgo/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// 驱动名称，对应各配置段的 driver 键
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+)
+
+// 限流算法，对应 ratelimit.<name>.algorithm
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+	AlgorithmFixedWindow   = "fixed_window"
+)
+
+// NewQueueDriver 根据 queue 配置段创建队列驱动
+//
+//	queue.driver              memory（默认）或 redis
+//	queue.prefix              Redis 键前缀，默认 queue
+//	queue.visibility_timeout  Redis 任务可见性超时，如 "10m"
+//	queue.shutdown_timeout    应用关闭时等待运行中任务完成的时间，默认 30s
+func (app *Application) NewQueueDriver() (queue.Driver, error) {
+	switch driver := app.Config.GetString("queue.driver", DriverMemory); driver {
+	case DriverMemory:
+		return queue.NewMemoryDriver(), nil
+	case DriverRedis:
+		client, err := app.redisClient()
+		if err != nil {
+			return nil, err
+		}
+
+		redisDriver := queue.NewRedisDriver(client, app.Config.GetString("queue.prefix", "queue"))
+		timeout, err := app.configDuration("queue.visibility_timeout", queue.DefaultVisibilityTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return redisDriver.SetVisibilityTimeout(timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", driver)
+	}
+}
+
+// NewCacheDriver 根据 cache 配置段创建缓存驱动
+//
+//	cache.driver       memory（默认）或 redis
+//	cache.max_entries  内存驱动（含 redis 前的一级缓存）最大条目数，大于 0 时按 LRU 淘汰
+//	cache.prefix       Redis 键前缀，默认 cache
+//	cache.l1_ttl       配置后在 Redis 前增加内存一级缓存，值为一级缓存过期时间，如 "30s"
+func (app *Application) NewCacheDriver() (cache.Driver, error) {
+	switch driver := app.Config.GetString("cache.driver", DriverMemory); driver {
+	case DriverMemory:
+		return app.newMemoryCacheDriver(), nil
+	case DriverRedis:
+		client, err := app.redisClient()
+		if err != nil {
+			return nil, err
+		}
+
+		redisDriver := cache.NewRedisDriver(client, app.Config.GetString("cache.prefix", "cache"))
+		l1TTL, err := app.configDuration("cache.l1_ttl", 0)
+		if err != nil {
+			return nil, err
+		}
+		if l1TTL <= 0 {
+			return redisDriver, nil
+		}
+		return cache.NewTieredDriver(app.newMemoryCacheDriver(), redisDriver).SetL1TTL(l1TTL), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache driver: %s", driver)
+	}
+}
+
+// newMemoryCacheDriver 按 cache.max_entries 创建内存缓存驱动
+func (app *Application) newMemoryCacheDriver() *cache.MemoryDriver {
+	if maxEntries := app.Config.GetInt("cache.max_entries", 0); maxEntries > 0 {
+		return cache.NewMemoryDriverWithLimit(maxEntries)
+	}
+	return cache.NewMemoryDriver()
+}
+
+// NewLimiter 根据 ratelimit.<name> 配置段创建限流器
+// 只支持进程内（memory）限流器，多实例部署时每个实例独立计数；driver 配置为其他值时返回错误
+//
+//	ratelimit.<name>.driver     memory（默认，也是唯一支持的驱动）
+//	ratelimit.<name>.algorithm  token_bucket（默认）、sliding_window 或 fixed_window
+//	ratelimit.<name>.rate       令牌桶每秒生成的令牌数
+//	ratelimit.<name>.capacity   令牌桶容量，默认等于 rate
+//	ratelimit.<name>.limit      窗口内允许的请求数
+//	ratelimit.<name>.window     窗口大小，如 "1m"
+func (app *Application) NewLimiter(name string) (ratelimit.Limiter, error) {
+	section := "ratelimit." + name
+
+	switch driver := app.Config.GetString(section+".driver", DriverMemory); driver {
+	case DriverMemory:
+	case DriverRedis:
+		return nil, fmt.Errorf("ratelimit driver %s is not supported: limiters are in-process only", driver)
+	default:
+		return nil, fmt.Errorf("unsupported ratelimit driver: %s", driver)
+	}
+
+	switch algorithm := app.Config.GetString(section+".algorithm", AlgorithmTokenBucket); algorithm {
+	case AlgorithmTokenBucket:
+		rate := app.Config.GetInt(section+".rate", 0)
+		if rate <= 0 {
+			return nil, fmt.Errorf("%s.rate must be positive", section)
+		}
+		return ratelimit.NewTokenBucket(rate, app.Config.GetInt(section+".capacity", rate)), nil
+	case AlgorithmSlidingWindow, AlgorithmFixedWindow:
+		limit := app.Config.GetInt(section+".limit", 0)
+		if limit <= 0 {
+			return nil, fmt.Errorf("%s.limit must be positive", section)
+		}
+		window, err := app.configDuration(section+".window", time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		if algorithm == AlgorithmSlidingWindow {
+			return ratelimit.NewSlidingWindow(limit, window), nil
+		}
+		return ratelimit.NewFixedWindow(limit, window), nil
+	default:
+		return nil, fmt.Errorf("unsupported ratelimit algorithm: %s", algorithm)
+	}
+}
+
+// redisClient 获取应用的 Redis 客户端，未在启动时初始化则按 redis 配置段创建
+func (app *Application) redisClient() (*goredis.Client, error) {
+	if app.Redis == nil || app.Redis.Raw() == nil {
+		app.Redis = redis.NewClient(&redis.Config{
+			Host:     app.Config.GetString("redis.host", "localhost"),
+			Port:     app.Config.GetString("redis.port", "6379"),
+			Password: app.Config.GetString("redis.password", ""),
+			DB:       app.Config.GetInt("redis.db", 0),
+		})
+		if err := app.Redis.Connect(); err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+	}
+	return app.Redis.Raw(), nil
+}
+
+// configDuration 读取时长配置，未配置时返回默认值
+func (app *Application) configDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := app.Config.GetString(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration for %s: %w", key, err)
+	}
+	return duration, nil
+}
@@ -0,0 +1,154 @@
+package framework
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/clarkgo/clarkgo/pkg/config"
+)
+
+// newFactoryApp 创建只包含指定配置的应用，redis 配置指向内存 Redis 服务
+func newFactoryApp(t *testing.T, values map[string]interface{}) *Application {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	app := NewApplication()
+	app.Config = config.NewConfig(nil)
+	app.Config.Set("redis.host", server.Host())
+	app.Config.Set("redis.port", server.Port())
+	for key, value := range values {
+		app.Config.Set(key, value)
+	}
+	t.Cleanup(func() {
+		if app.Redis != nil {
+			app.Redis.Close()
+		}
+	})
+	return app
+}
+
+// closeCreated 关闭工厂创建的驱动或限流器，停止其后台 goroutine
+func closeCreated(v interface{}) {
+	switch c := v.(type) {
+	case interface{ Close() error }:
+		c.Close()
+	case interface{ Close() }:
+		c.Close()
+	}
+}
+
+func TestNewQueueDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		wantType string
+		wantErr  bool
+	}{
+		{name: "default", wantType: "*queue.MemoryDriver"},
+		{name: "memory", config: map[string]interface{}{"queue.driver": "memory"}, wantType: "*queue.MemoryDriver"},
+		{name: "redis", config: map[string]interface{}{"queue.driver": "redis", "queue.visibility_timeout": "5m"}, wantType: "*queue.RedisDriver"},
+		{name: "redis invalid visibility timeout", config: map[string]interface{}{"queue.driver": "redis", "queue.visibility_timeout": "soon"}, wantErr: true},
+		{name: "unknown driver", config: map[string]interface{}{"queue.driver": "kafka"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, err := newFactoryApp(t, tt.config).NewQueueDriver()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %T", driver)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewQueueDriver error: %v", err)
+			}
+			defer closeCreated(driver)
+			if got := fmt.Sprintf("%T", driver); got != tt.wantType {
+				t.Errorf("NewQueueDriver() = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNewCacheDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		wantType string
+		wantErr  bool
+	}{
+		{name: "default", wantType: "*cache.MemoryDriver"},
+		{name: "memory with limit", config: map[string]interface{}{"cache.driver": "memory", "cache.max_entries": 10}, wantType: "*cache.MemoryDriver"},
+		{name: "redis", config: map[string]interface{}{"cache.driver": "redis"}, wantType: "*cache.RedisDriver"},
+		{name: "redis with l1", config: map[string]interface{}{"cache.driver": "redis", "cache.l1_ttl": "30s"}, wantType: "*cache.TieredDriver"},
+		{name: "redis invalid l1 ttl", config: map[string]interface{}{"cache.driver": "redis", "cache.l1_ttl": "soon"}, wantErr: true},
+		{name: "unknown driver", config: map[string]interface{}{"cache.driver": "memcached"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, err := newFactoryApp(t, tt.config).NewCacheDriver()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %T", driver)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCacheDriver error: %v", err)
+			}
+			defer closeCreated(driver)
+			if got := fmt.Sprintf("%T", driver); got != tt.wantType {
+				t.Errorf("NewCacheDriver() = %s, want %s", got, tt.wantType)
+			}
+
+			// 创建的驱动可以读写
+			if err := driver.Set("key", "value", 0); err != nil {
+				t.Fatalf("Set error: %v", err)
+			}
+			if value, err := driver.Get("key"); err != nil || value != "value" {
+				t.Errorf("Get() = %v, %v", value, err)
+			}
+		})
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		wantType string
+		wantErr  bool
+	}{
+		{name: "token bucket", config: map[string]interface{}{"ratelimit.api.rate": 10}, wantType: "*ratelimit.TokenBucket"},
+		{name: "token bucket without rate", config: map[string]interface{}{"ratelimit.api.capacity": 10}, wantErr: true},
+		{name: "sliding window", config: map[string]interface{}{"ratelimit.api.algorithm": "sliding_window", "ratelimit.api.limit": 5, "ratelimit.api.window": "1s"}, wantType: "*ratelimit.SlidingWindow"},
+		{name: "fixed window", config: map[string]interface{}{"ratelimit.api.algorithm": "fixed_window", "ratelimit.api.limit": 5}, wantType: "*ratelimit.FixedWindow"},
+		{name: "window without limit", config: map[string]interface{}{"ratelimit.api.algorithm": "fixed_window"}, wantErr: true},
+		{name: "invalid window", config: map[string]interface{}{"ratelimit.api.algorithm": "sliding_window", "ratelimit.api.limit": 5, "ratelimit.api.window": "soon"}, wantErr: true},
+		{name: "unknown algorithm", config: map[string]interface{}{"ratelimit.api.algorithm": "leaky_bucket", "ratelimit.api.rate": 10}, wantErr: true},
+		{name: "redis driver", config: map[string]interface{}{"ratelimit.api.driver": "redis", "ratelimit.api.rate": 10}, wantErr: true},
+		{name: "unknown driver", config: map[string]interface{}{"ratelimit.api.driver": "etcd", "ratelimit.api.rate": 10}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := newFactoryApp(t, tt.config).NewLimiter("api")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %T", limiter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLimiter error: %v", err)
+			}
+			defer closeCreated(limiter)
+			if got := fmt.Sprintf("%T", limiter); got != tt.wantType {
+				t.Errorf("NewLimiter() = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+}
@@ -10,10 +10,14 @@ import (
 )
 
 // HealthEndpoint 健康检查端点中间件
+// 支持 ?tag=critical 只执行带有指定标签的检查
 func HealthEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
-		results := checker.Check(ctx)
-		status := checker.GetStatus(ctx)
+		results, ok := runHealthChecks(ctx, c, checker)
+		if !ok {
+			return
+		}
+		status := health.OverallStatus(results)
 
 		// Set HTTP status based on health status
 		httpStatus := consts.StatusOK
@@ -30,6 +34,24 @@ func HealthEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 	}
 }
 
+// runHealthChecks 执行健康检查，请求带 tag 参数时只执行该标签的检查
+// 没有检查带该标签时返回 404，避免拼错的标签被当作健康
+func runHealthChecks(ctx context.Context, c *app.RequestContext, checker *health.HealthChecker) (map[string]health.CheckResult, bool) {
+	tag := c.Query("tag")
+	if tag == "" {
+		return checker.Check(ctx), true
+	}
+
+	results := checker.CheckByTag(ctx, tag)
+	if len(results) == 0 {
+		c.JSON(consts.StatusNotFound, map[string]interface{}{
+			"error": "no health checks tagged " + tag,
+		})
+		return nil, false
+	}
+	return results, true
+}
+
 // HealthSummaryEndpoint 健康检查摘要端点
 func HealthSummaryEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
@@ -46,6 +68,13 @@ func HealthSummaryEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 	}
 }
 
+// HealthHistoryEndpoint 健康状态历史端点，返回最近的整体状态和可用率（需先调用 EnableHistory）
+func HealthHistoryEndpoint(checker *health.HealthChecker) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		c.JSON(consts.StatusOK, checker.GetHistory())
+	}
+}
+
 // ReadinessEndpoint 就绪检查端点 (用于 Kubernetes readiness probe)
 func ReadinessEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
@@ -112,8 +141,11 @@ func DetailedHealthEndpoint(checker *health.HealthChecker) app.HandlerFunc {
 			c.JSON(httpStatus, result)
 		} else {
 			// Return all checks with full details
-			results := checker.Check(ctx)
-			status := checker.GetStatus(ctx)
+			results, ok := runHealthChecks(ctx, c, checker)
+			if !ok {
+				return
+			}
+			status := health.OverallStatus(results)
 
 			httpStatus := consts.StatusOK
 			if status == health.StatusUnhealthy {
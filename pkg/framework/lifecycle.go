@@ -0,0 +1,100 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Lifecycle 后台组件关闭注册表
+// 限流器、内存缓存、事件分发器、调度器、队列等组件会启动后台 goroutine，
+// 注册后由 Close（通常经 Application.Close）按注册的逆序统一关闭，避免退出时泄漏
+type Lifecycle struct {
+	mu      sync.Mutex
+	closers []namedCloser
+}
+
+// namedCloser 注册的关闭函数
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+// NewLifecycle 创建关闭注册表
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// Register 注册需要关闭的组件，组件需实现 Close() error、Close()、Stop() error 或 Stop() 之一，
+// 同时实现 Close 和 Stop 时使用 Close；不支持的类型 panic
+// 例如: app.Lifecycle.Register("limiter", limiter).Register("scheduler", scheduler)
+func (l *Lifecycle) Register(name string, component interface{}) *Lifecycle {
+	fn, ok := closerFunc(component)
+	if !ok {
+		panic(fmt.Sprintf("framework: %s (%T) has no Close or Stop method", name, component))
+	}
+	return l.RegisterFunc(name, fn)
+}
+
+// RegisterFunc 注册关闭函数，用于需要参数的关闭方法，如 queue.Queue.Shutdown(timeout)
+func (l *Lifecycle) RegisterFunc(name string, fn func() error) *Lifecycle {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closers = append(l.closers, namedCloser{name: name, close: fn})
+	return l
+}
+
+// Names 按注册顺序返回已注册的组件名称
+func (l *Lifecycle) Names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, len(l.closers))
+	for i, c := range l.closers {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Close 按注册的逆序关闭所有组件并清空注册表，后注册的组件通常依赖先注册的组件
+// 某个组件关闭失败或 panic 不影响其余组件，所有错误合并返回；重复调用不会再次关闭
+func (l *Lifecycle) Close() error {
+	l.mu.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].run(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", closers[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closerFunc 将组件的 Close 或 Stop 方法统一为 func() error
+func closerFunc(component interface{}) (func() error, bool) {
+	switch c := component.(type) {
+	case interface{ Close() error }:
+		return c.Close, true
+	case interface{ Close() }:
+		return func() error { c.Close(); return nil }, true
+	case interface{ Stop() error }:
+		return c.Stop, true
+	case interface{ Stop() }:
+		return func() error { c.Stop(); return nil }, true
+	}
+	return nil, false
+}
+
+// run 执行关闭函数，将 panic 转换为错误
+func (c namedCloser) run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during close: %v", r)
+		}
+	}()
+	return c.close()
+}
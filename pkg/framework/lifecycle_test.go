@@ -0,0 +1,83 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stopper 只实现 Stop() 的测试组件
+type stopper struct {
+	stopped *[]string
+	name    string
+}
+
+func (s stopper) Stop() { *s.stopped = append(*s.stopped, s.name) }
+
+// errCloser 实现 Close() error 的测试组件
+type errCloser struct {
+	closed *[]string
+	name   string
+	err    error
+}
+
+func (c errCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+func TestLifecycleCloseOrder(t *testing.T) {
+	var order []string
+	lc := NewLifecycle().
+		Register("events", stopper{stopped: &order, name: "events"}).
+		Register("cache", errCloser{closed: &order, name: "cache"}).
+		RegisterFunc("queue", func() error {
+			order = append(order, "queue")
+			return nil
+		})
+
+	if names := strings.Join(lc.Names(), ","); names != "events,cache,queue" {
+		t.Errorf("Expected registration order, got %s", names)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "queue,cache,events" {
+		t.Errorf("Expected reverse shutdown order, got %s", got)
+	}
+
+	// 重复关闭不会再次调用组件
+	if err := lc.Close(); err != nil || len(order) != 3 || len(lc.Names()) != 0 {
+		t.Errorf("Expected second Close to be a no-op, got %v, %v", err, order)
+	}
+}
+
+func TestLifecycleCloseErrors(t *testing.T) {
+	var order []string
+	errCache := errors.New("flush failed")
+	lc := NewLifecycle().
+		Register("cache", errCloser{closed: &order, name: "cache", err: errCache}).
+		RegisterFunc("broken", func() error { panic("boom") }).
+		Register("limiter", stopper{stopped: &order, name: "limiter"})
+
+	err := lc.Close()
+	if !errors.Is(err, errCache) {
+		t.Errorf("Expected joined error to wrap cache error, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "cache: flush failed") ||
+		!strings.Contains(err.Error(), "broken: panic during close: boom") {
+		t.Errorf("Expected named errors for each failure, got %v", err)
+	}
+	if got := strings.Join(order, ","); got != "limiter,cache" {
+		t.Errorf("Expected remaining components to close despite failures, got %s", got)
+	}
+}
+
+func TestLifecycleRegisterUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic for a component without Close or Stop")
+		}
+	}()
+	NewLifecycle().Register("invalid", struct{}{})
+}
@@ -2,12 +2,21 @@ package framework
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/event"
+	"github.com/clarkgo/clarkgo/pkg/log"
+	"github.com/clarkgo/clarkgo/pkg/webhook"
 	"github.com/cloudwego/hertz/pkg/app"
-	"github.com/cloudwego/hertz/pkg/common/hlog"
 )
 
+// RequestIDHeader 请求 ID 头
+const RequestIDHeader = "X-Request-ID"
+
 // Middleware 中间件管理器
 type Middleware struct {
 	handlers []app.HandlerFunc
@@ -64,15 +73,79 @@ func Cors() app.HandlerFunc {
 	}
 }
 
+// PanicRecoveredEventName 请求处理 panic 被恢复时触发的事件名称
+const PanicRecoveredEventName = "http.panic_recovered"
+
+// PanicRecoveredEvent 请求处理 panic 被恢复事件，可用于告警
+type PanicRecoveredEvent struct {
+	Error     string
+	Stack     string
+	Method    string
+	Path      string
+	RequestID string
+	Time      time.Time
+}
+
+// EventName 实现 event.Event 接口
+func (e *PanicRecoveredEvent) EventName() string {
+	return PanicRecoveredEventName
+}
+
+// RecoveryConfig 恢复中间件配置
+type RecoveryConfig struct {
+	// Debug 为 true 时在响应中返回 panic 信息和堆栈
+	Debug bool
+
+	// Dispatcher 事件分发器，为空时使用全局分发器
+	Dispatcher *event.Dispatcher
+}
+
 // Recovery 恢复中间件
 func Recovery() app.HandlerFunc {
+	return RecoveryWithConfig(RecoveryConfig{})
+}
+
+// RecoveryWithConfig 带配置的恢复中间件
+// 捕获 panic 及堆栈，通过结构化日志记录，并触发 PanicRecoveredEvent
+func RecoveryWithConfig(config RecoveryConfig) app.HandlerFunc {
 	return func(c context.Context, ctx *app.RequestContext) {
 		defer func() {
 			if err := recover(); err != nil {
-				ctx.JSON(500, map[string]interface{}{
+				stack := string(debug.Stack())
+
+				evt := &PanicRecoveredEvent{
+					Error:     fmt.Sprint(err),
+					Stack:     stack,
+					Method:    string(ctx.Request.Method()),
+					Path:      string(ctx.Request.URI().Path()),
+					RequestID: ctx.GetString("request_id"),
+					Time:      time.Now(),
+				}
+
+				log.FromContext(c).Error("panic recovered",
+					"error", evt.Error,
+					"method", evt.Method,
+					"path", evt.Path,
+					"request_id", evt.RequestID,
+					"stack", stack,
+				)
+
+				dispatcher := config.Dispatcher
+				if dispatcher == nil {
+					dispatcher = event.GetDispatcher()
+				}
+				dispatcher.DispatchWithContext(c, evt)
+
+				body := map[string]interface{}{
 					"code":    500,
 					"message": "Internal Server Error",
-				})
+				}
+				if config.Debug {
+					body["error"] = evt.Error
+					body["stack"] = stack
+				}
+
+				ctx.JSON(500, body)
 				ctx.Abort()
 			}
 		}()
@@ -80,7 +153,142 @@ func Recovery() app.HandlerFunc {
 	}
 }
 
-// Logger 日志中间件
+// RequestID 请求 ID 中间件
+// 沿用客户端传入的 X-Request-ID 或生成新 ID，写入响应头，
+// 并将携带 request_id 字段的 Logger 附加到 context（log.FromContext）
+func RequestID() app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		requestID := string(ctx.GetHeader(RequestIDHeader))
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+
+		ctx.Set("request_id", requestID)
+		ctx.Header(RequestIDHeader, requestID)
+
+		ctx.Next(log.WithRequestID(c, requestID))
+	}
+}
+
+// newRequestID 生成随机请求 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
+
+// Timeout 请求超时中间件
+// 后续处理器在独立的 goroutine 中基于 RequestContext 的副本执行，并拿到带超时的 context，
+// 下游的 HTTP 客户端和数据库调用会随之取消。处理器在截止时间前返回时，其响应、
+// Keys 和错误被复制回原请求；否则立即返回 503，之后处理器写入的内容会被丢弃。
+// 处理器中的 panic 会在请求 goroutine 中重新抛出，交由 Recovery 处理。
+// 副本不支持流式响应和连接劫持，这类路由不应使用该中间件。
+func Timeout(d time.Duration) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		timeoutCtx, cancel := context.WithTimeout(c, d)
+		defer cancel()
+
+		cp := ctx.Copy()
+		cp.SetHandlers(ctx.Handlers())
+		cp.SetIndex(ctx.GetIndex())
+
+		done := make(chan struct{})
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			cp.Next(timeoutCtx)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			finishTimeout(ctx, cp)
+			return
+		case <-timeoutCtx.Done():
+		}
+
+		// 处理器恰好在截止时间返回时保留其响应
+		select {
+		case <-done:
+			finishTimeout(ctx, cp)
+			return
+		default:
+		}
+
+		log.FromContext(c).Warn("request timed out",
+			"method", string(ctx.Request.Method()),
+			"path", string(ctx.Request.URI().Path()),
+			"timeout", d,
+		)
+
+		ctx.JSON(503, map[string]interface{}{
+			"code":    503,
+			"message": "Service Unavailable",
+		})
+		ctx.Abort()
+	}
+}
+
+// finishTimeout 将在截止时间前完成的副本的执行结果复制回原请求
+// 后续处理器已在副本中执行，因此中止原请求的处理链
+func finishTimeout(ctx, cp *app.RequestContext) {
+	cp.Response.CopyTo(&ctx.Response)
+	cp.ForEachKey(func(k string, v interface{}) {
+		ctx.Set(k, v)
+	})
+	ctx.Errors = append(ctx.Errors, cp.Errors...)
+	ctx.Abort()
+}
+
+// WebhookConfig webhook 签名校验中间件配置
+type WebhookConfig struct {
+	// Secret 签名密钥
+	Secret string
+
+	// Tolerance 签名时间戳允许的偏差，用于防止重放，0 表示不校验
+	Tolerance time.Duration
+}
+
+// VerifyWebhook webhook 签名校验中间件，使用默认的时间戳偏差
+func VerifyWebhook(secret string) app.HandlerFunc {
+	return VerifyWebhookWithConfig(WebhookConfig{
+		Secret:    secret,
+		Tolerance: webhook.DefaultTolerance,
+	})
+}
+
+// VerifyWebhookWithConfig 带配置的 webhook 签名校验中间件
+// 签名缺失、不匹配或时间戳过期时返回 401
+func VerifyWebhookWithConfig(config WebhookConfig) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		signature := string(ctx.GetHeader(webhook.SignatureHeader))
+		err := webhook.VerifySignatureWithTolerance([]byte(config.Secret), ctx.Request.Body(), signature, config.Tolerance)
+		if err != nil {
+			log.FromContext(c).Warn("webhook signature rejected",
+				"path", string(ctx.Request.URI().Path()),
+				"error", err,
+			)
+			ctx.JSON(401, map[string]interface{}{
+				"code":    401,
+				"message": err.Error(),
+			})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next(c)
+	}
+}
+
+// Logger 日志中间件，应注册在 RequestID 之后以记录请求 ID
 func Logger() app.HandlerFunc {
 	return func(c context.Context, ctx *app.RequestContext) {
 		start := time.Now()
@@ -92,6 +300,11 @@ func Logger() app.HandlerFunc {
 		latency := time.Since(start)
 		statusCode := ctx.Response.StatusCode()
 
-		hlog.Infof("[%s] %s %d %s", method, path, statusCode, latency)
+		log.FromContext(c).Info("request completed",
+			"method", method,
+			"path", path,
+			"status", statusCode,
+			"latency", latency,
+		)
 	}
 }
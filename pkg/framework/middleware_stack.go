@@ -0,0 +1,118 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// 全局中间件优先级，数字越小越先执行（越靠外层）
+const (
+	MiddlewarePriorityRecovery  = -300 // 最先执行，捕获之后所有中间件和处理器的 panic
+	MiddlewarePriorityRequestID = -200 // 在日志之前生成请求 ID
+	MiddlewarePriorityLogger    = -100 // 包裹之后所有中间件，记录完整耗时
+	MiddlewarePriorityDefault   = 0
+)
+
+// middlewareEntry 已注册的全局中间件
+type middlewareEntry struct {
+	name     string
+	priority int
+	handler  app.HandlerFunc
+}
+
+// middlewareStack 按优先级排序的全局中间件
+// 通过一个在路由注册前安装的入口中间件在请求时展开，因此中间件的注册时机不影响作用范围，
+// 先注册或后注册的路由都会经过所有全局中间件
+type middlewareStack struct {
+	mu      sync.RWMutex
+	entries []middlewareEntry // 注册顺序，插入到指定中间件前后时按位置插入
+	chain   app.HandlersChain // 排序后的处理器，注册时重建
+}
+
+// add 在末尾添加中间件，优先级相同时按注册顺序执行
+func (s *middlewareStack) add(name string, priority int, handler app.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, middlewareEntry{name: name, priority: priority, handler: handler})
+	s.rebuild()
+}
+
+// insert 在名为 target 的中间件之前或之后插入，使用与其相同的优先级
+func (s *middlewareStack) insert(target, name string, handler app.HandlerFunc, after bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, entry := range s.entries {
+		if entry.name != target {
+			continue
+		}
+
+		position := i
+		if after {
+			position = i + 1
+		}
+		inserted := middlewareEntry{name: name, priority: entry.priority, handler: handler}
+		s.entries = append(s.entries[:position], append([]middlewareEntry{inserted}, s.entries[position:]...)...)
+		s.rebuild()
+		return nil
+	}
+	return fmt.Errorf("middleware %s not registered", target)
+}
+
+// names 返回按执行顺序排列的中间件名称
+func (s *middlewareStack) names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sorted := s.sorted()
+	names := make([]string, len(sorted))
+	for i, entry := range sorted {
+		names[i] = entry.name
+	}
+	return names
+}
+
+// sorted 按优先级稳定排序，调用方需持有锁
+func (s *middlewareStack) sorted() []middlewareEntry {
+	sorted := make([]middlewareEntry, len(s.entries))
+	copy(sorted, s.entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].priority < sorted[j].priority
+	})
+	return sorted
+}
+
+// rebuild 重建处理器链，调用方需持有写锁
+func (s *middlewareStack) rebuild() {
+	sorted := s.sorted()
+	chain := make(app.HandlersChain, len(sorted))
+	for i, entry := range sorted {
+		chain[i] = entry.handler
+	}
+	s.chain = chain
+}
+
+// handle 入口中间件：将排序后的中间件插入到当前处理器之后，再继续执行
+func (s *middlewareStack) handle(ctx context.Context, c *app.RequestContext) {
+	s.mu.RLock()
+	chain := s.chain
+	s.mu.RUnlock()
+
+	if len(chain) > 0 {
+		handlers := c.Handlers()
+		index := int(c.GetIndex())
+
+		spliced := make(app.HandlersChain, 0, len(handlers)+len(chain))
+		spliced = append(spliced, handlers[:index+1]...)
+		spliced = append(spliced, chain...)
+		spliced = append(spliced, handlers[index+1:]...)
+		c.SetHandlers(spliced)
+	}
+
+	c.Next(ctx)
+}
@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestMiddlewareStackOrder(t *testing.T) {
+	testApp := newTestApp()
+
+	var order []string
+	record := func(name string) app.HandlerFunc {
+		return func(ctx context.Context, c *app.RequestContext) {
+			order = append(order, name)
+			c.Next(ctx)
+		}
+	}
+
+	testApp.UseMiddleware("auth", MiddlewarePriorityDefault, record("auth"))
+	testApp.UseMiddleware("logger", MiddlewarePriorityLogger, record("logger"))
+	testApp.Router.GET("/ping", func(ctx context.Context, c *RequestContext) {
+		order = append(order, "handler")
+		c.String(200, "pong")
+	})
+
+	// 路由注册之后添加的中间件同样生效
+	testApp.UseMiddleware("recovery", MiddlewarePriorityRecovery, record("recovery"))
+	if err := testApp.UseMiddlewareBefore("auth", "cors", record("cors")); err != nil {
+		t.Fatalf("UseMiddlewareBefore error: %v", err)
+	}
+	if err := testApp.UseMiddlewareAfter("auth", "audit", record("audit")); err != nil {
+		t.Fatalf("UseMiddlewareAfter error: %v", err)
+	}
+	if err := testApp.UseMiddlewareAfter("missing", "noop", record("noop")); err == nil {
+		t.Error("Expected error when inserting next to an unregistered middleware")
+	}
+
+	want := "recovery,logger,cors,auth,audit"
+	if names := strings.Join(testApp.MiddlewareNames(), ","); names != want {
+		t.Errorf("Expected middleware order %s, got %s", want, names)
+	}
+
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/ping", nil)
+	if w.Code != 200 || w.Body.String() != "pong" {
+		t.Fatalf("Expected 200 pong, got %d %s", w.Code, w.Body.String())
+	}
+	if got := strings.Join(order, ","); got != want+",handler" {
+		t.Errorf("Expected execution order %s,handler, got %s", want, got)
+	}
+}
+
+func TestMiddlewareStackAbort(t *testing.T) {
+	testApp := newTestApp()
+
+	called := false
+	testApp.UseMiddleware("deny", MiddlewarePriorityDefault, func(ctx context.Context, c *app.RequestContext) {
+		c.AbortWithStatus(403)
+	})
+	testApp.Router.GET("/secret", func(ctx context.Context, c *RequestContext) {
+		called = true
+	})
+
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/secret", nil)
+	if w.Code != 403 || called {
+		t.Errorf("Expected middleware to abort with 403 before the handler, got %d (handler called: %v)", w.Code, called)
+	}
+}
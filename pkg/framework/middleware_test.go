@@ -0,0 +1,156 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/event"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestTimeout(t *testing.T) {
+	testApp := newTestApp()
+	testApp.RegisterMiddleware(Timeout(20 * time.Millisecond))
+
+	canceled := make(chan bool, 1)
+	testApp.Router.GET("/slow", func(ctx context.Context, c *RequestContext) {
+		c.String(200, "partial")
+		select {
+		case <-ctx.Done():
+			canceled <- true
+		case <-time.After(time.Second):
+			canceled <- false
+		}
+	})
+	testApp.Router.GET("/fast", func(ctx context.Context, c *RequestContext) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("Expected handler context to carry the deadline")
+		}
+		c.String(200, "done")
+	})
+
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/slow", nil)
+	if w.Code != 503 || !strings.Contains(w.Body.String(), "Service Unavailable") {
+		t.Errorf("Expected 503 replacing the partial response, got %d %s", w.Code, w.Body.String())
+	}
+	if !<-canceled {
+		t.Error("Expected handler context to be canceled at the deadline")
+	}
+
+	w = ut.PerformRequest(testApp.Server.Engine, "GET", "/fast", nil)
+	if w.Code != 200 || w.Body.String() != "done" {
+		t.Errorf("Expected fast handler to be unaffected, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTimeoutHandlerIgnoringContext(t *testing.T) {
+	testApp := newTestApp()
+
+	var user interface{}
+	testApp.RegisterMiddleware(func(ctx context.Context, c *app.RequestContext) {
+		c.Next(ctx)
+		user, _ = c.Get("user")
+	}, Timeout(20*time.Millisecond))
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	testApp.Router.GET("/stuck", func(ctx context.Context, c *RequestContext) {
+		<-release
+		c.String(200, "late")
+		close(finished)
+	})
+	testApp.Router.GET("/keys", func(ctx context.Context, c *RequestContext) {
+		c.Set("user", "alice")
+		c.String(200, "ok")
+	})
+
+	start := time.Now()
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/stuck", nil)
+	elapsed := time.Since(start)
+	if w.Code != 503 || !strings.Contains(w.Body.String(), "Service Unavailable") {
+		t.Errorf("Expected 503, got %d %s", w.Code, w.Body.String())
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("Expected 503 at the deadline, took %v", elapsed)
+	}
+
+	// 超时后处理器写入的内容不会影响已发送的响应
+	close(release)
+	<-finished
+	if w.Body.String() == "late" {
+		t.Error("Expected late handler output to be discarded")
+	}
+
+	w = ut.PerformRequest(testApp.Server.Engine, "GET", "/keys", nil)
+	if w.Code != 200 || w.Body.String() != "ok" {
+		t.Errorf("Expected 200 ok, got %d %s", w.Code, w.Body.String())
+	}
+	if user != "alice" {
+		t.Errorf("Expected keys set by the handler to be visible upstream, got %v", user)
+	}
+}
+
+func TestTimeoutPropagatesPanic(t *testing.T) {
+	testApp := newTestApp()
+	testApp.RegisterMiddleware(Recovery(), Timeout(time.Second))
+	testApp.Router.GET("/boom", func(ctx context.Context, c *RequestContext) {
+		panic("boom")
+	})
+
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/boom", nil)
+	if w.Code != 500 {
+		t.Errorf("Expected panic to reach Recovery, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRecoveryWithConfig(t *testing.T) {
+	dispatcher := event.NewDispatcher(1)
+	defer dispatcher.Stop()
+
+	var recovered []*PanicRecoveredEvent
+	dispatcher.Listen(PanicRecoveredEventName, func(ctx context.Context, e event.Event) error {
+		recovered = append(recovered, e.(*PanicRecoveredEvent))
+		return nil
+	})
+
+	for _, debug := range []bool{true, false} {
+		testApp := newTestApp()
+		testApp.RegisterMiddleware(RecoveryWithConfig(RecoveryConfig{
+			Debug:      debug,
+			Dispatcher: dispatcher,
+		}), RequestID())
+		testApp.Router.GET("/boom", func(ctx context.Context, c *RequestContext) {
+			panic("database unavailable")
+		})
+
+		w := ut.PerformRequest(testApp.Server.Engine, "GET", "/boom", nil, ut.Header{Key: RequestIDHeader, Value: "req-1"})
+		if w.Code != 500 {
+			t.Fatalf("debug=%v: expected 500, got %d", debug, w.Code)
+		}
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("debug=%v: invalid response body: %v", debug, err)
+		}
+		stack, hasStack := body["stack"].(string)
+		if debug {
+			// 堆栈包含 panic 发生的位置
+			if body["error"] != "database unavailable" || !hasStack || !strings.Contains(stack, "TestRecoveryWithConfig") {
+				t.Errorf("Expected panic details in debug response, got %v", body)
+			}
+		} else if hasStack || body["error"] != nil || body["message"] != "Internal Server Error" {
+			t.Errorf("Expected generic response without stack, got %v", body)
+		}
+	}
+
+	if len(recovered) != 2 {
+		t.Fatalf("Expected a PanicRecoveredEvent per panic on the configured dispatcher, got %d", len(recovered))
+	}
+	if e := recovered[0]; e.Error != "database unavailable" || e.Method != "GET" || e.Path != "/boom" || e.RequestID != "req-1" || e.Stack == "" {
+		t.Errorf("Unexpected event %+v", e)
+	}
+}
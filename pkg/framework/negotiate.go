@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 内容协商支持的媒体类型
+const (
+	MIMEJSON = "application/json"
+	MIMEXML  = "application/xml"
+	MIMEXML2 = "text/xml"
+	MIMEHTML = "text/html"
+)
+
+var (
+	negotiateMu       sync.RWMutex
+	negotiateTemplate string
+)
+
+// SetNegotiateTemplate 设置 Negotiate 渲染 HTML 时使用的模板名称，需先通过服务器加载模板
+// 未设置时 Negotiate 只返回 JSON 或 XML
+func SetNegotiateTemplate(name string) {
+	negotiateMu.Lock()
+	defer negotiateMu.Unlock()
+	negotiateTemplate = name
+}
+
+// Negotiate 根据 Accept 请求头选择响应格式：JSON、XML 或 HTML（设置了模板时），默认 JSON
+func (c *RequestContext) Negotiate(code int, data interface{}) {
+	negotiateMu.RLock()
+	template := negotiateTemplate
+	negotiateMu.RUnlock()
+
+	offers := []string{MIMEJSON, MIMEXML, MIMEXML2}
+	if template != "" {
+		offers = append(offers, MIMEHTML)
+	}
+
+	switch negotiateFormat(c.GetHeader("Accept"), offers) {
+	case MIMEXML, MIMEXML2:
+		c.XML(code, data)
+	case MIMEHTML:
+		c.HTML(code, template, data)
+	default:
+		c.JSON(code, data)
+	}
+}
+
+// acceptRange Accept 请求头中的一个媒体范围
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiateFormat 返回 offers 中客户端最偏好的格式，没有可接受的格式时返回空字符串
+// 每个格式的质量值取自匹配它的最具体的媒体范围，因此 q=0 可以排除通配符匹配到的格式；
+// 质量值相同时更具体的媒体范围优先，再按 offers 的顺序
+func negotiateFormat(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQuality, bestSpecificity := "", 0.0, -1
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+		for _, r := range ranges {
+			if s := mediaMatch(r.mediaType, offer); s > specificity {
+				quality, specificity = r.quality, s
+			}
+		}
+		if specificity < 0 || quality == 0 {
+			continue
+		}
+		if quality > bestQuality || (quality == bestQuality && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = offer, quality, specificity
+		}
+	}
+	return best
+}
+
+// parseAccept 解析 Accept 请求头，按质量值从高到低排序
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	return ranges
+}
+
+// mediaMatch 判断媒体范围是否匹配 offer，返回匹配的具体程度：
+// */* 为 0，type/* 为 1，完全匹配为 2，不匹配为 -1
+func mediaMatch(mediaRange, offer string) int {
+	if mediaRange == offer {
+		return 2
+	}
+	if mediaRange == "*/*" || mediaRange == "*" {
+		return 0
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok && strings.HasPrefix(offer, prefix+"/") {
+		return 1
+	}
+	return -1
+}
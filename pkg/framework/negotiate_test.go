@@ -0,0 +1,68 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	offers := []string{MIMEJSON, MIMEXML, MIMEXML2, MIMEHTML}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"empty header uses first offer", "", MIMEJSON},
+		{"exact match", "application/xml", MIMEXML},
+		{"highest quality wins", "application/json;q=0.5, text/html;q=0.9", MIMEHTML},
+		{"order does not matter", "text/html;q=0.2, application/xml", MIMEXML},
+		{"zero quality is rejected", "application/json;q=0, application/*;q=0.1", MIMEXML},
+		{"zero quality excludes wildcard match", "text/*;q=0.5, text/xml;q=0, application/json;q=0.1", MIMEHTML},
+		{"type wildcard", "text/*", MIMEXML2},
+		{"full wildcard uses first offer", "*/*", MIMEJSON},
+		{"specific beats wildcard at same quality", "*/*, text/html", MIMEHTML},
+		{"browser header", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", MIMEHTML},
+		{"case and spacing", " Application/XML ; q=1 ", MIMEXML},
+		{"invalid quality defaults to 1", "text/html;q=abc, application/json;q=0.9", MIMEHTML},
+		{"nothing acceptable", "image/png", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateFormat(tt.accept, offers); got != tt.want {
+			t.Errorf("%s: negotiateFormat(%q) = %q, want %q", tt.name, tt.accept, got, tt.want)
+		}
+	}
+}
+
+type negotiateUser struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestNegotiate(t *testing.T) {
+	testApp := newTestApp()
+	testApp.Router.GET("/user", func(ctx context.Context, c *RequestContext) {
+		c.Negotiate(200, negotiateUser{Name: "clark"})
+	})
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/xml", "application/xml"},
+		{"application/json", "application/json"},
+		// 没有设置模板时不提供 HTML，无法满足的 Accept 回退到 JSON
+		{"text/html", "application/json"},
+		{"image/png", "application/json"},
+	}
+
+	for _, tt := range tests {
+		w := ut.PerformRequest(testApp.Server.Engine, "GET", "/user", nil, ut.Header{Key: "Accept", Value: tt.accept})
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.contentType) {
+			t.Errorf("Accept %s: expected %s, got %s", tt.accept, tt.contentType, contentType)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package framework
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/clarkgo/clarkgo/pkg/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 列表分页默认值
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// QueryOptions 解析列表查询参数时的白名单和默认值
+// 排序字段和过滤字段会直接作为列名拼入 SQL，只能来自代码中的白名单，不能来自请求
+type QueryOptions struct {
+	SortFields     []string // 允许排序的字段
+	DefaultSort    string   // sort 参数缺失或不在白名单中时使用，为空时不排序
+	DefaultOrder   string   // asc 或 desc，默认 desc
+	FilterFields   []string // 允许按等值过滤的字段，查询参数名与字段名相同
+	DefaultPerPage int      // 默认每页数量，默认 DefaultPerPage
+	MaxPerPage     int      // 每页数量上限，默认 MaxPerPage
+}
+
+// QuerySpec 解析后的分页、排序和过滤参数
+type QuerySpec struct {
+	Page    int
+	PerPage int
+	Sort    string            // 白名单中的排序字段，为空时不排序
+	Order   string            // asc 或 desc
+	Filters map[string]string // 白名单中且请求携带了非空值的过滤字段
+}
+
+// QueryParams 解析 page、per_page、sort、order 和过滤参数
+// 例如: GET /posts?page=2&per_page=10&sort=title&order=asc&status=published
+func (c *RequestContext) QueryParams(options QueryOptions) QuerySpec {
+	if options.DefaultPerPage <= 0 {
+		options.DefaultPerPage = DefaultPerPage
+	}
+	if options.MaxPerPage <= 0 {
+		options.MaxPerPage = MaxPerPage
+	}
+
+	spec := QuerySpec{
+		Page:    1,
+		PerPage: options.DefaultPerPage,
+		Sort:    options.DefaultSort,
+		Order:   "desc",
+		Filters: make(map[string]string),
+	}
+
+	if page, err := strconv.Atoi(c.GetQuery("page")); err == nil && page > 0 {
+		spec.Page = page
+	}
+	if perPage, err := strconv.Atoi(c.GetQuery("per_page")); err == nil && perPage > 0 {
+		spec.PerPage = perPage
+	}
+	if spec.PerPage > options.MaxPerPage {
+		spec.PerPage = options.MaxPerPage
+	}
+
+	if field := c.GetQuery("sort"); containsString(options.SortFields, field) {
+		spec.Sort = field
+	}
+	if order := strings.ToLower(options.DefaultOrder); order == "asc" || order == "desc" {
+		spec.Order = order
+	}
+	if order := strings.ToLower(c.GetQuery("order")); order == "asc" || order == "desc" {
+		spec.Order = order
+	}
+
+	for _, field := range options.FilterFields {
+		if value := c.GetQuery(field); value != "" {
+			spec.Filters[field] = value
+		}
+	}
+
+	return spec
+}
+
+// Offset 当前页的偏移量
+func (s QuerySpec) Offset() int {
+	return (s.Page - 1) * s.PerPage
+}
+
+// ApplyFilters 添加过滤条件，用于分页前统计总数
+func (s QuerySpec) ApplyFilters(db *gorm.DB) *gorm.DB {
+	// 按字段名排序，保证生成的 SQL 稳定
+	fields := make([]string, 0, len(s.Filters))
+	for field := range s.Filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		db = db.Where(clause.Eq{Column: clause.Column{Name: field}, Value: s.Filters[field]})
+	}
+	return db
+}
+
+// ApplyToGorm 添加过滤条件、排序和分页
+func (s QuerySpec) ApplyToGorm(db *gorm.DB) *gorm.DB {
+	db = s.ApplyFilters(db)
+	if s.Sort != "" {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Sort}, Desc: s.Order == "desc"})
+	}
+	return db.Offset(s.Offset()).Limit(s.PerPage)
+}
+
+// Meta 根据总数生成分页元数据
+func (s QuerySpec) Meta(total int64) *response.Meta {
+	return response.NewMeta(s.Page, s.PerPage, total)
+}
+
+// containsString 判断字符串是否在列表中
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newQueryContext 创建携带指定请求 URI 的请求上下文
+func newQueryContext(uri string) *RequestContext {
+	c := app.NewContext(0)
+	c.Request.SetRequestURI(uri)
+	return NewRequestContext(c)
+}
+
+var postQueryOptions = QueryOptions{
+	SortFields:   []string{"title", "created_at"},
+	DefaultSort:  "created_at",
+	FilterFields: []string{"status", "author_id"},
+}
+
+func TestQueryParams(t *testing.T) {
+	spec := newQueryContext("/posts?page=3&per_page=10&sort=title&order=ASC&status=published&author_id=&secret=1").
+		QueryParams(postQueryOptions)
+
+	if spec.Page != 3 || spec.PerPage != 10 || spec.Offset() != 20 {
+		t.Errorf("Unexpected pagination %+v", spec)
+	}
+	if spec.Sort != "title" || spec.Order != "asc" {
+		t.Errorf("Expected title asc, got %s %s", spec.Sort, spec.Order)
+	}
+	// 只保留白名单中且非空的过滤字段
+	if len(spec.Filters) != 1 || spec.Filters["status"] != "published" {
+		t.Errorf("Expected only the status filter, got %v", spec.Filters)
+	}
+}
+
+func TestQueryParamsDefaultsAndWhitelist(t *testing.T) {
+	tests := []struct {
+		uri     string
+		options QueryOptions
+		page    int
+		perPage int
+		sort    string
+		order   string
+	}{
+		{"/posts", postQueryOptions, 1, DefaultPerPage, "created_at", "desc"},
+		{"/posts?page=0&per_page=-5", postQueryOptions, 1, DefaultPerPage, "created_at", "desc"},
+		{"/posts?page=abc&per_page=1000", postQueryOptions, 1, MaxPerPage, "created_at", "desc"},
+		// 不在白名单中的排序字段回退到默认排序，非法的排序方向被忽略
+		{"/posts?sort=password;drop&order=sideways", postQueryOptions, 1, DefaultPerPage, "created_at", "desc"},
+		{"/posts?per_page=80", QueryOptions{DefaultPerPage: 5, MaxPerPage: 50, DefaultOrder: "asc"}, 1, 50, "", "asc"},
+		{"/posts", QueryOptions{DefaultPerPage: 5}, 1, 5, "", "desc"},
+	}
+
+	for _, tt := range tests {
+		spec := newQueryContext(tt.uri).QueryParams(tt.options)
+		if spec.Page != tt.page || spec.PerPage != tt.perPage || spec.Sort != tt.sort || spec.Order != tt.order {
+			t.Errorf("%s: got page=%d per_page=%d sort=%q order=%s", tt.uri, spec.Page, spec.PerPage, spec.Sort, spec.Order)
+		}
+	}
+}
+
+type queryPost struct {
+	ID       uint
+	Title    string
+	Status   string
+	AuthorID uint
+}
+
+func TestQuerySpecApplyToGorm(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	spec := newQueryContext("/posts?page=2&per_page=5&sort=title&order=asc&status=published&author_id=7").
+		QueryParams(postQueryOptions)
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var posts []queryPost
+		return spec.ApplyToGorm(tx.Model(&queryPost{})).Find(&posts)
+	})
+	want := "SELECT * FROM `query_posts` WHERE `author_id` = \"7\" AND `status` = \"published\" ORDER BY `title` LIMIT 5 OFFSET 5"
+	if sql != want {
+		t.Errorf("Unexpected SQL:\n got: %s\nwant: %s", sql, want)
+	}
+
+	if meta := spec.Meta(12); meta.CurrentPage != 2 || meta.PerPage != 5 || meta.TotalPages != 3 {
+		t.Errorf("Unexpected pagination meta %+v", meta)
+	}
+}
@@ -12,9 +12,12 @@ import (
 
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
-	// Limiter 限流器实例
+	// Limiter 限流器实例，设置 Router 时作为未声明限流的路由的默认限流器
 	Limiter ratelimit.Limiter
 
+	// Router 路由管理器，设置后按匹配到的路由使用其通过 Route.Limit 声明的限流
+	Router *Router
+
 	// KeyFunc 键生成函数
 	KeyFunc func(ctx context.Context, c *app.RequestContext) string
 
@@ -23,6 +26,9 @@ type RateLimitConfig struct {
 
 	// SkipFunc 跳过函数
 	SkipFunc func(ctx context.Context, c *app.RequestContext) bool
+
+	// CostFunc 请求消耗的配额，用于按权重限流的端点，默认每个请求消耗 1，小于 1 时按 1 计算
+	CostFunc func(ctx context.Context, c *app.RequestContext) int
 }
 
 // RateLimit 限流中间件
@@ -43,11 +49,30 @@ func RateLimit(config RateLimitConfig) app.HandlerFunc {
 			return
 		}
 
+		// 选择限流器，路由声明的限流优先
+		limiter := config.Limiter
+		if config.Router != nil {
+			if l := config.Router.routeLimiter(string(c.Method()), c.FullPath()); l != nil {
+				limiter = l
+			}
+		}
+		if limiter == nil {
+			c.Next(ctx)
+			return
+		}
+
 		// 生成键
 		key := config.KeyFunc(ctx, c)
 
+		cost := 1
+		if config.CostFunc != nil {
+			if n := config.CostFunc(ctx, c); n > 1 {
+				cost = n
+			}
+		}
+
 		// 检查是否允许
-		if !config.Limiter.Allow(key) {
+		if !limiter.AllowN(key, cost) {
 			config.ErrorHandler(ctx, c)
 			c.Abort()
 			return
@@ -57,6 +82,22 @@ func RateLimit(config RateLimitConfig) app.HandlerFunc {
 	}
 }
 
+// EndpointCost 按端点权重表生成 CostFunc，未列出的端点消耗 defaultCost
+// 权重表的键为 "方法 路由模式"（如 "GET /api/orders/:id"）或只有路由模式，前者优先，
+// 可以从配置中加载，例如: framework.EndpointCost(cfg.GetIntMap("ratelimit.weights"), 1)
+func EndpointCost(weights map[string]int, defaultCost int) func(ctx context.Context, c *app.RequestContext) int {
+	return func(ctx context.Context, c *app.RequestContext) int {
+		route := c.FullPath()
+		if cost, ok := weights[string(c.Method())+" "+route]; ok {
+			return cost
+		}
+		if cost, ok := weights[route]; ok {
+			return cost
+		}
+		return defaultCost
+	}
+}
+
 // defaultKeyFunc 默认键生成函数（基于 IP）
 func defaultKeyFunc(ctx context.Context, c *app.RequestContext) string {
 	return ratelimit.IPKeyGenerator(c.ClientIP())
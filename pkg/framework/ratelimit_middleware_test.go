@@ -0,0 +1,100 @@
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/ratelimit"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func okHandler(ctx context.Context, c *RequestContext) {
+	c.String(200, "ok")
+}
+
+func TestRateLimitRouteLimits(t *testing.T) {
+	testApp := newTestApp()
+	testApp.RegisterMiddleware(RateLimit(RateLimitConfig{
+		Router: testApp.Router,
+	}))
+
+	testApp.Router.POST("/login", okHandler).Limit(2, time.Minute)
+	testApp.Router.POST("/signup", okHandler).Limit(1, time.Minute)
+	testApp.Router.GET("/home", okHandler)
+
+	engine := testApp.Server.Engine
+	for i := 0; i < 2; i++ {
+		if w := ut.PerformRequest(engine, "POST", "/login", nil); w.Code != 200 {
+			t.Fatalf("Expected login request %d to pass, got %d", i+1, w.Code)
+		}
+	}
+	if w := ut.PerformRequest(engine, "POST", "/login", nil); w.Code != 429 {
+		t.Errorf("Expected login to be limited after its quota, got %d", w.Code)
+	}
+
+	// 其他路由独立计数，未声明限流的路由不受影响
+	if w := ut.PerformRequest(engine, "POST", "/signup", nil); w.Code != 200 {
+		t.Errorf("Expected signup to have its own quota, got %d", w.Code)
+	}
+	for i := 0; i < 5; i++ {
+		if w := ut.PerformRequest(engine, "GET", "/home", nil); w.Code != 200 {
+			t.Fatalf("Expected unlimited route to pass, got %d", w.Code)
+		}
+	}
+
+	routes := testApp.Router.GetRoutes()
+	if routes[0].RateLimit == nil || routes[0].RateLimit.Rate != 2 || routes[2].RateLimit != nil {
+		t.Errorf("Expected rate limits in route info, got %+v", routes)
+	}
+
+	// 限流器在声明时创建，应用关闭时统一关闭
+	if names := testApp.Router.limiters.Names(); len(names) != 2 || names[0] != "POST /login" || names[1] != "POST /signup" {
+		t.Errorf("Expected limiters to be built at registration, got %v", names)
+	}
+	if err := testApp.Lifecycle.Close(); err != nil {
+		t.Fatalf("Lifecycle.Close error: %v", err)
+	}
+	if names := testApp.Router.limiters.Names(); len(names) != 0 {
+		t.Errorf("Expected route limiters to be closed on shutdown, got %v", names)
+	}
+}
+
+func TestRateLimitEndpointCost(t *testing.T) {
+	limiter := ratelimit.NewSlidingWindow(10, time.Minute)
+	defer limiter.Close()
+
+	testApp := newTestApp()
+	testApp.UseMiddleware("ratelimit", MiddlewarePriorityDefault, RateLimit(RateLimitConfig{
+		Limiter: limiter,
+		CostFunc: EndpointCost(map[string]int{
+			"POST /orders": 4,
+			"/reports/:id": 3,
+			"GET /free":    0,
+		}, 1),
+	}))
+	testApp.Router.POST("/orders", okHandler)
+	testApp.Router.GET("/reports/:id", okHandler)
+	testApp.Router.GET("/free", okHandler)
+
+	engine := testApp.Server.Engine
+	for i := 0; i < 2; i++ {
+		if w := ut.PerformRequest(engine, "POST", "/orders", nil); w.Code != 200 {
+			t.Fatalf("Expected order request %d to pass, got %d", i+1, w.Code)
+		}
+	}
+	// 已消耗 8 个配额，剩余 2 个不足以支付权重为 3 的请求
+	if w := ut.PerformRequest(engine, "GET", "/reports/7", nil); w.Code != 429 {
+		t.Errorf("Expected cost-3 request to be limited with 2 remaining, got %d", w.Code)
+	}
+
+	// 权重小于 1 时按 1 计算
+	for i := 0; i < 2; i++ {
+		if w := ut.PerformRequest(engine, "GET", "/free", nil); w.Code != 200 {
+			t.Fatalf("Expected cost-1 request %d to pass, got %d", i+1, w.Code)
+		}
+	}
+	if w := ut.PerformRequest(engine, "GET", "/free", nil); w.Code != 429 {
+		t.Errorf("Expected quota to be exhausted, got %d", w.Code)
+	}
+}
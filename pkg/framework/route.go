@@ -4,23 +4,52 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/ratelimit"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 )
 
 // RouteInfo 存储路由信息
 type RouteInfo struct {
-	Method  string
-	Path    string
-	Handler string
+	Method    string          `json:"method"`
+	Path      string          `json:"path"`
+	Handler   string          `json:"handler"`
+	RateLimit *RouteRateLimit `json:"rate_limit,omitempty"`
+}
+
+// RouteRateLimit 路由级限流配置：每 Per 时间内最多 Rate 个请求
+type RouteRateLimit struct {
+	Rate int           `json:"rate"`
+	Per  time.Duration `json:"per"`
+}
+
+// Route 已注册的路由，用于链式声明路由级配置
+type Route struct {
+	router  *Router
+	indexes []int // 在 router.routes 中的位置，Any 注册的路由对应多条
+}
+
+// Limit 声明路由级限流，需在 RateLimitConfig 中设置 Router 才会生效
+// 限流器在声明时创建，每条路由独立计数，重复声明时替换并关闭原限流器
+// 例如: router.GET("/login", handler).Limit(5, time.Minute)
+func (rt *Route) Limit(rate int, per time.Duration) *Route {
+	for _, i := range rt.indexes {
+		route := &(*rt.router.routes)[i]
+		route.RateLimit = &RouteRateLimit{Rate: rate, Per: per}
+		rt.router.limiters.Register(routeKey(route.Method, route.Path), ratelimit.NewSlidingWindow(rate, per))
+	}
+	return rt
 }
 
 // Router 路由管理器
 type Router struct {
-	server *server.Hertz
-	prefix string
-	routes []RouteInfo // 存储所有注册的路由
+	server   *server.Hertz
+	prefix   string
+	routes   *[]RouteInfo       // 存储所有注册的路由（路由组共享）
+	limiters *ratelimit.Manager // 路由声明的限流器，键为 "方法 路由模式"（路由组共享）
 }
 
 // HandlerFunc 路由处理函数类型
@@ -29,22 +58,54 @@ type HandlerFunc func(context.Context, *RequestContext)
 // NewRouter 创建一个新的路由管理器
 func NewRouter(server *server.Hertz) *Router {
 	return &Router{
-		server: server,
-		prefix: "",
-		routes: []RouteInfo{},
+		server:   server,
+		prefix:   "",
+		routes:   &[]RouteInfo{},
+		limiters: ratelimit.NewManager(),
 	}
 }
 
+// addRoute 收集路由信息，返回路由在列表中的位置
+func (r *Router) addRoute(method, path, handler string) int {
+	*r.routes = append(*r.routes, RouteInfo{
+		Method:  method,
+		Path:    path,
+		Handler: handler,
+	})
+	return len(*r.routes) - 1
+}
+
+// routeKey 路由限流器的键
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// routeLimiter 返回路由声明的限流器，path 为匹配到的路由模式，未声明限流时返回 nil
+func (r *Router) routeLimiter(method, path string) ratelimit.Limiter {
+	limiter, _ := r.limiters.Get(routeKey(method, path))
+	return limiter
+}
+
+// Close 关闭路由声明的限流器，停止其 GC goroutine
+func (r *Router) Close() {
+	r.limiters.Close()
+}
+
 // PrintRoutes 打印所有已注册的路由
 func (r *Router) PrintRoutes() {
-	if len(r.routes) == 0 {
+	PrintRouteTable(r.GetRoutes())
+}
+
+// PrintRouteTable 以表格形式打印路由（按路径排序）
+func PrintRouteTable(routes []RouteInfo) {
+	if len(routes) == 0 {
 		fmt.Println("No routes registered.")
 		return
 	}
 
 	// 按路径排序
-	sort.Slice(r.routes, func(i, j int) bool {
-		return r.routes[i].Path < r.routes[j].Path
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Path < routes[j].Path
 	})
 
 	// 打印表头
@@ -53,7 +114,7 @@ func (r *Router) PrintRoutes() {
 	fmt.Println("+--------+------------------------------------+------------------------------------+")
 
 	// 打印路由
-	for _, route := range r.routes {
+	for _, route := range routes {
 		method := fmt.Sprintf("%-6s", route.Method)
 		path := route.Path
 		if len(path) > 34 {
@@ -73,12 +134,14 @@ func (r *Router) PrintRoutes() {
 	}
 
 	fmt.Println("+--------+------------------------------------+------------------------------------+")
-	fmt.Printf("\nTotal routes: %d\n\n", len(r.routes))
+	fmt.Printf("\nTotal routes: %d\n\n", len(routes))
 }
 
 // GetRoutes 获取所有已注册的路由
 func (r *Router) GetRoutes() []RouteInfo {
-	return r.routes
+	routes := make([]RouteInfo, len(*r.routes))
+	copy(routes, *r.routes)
+	return routes
 }
 
 // Group 创建一个路由组
@@ -94,118 +157,92 @@ func (r *Router) Group(prefix string, handlers ...HandlerFunc) *Router {
 	// 创建路由组
 	r.server.Group(r.prefix+prefix, h...)
 	return &Router{
-		server: r.server,
-		prefix: r.prefix + prefix,
+		server:   r.server,
+		prefix:   r.prefix + prefix,
+		routes:   r.routes,
+		limiters: r.limiters,
 	}
 }
 
 // GET 注册GET路由
-func (r *Router) GET(path string, handler HandlerFunc) {
+func (r *Router) GET(path string, handler HandlerFunc) *Route {
 	r.server.GET(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "GET",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("GET", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // POST 注册POST路由
-func (r *Router) POST(path string, handler HandlerFunc) {
+func (r *Router) POST(path string, handler HandlerFunc) *Route {
 	r.server.POST(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "POST",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("POST", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // PUT 注册PUT路由
-func (r *Router) PUT(path string, handler HandlerFunc) {
+func (r *Router) PUT(path string, handler HandlerFunc) *Route {
 	r.server.PUT(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "PUT",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("PUT", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // DELETE 注册DELETE路由
-func (r *Router) DELETE(path string, handler HandlerFunc) {
+func (r *Router) DELETE(path string, handler HandlerFunc) *Route {
 	r.server.DELETE(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "DELETE",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("DELETE", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // PATCH 注册PATCH路由
-func (r *Router) PATCH(path string, handler HandlerFunc) {
+func (r *Router) PATCH(path string, handler HandlerFunc) *Route {
 	r.server.PATCH(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "PATCH",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("PATCH", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // OPTIONS 注册OPTIONS路由
-func (r *Router) OPTIONS(path string, handler HandlerFunc) {
+func (r *Router) OPTIONS(path string, handler HandlerFunc) *Route {
 	r.server.OPTIONS(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "OPTIONS",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("OPTIONS", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // HEAD 注册HEAD路由
-func (r *Router) HEAD(path string, handler HandlerFunc) {
+func (r *Router) HEAD(path string, handler HandlerFunc) *Route {
 	r.server.HEAD(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
 
 	// 收集路由信息
-	handlerName := fmt.Sprintf("%T", handler)
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "HEAD",
-		Path:    r.prefix + path,
-		Handler: handlerName,
-	})
+	index := r.addRoute("HEAD", r.prefix+path, fmt.Sprintf("%T", handler))
+	return &Route{router: r, indexes: []int{index}}
 }
 
 // Any 注册所有HTTP方法的路由
-func (r *Router) Any(path string, handler HandlerFunc) {
+func (r *Router) Any(path string, handler HandlerFunc) *Route {
 	r.server.Any(r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
 		handler(ctx, NewRequestContext(c))
 	})
@@ -213,13 +250,62 @@ func (r *Router) Any(path string, handler HandlerFunc) {
 	// 收集路由信息
 	handlerName := fmt.Sprintf("%T", handler)
 	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	route := &Route{router: r}
 	for _, method := range methods {
-		r.routes = append(r.routes, RouteInfo{
-			Method:  method,
-			Path:    r.prefix + path,
-			Handler: handlerName,
-		})
+		route.indexes = append(route.indexes, r.addRoute(method, r.prefix+path, handlerName))
+	}
+	return route
+}
+
+// ResourceController 资源控制器，配合 Router.Resource 按约定注册 CRUD 路由
+type ResourceController interface {
+	// Index 列表 GET {prefix}
+	Index(ctx context.Context, c *RequestContext)
+	// Show 详情 GET {prefix}/:id
+	Show(ctx context.Context, c *RequestContext)
+	// Store 创建 POST {prefix}
+	Store(ctx context.Context, c *RequestContext)
+	// Update 更新 PUT {prefix}/:id
+	Update(ctx context.Context, c *RequestContext)
+	// Destroy 删除 DELETE {prefix}/:id
+	Destroy(ctx context.Context, c *RequestContext)
+}
+
+// ResourceFormController 提供表单页面的资源控制器，Resource 检测到时额外注册表单路由
+type ResourceFormController interface {
+	// Create 创建表单 GET {prefix}/create
+	Create(ctx context.Context, c *RequestContext)
+	// Edit 编辑表单 GET {prefix}/:id/edit
+	Edit(ctx context.Context, c *RequestContext)
+}
+
+// Resource 按约定为资源控制器注册 CRUD 路由，控制器实现 ResourceFormController 时共注册七条路由
+func (r *Router) Resource(prefix string, controller ResourceController) {
+	prefix = strings.TrimRight(prefix, "/")
+	name := fmt.Sprintf("%T", controller)
+	form, hasForms := controller.(ResourceFormController)
+
+	r.handle("GET", prefix, name+".Index", controller.Index)
+	if hasForms {
+		r.handle("GET", prefix+"/create", name+".Create", form.Create)
 	}
+	r.handle("GET", prefix+"/:id", name+".Show", controller.Show)
+	if hasForms {
+		r.handle("GET", prefix+"/:id/edit", name+".Edit", form.Edit)
+	}
+	r.handle("POST", prefix, name+".Store", controller.Store)
+	r.handle("PUT", prefix+"/:id", name+".Update", controller.Update)
+	r.handle("DELETE", prefix+"/:id", name+".Destroy", controller.Destroy)
+}
+
+// handle 注册指定方法的路由并记录处理器名称
+func (r *Router) handle(method, path, handlerName string, handler HandlerFunc) {
+	r.server.Handle(method, r.prefix+path, func(ctx context.Context, c *app.RequestContext) {
+		handler(ctx, NewRequestContext(c))
+	})
+
+	// 收集路由信息
+	r.addRoute(method, r.prefix+path, handlerName)
 }
 
 // Static 注册静态文件路由
@@ -227,11 +313,7 @@ func (r *Router) Static(path, root string) {
 	r.server.Static(r.prefix+path, root)
 
 	// 收集路由信息
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "GET",
-		Path:    r.prefix + path + "/*filepath",
-		Handler: "Static(" + root + ")",
-	})
+	r.addRoute("GET", r.prefix+path+"/*filepath", "Static("+root+")")
 }
 
 // StaticFile 注册静态文件路由
@@ -239,11 +321,7 @@ func (r *Router) StaticFile(path, filepath string) {
 	r.server.StaticFile(r.prefix+path, filepath)
 
 	// 收集路由信息
-	r.routes = append(r.routes, RouteInfo{
-		Method:  "GET",
-		Path:    r.prefix + path,
-		Handler: "StaticFile(" + filepath + ")",
-	})
+	r.addRoute("GET", r.prefix+path, "StaticFile("+filepath+")")
 }
 
 // StaticFS 注册静态文件系统路由
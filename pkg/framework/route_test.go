@@ -0,0 +1,93 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+// todoController 记录被调用动作的资源控制器
+type todoController struct{}
+
+func (todoController) Index(ctx context.Context, c *RequestContext) { c.String(200, "index") }
+func (todoController) Show(ctx context.Context, c *RequestContext) {
+	c.String(200, "show "+c.GetParam("id"))
+}
+func (todoController) Store(ctx context.Context, c *RequestContext) { c.String(201, "store") }
+func (todoController) Update(ctx context.Context, c *RequestContext) {
+	c.String(200, "update "+c.GetParam("id"))
+}
+func (todoController) Destroy(ctx context.Context, c *RequestContext) {
+	c.String(200, "destroy "+c.GetParam("id"))
+}
+
+// todoFormController 同时提供表单页面的资源控制器
+type todoFormController struct {
+	todoController
+}
+
+func (todoFormController) Create(ctx context.Context, c *RequestContext) { c.String(200, "create") }
+func (todoFormController) Edit(ctx context.Context, c *RequestContext) {
+	c.String(200, "edit "+c.GetParam("id"))
+}
+
+func TestResource(t *testing.T) {
+	testApp := newTestApp()
+	testApp.Router.Group("/api").Resource("/todos/", todoFormController{})
+
+	name := fmt.Sprintf("%T", todoFormController{})
+	want := []RouteInfo{
+		{Method: "GET", Path: "/api/todos", Handler: name + ".Index"},
+		{Method: "GET", Path: "/api/todos/create", Handler: name + ".Create"},
+		{Method: "GET", Path: "/api/todos/:id", Handler: name + ".Show"},
+		{Method: "GET", Path: "/api/todos/:id/edit", Handler: name + ".Edit"},
+		{Method: "POST", Path: "/api/todos", Handler: name + ".Store"},
+		{Method: "PUT", Path: "/api/todos/:id", Handler: name + ".Update"},
+		{Method: "DELETE", Path: "/api/todos/:id", Handler: name + ".Destroy"},
+	}
+
+	routes := testApp.Router.GetRoutes()
+	if len(routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %+v", len(want), routes)
+	}
+	for _, expected := range want {
+		found := false
+		for _, route := range routes {
+			if route.Method == expected.Method && route.Path == expected.Path && route.Handler == expected.Handler {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Missing route %s %s -> %s", expected.Method, expected.Path, expected.Handler)
+		}
+	}
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{"GET", "/api/todos", "index"},
+		{"GET", "/api/todos/create", "create"},
+		{"GET", "/api/todos/7", "show 7"},
+		{"GET", "/api/todos/7/edit", "edit 7"},
+		{"POST", "/api/todos", "store"},
+		{"PUT", "/api/todos/7", "update 7"},
+		{"DELETE", "/api/todos/7", "destroy 7"},
+	}
+	for _, req := range requests {
+		w := ut.PerformRequest(testApp.Server.Engine, req.method, req.path, nil)
+		if w.Body.String() != req.body {
+			t.Errorf("%s %s: expected %q, got %d %q", req.method, req.path, req.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestResourceWithoutForms(t *testing.T) {
+	testApp := newTestApp()
+	testApp.Router.Resource("/todos", todoController{})
+
+	if routes := testApp.Router.GetRoutes(); len(routes) != 5 {
+		t.Errorf("Expected five API routes without form actions, got %+v", routes)
+	}
+}
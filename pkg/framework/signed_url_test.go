@@ -0,0 +1,110 @@
+package framework
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestSignedURL(t *testing.T) {
+	secret := []byte("app-key")
+	now := time.Unix(1700000000, 0)
+
+	signed := SignedURL("/private/report.pdf?download=1", now.Add(time.Hour), secret)
+	u, _ := url.Parse(signed)
+	if u.Path != "/private/report.pdf" || u.Query().Get(SignedURLExpiresParam) != "1700003600" {
+		t.Errorf("Unexpected signed url %s", signed)
+	}
+	if err := verifySignedURL(u.Path, u.RawQuery, secret, now); err != nil {
+		t.Errorf("Expected signed url to verify, got %v", err)
+	}
+
+	// 重新签名已签名的 URL 会替换旧签名
+	resigned := SignedURL(signed, now.Add(time.Hour), secret)
+	if strings.Count(resigned, SignedURLSignatureParam+"=") != 1 {
+		t.Errorf("Expected a single signature, got %s", resigned)
+	}
+
+	// 无法按 URL 解析的路径整体转义后签名
+	literal := SignedURL("/private/100%.pdf", now.Add(time.Hour), secret)
+	u, err := url.Parse(literal)
+	if err != nil || u.Path != "/private/100%.pdf" {
+		t.Fatalf("Expected literal path to be escaped, got %s (%v)", literal, err)
+	}
+	if err := verifySignedURL(u.Path, u.RawQuery, secret, now); err != nil {
+		t.Errorf("Expected escaped path to verify, got %v", err)
+	}
+}
+
+func TestVerifySignedURLRejects(t *testing.T) {
+	secret := []byte("app-key")
+	now := time.Unix(1700000000, 0)
+	signed := SignedURL("/private/report.pdf?user=1", now.Add(time.Minute), secret)
+	u, _ := url.Parse(signed)
+	query := u.Query()
+
+	tampered := url.Values{}
+	for k, v := range query {
+		tampered[k] = v
+	}
+	tampered.Set("user", "2")
+
+	missing := url.Values{}
+	for k, v := range query {
+		missing[k] = v
+	}
+	missing.Del(SignedURLSignatureParam)
+
+	tests := []struct {
+		name   string
+		path   string
+		query  string
+		secret []byte
+		now    time.Time
+		err    string
+	}{
+		{"tampered query", u.Path, tampered.Encode(), secret, now, "invalid url signature"},
+		{"tampered path", "/private/other.pdf", u.RawQuery, secret, now, "invalid url signature"},
+		{"wrong secret", u.Path, u.RawQuery, []byte("other-key"), now, "invalid url signature"},
+		{"expired", u.Path, u.RawQuery, secret, now.Add(2 * time.Minute), "signed url has expired"},
+		{"missing signature", u.Path, missing.Encode(), secret, now, "missing or malformed url signature"},
+		{"malformed signature", u.Path, "expires=1700000060&signature=zz", secret, now, "missing or malformed url signature"},
+	}
+
+	for _, tt := range tests {
+		err := verifySignedURL(tt.path, tt.query, tt.secret, tt.now)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("%s: expected %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestVerifySignedURLMiddleware(t *testing.T) {
+	secret := []byte("app-key")
+	testApp := newTestApp()
+	testApp.Server.GET("/private/*filepath", VerifySignedURL(secret), func(ctx context.Context, c *app.RequestContext) {
+		c.String(200, "file")
+	})
+
+	for _, path := range []string{"/private/report.pdf", "/private/100%.pdf"} {
+		signed := SignedURL(path, time.Now().Add(time.Hour), secret)
+		if w := ut.PerformRequest(testApp.Server.Engine, "GET", signed, nil); w.Code != 200 || w.Body.String() != "file" {
+			t.Errorf("Expected signed request for %s to pass, got %d %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	expired := SignedURL("/private/report.pdf", time.Now().Add(-time.Minute), secret)
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", expired, nil)
+	if w.Code != 403 || !strings.Contains(w.Body.String(), "signed url has expired") {
+		t.Errorf("Expected 403 for expired url, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := ut.PerformRequest(testApp.Server.Engine, "GET", "/private/report.pdf", nil); w.Code != 403 {
+		t.Errorf("Expected 403 for unsigned url, got %d", w.Code)
+	}
+}
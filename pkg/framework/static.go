@@ -0,0 +1,172 @@
+package framework
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+const (
+	// staticCacheControl 静态资源的缓存策略，过期后通过 ETag 重新验证
+	staticCacheControl = "public, max-age=3600"
+	// indexCacheControl 入口页面每次都重新验证，保证发布后立即生效
+	indexCacheControl = "no-cache"
+)
+
+// SPA 注册单页应用路由：存在的文件直接返回，其余不带扩展名的路径返回 indexFile，
+// 由前端路由处理；带扩展名但不存在的路径（如丢失的 .js 文件）返回 404
+// 例如: router.SPA("/", "public/dist", "index.html")
+func (r *Router) SPA(urlPrefix, root, indexFile string) {
+	pattern := r.prefix + strings.TrimSuffix(urlPrefix, "/") + "/*filepath"
+	indexPath := filepath.Join(root, indexFile)
+
+	handler := func(ctx context.Context, c *app.RequestContext) {
+		name := path.Clean("/" + c.Param("filepath"))
+		fullPath := filepath.Join(root, filepath.FromSlash(name))
+
+		if info, err := os.Stat(fullPath); err == nil && !info.IsDir() {
+			serveStaticFile(c, fullPath, info, staticCacheControl)
+			return
+		}
+
+		if path.Ext(name) != "" {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		info, err := os.Stat(indexPath)
+		if err != nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		serveStaticFile(c, indexPath, info, indexCacheControl)
+	}
+
+	r.server.GET(pattern, handler)
+	r.server.HEAD(pattern, handler)
+
+	// 收集路由信息
+	r.addRoute("GET", pattern, "SPA("+root+")")
+	r.addRoute("HEAD", pattern, "SPA("+root+")")
+}
+
+// serveStaticFile 返回磁盘文件，设置 Cache-Control 和基于修改时间的 ETag
+func serveStaticFile(c *app.RequestContext, fullPath string, info os.FileInfo, cacheControl string) {
+	etag := modTimeETag(info.ModTime(), info.Size())
+	c.Header("Cache-Control", cacheControl)
+	c.Header("ETag", etag)
+
+	if notModified(c, etag, info.ModTime()) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+	c.File(fullPath)
+}
+
+// modTimeETag 根据修改时间和文件大小生成弱 ETag
+func modTimeETag(modTime time.Time, size int64) string {
+	return fmt.Sprintf(`W/"%x-%x"`, modTime.UnixNano(), size)
+}
+
+// notModified 检查条件请求头，客户端缓存仍然有效时返回 true
+// 同时存在时 If-None-Match 优先于 If-Modified-Since
+func notModified(c *app.RequestContext, etag string, modTime time.Time) bool {
+	if match := string(c.GetHeader("If-None-Match")); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := string(c.GetHeader("If-Modified-Since")); since != "" && !modTime.IsZero() {
+		if t, err := http.ParseTime(since); err == nil {
+			return !modTime.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
+
+// StaticEmbed 注册嵌入文件系统（如 embed.FS）中 subdir 目录下的静态文件路由，适合单文件部署
+// embed.FS 中的文件没有修改时间，Last-Modified 使用注册时间，ETag 根据文件内容生成
+// 例如: router.StaticEmbed("/assets", assets, "public")
+func (r *Router) StaticEmbed(urlPrefix string, fsys fs.FS, subdir string) {
+	if subdir != "" && subdir != "." {
+		sub, err := fs.Sub(fsys, subdir)
+		if err != nil {
+			panic(fmt.Sprintf("framework: invalid embedded static directory %q: %v", subdir, err))
+		}
+		fsys = sub
+	}
+
+	pattern := r.prefix + strings.TrimSuffix(urlPrefix, "/") + "/*filepath"
+	registeredAt := time.Now()
+	var etags sync.Map // 文件名 -> ETag，内容不会变化，计算一次即可
+
+	handler := func(ctx context.Context, c *app.RequestContext) {
+		name := strings.TrimPrefix(path.Clean("/"+c.Param("filepath")), "/")
+		if name == "" {
+			name = "."
+		}
+
+		info, err := fs.Stat(fsys, name)
+		if err == nil && info.IsDir() {
+			name = path.Join(name, "index.html")
+			info, err = fs.Stat(fsys, name)
+		}
+		if err != nil || info.IsDir() {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		modTime := info.ModTime()
+		if modTime.IsZero() {
+			modTime = registeredAt
+		}
+		etag, ok := etags.Load(name)
+		if !ok {
+			sum := sha256.Sum256(data)
+			etag, _ = etags.LoadOrStore(name, fmt.Sprintf(`"%x"`, sum[:16]))
+		}
+
+		c.Header("Cache-Control", staticCacheControl)
+		c.Header("ETag", etag.(string))
+		c.Header("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+		if notModified(c, etag.(string), modTime) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		c.Data(http.StatusOK, contentType, data)
+	}
+
+	r.server.GET(pattern, handler)
+	r.server.HEAD(pattern, handler)
+
+	// 收集路由信息
+	r.addRoute("GET", pattern, "StaticEmbed("+subdir+")")
+	r.addRoute("HEAD", pattern, "StaticEmbed("+subdir+")")
+}
@@ -0,0 +1,135 @@
+package framework
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+)
+
+func TestSPA(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "index.html"), []byte("<div id=app></div>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "app.js"), []byte("console.log(1)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testApp := newTestApp()
+	testApp.Router.SPA("/app", root, "index.html")
+	engine := testApp.Server.Engine
+
+	w := ut.PerformRequest(engine, "GET", "/app/assets/app.js", nil)
+	if w.Code != 200 || w.Body.String() != "console.log(1)" {
+		t.Errorf("Expected existing asset to be served as-is, got %d %q", w.Code, w.Body.String())
+	}
+	if cacheControl := w.Header().Get("Cache-Control"); cacheControl != staticCacheControl {
+		t.Errorf("Expected asset cache policy, got %q", cacheControl)
+	}
+
+	// 前端路由的路径返回入口页面
+	for _, route := range []string{"/app/", "/app/users/42", "/app/settings/profile"} {
+		w := ut.PerformRequest(engine, "GET", route, nil)
+		if w.Code != 200 || w.Body.String() != "<div id=app></div>" {
+			t.Errorf("%s: expected index.html fallback, got %d %q", route, w.Code, w.Body.String())
+		}
+		if cacheControl := w.Header().Get("Cache-Control"); cacheControl != indexCacheControl {
+			t.Errorf("%s: expected index cache policy, got %q", route, cacheControl)
+		}
+	}
+
+	// 缺失的带扩展名的资源返回 404 而不是入口页面，路径穿越被限制在根目录内
+	for _, route := range []string{"/app/assets/missing.js", "/app/../static_test.go"} {
+		if w := ut.PerformRequest(engine, "GET", route, nil); w.Code != 404 {
+			t.Errorf("%s: expected 404, got %d", route, w.Code)
+		}
+	}
+}
+
+func TestStaticEmbedConditionalRequests(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"public/index.html":   {Data: []byte("<h1>home</h1>")},
+		"public/css/site.css": {Data: []byte("body{}"), ModTime: modTime},
+	}
+
+	testApp := newTestApp()
+	testApp.Router.StaticEmbed("/assets", fsys, "public")
+	engine := testApp.Server.Engine
+
+	w := ut.PerformRequest(engine, "GET", "/assets/css/site.css", nil)
+	if w.Code != 200 || w.Body.String() != "body{}" {
+		t.Fatalf("Expected embedded file, got %d %q", w.Code, w.Body.String())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") != modTime.Format(http.TimeFormat) {
+		t.Fatalf("Expected ETag and Last-Modified, got %q %q", etag, w.Header().Get("Last-Modified"))
+	}
+
+	tests := []struct {
+		name    string
+		headers []ut.Header
+		code    int
+	}{
+		{"matching etag", []ut.Header{{Key: "If-None-Match", Value: etag}}, 304},
+		{"weak etag in list", []ut.Header{{Key: "If-None-Match", Value: `"other", W/` + etag}}, 304},
+		{"wildcard etag", []ut.Header{{Key: "If-None-Match", Value: "*"}}, 304},
+		{"stale etag", []ut.Header{{Key: "If-None-Match", Value: `"other"`}}, 200},
+		{"not modified since", []ut.Header{{Key: "If-Modified-Since", Value: modTime.Format(http.TimeFormat)}}, 304},
+		{"modified since", []ut.Header{{Key: "If-Modified-Since", Value: modTime.Add(-time.Hour).Format(http.TimeFormat)}}, 200},
+		{"invalid date", []ut.Header{{Key: "If-Modified-Since", Value: "yesterday"}}, 200},
+		// If-None-Match 优先于 If-Modified-Since
+		{"etag takes precedence", []ut.Header{
+			{Key: "If-None-Match", Value: `"other"`},
+			{Key: "If-Modified-Since", Value: modTime.Format(http.TimeFormat)},
+		}, 200},
+	}
+
+	for _, tt := range tests {
+		w := ut.PerformRequest(engine, "GET", "/assets/css/site.css", nil, tt.headers...)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.code, w.Code)
+		}
+		if tt.code == 304 && len(w.Body.Bytes()) != 0 {
+			t.Errorf("%s: expected empty body for 304, got %q", tt.name, w.Body.String())
+		}
+	}
+
+	// 目录返回 index.html，没有修改时间的文件使用注册时间
+	w = ut.PerformRequest(engine, "GET", "/assets/", nil)
+	if w.Code != 200 || w.Body.String() != "<h1>home</h1>" || w.Header().Get("Last-Modified") == "" {
+		t.Errorf("Expected directory index, got %d %q", w.Code, w.Body.String())
+	}
+	if w := ut.PerformRequest(engine, "GET", "/assets/missing.css", nil); w.Code != 404 {
+		t.Errorf("Expected 404 for missing file, got %d", w.Code)
+	}
+}
+
+func TestServeStaticFileNotModified(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "index.html"), []byte("home"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testApp := newTestApp()
+	testApp.Router.SPA("/", root, "index.html")
+
+	w := ut.PerformRequest(testApp.Server.Engine, "GET", "/index.html", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != 200 || etag == "" {
+		t.Fatalf("Expected ETag on disk file, got %d %q", w.Code, etag)
+	}
+
+	w = ut.PerformRequest(testApp.Server.Engine, "GET", "/index.html", nil, ut.Header{Key: "If-None-Match", Value: etag})
+	if w.Code != 304 {
+		t.Errorf("Expected 304 for matching ETag, got %d", w.Code)
+	}
+}
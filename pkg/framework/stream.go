@@ -0,0 +1,51 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+)
+
+// jsonStreamFlushEvery 流式 JSON 响应最多缓冲的元素数量
+const jsonStreamFlushEvery = 100
+
+// JSONStream 以分块传输的方式逐个写出 JSON 数组元素，channel 关闭时结束数组
+// 已缓冲的元素写完或累计 jsonStreamFlushEvery 个元素时刷新到客户端，避免在内存中构建完整切片
+// 响应头在第一次写入前发送，出错后无法再修改状态码，此时停止读取 ch 并返回错误，
+// 生产者应通过请求 context 感知连接结束
+func (c *RequestContext) JSONStream(code int, ch <-chan interface{}) error {
+	c.SetStatusCode(code)
+	c.SetContentType("application/json; charset=utf-8")
+	c.Response.HijackWriter(resp.NewChunkedBodyWriter(&c.Response, c.GetWriter()))
+
+	if _, err := c.Write([]byte("[")); err != nil {
+		return err
+	}
+
+	count := 0
+	for item := range ch {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode stream item %d: %w", count, err)
+		}
+		if count > 0 {
+			data = append([]byte(","), data...)
+		}
+		if _, err := c.Write(data); err != nil {
+			return err
+		}
+		count++
+
+		if len(ch) == 0 || count%jsonStreamFlushEvery == 0 {
+			if err := c.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := c.Write([]byte("]")); err != nil {
+		return err
+	}
+	return c.Flush()
+}
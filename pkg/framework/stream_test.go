@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/test/mock"
+)
+
+// brokenConn 刷新时返回错误的连接，模拟客户端断开
+type brokenConn struct {
+	*mock.Conn
+}
+
+func (c *brokenConn) Flush() error {
+	return errors.New("broken pipe")
+}
+
+func TestJSONStream(t *testing.T) {
+	conn := mock.NewConn("")
+	c := app.NewContext(0)
+	c.SetConn(conn)
+
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+		for i := 0; i < jsonStreamFlushEvery+5; i++ {
+			ch <- map[string]int{"id": i}
+		}
+	}()
+
+	if err := NewRequestContext(c).JSONStream(200, ch); err != nil {
+		t.Fatalf("JSONStream error: %v", err)
+	}
+	// 处理器返回后由服务器写出结束块
+	c.Response.GetHijackWriter().Finalize()
+
+	recorder := conn.WriterRecorder()
+	raw, _ := recorder.Peek(recorder.WroteLen())
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if err != nil {
+		t.Fatalf("Invalid HTTP response: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Invalid chunked body: %v", err)
+	}
+
+	if resp.StatusCode != 200 || len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+		t.Errorf("Expected chunked 200 response, got %d %v", resp.StatusCode, resp.TransferEncoding)
+	}
+	if !bytes.HasPrefix(body, []byte(`[{"id":0},{"id":1},`)) || !bytes.HasSuffix(body, []byte(`{"id":104}]`)) {
+		t.Errorf("Unexpected stream body %s", body)
+	}
+}
+
+func TestJSONStreamEmpty(t *testing.T) {
+	conn := mock.NewConn("")
+	c := app.NewContext(0)
+	c.SetConn(conn)
+
+	ch := make(chan interface{})
+	close(ch)
+	if err := NewRequestContext(c).JSONStream(200, ch); err != nil {
+		t.Fatalf("JSONStream error: %v", err)
+	}
+	// 处理器返回后由服务器写出结束块
+	c.Response.GetHijackWriter().Finalize()
+
+	recorder := conn.WriterRecorder()
+	raw, _ := recorder.Peek(recorder.WroteLen())
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), nil)
+	if err != nil {
+		t.Fatalf("Invalid HTTP response: %v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "[]" {
+		t.Errorf("Expected empty array, got %s", body)
+	}
+}
+
+func TestJSONStreamClientDisconnect(t *testing.T) {
+	c := app.NewContext(0)
+	c.SetConn(&brokenConn{Conn: mock.NewConn("")})
+
+	ch := make(chan interface{})
+	stop := make(chan struct{})
+	sent := 0
+	go func() {
+		defer close(ch)
+		for i := 0; i < 10; i++ {
+			select {
+			case ch <- i:
+				sent++
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	err := NewRequestContext(c).JSONStream(200, ch)
+	close(stop)
+	if err == nil {
+		t.Fatal("Expected error when the client disconnects")
+	}
+	for range ch {
+	}
+	if sent == 10 {
+		t.Error("Expected JSONStream to stop reading after the write failed")
+	}
+
+	// 无法编码的元素返回错误
+	c = app.NewContext(0)
+	c.SetConn(mock.NewConn(""))
+	bad := make(chan interface{}, 1)
+	bad <- func() {}
+	close(bad)
+	if err := NewRequestContext(c).JSONStream(200, bad); err == nil {
+		t.Error("Expected encoding error")
+	}
+}
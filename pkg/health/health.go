@@ -2,7 +2,10 @@ package health
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -14,6 +17,7 @@ const (
 	StatusHealthy   Status = "healthy"
 	StatusDegraded  Status = "degraded"
 	StatusUnhealthy Status = "unhealthy"
+	StatusUnknown   Status = "unknown" // 依赖不健康，未执行检查
 )
 
 // CheckResult 健康检查结果
@@ -27,6 +31,54 @@ type CheckResult struct {
 	Details   map[string]interface{} `json:"details,omitempty"`
 }
 
+// checkResultJSON CheckResult 的 JSON 结构，耗时以毫秒和可读字符串输出
+type checkResultJSON struct {
+	Name       string                 `json:"name"`
+	Status     Status                 `json:"status"`
+	Message    string                 `json:"message,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Duration   string                 `json:"duration"`
+	DurationMS float64                `json:"duration_ms"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler，duration 输出为可读字符串（如 "12.5ms"），duration_ms 为毫秒数
+func (r CheckResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(checkResultJSON{
+		Name:       r.Name,
+		Status:     r.Status,
+		Message:    r.Message,
+		Error:      r.Error,
+		Timestamp:  r.Timestamp,
+		Duration:   r.Duration.String(),
+		DurationMS: float64(r.Duration) / float64(time.Millisecond),
+		Details:    r.Details,
+	})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，优先使用 duration_ms 还原耗时
+func (r *CheckResult) UnmarshalJSON(data []byte) error {
+	var v checkResultJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = CheckResult{
+		Name:      v.Name,
+		Status:    v.Status,
+		Message:   v.Message,
+		Error:     v.Error,
+		Timestamp: v.Timestamp,
+		Duration:  time.Duration(v.DurationMS * float64(time.Millisecond)),
+		Details:   v.Details,
+	}
+	if d, err := time.ParseDuration(v.Duration); err == nil {
+		r.Duration = d
+	}
+	return nil
+}
+
 // Checker 健康检查接口
 type Checker interface {
 	// Name 返回检查器名称
@@ -35,13 +87,23 @@ type Checker interface {
 	Check(ctx context.Context) CheckResult
 }
 
+// ErrDuplicateChecker 同名检查器已注册，检查结果按名称区分，名称必须唯一
+var ErrDuplicateChecker = errors.New("health checker already registered")
+
 // HealthChecker 健康检查器管理
 type HealthChecker struct {
 	checkers []Checker
+	deps     map[string][]string      // 检查器名称 -> 依赖的检查器名称
+	tags     map[string][]string      // 检查器名称 -> 标签
+	timeouts map[string]time.Duration // 检查器名称 -> 单独设置的超时时间
 	mu       sync.RWMutex
-	timeout  time.Duration
+	timeout  time.Duration // 默认超时时间
 	cache    map[string]*cachedResult
 	cacheTTL time.Duration
+
+	// 整体状态历史，通过 EnableHistory 开启
+	history     []HistoryEntry
+	historySize int
 }
 
 type cachedResult struct {
@@ -53,6 +115,9 @@ type cachedResult struct {
 func NewHealthChecker(timeout time.Duration) *HealthChecker {
 	return &HealthChecker{
 		checkers: make([]Checker, 0),
+		deps:     make(map[string][]string),
+		tags:     make(map[string][]string),
+		timeouts: make(map[string]time.Duration),
 		timeout:  timeout,
 		cache:    make(map[string]*cachedResult),
 		cacheTTL: 10 * time.Second,
@@ -60,19 +125,138 @@ func NewHealthChecker(timeout time.Duration) *HealthChecker {
 }
 
 // Register 注册健康检查
-func (h *HealthChecker) Register(checker Checker) {
+// timeout 可选，为该检查单独设置超时时间（如数据库 ping 比外部 HTTP 检查更短），未设置或 <= 0 时使用全局超时
+// 同名检查器已注册时返回 ErrDuplicateChecker
+func (h *HealthChecker) Register(checker Checker, timeout ...time.Duration) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+
+	if err := h.checkDuplicate(checker.Name()); err != nil {
+		return err
+	}
 	h.checkers = append(h.checkers, checker)
+	if len(timeout) > 0 && timeout[0] > 0 {
+		h.timeouts[checker.Name()] = timeout[0]
+	}
+	return nil
+}
+
+// checkDuplicate 检查名称是否已被注册，调用方需持有锁
+func (h *HealthChecker) checkDuplicate(name string) error {
+	for _, checker := range h.checkers {
+		if checker.Name() == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateChecker, name)
+		}
+	}
+	return nil
+}
+
+// checkerTimeout 返回检查器的超时时间，调用方需持有锁
+func (h *HealthChecker) checkerTimeout(name string) time.Duration {
+	if timeout, ok := h.timeouts[name]; ok {
+		return timeout
+	}
+	return h.timeout
+}
+
+// RegisterWithDeps 注册依赖其他检查的健康检查
+// 任一依赖不健康（unhealthy 或 unknown）时跳过该检查并标记为 unknown，避免级联告警
+// 依赖未注册时忽略该依赖，形成循环依赖或同名检查器已注册时返回错误
+func (h *HealthChecker) RegisterWithDeps(checker Checker, dependsOn ...string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := checker.Name()
+	if err := h.checkDuplicate(name); err != nil {
+		return err
+	}
+	for _, dep := range dependsOn {
+		if dep == name || h.dependsOn(dep, name, map[string]bool{}) {
+			return fmt.Errorf("health check %s: circular dependency on %s", name, dep)
+		}
+	}
+
+	h.checkers = append(h.checkers, checker)
+	h.deps[name] = append([]string{}, dependsOn...)
+	return nil
+}
+
+// RegisterTagged 注册带标签的健康检查，可通过 CheckByTag 只执行指定标签的检查
+// 同名检查器已注册时返回 ErrDuplicateChecker
+func (h *HealthChecker) RegisterTagged(checker Checker, tags ...string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.checkDuplicate(checker.Name()); err != nil {
+		return err
+	}
+	h.checkers = append(h.checkers, checker)
+	h.tags[checker.Name()] = append([]string{}, tags...)
+	return nil
+}
+
+// hasTag 检查器是否带有指定标签，调用方需持有锁
+func (h *HealthChecker) hasTag(name, tag string) bool {
+	for _, t := range h.tags[name] {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// dependsOn 检查 name 是否直接或间接依赖 target，调用方需持有锁
+func (h *HealthChecker) dependsOn(name, target string, visited map[string]bool) bool {
+	if visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	for _, dep := range h.deps[name] {
+		if dep == target || h.dependsOn(dep, target, visited) {
+			return true
+		}
+	}
+	return false
 }
 
 // Check 执行所有健康检查
+// 无依赖关系的检查并发执行，有依赖的检查等待其依赖完成后再执行
 func (h *HealthChecker) Check(ctx context.Context) map[string]CheckResult {
+	return h.check(ctx, "")
+}
+
+// CheckByTag 只执行带有指定标签的健康检查
+// 依赖未带该标签的检查时忽略该依赖；没有检查带该标签时返回空结果
+func (h *HealthChecker) CheckByTag(ctx context.Context, tag string) map[string]CheckResult {
+	return h.check(ctx, tag)
+}
+
+// check 执行健康检查，tag 非空时只执行带有该标签的检查
+func (h *HealthChecker) check(ctx context.Context, tag string) map[string]CheckResult {
 	h.mu.RLock()
-	checkers := make([]Checker, len(h.checkers))
-	copy(checkers, h.checkers)
+	checkers := make([]Checker, 0, len(h.checkers))
+	for _, checker := range h.checkers {
+		if tag == "" || h.hasTag(checker.Name(), tag) {
+			checkers = append(checkers, checker)
+		}
+	}
+	deps := make(map[string][]string, len(h.deps))
+	for name, d := range h.deps {
+		deps[name] = d
+	}
+	timeouts := make(map[string]time.Duration, len(checkers))
+	for _, checker := range checkers {
+		timeouts[checker.Name()] = h.checkerTimeout(checker.Name())
+	}
 	h.mu.RUnlock()
 
+	// 每个检查完成时关闭对应的 channel，供依赖方等待
+	done := make(map[string]chan struct{}, len(checkers))
+	for _, checker := range checkers {
+		done[checker.Name()] = make(chan struct{})
+	}
+
 	results := make(map[string]CheckResult)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -81,6 +265,36 @@ func (h *HealthChecker) Check(ctx context.Context) map[string]CheckResult {
 		wg.Add(1)
 		go func(c Checker) {
 			defer wg.Done()
+			defer close(done[c.Name()])
+
+			// 等待依赖完成，依赖不健康时跳过
+			var failed []string
+			for _, dep := range deps[c.Name()] {
+				ch, registered := done[dep]
+				if !registered {
+					continue
+				}
+				<-ch
+
+				mu.Lock()
+				status := results[dep].Status
+				mu.Unlock()
+				if status == StatusUnhealthy || status == StatusUnknown {
+					failed = append(failed, dep)
+				}
+			}
+
+			if len(failed) > 0 {
+				mu.Lock()
+				results[c.Name()] = CheckResult{
+					Name:      c.Name(),
+					Status:    StatusUnknown,
+					Message:   fmt.Sprintf("skipped: dependency %s is not healthy", strings.Join(failed, ", ")),
+					Timestamp: time.Now(),
+				}
+				mu.Unlock()
+				return
+			}
 
 			// Check cache
 			if cached := h.getCached(c.Name()); cached != nil {
@@ -91,7 +305,7 @@ func (h *HealthChecker) Check(ctx context.Context) map[string]CheckResult {
 			}
 
 			// Execute check with timeout
-			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			checkCtx, cancel := context.WithTimeout(ctx, timeouts[c.Name()])
 			defer cancel()
 
 			result := c.Check(checkCtx)
@@ -106,37 +320,51 @@ func (h *HealthChecker) Check(ctx context.Context) map[string]CheckResult {
 	}
 
 	wg.Wait()
+
+	// 只有完整检查的结果代表整体状态
+	if tag == "" {
+		h.recordHistory(OverallStatus(results), time.Now())
+	}
 	return results
 }
 
-// CheckOne 执行单个健康检查
+// CheckOne 执行单个健康检查（不检查依赖）
 func (h *HealthChecker) CheckOne(ctx context.Context, name string) (CheckResult, error) {
+	var target Checker
 	h.mu.RLock()
-	defer h.mu.RUnlock()
-
 	for _, checker := range h.checkers {
 		if checker.Name() == name {
-			// Check cache
-			if cached := h.getCached(name); cached != nil {
-				return *cached, nil
-			}
+			target = checker
+			break
+		}
+	}
+	timeout := h.checkerTimeout(name)
+	h.mu.RUnlock()
 
-			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
-			defer cancel()
+	if target == nil {
+		return CheckResult{}, fmt.Errorf("checker not found: %s", name)
+	}
 
-			result := checker.Check(checkCtx)
-			h.setCached(name, result)
-			return result, nil
-		}
+	// Check cache
+	if cached := h.getCached(name); cached != nil {
+		return *cached, nil
 	}
 
-	return CheckResult{}, fmt.Errorf("checker not found: %s", name)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := target.Check(checkCtx)
+	h.setCached(name, result)
+	return result, nil
 }
 
 // GetStatus 获取整体健康状态
 func (h *HealthChecker) GetStatus(ctx context.Context) Status {
-	results := h.Check(ctx)
+	return OverallStatus(h.Check(ctx))
+}
 
+// OverallStatus 根据检查结果计算整体健康状态
+func OverallStatus(results map[string]CheckResult) Status {
 	healthyCount := 0
 	degradedCount := 0
 	unhealthyCount := 0
@@ -168,11 +396,12 @@ func (h *HealthChecker) GetStatus(ctx context.Context) Status {
 // GetSummary 获取健康检查摘要
 func (h *HealthChecker) GetSummary(ctx context.Context) map[string]interface{} {
 	results := h.Check(ctx)
-	status := h.GetStatus(ctx)
+	status := OverallStatus(results)
 
 	healthyCount := 0
 	degradedCount := 0
 	unhealthyCount := 0
+	unknownCount := 0
 
 	for _, result := range results {
 		switch result.Status {
@@ -182,6 +411,8 @@ func (h *HealthChecker) GetSummary(ctx context.Context) map[string]interface{} {
 			degradedCount++
 		case StatusUnhealthy:
 			unhealthyCount++
+		case StatusUnknown:
+			unknownCount++
 		}
 	}
 
@@ -192,6 +423,7 @@ func (h *HealthChecker) GetSummary(ctx context.Context) map[string]interface{} {
 		"healthy_count":   healthyCount,
 		"degraded_count":  degradedCount,
 		"unhealthy_count": unhealthyCount,
+		"unknown_count":   unknownCount,
 		"checks":          results,
 	}
 }
@@ -2,7 +2,9 @@ package health
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -118,6 +120,23 @@ func TestHealthChecker_Register(t *testing.T) {
 	if _, exists := results["check2"]; !exists {
 		t.Error("Expected check2 in results")
 	}
+
+	// 同名检查器会导致结果互相覆盖，注册时拒绝
+	duplicate := NewSimpleChecker("check1", func(ctx context.Context) error {
+		return nil
+	})
+	if err := hc.Register(duplicate); !errors.Is(err, ErrDuplicateChecker) {
+		t.Errorf("Expected ErrDuplicateChecker from Register, got %v", err)
+	}
+	if err := hc.RegisterTagged(duplicate, "core"); !errors.Is(err, ErrDuplicateChecker) {
+		t.Errorf("Expected ErrDuplicateChecker from RegisterTagged, got %v", err)
+	}
+	if err := hc.RegisterWithDeps(duplicate, "check2"); !errors.Is(err, ErrDuplicateChecker) {
+		t.Errorf("Expected ErrDuplicateChecker from RegisterWithDeps, got %v", err)
+	}
+	if results := hc.Check(context.Background()); len(results) != 2 {
+		t.Errorf("Expected duplicates to be ignored, got %d results", len(results))
+	}
 }
 
 func TestHealthChecker_Check(t *testing.T) {
@@ -327,6 +346,34 @@ func TestHealthChecker_Timeout(t *testing.T) {
 	}
 }
 
+func TestHealthChecker_PerCheckerTimeout(t *testing.T) {
+	hc := NewHealthChecker(time.Second)
+
+	slow := func(ctx context.Context) error {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	hc.Register(NewSimpleChecker("database", slow), 50*time.Millisecond)
+	hc.Register(NewSimpleChecker("external", slow))
+
+	results := hc.Check(context.Background())
+	if results["database"].Status == StatusHealthy {
+		t.Error("Expected database check to fail with its own shorter timeout")
+	}
+	if results["external"].Status != StatusHealthy {
+		t.Errorf("Expected external check to use the global timeout, got %s", results["external"].Status)
+	}
+
+	hc.ClearCache()
+	if result, _ := hc.CheckOne(context.Background(), "database"); result.Status == StatusHealthy {
+		t.Error("Expected CheckOne to apply the per-checker timeout")
+	}
+}
+
 func TestConcurrentHealthChecks(t *testing.T) {
 	hc := NewHealthChecker(5 * time.Second)
 
@@ -353,3 +400,180 @@ func TestConcurrentHealthChecks(t *testing.T) {
 		t.Errorf("Expected 10 results, got %d", len(results))
 	}
 }
+
+func TestCheckResultJSON(t *testing.T) {
+	result := CheckResult{
+		Name:     "database",
+		Status:   StatusHealthy,
+		Duration: 1500 * time.Microsecond,
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"duration_ms":1.5`) {
+		t.Errorf("expected duration_ms in %s", data)
+	}
+	if !strings.Contains(string(data), `"duration":"1.5ms"`) {
+		t.Errorf("expected human readable duration in %s", data)
+	}
+
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Duration != result.Duration || decoded.Name != result.Name {
+		t.Errorf("round trip mismatch: %+v", decoded)
+	}
+}
+
+func TestHealthChecker_Dependencies(t *testing.T) {
+	hc := NewHealthChecker(time.Second)
+	hc.SetCacheTTL(0)
+
+	dbHealthy := false
+	cacheChecked := false
+	hc.Register(NewSimpleChecker("database", func(ctx context.Context) error {
+		if !dbHealthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+	if err := hc.RegisterWithDeps(NewSimpleChecker("cache", func(ctx context.Context) error {
+		cacheChecked = true
+		return nil
+	}), "database"); err != nil {
+		t.Fatalf("RegisterWithDeps failed: %v", err)
+	}
+
+	results := hc.Check(context.Background())
+	if results["cache"].Status != StatusUnknown || cacheChecked {
+		t.Errorf("expected cache to be skipped as unknown, got %+v", results["cache"])
+	}
+
+	summary := hc.GetSummary(context.Background())
+	if summary["unhealthy_count"] != 1 || summary["unknown_count"] != 1 {
+		t.Errorf("unexpected summary counts: %v", summary)
+	}
+
+	dbHealthy = true
+	results = hc.Check(context.Background())
+	if results["cache"].Status != StatusHealthy || !cacheChecked {
+		t.Errorf("expected cache to be checked once database is healthy, got %+v", results["cache"])
+	}
+
+	// 循环依赖
+	if err := hc.RegisterWithDeps(NewSimpleChecker("database_replica", func(ctx context.Context) error {
+		return nil
+	}), "database_replica"); err == nil {
+		t.Error("expected error for self dependency")
+	}
+	hc.RegisterWithDeps(NewSimpleChecker("a", func(ctx context.Context) error { return nil }), "b")
+	if err := hc.RegisterWithDeps(NewSimpleChecker("b", func(ctx context.Context) error { return nil }), "a"); err == nil {
+		t.Error("expected error for circular dependency")
+	}
+}
+
+func TestHealthChecker_CheckByTag(t *testing.T) {
+	hc := NewHealthChecker(time.Second)
+
+	hc.RegisterTagged(NewSimpleChecker("database", func(ctx context.Context) error {
+		return nil
+	}), "critical", "storage")
+	hc.RegisterTagged(NewSimpleChecker("disk", func(ctx context.Context) error {
+		return errors.New("disk full")
+	}), "storage")
+	hc.Register(NewSimpleChecker("cache", func(ctx context.Context) error {
+		return nil
+	}))
+
+	results := hc.CheckByTag(context.Background(), "critical")
+	if len(results) != 1 || results["database"].Status != StatusHealthy {
+		t.Errorf("expected only database for critical tag, got %v", results)
+	}
+	if status := OverallStatus(results); status != StatusHealthy {
+		t.Errorf("expected critical checks to be healthy, got %s", status)
+	}
+
+	results = hc.CheckByTag(context.Background(), "storage")
+	if len(results) != 2 || OverallStatus(results) != StatusUnhealthy {
+		t.Errorf("expected unhealthy storage checks, got %v", results)
+	}
+
+	if results := hc.CheckByTag(context.Background(), "missing"); len(results) != 0 {
+		t.Errorf("expected no results for unknown tag, got %v", results)
+	}
+	if results := hc.Check(context.Background()); len(results) != 3 {
+		t.Errorf("expected Check to run all 3 checks, got %d", len(results))
+	}
+}
+
+func TestHealthChecker_History(t *testing.T) {
+	hc := NewHealthChecker(time.Second)
+	hc.SetCacheTTL(0)
+
+	healthy := true
+	hc.Register(NewSimpleChecker("database", func(ctx context.Context) error {
+		if !healthy {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+
+	// 未开启时不记录
+	hc.Check(context.Background())
+	if history := hc.GetHistory(); len(history.Entries) != 0 || history.Uptime != 0 {
+		t.Fatalf("expected empty history before EnableHistory, got %+v", history)
+	}
+
+	hc.EnableHistory(4)
+	for i := 0; i < 5; i++ {
+		healthy = i != 2
+		hc.Check(context.Background())
+	}
+	hc.CheckByTag(context.Background(), "critical") // 按标签检查不计入历史
+
+	history := hc.GetHistory()
+	if len(history.Entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(history.Entries))
+	}
+	if history.Entries[1].Status != StatusUnhealthy || history.Entries[0].Status != StatusHealthy {
+		t.Errorf("unexpected entries: %+v", history.Entries)
+	}
+	if history.Uptime != 75 {
+		t.Errorf("expected 75%% uptime, got %v", history.Uptime)
+	}
+
+	// GetSummary 只执行一次完整检查
+	hc.GetSummary(context.Background())
+	if last := hc.GetHistory().Entries[3]; last.Status != StatusHealthy || len(hc.GetHistory().Entries) != 4 {
+		t.Errorf("unexpected history after summary: %+v", hc.GetHistory())
+	}
+
+	hc.EnableHistory(0)
+	if len(hc.GetHistory().Entries) != 0 {
+		t.Error("expected history to be cleared when disabled")
+	}
+}
+
+func TestHealthChecker_StartEvaluator(t *testing.T) {
+	hc := NewHealthChecker(time.Second)
+	hc.Register(NewSimpleChecker("ok", func(ctx context.Context) error { return nil }))
+	hc.EnableHistory(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hc.StartEvaluator(ctx, 10*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for len(hc.GetHistory().Entries) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	history := hc.GetHistory()
+	if len(history.Entries) < 3 || history.Uptime != 100 {
+		t.Errorf("expected evaluator to record healthy entries, got %+v", history)
+	}
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryEntry 一次完整检查的整体状态
+type HistoryEntry struct {
+	Status    Status    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// History 最近的整体状态历史
+type History struct {
+	Entries []HistoryEntry `json:"entries"` // 按时间升序
+	Uptime  float64        `json:"uptime"`  // 窗口内 healthy 或 degraded 的百分比，没有记录时为 0
+}
+
+// EnableHistory 保留最近 size 次完整检查（Check、GetStatus、GetSummary）的整体状态
+// size <= 0 时关闭并清空历史
+func (h *HealthChecker) EnableHistory(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.historySize = size
+	if size <= 0 {
+		h.history = nil
+		return
+	}
+	if len(h.history) > size {
+		h.history = h.history[len(h.history)-size:]
+	}
+}
+
+// GetHistory 获取状态历史及窗口内的可用率
+func (h *HealthChecker) GetHistory() History {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	entries := make([]HistoryEntry, len(h.history))
+	copy(entries, h.history)
+
+	history := History{Entries: entries}
+	if len(entries) > 0 {
+		up := 0
+		for _, entry := range entries {
+			if entry.Status == StatusHealthy || entry.Status == StatusDegraded {
+				up++
+			}
+		}
+		history.Uptime = float64(up) / float64(len(entries)) * 100
+	}
+	return history
+}
+
+// StartEvaluator 在后台每隔 interval 执行一次完整检查，为状态历史提供数据，ctx 结束时停止
+func (h *HealthChecker) StartEvaluator(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			h.Check(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// recordHistory 记录整体状态，未开启历史时忽略
+func (h *HealthChecker) recordHistory(status Status, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.historySize <= 0 {
+		return
+	}
+
+	h.history = append(h.history, HistoryEntry{Status: status, Timestamp: at})
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+}
@@ -4,18 +4,44 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/retry"
 )
 
 // Client HTTP客户端
 type Client struct {
-	client  *http.Client
-	baseURL string
-	headers map[string]string
+	client    *http.Client
+	baseURL   string
+	headers   map[string]string
+	transport *TransportConfig
+	retry     []retry.Option
+	errorBody reflect.Type // WithErrorBody 设置的错误响应体类型
+}
+
+// TransportConfig 连接池配置，零值字段使用 http.DefaultTransport 的默认值
+type TransportConfig struct {
+	MaxIdleConns    int
+	MaxConnsPerHost int
+	IdleConnTimeout time.Duration
 }
 
+// 相同连接池配置的客户端共享同一个 Transport，以复用连接
+var (
+	transports   = make(map[TransportConfig]*http.Transport)
+	transportsMu sync.Mutex
+)
+
 // ClientOption 客户端选项
 type ClientOption func(*Client)
 
@@ -33,9 +59,50 @@ func NewClient(options ...ClientOption) *Client {
 		option(client)
 	}
 
+	if client.transport != nil {
+		client.client.Transport = sharedTransport(*client.transport)
+	}
+
 	return client
 }
 
+// sharedTransport 获取连接池配置对应的共享 Transport
+func sharedTransport(config TransportConfig) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if transport, ok := transports[config]; ok {
+		return transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+		transport.MaxIdleConnsPerHost = config.MaxIdleConns
+	}
+	if config.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = config.MaxConnsPerHost
+		// 每个主机的空闲连接数不超过最大连接数
+		if transport.MaxIdleConnsPerHost == 0 || transport.MaxIdleConnsPerHost > config.MaxConnsPerHost {
+			transport.MaxIdleConnsPerHost = config.MaxConnsPerHost
+		}
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+
+	transports[config] = transport
+	return transport
+}
+
+// transportConfig 获取待应用的连接池配置
+func (c *Client) transportConfig() *TransportConfig {
+	if c.transport == nil {
+		c.transport = &TransportConfig{}
+	}
+	return c.transport
+}
+
 // WithTimeout 设置超时时间
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
@@ -57,6 +124,48 @@ func WithHeader(key, value string) ClientOption {
 	}
 }
 
+// WithMaxIdleConns 设置最大空闲连接数（同时作为每个主机的最大空闲连接数）
+func WithMaxIdleConns(n int) ClientOption {
+	return func(c *Client) {
+		c.transportConfig().MaxIdleConns = n
+	}
+}
+
+// WithMaxConnsPerHost 设置每个主机的最大连接数
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.transportConfig().MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout 设置空闲连接超时时间
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.transportConfig().IdleConnTimeout = timeout
+	}
+}
+
+// WithRetry 为幂等请求（GET/HEAD/OPTIONS/PUT/DELETE）启用自动重试
+// 网络错误和 429/502/503/504 响应会按 pkg/retry 的指数退避策略重试
+func WithRetry(maxAttempts int, opts ...retry.Option) ClientOption {
+	return func(c *Client) {
+		c.retry = append([]retry.Option{retry.WithMaxAttempts(maxAttempts)}, opts...)
+	}
+}
+
+// WithErrorBody 设置非 2xx 响应的错误响应体类型，v 必须是指针，如 &KuCoinError{}
+// 设置后 GetJSON 等方法遇到非 2xx 响应时返回 *ResponseError，响应体解析到 v 类型的新实例中，
+// v 本身只用于确定类型，并发请求不会互相覆盖；该类型实现 error 时可以直接通过 errors.As 取出
+func WithErrorBody(v interface{}) ClientOption {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		panic("http: WithErrorBody requires a pointer")
+	}
+	return func(c *Client) {
+		c.errorBody = t.Elem()
+	}
+}
+
 // Get 发送GET请求
 func (c *Client) Get(ctx context.Context, path string, headers map[string]string) (*http.Response, error) {
 	return c.Request(ctx, http.MethodGet, path, nil, headers)
@@ -77,23 +186,74 @@ func (c *Client) Delete(ctx context.Context, path string, headers map[string]str
 	return c.Request(ctx, http.MethodDelete, path, nil, headers)
 }
 
-// Request 发送请求
-func (c *Client) Request(ctx context.Context, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
-	url := path
-	if c.baseURL != "" {
-		url = c.baseURL + path
+// PostForm 发送 application/x-www-form-urlencoded 表单请求
+func (c *Client) PostForm(ctx context.Context, path string, values url.Values, headers map[string]string) (*http.Response, error) {
+	body := strings.NewReader(values.Encode())
+	return c.do(ctx, http.MethodPost, path, body, "application/x-www-form-urlencoded", headers)
+}
+
+// PostMultipart 发送 multipart/form-data 请求
+// files 的键为表单字段名，文件名取自 *os.File 等实现了 Name() 的 reader，否则使用字段名
+func (c *Client) PostMultipart(ctx context.Context, path string, fields map[string]string, files map[string]io.Reader, headers map[string]string) (*http.Response, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, err
+		}
 	}
 
-	var bodyReader io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
+	for name, file := range files {
+		filename := name
+		if named, ok := file.(interface{ Name() string }); ok {
+			filename = filepath.Base(named.Name())
+		}
+
+		part, err := writer.CreateFormFile(name, filename)
 		if err != nil {
 			return nil, err
 		}
-		bodyReader = bytes.NewReader(jsonBody)
+		if _, err := io.Copy(part, file); err != nil {
+			return nil, fmt.Errorf("failed to read multipart file %s: %w", name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// multipart 的 Content-Type 必须携带 boundary，覆盖调用方设置的值
+	merged := make(map[string]string, len(headers)+1)
+	for key, value := range headers {
+		merged[key] = value
+	}
+	merged["Content-Type"] = writer.FormDataContentType()
+
+	return c.do(ctx, http.MethodPost, path, &body, "", merged)
+}
+
+// Request 发送请求，请求体编码为 JSON
+func (c *Client) Request(ctx context.Context, method, path string, body interface{}, headers map[string]string) (*http.Response, error) {
+	if body == nil {
+		return c.do(ctx, method, path, nil, "", headers)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, method, path, bytes.NewReader(jsonBody), "application/json", headers)
+}
+
+// do 发送请求，请求头未设置 Content-Type 时使用 contentType
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, contentType string, headers map[string]string) (*http.Response, error) {
+	reqURL := path
+	if c.baseURL != "" {
+		reqURL = c.baseURL + path
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -108,12 +268,82 @@ func (c *Client) Request(ctx context.Context, method, path string, body interfac
 		req.Header.Set(key, value)
 	}
 
-	// 如果有请求体，设置Content-Type
-	if body != nil && req.Header.Get("Content-Type") == "" {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
-	return c.client.Do(req)
+	if c.retry == nil || !idempotentMethods[method] {
+		return c.client.Do(req)
+	}
+	return c.doWithRetry(ctx, req)
+}
+
+// idempotentMethods 允许自动重试的幂等请求方法，POST 等请求重试可能导致重复下单等副作用
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// statusError 可重试的响应状态码
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("retryable status code: %d", e.code)
+}
+
+// isRetryableStatus 判断响应状态码是否可重试
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// doWithRetry 按重试策略发送请求，网络错误和 429/502/503/504 响应会重试
+// 重试耗尽时返回最后一次的响应，由调用方处理状态码
+func (c *Client) doWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	err := retry.Do(ctx, func() error {
+		if resp != nil {
+			resp.Body.Close()
+			resp = nil
+		}
+
+		attemptReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return retry.Permanent(err)
+			}
+			attemptReq.Body = body
+		}
+
+		r, err := c.client.Do(attemptReq)
+		if err != nil {
+			return err
+		}
+
+		resp = r
+		if isRetryableStatus(r.StatusCode) {
+			return &statusError{code: r.StatusCode}
+		}
+		return nil
+	}, c.retry...)
+
+	var retryable *statusError
+	if resp != nil && (err == nil || errors.As(err, &retryable)) {
+		return resp, nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil, err
 }
 
 // GetJSON 发送GET请求并解析JSON响应
@@ -122,9 +352,7 @@ func (c *Client) GetJSON(ctx context.Context, path string, headers map[string]st
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	return json.NewDecoder(resp.Body).Decode(v)
+	return c.decodeJSON(resp, v)
 }
 
 // PostJSON 发送POST请求并解析JSON响应
@@ -133,9 +361,7 @@ func (c *Client) PostJSON(ctx context.Context, path string, body interface{}, he
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	return json.NewDecoder(resp.Body).Decode(v)
+	return c.decodeJSON(resp, v)
 }
 
 // PutJSON 发送PUT请求并解析JSON响应
@@ -144,9 +370,7 @@ func (c *Client) PutJSON(ctx context.Context, path string, body interface{}, hea
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	return json.NewDecoder(resp.Body).Decode(v)
+	return c.decodeJSON(resp, v)
 }
 
 // DeleteJSON 发送DELETE请求并解析JSON响应
@@ -155,7 +379,88 @@ func (c *Client) DeleteJSON(ctx context.Context, path string, headers map[string
 	if err != nil {
 		return err
 	}
+	return c.decodeJSON(resp, v)
+}
+
+// ResponseError 非 2xx 响应错误，Detail 为按 WithErrorBody 类型解析的响应体，无法解析时为 nil
+type ResponseError struct {
+	StatusCode int
+	Body       []byte
+	Detail     interface{}
+}
+
+func (e *ResponseError) Error() string {
+	if err, ok := e.Detail.(error); ok {
+		return fmt.Sprintf("http status %d: %v", e.StatusCode, err)
+	}
+	return fmt.Sprintf("http status %d: %s", e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+// Unwrap 错误响应体类型实现 error 时返回该错误
+func (e *ResponseError) Unwrap() error {
+	err, _ := e.Detail.(error)
+	return err
+}
+
+// decodeJSON 解析JSON响应，设置了 WithErrorBody 时非 2xx 响应返回 *ResponseError
+func (c *Client) decodeJSON(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	if c.errorBody == nil || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error response (status %d): %w", resp.StatusCode, err)
+	}
+
+	respErr := &ResponseError{StatusCode: resp.StatusCode, Body: body}
+	detail := reflect.New(c.errorBody).Interface()
+	if json.Unmarshal(body, detail) == nil {
+		respErr.Detail = detail
+	}
+	return respErr
+}
+
+// Response 带类型响应体的响应，保留状态码和响应头
+type Response[T any] struct {
+	Status  int
+	Headers http.Header
+	Body    T
+}
+
+// GetInto 发送GET请求并将JSON响应解析为 T，同时返回状态码和响应头（例如分页游标）
+// 非 2xx 响应同样会尝试解析响应体，由调用方根据 Status 判断
+func GetInto[T any](ctx context.Context, c *Client, path string, headers map[string]string) (*Response[T], error) {
+	resp, err := c.Get(ctx, path, headers)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInto[T](resp)
+}
+
+// PostInto 发送POST请求并将JSON响应解析为 T，同时返回状态码和响应头
+func PostInto[T any](ctx context.Context, c *Client, path string, body interface{}, headers map[string]string) (*Response[T], error) {
+	resp, err := c.Post(ctx, path, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInto[T](resp)
+}
+
+// decodeInto 解析响应，空响应体时 Body 为零值
+func decodeInto[T any](resp *http.Response) (*Response[T], error) {
 	defer resp.Body.Close()
 
-	return json.NewDecoder(resp.Body).Decode(v)
+	result := &Response[T]{
+		Status:  resp.StatusCode,
+		Headers: resp.Header,
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result.Body); err != nil && err != io.EOF {
+		return result, fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+
+	return result, nil
 }
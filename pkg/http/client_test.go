@@ -0,0 +1,221 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/retry"
+)
+
+func TestSharedTransport(t *testing.T) {
+	a := NewClient(WithMaxIdleConns(50), WithMaxConnsPerHost(20), WithIdleConnTimeout(time.Minute))
+	b := NewClient(WithMaxConnsPerHost(20), WithIdleConnTimeout(time.Minute), WithMaxIdleConns(50))
+	c := NewClient(WithMaxIdleConns(10))
+
+	transport, ok := a.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("expected custom transport")
+	}
+	if b.client.Transport != transport {
+		t.Error("expected clients with the same config to share a transport")
+	}
+	if c.client.Transport == transport {
+		t.Error("expected clients with different config to use separate transports")
+	}
+
+	if transport.MaxIdleConns != 50 || transport.MaxConnsPerHost != 20 || transport.MaxIdleConnsPerHost != 20 {
+		t.Errorf("unexpected pool settings: idle=%d perHost=%d idlePerHost=%d",
+			transport.MaxIdleConns, transport.MaxConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != time.Minute {
+		t.Errorf("unexpected idle timeout %v", transport.IdleConnTimeout)
+	}
+
+	if NewClient().client.Transport != nil {
+		t.Error("expected default transport without pool options")
+	}
+}
+
+func TestPostFormAndMultipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/form":
+			if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+				t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+			}
+			r.ParseForm()
+			if r.PostForm.Get("symbol") != "BTC-USD" {
+				t.Errorf("unexpected form %v", r.PostForm)
+			}
+		case "/upload":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("ParseMultipartForm failed: %v", err)
+			}
+			if r.FormValue("title") != "report" {
+				t.Errorf("unexpected field %q", r.FormValue("title"))
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("FormFile failed: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			if string(data) != "hello" || header.Filename != "file" {
+				t.Errorf("unexpected file %q (%s)", data, header.Filename)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	resp, err := client.PostForm(ctx, "/form", url.Values{"symbol": {"BTC-USD"}}, nil)
+	if err != nil {
+		t.Fatalf("PostForm failed: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = client.PostMultipart(ctx, "/upload",
+		map[string]string{"title": "report"},
+		map[string]io.Reader{"file": strings.NewReader("hello")},
+		map[string]string{"Content-Type": "application/json"},
+	)
+	if err != nil {
+		t.Fatalf("PostMultipart failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestGetInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cb-After", "cursor-2")
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `{"id":"1","name":"btc"}`)
+	}))
+	defer server.Close()
+
+	type item struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	resp, err := GetInto[item](context.Background(), NewClient(WithBaseURL(server.URL)), "/items", nil)
+	if err != nil {
+		t.Fatalf("GetInto failed: %v", err)
+	}
+	if resp.Status != http.StatusAccepted || resp.Headers.Get("Cb-After") != "cursor-2" {
+		t.Errorf("unexpected status/headers: %d %v", resp.Status, resp.Headers)
+	}
+	if resp.Body.ID != "1" || resp.Body.Name != "btc" {
+		t.Errorf("unexpected body %+v", resp.Body)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"a":1}` {
+				t.Errorf("unexpected body on attempt %d: %q", calls, body)
+			}
+		}
+		if calls%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetry(3, retry.WithBackoff(time.Millisecond, time.Millisecond)),
+	)
+	ctx := context.Background()
+
+	// 幂等请求重试，每次重试重新发送请求体
+	resp, err := client.Put(ctx, "/", map[string]int{"a": 1}, nil)
+	if err != nil || resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("Put() = %v, %v after %d calls", resp, err, calls)
+	}
+	resp.Body.Close()
+
+	// POST 不重试
+	calls = 0
+	resp, err = client.Post(ctx, "/", nil, nil)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || calls != 1 {
+		t.Fatalf("Post() = %v, %v after %d calls", resp, err, calls)
+	}
+	resp.Body.Close()
+
+	// 重试耗尽返回最后一次响应
+	client = NewClient(WithBaseURL(server.URL), WithRetry(2, retry.WithBackoff(time.Millisecond, time.Millisecond)))
+	calls = 0
+	resp, err = client.Get(ctx, "/", nil)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || calls != 2 {
+		t.Fatalf("Get() = %v, %v after %d calls", resp, err, calls)
+	}
+	resp.Body.Close()
+}
+
+type testAPIError struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (e *testAPIError) Error() string {
+	return e.Code + ": " + e.Msg
+}
+
+func TestWithErrorBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			io.WriteString(w, `{"code":"200000"}`)
+		case "/text":
+			w.WriteHeader(http.StatusBadGateway)
+			io.WriteString(w, "bad gateway")
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"code":"400100","msg":"invalid symbol"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithErrorBody(&testAPIError{}))
+
+	var ok map[string]string
+	if err := client.GetJSON(context.Background(), "/ok", nil, &ok); err != nil || ok["code"] != "200000" {
+		t.Fatalf("expected successful decode, got %v %v", ok, err)
+	}
+
+	err := client.GetJSON(context.Background(), "/order", nil, &ok)
+	var apiErr *testAPIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "400100" || apiErr.Msg != "invalid symbol" {
+		t.Fatalf("expected structured api error, got %v", err)
+	}
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected ResponseError with status 400, got %v", err)
+	}
+
+	err = client.GetJSON(context.Background(), "/text", nil, &ok)
+	if !errors.As(err, &respErr) || respErr.Detail != nil || string(respErr.Body) != "bad gateway" {
+		t.Errorf("expected raw body for undecodable error, got %v", err)
+	}
+
+	// 未设置错误响应体类型时保持原有行为
+	if err := NewClient(WithBaseURL(server.URL)).GetJSON(context.Background(), "/order", nil, &ok); err != nil || ok["msg"] != "invalid symbol" {
+		t.Errorf("expected body decoded without WithErrorBody, got %v %v", ok, err)
+	}
+}
@@ -0,0 +1,41 @@
+package log
+
+import "context"
+
+// contextKey context 中 Logger 的键
+type contextKey struct{}
+
+// WithContext 将 Logger 附加到 context
+func WithContext(ctx context.Context, logger StructuredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext 获取 context 中的 Logger，不存在时返回全局 Logger
+func FromContext(ctx context.Context) StructuredLogger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(StructuredLogger); ok && logger != nil {
+			return logger
+		}
+	}
+	return Default()
+}
+
+// WithFields 在 context 的 Logger 上追加字段并返回新的 context
+func WithFields(ctx context.Context, fields ...interface{}) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// WithRequestID 追加 request_id 字段
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithFields(ctx, "request_id", requestID)
+}
+
+// WithJobID 追加队列任务字段
+func WithJobID(ctx context.Context, jobID, jobType string) context.Context {
+	return WithFields(ctx, "job_id", jobID, "job_type", jobType)
+}
+
+// WithTaskID 追加调度任务字段
+func WithTaskID(ctx context.Context, taskID, taskName string) context.Context {
+	return WithFields(ctx, "task_id", taskID, "task_name", taskName)
+}
@@ -17,20 +17,30 @@ type Config struct {
 	TimeFormat string
 }
 
-// Logger 日志管理器
-type Logger struct {
+// Manager 日志管理器，负责配置 hlog 及全局结构化 Logger
+type Manager struct {
 	Config *Config
 }
 
-// NewLogger 创建一个新的日志管理器
-func NewLogger(config *Config) *Logger {
-	return &Logger{
+// NewManager 创建一个新的日志管理器
+func NewManager(config *Config) *Manager {
+	return &Manager{
 		Config: config,
 	}
 }
 
+// Logger 日志管理器的旧名称
+//
+// Deprecated: 使用 Manager，结构化日志接口为 StructuredLogger
+type Logger = Manager
+
+// NewLogger 创建日志管理器的旧名称
+//
+// Deprecated: 使用 NewManager
+var NewLogger = NewManager
+
 // Init 初始化日志
-func (l *Logger) Init() error {
+func (l *Manager) Init() error {
 	// 设置日志级别
 	level := hlog.LevelInfo
 	switch l.Config.Level {
@@ -81,55 +91,58 @@ func (l *Logger) Init() error {
 		l.Config.TimeFormat = "2006-01-02 15:04:05"
 	}
 
+	// 设置全局结构化 Logger
+	SetDefault(New(output, ParseLevel(l.Config.Level), l.Config.Format))
+
 	return nil
 }
 
 // Debug 调试日志
-func (l *Logger) Debug(args ...interface{}) {
+func (l *Manager) Debug(args ...interface{}) {
 	hlog.Debug(args...)
 }
 
 // Debugf 格式化调试日志
-func (l *Logger) Debugf(format string, args ...interface{}) {
+func (l *Manager) Debugf(format string, args ...interface{}) {
 	hlog.Debugf(format, args...)
 }
 
 // Info 信息日志
-func (l *Logger) Info(args ...interface{}) {
+func (l *Manager) Info(args ...interface{}) {
 	hlog.Info(args...)
 }
 
 // Infof 格式化信息日志
-func (l *Logger) Infof(format string, args ...interface{}) {
+func (l *Manager) Infof(format string, args ...interface{}) {
 	hlog.Infof(format, args...)
 }
 
 // Warn 警告日志
-func (l *Logger) Warn(args ...interface{}) {
+func (l *Manager) Warn(args ...interface{}) {
 	hlog.Warn(args...)
 }
 
 // Warnf 格式化警告日志
-func (l *Logger) Warnf(format string, args ...interface{}) {
+func (l *Manager) Warnf(format string, args ...interface{}) {
 	hlog.Warnf(format, args...)
 }
 
 // Error 错误日志
-func (l *Logger) Error(args ...interface{}) {
+func (l *Manager) Error(args ...interface{}) {
 	hlog.Error(args...)
 }
 
 // Errorf 格式化错误日志
-func (l *Logger) Errorf(format string, args ...interface{}) {
+func (l *Manager) Errorf(format string, args ...interface{}) {
 	hlog.Errorf(format, args...)
 }
 
 // Fatal 致命错误日志
-func (l *Logger) Fatal(args ...interface{}) {
+func (l *Manager) Fatal(args ...interface{}) {
 	hlog.Fatal(args...)
 }
 
 // Fatalf 格式化致命错误日志
-func (l *Logger) Fatalf(format string, args ...interface{}) {
+func (l *Manager) Fatalf(format string, args ...interface{}) {
 	hlog.Fatalf(format, args...)
 }
@@ -0,0 +1,253 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level 日志级别
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String 返回日志级别名称
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// ParseLevel 解析日志级别，无法识别时返回 LevelInfo
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error", "fatal":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+// StructuredLogger 结构化日志接口
+// fields 为交替出现的键值对，例如 Info("job done", "job_id", id, "duration", d)
+type StructuredLogger interface {
+	Debug(msg string, fields ...interface{})
+	Info(msg string, fields ...interface{})
+	Warn(msg string, fields ...interface{})
+	Error(msg string, fields ...interface{})
+	// With 返回携带固定字段的子 Logger
+	With(fields ...interface{}) StructuredLogger
+}
+
+// Field 日志字段
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Entry 日志条目
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// encoder 日志编码函数
+type encoder func(buf *bytes.Buffer, entry *Entry)
+
+// logger Logger 的默认实现
+type logger struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	level  Level
+	encode encoder
+	fields []Field
+}
+
+// New 根据格式创建 Logger，format 为 json 时输出 JSON，其余输出控制台格式
+func New(out io.Writer, level Level, format string) StructuredLogger {
+	if format == "json" {
+		return NewJSONLogger(out, level)
+	}
+	return NewConsoleLogger(out, level)
+}
+
+// NewJSONLogger 创建每行一个 JSON 对象的 Logger，适合日志采集
+func NewJSONLogger(out io.Writer, level Level) StructuredLogger {
+	return &logger{out: out, mu: &sync.Mutex{}, level: level, encode: encodeJSON}
+}
+
+// NewConsoleLogger 创建便于阅读的控制台 Logger
+func NewConsoleLogger(out io.Writer, level Level) StructuredLogger {
+	return &logger{out: out, mu: &sync.Mutex{}, level: level, encode: encodeConsole}
+}
+
+// Debug 调试日志
+func (l *logger) Debug(msg string, fields ...interface{}) {
+	l.log(LevelDebug, msg, fields)
+}
+
+// Info 信息日志
+func (l *logger) Info(msg string, fields ...interface{}) {
+	l.log(LevelInfo, msg, fields)
+}
+
+// Warn 警告日志
+func (l *logger) Warn(msg string, fields ...interface{}) {
+	l.log(LevelWarn, msg, fields)
+}
+
+// Error 错误日志
+func (l *logger) Error(msg string, fields ...interface{}) {
+	l.log(LevelError, msg, fields)
+}
+
+// With 返回携带固定字段的子 Logger，与父 Logger 共享输出
+func (l *logger) With(fields ...interface{}) StructuredLogger {
+	child := *l
+	child.fields = append(append([]Field{}, l.fields...), toFields(fields)...)
+	return &child
+}
+
+// log 编码并写出日志条目
+func (l *logger) log(level Level, msg string, fields []interface{}) {
+	if level < l.level {
+		return
+	}
+
+	entry := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Fields:  append(append([]Field{}, l.fields...), toFields(fields)...),
+	}
+
+	var buf bytes.Buffer
+	l.encode(&buf, entry)
+	buf.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(buf.Bytes())
+}
+
+// toFields 将键值对转换为字段，缺少值的键记为 !BADKEY
+func toFields(kv []interface{}) []Field {
+	fields := make([]Field, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 >= len(kv) {
+			fields = append(fields, Field{Key: "!BADKEY", Value: kv[i]})
+			break
+		}
+
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		fields = append(fields, Field{Key: key, Value: kv[i+1]})
+	}
+	return fields
+}
+
+// fieldValue 规范化字段值，error 和 Stringer 转为字符串
+func fieldValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case error:
+		return val.Error()
+	case time.Duration:
+		return val.String()
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return v
+	}
+}
+
+// encodeJSON 编码为 JSON，字段顺序为 time、level、msg 及自定义字段
+func encodeJSON(buf *bytes.Buffer, entry *Entry) {
+	buf.WriteString(`{"time":`)
+	writeJSON(buf, entry.Time.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(buf, entry.Level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(buf, entry.Message)
+	for _, field := range entry.Fields {
+		buf.WriteByte(',')
+		writeJSON(buf, field.Key)
+		buf.WriteByte(':')
+		writeJSON(buf, fieldValue(field.Value))
+	}
+	buf.WriteByte('}')
+}
+
+// writeJSON 写入 JSON 值，无法序列化时写入其字符串形式
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	buf.Write(data)
+}
+
+// encodeConsole 编码为 "时间 级别 消息 key=value" 格式
+func encodeConsole(buf *bytes.Buffer, entry *Entry) {
+	buf.WriteString(entry.Time.Format("2006-01-02 15:04:05"))
+	buf.WriteByte(' ')
+	fmt.Fprintf(buf, "%-5s", strings.ToUpper(entry.Level.String()))
+	buf.WriteByte(' ')
+	buf.WriteString(entry.Message)
+	for _, field := range entry.Fields {
+		buf.WriteByte(' ')
+		buf.WriteString(field.Key)
+		buf.WriteByte('=')
+		s := fmt.Sprint(fieldValue(field.Value))
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = strconv.Quote(s)
+		}
+		buf.WriteString(s)
+	}
+}
+
+var (
+	defaultLogger StructuredLogger = NewConsoleLogger(os.Stdout, LevelInfo)
+	defaultMu     sync.RWMutex
+)
+
+// Default 获取全局 Logger
+func Default() StructuredLogger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault 设置全局 Logger
+func SetDefault(l StructuredLogger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
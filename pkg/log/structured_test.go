@@ -0,0 +1,77 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf, LevelInfo).With("service", "queue")
+
+	logger.Debug("hidden")
+	logger.Error("job failed", "job_id", "job_1", "attempts", 2, "error", errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line, got %d: %q", len(lines), buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"level":    "error",
+		"msg":      "job failed",
+		"service":  "queue",
+		"job_id":   "job_1",
+		"attempts": float64(2),
+		"error":    "boom",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+}
+
+func TestConsoleLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewConsoleLogger(&buf, LevelDebug)
+
+	logger.Warn("queue full", "listener", "send mail", "odd")
+
+	line := buf.String()
+	for _, want := range []string{"WARN", "queue full", `listener="send mail"`, "!BADKEY=odd"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+}
+
+func TestContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithContext(context.Background(), NewJSONLogger(&buf, LevelInfo))
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithJobID(ctx, "job_1", "SendMail")
+
+	FromContext(ctx).Info("processing")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if entry["request_id"] != "req-1" || entry["job_id"] != "job_1" || entry["job_type"] != "SendMail" {
+		t.Errorf("Expected context fields in entry, got %v", entry)
+	}
+
+	if FromContext(context.Background()) != Default() {
+		t.Error("Expected default logger for context without logger")
+	}
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"fmt"
+	"time"
+)
+
+// autoScaleInterval 自动扩缩容检查队列深度的间隔
+var autoScaleInterval = 5 * time.Second
+
+// autoScale 自动扩缩容配置
+type autoScale struct {
+	min         int
+	max         int
+	targetDepth int // 每个工作进程期望承担的待执行任务数
+}
+
+// EnableAutoScale 按队列积压自动调整工作进程数量，替代 SetWorkers
+// Work 启动 min 个常驻工作进程，之后定期读取监听队列的待执行任务数，
+// 按每个工作进程承担 targetDepthPerWorker 个任务计算所需数量，在 [min, max] 内补充工作进程；
+// 超出 min 的工作进程在一轮轮询没有获取到任务时退出。驱动需实现 DepthReporter
+func (q *Queue) EnableAutoScale(min, max, targetDepthPerWorker int) *Queue {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	if targetDepthPerWorker < 1 {
+		targetDepthPerWorker = 1
+	}
+
+	q.autoScale = &autoScale{min: min, max: max, targetDepth: targetDepthPerWorker}
+	q.workers = min
+	return q
+}
+
+// ActiveWorkers 返回运行中的工作进程数量
+func (q *Queue) ActiveWorkers() int {
+	return int(q.activeWorkers.Load())
+}
+
+// runAutoScale 后台按队列深度补充工作进程，直到队列停止
+func (q *Queue) runAutoScale(reporter DepthReporter) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(autoScaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+			pending, err := q.pendingDepth(reporter)
+			if err != nil {
+				q.logger.Error("failed to read queue depth for auto scaling", "error", err)
+				continue
+			}
+			q.scaleTo(q.desiredWorkers(pending))
+		}
+	}
+}
+
+// pendingDepth 返回所有监听队列的待执行任务总数
+func (q *Queue) pendingDepth(reporter DepthReporter) (int64, error) {
+	var total int64
+	for _, queueName := range q.workerQueues {
+		pending, _, _, err := reporter.GetQueueDepth(queueName)
+		if err != nil {
+			return 0, fmt.Errorf("queue %s: %w", queueName, err)
+		}
+		total += pending
+	}
+	return total, nil
+}
+
+// desiredWorkers 按积压计算所需的工作进程数量，限制在 [min, max] 内
+func (q *Queue) desiredWorkers(pending int64) int {
+	config := q.autoScale
+	desired := int((pending + int64(config.targetDepth) - 1) / int64(config.targetDepth))
+	if desired < config.min {
+		desired = config.min
+	}
+	if desired > config.max {
+		desired = config.max
+	}
+	return desired
+}
+
+// scaleTo 补充临时工作进程直到运行中的数量达到 desired，缩容由临时工作进程空闲时自行退出
+func (q *Queue) scaleTo(desired int) {
+	active := q.ActiveWorkers()
+	if desired <= active || q.ctx.Err() != nil {
+		return
+	}
+
+	q.logger.Info("queue workers scaling up", "from", active, "to", desired)
+	for i := active; i < desired; i++ {
+		q.startWorker(true)
+	}
+}
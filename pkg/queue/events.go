@@ -0,0 +1,47 @@
+package queue
+
+import (
+	"time"
+)
+
+// 队列事件名称，设置 WithDispatcher 后触发
+const (
+	JobCompletedEventName = "job.completed"
+	JobFailedEventName    = "job.failed"
+	JobDeadEventName      = "job.dead"
+)
+
+// JobCompletedEvent 任务执行成功事件
+type JobCompletedEvent struct {
+	Job      JobRecord
+	Duration time.Duration
+}
+
+// EventName 实现 event.Event 接口
+func (e *JobCompletedEvent) EventName() string {
+	return JobCompletedEventName
+}
+
+// JobFailedEvent 任务执行失败事件，每次失败都会触发，WillRetry 表示是否会重试
+type JobFailedEvent struct {
+	Job       JobRecord
+	Error     string
+	WillRetry bool
+	Duration  time.Duration
+}
+
+// EventName 实现 event.Event 接口
+func (e *JobFailedEvent) EventName() string {
+	return JobFailedEventName
+}
+
+// JobDeadEvent 任务进入死信队列事件（超过最大重试次数或没有处理器），可用于告警
+type JobDeadEvent struct {
+	Job   JobRecord
+	Error string
+}
+
+// EventName 实现 event.Event 接口
+func (e *JobDeadEvent) EventName() string {
+	return JobDeadEventName
+}
@@ -1,19 +1,32 @@
 package queue
 
 import (
+	"container/heap"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 )
 
+// ErrDriverClosed 驱动已关闭，不再接受任务
+var ErrDriverClosed = errors.New("queue driver is closed")
+
 // MemoryDriver 内存队列驱动（用于测试和开发）
 type MemoryDriver struct {
 	jobs    map[string]*JobRecord
 	queues  map[string][]*JobRecord // queue name -> jobs
 	mu      sync.RWMutex
 	signals map[string]chan struct{} // queue name -> signal channel
+
+	// 延迟任务按到期时间保存在最小堆中，由一个定时 goroutine 在最早到期时移入队列
+	delayed delayedJobs
+	wake    chan struct{} // 堆顶变化时唤醒定时 goroutine
+	stop    chan struct{} // 关闭时停止定时 goroutine，首个延迟任务推送时创建
+	closed  bool          // Close 之后拒绝推送、重试和获取任务
+
+	memoryRecurring // 周期任务定义仅保存在内存中，进程重启后需重新调用 Queue.Schedule
 }
 
 // NewMemoryDriver 创建内存驱动
@@ -35,6 +48,10 @@ func (d *MemoryDriver) PushDelay(job Job, delay time.Duration) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if d.closed {
+		return ErrDriverClosed
+	}
+
 	payload, err := MarshalJob(job)
 	if err != nil {
 		return err
@@ -46,7 +63,7 @@ func (d *MemoryDriver) PushDelay(job Job, delay time.Duration) error {
 	record := &JobRecord{
 		ID:          job.GetID(),
 		Queue:       job.GetQueue(),
-		JobType:     fmt.Sprintf("%T", job),
+		JobType:     jobTypeOf(job),
 		Payload:     payload,
 		Status:      StatusPending,
 		Attempts:    0,
@@ -57,21 +74,64 @@ func (d *MemoryDriver) PushDelay(job Job, delay time.Duration) error {
 	}
 
 	d.jobs[record.ID] = record
+	d.schedule(record)
 
-	// 如果不是延迟任务，立即加入队列
-	if delay == 0 {
+	return nil
+}
+
+// schedule 到期的任务立即加入队列，未到期的放入延迟堆（内部方法，需要持有锁）
+func (d *MemoryDriver) schedule(record *JobRecord) {
+	if !record.ScheduledAt.After(time.Now()) {
 		d.addToQueue(record)
-	} else {
-		// 延迟任务，启动定时器
-		go func() {
-			time.Sleep(delay)
-			d.mu.Lock()
-			d.addToQueue(record)
-			d.mu.Unlock()
-		}()
+		return
 	}
 
-	return nil
+	heap.Push(&d.delayed, record)
+	if d.stop == nil {
+		d.wake = make(chan struct{}, 1)
+		d.stop = make(chan struct{})
+		go d.runDelayed(d.wake, d.stop)
+	}
+
+	// 新任务成为最早到期的任务时，定时 goroutine 需要重新计算等待时间
+	if d.delayed[0] == record {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// runDelayed 在最早的延迟任务到期时将所有到期任务移入队列，直到驱动关闭
+func (d *MemoryDriver) runDelayed(wake, stop chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-wake:
+		case <-timer.C:
+		}
+
+		d.mu.Lock()
+		if d.closed {
+			// 与 Close 竞争时信号通道已关闭，不再入队
+			d.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		for len(d.delayed) > 0 && !d.delayed[0].ScheduledAt.After(now) {
+			d.addToQueue(heap.Pop(&d.delayed).(*JobRecord))
+		}
+		if len(d.delayed) > 0 {
+			timer.Reset(d.delayed[0].ScheduledAt.Sub(now))
+		} else {
+			timer.Stop()
+		}
+		d.mu.Unlock()
+	}
 }
 
 // addToQueue 添加任务到队列（内部方法，需要持有锁）
@@ -91,7 +151,7 @@ func (d *MemoryDriver) addToQueue(record *JobRecord) {
 	}
 }
 
-// Pop 获取任务
+// Pop 获取任务，驱动关闭后返回 ErrDriverClosed
 func (d *MemoryDriver) Pop(queue string, timeout time.Duration) (*JobRecord, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
@@ -99,6 +159,10 @@ func (d *MemoryDriver) Pop(queue string, timeout time.Duration) (*JobRecord, err
 	for {
 		// 尝试获取任务
 		d.mu.Lock()
+		if d.closed {
+			d.mu.Unlock()
+			return nil, ErrDriverClosed
+		}
 		if len(d.queues[queue]) > 0 {
 			// 获取第一个待执行的任务
 			for i, record := range d.queues[queue] {
@@ -117,20 +181,17 @@ func (d *MemoryDriver) Pop(queue string, timeout time.Duration) (*JobRecord, err
 				}
 			}
 		}
-		d.mu.Unlock()
-
-		// 等待新任务或超时
-		if d.signals[queue] == nil {
-			d.mu.Lock()
-			if d.signals[queue] == nil {
-				d.signals[queue] = make(chan struct{}, 100)
-			}
-			d.mu.Unlock()
+		signal := d.signals[queue]
+		if signal == nil {
+			signal = make(chan struct{}, 100)
+			d.signals[queue] = signal
 		}
+		d.mu.Unlock()
 
+		// 等待新任务、驱动关闭或超时
 		select {
-		case <-d.signals[queue]:
-			// 有新任务，继续循环
+		case <-signal:
+			// 有新任务或驱动已关闭，继续循环
 			continue
 		case <-ctx.Done():
 			// 超时
@@ -179,6 +240,10 @@ func (d *MemoryDriver) Retry(jobID string) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if d.closed {
+		return ErrDriverClosed
+	}
+
 	record, exists := d.jobs[jobID]
 	if !exists {
 		return fmt.Errorf("job %s not found", jobID)
@@ -188,7 +253,7 @@ func (d *MemoryDriver) Retry(jobID string) error {
 	record.ScheduledAt = time.Now().Add(time.Duration(record.Attempts) * time.Minute) // 指数退避
 	record.Error = ""
 
-	d.addToQueue(record)
+	d.schedule(record)
 
 	return nil
 }
@@ -268,11 +333,64 @@ func (d *MemoryDriver) GetStats(queue string) (map[string]interface{}, error) {
 	return stats, nil
 }
 
-// Close 关闭驱动
+// GetQueueDepth 实现 DepthReporter 接口
+func (d *MemoryDriver) GetQueueDepth(queue string) (pending, delayed, processing int64, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	queued := make(map[*JobRecord]bool, len(d.queues[queue]))
+	for _, record := range d.queues[queue] {
+		queued[record] = true
+	}
+
+	pending = int64(len(queued))
+	for _, record := range d.jobs {
+		if record.Queue != queue {
+			continue
+		}
+		switch record.Status {
+		case StatusRunning:
+			processing++
+		case StatusPending:
+			// 延迟任务到期前不在队列中
+			if !queued[record] {
+				delayed++
+			}
+		}
+	}
+	return pending, delayed, processing, nil
+}
+
+// OldestJobAge 实现 DepthReporter 接口
+func (d *MemoryDriver) OldestJobAge(queue string) (time.Duration, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	now := time.Now()
+	var oldest time.Duration
+	for _, record := range d.queues[queue] {
+		if age := jobAge(record, now); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest, nil
+}
+
+// Close 关闭驱动，之后推送任务返回 ErrDriverClosed，重复调用安全
 func (d *MemoryDriver) Close() error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	// 停止延迟任务定时器
+	if d.stop != nil {
+		close(d.stop)
+	}
+
 	// 关闭所有信号通道
 	for _, ch := range d.signals {
 		close(ch)
@@ -295,3 +413,23 @@ func (d *MemoryDriver) SaveToFile(filename string) error {
 	_ = data
 	return nil
 }
+
+// delayedJobs 按 ScheduledAt 排序的最小堆，实现 heap.Interface
+type delayedJobs []*JobRecord
+
+func (h delayedJobs) Len() int           { return len(h) }
+func (h delayedJobs) Less(i, j int) bool { return h[i].ScheduledAt.Before(h[j].ScheduledAt) }
+func (h delayedJobs) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *delayedJobs) Push(x interface{}) {
+	*h = append(*h, x.(*JobRecord))
+}
+
+func (h *delayedJobs) Pop() interface{} {
+	old := *h
+	n := len(old)
+	record := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return record
+}
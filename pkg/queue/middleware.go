@@ -0,0 +1,36 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/clarkgo/clarkgo/pkg/log"
+)
+
+// Middleware 任务中间件，包装任务处理器以实现日志、指标、恢复等横切逻辑
+// 中间件在任务超时 context 内执行，ctx 与处理器收到的相同
+type Middleware func(next ContextJobHandler) ContextJobHandler
+
+// Recover 恢复任务处理器中的 panic 并转换为错误，任务按失败处理（重试或进入死信）
+func Recover() Middleware {
+	return func(next ContextJobHandler) ContextJobHandler {
+		return func(ctx context.Context, payload []byte) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.FromContext(ctx).Error("job panic recovered", "panic", r, "stack", string(debug.Stack()))
+					err = fmt.Errorf("job panicked: %v", r)
+				}
+			}()
+			return next(ctx, payload)
+		}
+	}
+}
+
+// applyMiddleware 按注册顺序包装处理器，先注册的中间件位于最外层
+func applyMiddleware(handler ContextJobHandler, middleware []Middleware) ContextJobHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
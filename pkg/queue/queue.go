@@ -4,7 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/event"
+	"github.com/clarkgo/clarkgo/pkg/log"
 )
 
 // Job 队列任务接口
@@ -120,33 +126,70 @@ type Driver interface {
 	Close() error
 }
 
+// DepthReporter 支持查询队列深度和积压时间的驱动，用于自动扩缩容
+type DepthReporter interface {
+	// GetQueueDepth 返回待执行、延迟中和处理中的任务数量
+	GetQueueDepth(queue string) (pending, delayed, processing int64, err error)
+	// OldestJobAge 返回最早的待执行任务已等待的时间，队列为空时返回 0
+	OldestJobAge(queue string) (time.Duration, error)
+}
+
+// popTimeout 工作进程每次从队列获取任务的最长等待时间
+var popTimeout = 5 * time.Second
+
 // Queue 队列管理器
 type Queue struct {
 	driver       Driver
-	handlers     map[string]JobHandler
+	handlers     map[string]ContextJobHandler
+	middleware   []Middleware
 	ctx          context.Context
 	cancel       context.CancelFunc
 	workers      int
 	workerQueues []string
+	wg           sync.WaitGroup // 追踪运行中的工作进程
+	logger       log.StructuredLogger
+	dispatcher   *event.Dispatcher
+
+	autoScale     *autoScale   // EnableAutoScale 设置，为 nil 时工作进程数量固定
+	activeWorkers atomic.Int32 // 运行中的工作进程数量
+	nextWorkerID  atomic.Int32
 }
 
 // JobHandler 任务处理函数
 type JobHandler func(payload []byte) error
 
+// ContextJobHandler 带 context 的任务处理函数
+// ctx 携带任务超时以及附加了 job_id/job_type 字段的 Logger（log.FromContext）
+type ContextJobHandler func(ctx context.Context, payload []byte) error
+
 // NewQueue 创建新的队列管理器
 func NewQueue(driver Driver) *Queue {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Queue{
 		driver:       driver,
-		handlers:     make(map[string]JobHandler),
+		handlers:     make(map[string]ContextJobHandler),
 		ctx:          ctx,
 		cancel:       cancel,
 		workers:      1,
 		workerQueues: []string{"default"},
+		logger:       log.Default(),
 	}
 }
 
-// SetWorkers 设置工作进程数量
+// SetLogger 设置日志记录器
+func (q *Queue) SetLogger(logger log.StructuredLogger) *Queue {
+	q.logger = logger
+	return q
+}
+
+// WithDispatcher 设置事件分发器，任务完成、失败和进入死信队列时触发
+// job.completed、job.failed、job.dead 事件，事件携带任务记录
+func (q *Queue) WithDispatcher(dispatcher *event.Dispatcher) *Queue {
+	q.dispatcher = dispatcher
+	return q
+}
+
+// SetWorkers 设置工作进程数量，启用自动扩缩容后不再生效
 func (q *Queue) SetWorkers(workers int) *Queue {
 	q.workers = workers
 	return q
@@ -159,10 +202,24 @@ func (q *Queue) SetQueues(queues []string) *Queue {
 }
 
 // Register 注册任务处理器
+// 处理器不接收 context，超时后无法被取消，需要响应超时的任务应使用 RegisterWithContext
 func (q *Queue) Register(jobType string, handler JobHandler) {
+	q.handlers[jobType] = func(ctx context.Context, payload []byte) error {
+		return handler(payload)
+	}
+}
+
+// RegisterWithContext 注册带 context 的任务处理器
+func (q *Queue) RegisterWithContext(jobType string, handler ContextJobHandler) {
 	q.handlers[jobType] = handler
 }
 
+// Use 添加任务中间件，作用于所有任务处理器
+func (q *Queue) Use(middleware ...Middleware) *Queue {
+	q.middleware = append(q.middleware, middleware...)
+	return q
+}
+
 // Push 推送任务
 func (q *Queue) Push(job Job) error {
 	return q.driver.Push(job)
@@ -175,11 +232,27 @@ func (q *Queue) PushDelay(job Job, delay time.Duration) error {
 
 // Work 启动队列工作进程
 func (q *Queue) Work() error {
-	fmt.Printf("Starting %d workers for queues: %v\n", q.workers, q.workerQueues)
+	q.logger.Info("queue workers starting", "workers", q.workers, "queues", q.workerQueues)
 
 	// 启动多个工作进程
 	for i := 0; i < q.workers; i++ {
-		go q.worker(i)
+		q.startWorker(false)
+	}
+
+	// 启用自动扩缩容且驱动支持查询队列深度时启动扩缩容协程
+	if q.autoScale != nil {
+		if reporter, ok := q.driver.(DepthReporter); ok {
+			q.wg.Add(1)
+			go q.runAutoScale(reporter)
+		} else {
+			q.logger.Warn("queue driver does not report depth, auto scaling disabled", "driver", fmt.Sprintf("%T", q.driver))
+		}
+	}
+
+	// 驱动支持周期任务时启动调度协程
+	if _, ok := q.driver.(RecurringStore); ok {
+		q.wg.Add(1)
+		go q.runRecurring()
 	}
 
 	// 等待取消信号
@@ -187,6 +260,16 @@ func (q *Queue) Work() error {
 	return nil
 }
 
+// WorkOnce 按队列顺序获取并处理一个任务，返回是否处理了任务
+func (q *Queue) WorkOnce() bool {
+	for _, queueName := range q.workerQueues {
+		if q.processQueue(queueName) {
+			return true
+		}
+	}
+	return false
+}
+
 // Stop 停止队列工作进程
 func (q *Queue) Stop() {
 	q.cancel()
@@ -195,67 +278,190 @@ func (q *Queue) Stop() {
 	}
 }
 
-// worker 工作进程
-func (q *Queue) worker(id int) {
-	fmt.Printf("Worker #%d started\n", id)
+// DefaultShutdownTimeout 默认优雅关闭超时，等待正在执行的任务完成的最长时间
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Shutdown 优雅关闭：停止获取新任务，等待正在执行的任务完成后关闭驱动
+// 超过 timeout 仍未完成时强制关闭并返回错误
+func (q *Queue) Shutdown(timeout time.Duration) error {
+	q.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		err = fmt.Errorf("queue shutdown timed out after %v", timeout)
+	}
+
+	if q.driver != nil {
+		q.driver.Close()
+	}
+
+	return err
+}
+
+// startWorker 启动一个工作进程，temporary 为 true 时工作进程在空闲时退出
+func (q *Queue) startWorker(temporary bool) {
+	q.wg.Add(1)
+	q.activeWorkers.Add(1)
+	go q.worker(int(q.nextWorkerID.Add(1)-1), temporary)
+}
+
+// worker 工作进程，temporary 为自动扩容启动的临时工作进程，一轮轮询没有获取到任务时退出
+func (q *Queue) worker(id int, temporary bool) {
+	defer q.wg.Done()
+	defer q.activeWorkers.Add(-1)
+	q.logger.Debug("queue worker started", "worker", id, "temporary", temporary)
 
 	for {
 		select {
 		case <-q.ctx.Done():
-			fmt.Printf("Worker #%d stopped\n", id)
+			q.logger.Debug("queue worker stopped", "worker", id)
 			return
 		default:
 			// 轮询所有队列
+			processed := false
 			for _, queueName := range q.workerQueues {
-				q.processQueue(queueName)
+				if q.ctx.Err() != nil {
+					break
+				}
+				if q.processQueue(queueName) {
+					processed = true
+				}
+			}
+			if processed {
+				continue
+			}
+			if temporary {
+				q.logger.Debug("queue worker idle, scaling down", "worker", id)
+				return
 			}
 			time.Sleep(1 * time.Second) // 避免空轮询消耗 CPU
 		}
 	}
 }
 
-// processQueue 处理队列中的任务
-func (q *Queue) processQueue(queueName string) {
-	// 获取任务（阻塞等待 5 秒）
-	jobRecord, err := q.driver.Pop(queueName, 5*time.Second)
+// processQueue 处理队列中的任务，返回是否获取到了任务
+func (q *Queue) processQueue(queueName string) bool {
+	// 获取任务（阻塞等待 popTimeout）
+	jobRecord, err := q.driver.Pop(queueName, popTimeout)
 	if err != nil || jobRecord == nil {
-		return
+		return false
 	}
 
-	// 查找处理器
+	// 查找处理器，没有注册处理器时使用 RegisterJobType 注册的任务类型自身的 Handle 方法
 	handler, exists := q.handlers[jobRecord.JobType]
 	if !exists {
-		q.driver.Fail(jobRecord.ID, fmt.Errorf("no handler for job type: %s", jobRecord.JobType))
-		return
+		handler, exists = registeredHandler(jobRecord.JobType)
+	}
+	if !exists {
+		err := fmt.Errorf("no handler for job type: %s", jobRecord.JobType)
+		q.jobLogger(jobRecord).Error("no handler for job")
+		q.driver.Fail(jobRecord.ID, err)
+		q.dispatchDead(jobRecord, err)
+		return true
 	}
 
 	// 执行任务
-	err = q.executeJob(jobRecord, handler)
+	start := time.Now()
+	err = q.executeJob(jobRecord, applyMiddleware(handler, q.middleware))
+	duration := time.Since(start)
 	if err != nil {
+		q.jobLogger(jobRecord).Error("job failed", "attempts", jobRecord.Attempts, "error", err)
+
 		// 任务失败，检查是否需要重试
-		if jobRecord.Attempts < jobRecord.MaxRetries {
+		willRetry := jobRecord.Attempts < jobRecord.MaxRetries
+		q.dispatchFailed(jobRecord, err, willRetry, duration)
+		if willRetry {
 			q.driver.Retry(jobRecord.ID)
 		} else {
 			// 超过最大重试次数，进入死信队列
 			q.driver.Fail(jobRecord.ID, err)
+			q.dispatchDead(jobRecord, err)
 		}
-		return
+		return true
 	}
 
 	// 任务成功，确认完成
 	q.driver.Ack(jobRecord.ID)
+	q.dispatchCompleted(jobRecord, duration)
+	return true
+}
+
+// dispatchCompleted 触发任务完成事件
+func (q *Queue) dispatchCompleted(jobRecord *JobRecord, duration time.Duration) {
+	if q.dispatcher == nil {
+		return
+	}
+
+	record := *jobRecord
+	record.Status = StatusCompleted
+	q.dispatch(&record, &JobCompletedEvent{Job: record, Duration: duration})
+}
+
+// dispatchFailed 触发任务失败事件
+func (q *Queue) dispatchFailed(jobRecord *JobRecord, err error, willRetry bool, duration time.Duration) {
+	if q.dispatcher == nil {
+		return
+	}
+
+	record := *jobRecord
+	record.Status = StatusFailed
+	if willRetry {
+		record.Status = StatusRetrying
+	}
+	record.Error = err.Error()
+	q.dispatch(&record, &JobFailedEvent{Job: record, Error: err.Error(), WillRetry: willRetry, Duration: duration})
+}
+
+// dispatchDead 触发任务进入死信队列事件
+func (q *Queue) dispatchDead(jobRecord *JobRecord, err error) {
+	if q.dispatcher == nil {
+		return
+	}
+
+	record := *jobRecord
+	record.Status = StatusDead
+	record.Error = err.Error()
+	q.dispatch(&record, &JobDeadEvent{Job: record, Error: err.Error()})
+}
+
+// dispatch 分发队列事件，监听器错误只记录日志，不影响任务状态
+func (q *Queue) dispatch(jobRecord *JobRecord, evt event.Event) {
+	ctx := log.WithContext(context.Background(), q.jobLogger(jobRecord))
+	if err := q.dispatcher.DispatchWithContext(ctx, evt); err != nil {
+		q.jobLogger(jobRecord).Warn("job event listener failed", "event", evt.EventName(), "error", err)
+	}
+}
+
+// jobLogger 获取附加了任务字段的 Logger
+func (q *Queue) jobLogger(jobRecord *JobRecord) log.StructuredLogger {
+	return q.logger.With("job_id", jobRecord.ID, "job_type", jobRecord.JobType, "queue", jobRecord.Queue)
 }
 
 // executeJob 执行任务
-func (q *Queue) executeJob(jobRecord *JobRecord, handler JobHandler) error {
-	// 创建带超时的 context
+func (q *Queue) executeJob(jobRecord *JobRecord, handler ContextJobHandler) error {
+	// 创建带超时和任务日志字段的 context
 	ctx, cancel := context.WithTimeout(context.Background(), jobRecord.Timeout)
 	defer cancel()
+	ctx = log.WithContext(ctx, q.logger)
+	ctx = log.WithJobID(ctx, jobRecord.ID, jobRecord.JobType)
 
-	// 在 goroutine 中执行任务
+	// 在 goroutine 中执行任务，panic 转换为错误，避免导致工作进程崩溃
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- handler([]byte(jobRecord.Payload))
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- fmt.Errorf("job panicked: %v\n%s", r, debug.Stack())
+			}
+		}()
+		errChan <- handler(ctx, []byte(jobRecord.Payload))
 	}()
 
 	// 等待完成或超时
@@ -263,13 +469,51 @@ func (q *Queue) executeJob(jobRecord *JobRecord, handler JobHandler) error {
 	case err := <-errChan:
 		return err
 	case <-ctx.Done():
-		return fmt.Errorf("job timeout after %v", jobRecord.Timeout)
+		// 处理器通过 ctx 感知取消，返回的错误可通过 errors.Is(err, context.DeadlineExceeded) 判断
+		return fmt.Errorf("job timeout after %v: %w", jobRecord.Timeout, ctx.Err())
 	}
 }
 
 // GetStats 获取队列统计
+// 驱动实现 DepthReporter 且指定了队列时，附加 depth_pending、depth_delayed、depth_processing 和
+// oldest_job_age_seconds，供扩缩容判断是否需要增加工作进程
 func (q *Queue) GetStats(queue string) (map[string]interface{}, error) {
-	return q.driver.GetStats(queue)
+	stats, err := q.driver.GetStats(queue)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter, ok := q.driver.(DepthReporter)
+	if !ok || queue == "" {
+		return stats, nil
+	}
+
+	pending, delayed, processing, err := reporter.GetQueueDepth(queue)
+	if err != nil {
+		return nil, err
+	}
+	age, err := reporter.OldestJobAge(queue)
+	if err != nil {
+		return nil, err
+	}
+
+	stats["depth_pending"] = pending
+	stats["depth_delayed"] = delayed
+	stats["depth_processing"] = processing
+	stats["oldest_job_age_seconds"] = age.Seconds()
+	return stats, nil
+}
+
+// jobAge 返回任务从可执行起已等待的时间，延迟任务从 ScheduledAt 开始计算
+func jobAge(record *JobRecord, now time.Time) time.Duration {
+	since := record.CreatedAt
+	if record.ScheduledAt.After(since) {
+		since = record.ScheduledAt
+	}
+	if age := now.Sub(since); age > 0 {
+		return age
+	}
+	return 0
 }
 
 // RetryFailed 重试所有失败的任务
@@ -281,7 +525,7 @@ func (q *Queue) RetryFailed(queue string) error {
 
 	for _, job := range jobs {
 		if err := q.driver.Retry(job.ID); err != nil {
-			fmt.Printf("Failed to retry job %s: %v\n", job.ID, err)
+			q.logger.Error("failed to retry job", "job_id", job.ID, "error", err)
 		}
 	}
 
@@ -297,7 +541,7 @@ func (q *Queue) PurgeQueue(queue string, status JobStatus) error {
 
 	for _, job := range jobs {
 		if err := q.driver.Delete(job.ID); err != nil {
-			fmt.Printf("Failed to delete job %s: %v\n", job.ID, err)
+			q.logger.Error("failed to delete job", "job_id", job.ID, "error", err)
 		}
 	}
 
@@ -313,7 +557,7 @@ func MarshalJob(job interface{}) (string, error) {
 	return string(data), nil
 }
 
-// UnmarshalJob 反序列化任务
+// UnmarshalJob 反序列化任务，需要按任务类型名称还原具体类型时使用 DecodeJob
 func UnmarshalJob(data string, job interface{}) error {
 	return json.Unmarshal([]byte(data), job)
 }
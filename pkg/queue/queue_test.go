@@ -0,0 +1,468 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/event"
+)
+
+type testJob struct {
+	BaseJob
+	Name string `json:"name"`
+}
+
+func (j *testJob) Handle() error {
+	return nil
+}
+
+var testJobType = fmt.Sprintf("%T", &testJob{})
+
+// registeredJob 通过 RegisterJobType 注册名称的任务
+type registeredJob struct {
+	BaseJob
+	Name string `json:"name"`
+}
+
+var registeredJobHandled = make(chan string, 1)
+
+func (j *registeredJob) Handle() error {
+	registeredJobHandled <- j.Name
+	return nil
+}
+
+func init() {
+	RegisterJobType("test.registered", func() Job { return &registeredJob{} })
+}
+
+func TestQueueMiddleware(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next ContextJobHandler) ContextJobHandler {
+			return func(ctx context.Context, payload []byte) error {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Errorf("Expected middleware %s to run within the job timeout context", name)
+				}
+				calls = append(calls, name+":before")
+				err := next(ctx, payload)
+				calls = append(calls, name+":after")
+				return err
+			}
+		}
+	}
+	q.Use(trace("outer"), trace("inner"))
+
+	q.RegisterWithContext(testJobType, func(ctx context.Context, payload []byte) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	q.Push(&testJob{BaseJob: BaseJob{ID: "job_1"}})
+	if !q.WorkOnce() {
+		t.Fatal("Expected a job to be processed")
+	}
+
+	expected := "outer:before,inner:before,handler,inner:after,outer:after"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Errorf("Expected call order %s, got %s", expected, got)
+	}
+
+	record, _ := driver.GetJob("job_1")
+	if record.Status != StatusCompleted {
+		t.Errorf("Expected job completed, got %s", record.Status)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver).Use(Recover())
+
+	q.RegisterWithContext(testJobType, func(ctx context.Context, payload []byte) error {
+		panic("boom")
+	})
+
+	q.Push(&testJob{BaseJob: BaseJob{ID: "job_1", MaxRetries: 1}})
+	q.WorkOnce()
+
+	record, _ := driver.GetJob("job_1")
+	if record.Status != StatusDead {
+		t.Errorf("Expected job dead after panic, got %s", record.Status)
+	}
+	if !strings.Contains(record.Error, "boom") {
+		t.Errorf("Expected panic message in error, got %q", record.Error)
+	}
+}
+
+func TestExecuteJobPanic(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	q.Register(testJobType, func(payload []byte) error {
+		var m map[string]int
+		m["boom"]++ // nil map 写入触发 panic
+		return nil
+	})
+
+	q.Push(&testJob{BaseJob: BaseJob{ID: "job_1", MaxRetries: 2}})
+	if !q.WorkOnce() {
+		t.Fatal("Expected a job to be processed")
+	}
+
+	record, _ := driver.GetJob("job_1")
+	if record.Status != StatusPending || record.Attempts != 1 {
+		t.Errorf("Expected job requeued for retry, got status %s after %d attempts", record.Status, record.Attempts)
+	}
+
+	if err := q.executeJob(record, q.handlers[testJobType]); err == nil || !strings.Contains(err.Error(), "panicked") || !strings.Contains(err.Error(), "goroutine") {
+		t.Errorf("Expected panic error with stack, got %v", err)
+	}
+}
+
+func TestExecuteJobTimeoutCancelsHandler(t *testing.T) {
+	q := NewQueue(NewMemoryDriver())
+
+	cancelled := make(chan struct{})
+	handler := func(ctx context.Context, payload []byte) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}
+
+	record := &JobRecord{ID: "job_1", JobType: testJobType, Payload: "{}", Timeout: 10 * time.Millisecond}
+	err := q.executeJob(record, handler)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded error, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected handler to observe cancellation")
+	}
+}
+
+func TestQueueEvents(t *testing.T) {
+	driver := NewMemoryDriver()
+	dispatcher := event.NewDispatcher(1)
+	defer dispatcher.Stop()
+	q := NewQueue(driver).WithDispatcher(dispatcher)
+
+	var (
+		mu     sync.Mutex
+		events []string
+		dead   *JobDeadEvent
+	)
+	record := func(ctx context.Context, e event.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e.EventName())
+		if d, ok := e.(*JobDeadEvent); ok {
+			dead = d
+		}
+		return nil
+	}
+	dispatcher.Subscribe([]string{JobCompletedEventName, JobFailedEventName, JobDeadEventName}, record)
+
+	fail := true
+	q.RegisterWithContext(testJobType, func(ctx context.Context, payload []byte) error {
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	q.Push(&testJob{BaseJob: BaseJob{ID: "job_1", MaxRetries: 1}})
+	q.WorkOnce()
+
+	fail = false
+	q.Push(&testJob{BaseJob: BaseJob{ID: "job_2"}})
+	q.WorkOnce()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expected := "job.failed,job.dead,job.completed"
+	if got := strings.Join(events, ","); got != expected {
+		t.Errorf("Expected events %s, got %s", expected, got)
+	}
+	if dead == nil || dead.Job.ID != "job_1" || dead.Job.Status != StatusDead || dead.Error != "boom" {
+		t.Errorf("Unexpected dead event: %+v", dead)
+	}
+}
+
+func TestQueueScheduleRecurring(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	if _, err := q.Schedule(&testJob{Name: "report"}, "invalid"); err == nil {
+		t.Error("Expected invalid cron expression to be rejected")
+	}
+
+	recurring, err := q.Schedule(&testJob{Name: "report"}, "*/5 * * * *")
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 10, 5, 30, 0, time.UTC)
+	if pushed, _ := q.EnqueueDueRecurring(now); pushed != 1 {
+		t.Fatalf("Expected 1 job to be pushed, got %d", pushed)
+	}
+	if pushed, _ := q.EnqueueDueRecurring(now.Add(10 * time.Second)); pushed != 0 {
+		t.Errorf("Expected no duplicate push within the same minute, got %d", pushed)
+	}
+	if pushed, _ := q.EnqueueDueRecurring(now.Add(time.Minute)); pushed != 0 {
+		t.Errorf("Expected no push when cron is not due, got %d", pushed)
+	}
+
+	// 重启后再次注册同一周期任务不会在同一分钟内重复推送
+	restarted := NewQueue(driver)
+	if _, err := restarted.Schedule(&testJob{Name: "report"}, "*/5 * * * *"); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if pushed, _ := restarted.EnqueueDueRecurring(now); pushed != 0 {
+		t.Errorf("Expected rescheduling to keep the last run time, got %d pushes", pushed)
+	}
+	if pushed, _ := restarted.EnqueueDueRecurring(now.Add(5 * time.Minute)); pushed != 1 {
+		t.Errorf("Expected 1 job to be pushed at the next fire time, got %d", pushed)
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		record, err := driver.Pop("default", time.Second)
+		if err != nil || record == nil {
+			t.Fatalf("Expected recurring job instance, got %v, %v", record, err)
+		}
+		if record.JobType != testJobType {
+			t.Errorf("Expected job type %s, got %s", testJobType, record.JobType)
+		}
+		var job testJob
+		if err := UnmarshalJob(record.Payload, &job); err != nil || job.Name != "report" {
+			t.Errorf("Expected original payload, got %s", record.Payload)
+		}
+		ids = append(ids, record.ID)
+	}
+	if ids[0] == ids[1] {
+		t.Error("Expected each recurring push to be a fresh job instance")
+	}
+
+	if err := q.Unschedule(recurring.ID); err != nil {
+		t.Fatalf("Unschedule failed: %v", err)
+	}
+	if pushed, _ := q.EnqueueDueRecurring(now.Add(10 * time.Minute)); pushed != 0 {
+		t.Errorf("Expected no push after Unschedule, got %d", pushed)
+	}
+}
+
+func TestQueueDepthStats(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	q.Push(&testJob{BaseJob: BaseJob{ID: "a"}})
+	q.Push(&testJob{BaseJob: BaseJob{ID: "b"}})
+	q.PushDelay(&testJob{BaseJob: BaseJob{ID: "c"}}, time.Hour)
+	if _, err := driver.Pop("default", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// 回拨创建时间模拟积压
+	driver.mu.Lock()
+	driver.jobs["b"].CreatedAt = time.Now().Add(-time.Minute)
+	driver.jobs["b"].ScheduledAt = driver.jobs["b"].CreatedAt
+	driver.mu.Unlock()
+
+	stats, err := q.GetStats("default")
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats["depth_pending"] != int64(1) || stats["depth_delayed"] != int64(1) || stats["depth_processing"] != int64(1) {
+		t.Errorf("Unexpected depth stats: %v", stats)
+	}
+	if age := stats["oldest_job_age_seconds"].(float64); age < 59 || age > 70 {
+		t.Errorf("Expected oldest job age around 60s, got %v", age)
+	}
+
+	if stats, _ := q.GetStats(""); stats["depth_pending"] != nil {
+		t.Error("Expected depth stats only for a named queue")
+	}
+}
+
+func TestRegisterJobType(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	if err := q.Push(&registeredJob{Name: "invoice"}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	driver.mu.Lock()
+	var record *JobRecord
+	for _, r := range driver.jobs {
+		record = r
+	}
+	driver.mu.Unlock()
+	if record.JobType != "test.registered" {
+		t.Fatalf("Expected registered job type name, got %s", record.JobType)
+	}
+
+	job, err := DecodeJob(record.JobType, []byte(record.Payload))
+	if decoded, ok := job.(*registeredJob); err != nil || !ok || decoded.Name != "invoice" {
+		t.Fatalf("Expected *registeredJob, got %#v, %v", job, err)
+	}
+	if _, err := DecodeJob("missing", []byte("{}")); err == nil {
+		t.Error("Expected error for unregistered job type")
+	}
+
+	// 没有注册处理器时执行任务自身的 Handle
+	if !q.WorkOnce() {
+		t.Fatal("Expected job to be processed")
+	}
+	select {
+	case name := <-registeredJobHandled:
+		if name != "invoice" {
+			t.Errorf("Expected decoded job to be handled, got %s", name)
+		}
+	default:
+		t.Error("Expected registered job Handle to be called")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	RegisterJobType("test.registered", func() Job { return &testJob{} })
+}
+
+func TestQueueScheduleRegisteredJobType(t *testing.T) {
+	q := NewQueue(NewMemoryDriver())
+
+	recurring, err := q.Schedule(&registeredJob{Name: "digest"}, "0 * * * *")
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	if recurring.JobType != "test.registered" || recurring.ID != "test.registered@default:0 * * * *" {
+		t.Errorf("Expected registered job type name, got %s (%s)", recurring.JobType, recurring.ID)
+	}
+
+	if pushed, _ := q.EnqueueDueRecurring(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)); pushed != 1 {
+		t.Fatalf("Expected 1 job to be pushed, got %d", pushed)
+	}
+	if !q.WorkOnce() {
+		t.Fatal("Expected recurring job to be processed")
+	}
+	select {
+	case name := <-registeredJobHandled:
+		if name != "digest" {
+			t.Errorf("Expected scheduled payload to be handled, got %s", name)
+		}
+	default:
+		t.Error("Expected registered job Handle to be called")
+	}
+}
+
+func TestMemoryDriverDelayedJobs(t *testing.T) {
+	driver := NewMemoryDriver()
+	q := NewQueue(driver)
+
+	// 乱序推送，按到期时间出队
+	q.PushDelay(&testJob{BaseJob: BaseJob{ID: "late"}}, 60*time.Millisecond)
+	q.PushDelay(&testJob{BaseJob: BaseJob{ID: "early"}}, 20*time.Millisecond)
+	q.PushDelay(&testJob{BaseJob: BaseJob{ID: "never"}}, time.Hour)
+	q.Push(&testJob{BaseJob: BaseJob{ID: "now"}})
+
+	start := time.Now()
+	for _, want := range []string{"now", "early", "late"} {
+		record, err := driver.Pop("default", time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record == nil || record.ID != want {
+			t.Fatalf("Expected job %s, got %+v", want, record)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("Delayed job popped early after %v", elapsed)
+	}
+
+	if record, _ := driver.Pop("default", 20*time.Millisecond); record != nil {
+		t.Errorf("Expected no due jobs, got %s", record.ID)
+	}
+	if _, delayed, _, _ := driver.GetQueueDepth("default"); delayed != 1 {
+		t.Errorf("Expected 1 delayed job, got %d", delayed)
+	}
+
+	// 关闭后定时 goroutine 退出，未到期任务不再入队
+	driver.Close()
+	select {
+	case <-driver.stop:
+	default:
+		t.Error("Expected Close to stop the delayed job timer")
+	}
+
+	// 重复关闭安全，关闭后拒绝推送、重试和获取任务
+	if err := driver.Close(); err != nil {
+		t.Errorf("Expected second Close to succeed, got %v", err)
+	}
+	if err := driver.Push(&testJob{BaseJob: BaseJob{ID: "late", Queue: "default"}}); !errors.Is(err, ErrDriverClosed) {
+		t.Errorf("Expected ErrDriverClosed from Push, got %v", err)
+	}
+	if err := driver.PushDelay(&testJob{BaseJob: BaseJob{ID: "later", Queue: "default"}}, time.Minute); !errors.Is(err, ErrDriverClosed) {
+		t.Errorf("Expected ErrDriverClosed from PushDelay, got %v", err)
+	}
+	if err := driver.Retry("now"); !errors.Is(err, ErrDriverClosed) {
+		t.Errorf("Expected ErrDriverClosed from Retry, got %v", err)
+	}
+	if _, err := driver.Pop("default", time.Second); !errors.Is(err, ErrDriverClosed) {
+		t.Errorf("Expected ErrDriverClosed from Pop, got %v", err)
+	}
+}
+
+func TestQueueAutoScale(t *testing.T) {
+	autoScaleInterval = 10 * time.Millisecond
+	popTimeout = 20 * time.Millisecond
+
+	driver := NewMemoryDriver()
+	q := NewQueue(driver).EnableAutoScale(1, 3, 2)
+
+	release := make(chan struct{})
+	q.Register(testJobType, func(payload []byte) error {
+		<-release
+		return nil
+	})
+	for i := 0; i < 8; i++ {
+		q.Push(&testJob{BaseJob: BaseJob{ID: fmt.Sprintf("job-%d", i)}})
+	}
+
+	go q.Work()
+	defer q.Shutdown(time.Second)
+
+	waitFor := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for q.ActiveWorkers() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d active workers, got %d", want, q.ActiveWorkers())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// 积压 8 个任务，每个工作进程承担 2 个，扩容到上限 3
+	waitFor(3)
+
+	// 任务处理完后临时工作进程空闲退出，保留 min 个
+	close(release)
+	waitFor(1)
+
+	if q.desiredWorkers(0) != 1 || q.desiredWorkers(3) != 2 || q.desiredWorkers(100) != 3 {
+		t.Error("Expected desired workers to be clamped to [min, max]")
+	}
+}
@@ -0,0 +1,276 @@
+package queue
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/schedule"
+)
+
+// RecurringJob 周期任务定义，按 cron 表达式定时推送新的任务实例
+type RecurringJob struct {
+	ID         string        `json:"id"`
+	JobType    string        `json:"job_type"`
+	Queue      string        `json:"queue"`
+	Payload    string        `json:"payload"` // JSON 编码的任务数据，每次推送时复用
+	Cron       string        `json:"cron"`
+	MaxRetries int           `json:"max_retries"`
+	Timeout    time.Duration `json:"timeout"`
+	CreatedAt  time.Time     `json:"created_at"`
+	LastRunAt  time.Time     `json:"last_run_at"`
+}
+
+// RecurringStore 周期任务存储，驱动实现该接口后周期任务定义在进程重启后仍然有效
+type RecurringStore interface {
+	// SaveRecurring 保存周期任务定义，ID 相同时覆盖
+	SaveRecurring(job *RecurringJob) error
+	// ListRecurring 列出所有周期任务定义
+	ListRecurring() ([]*RecurringJob, error)
+	// DeleteRecurring 删除周期任务定义
+	DeleteRecurring(id string) error
+	// ClaimRecurring 认领周期任务在 at 时刻的执行，多个进程同时调度时只有一个返回 true
+	ClaimRecurring(id string, at time.Time) (bool, error)
+}
+
+// recurringInterval 周期任务检查间隔
+var recurringInterval = time.Second
+
+// Schedule 注册周期任务，每当 cron 表达式触发时推送一个新的任务实例
+// 同一任务类型、队列和 cron 表达式视为同一周期任务，重复调用会更新任务数据，
+// 因此可以在每次启动时调用；驱动需实现 RecurringStore
+func (q *Queue) Schedule(job Job, cronExpr string) (*RecurringJob, error) {
+	if _, err := schedule.ParseCron(cronExpr); err != nil {
+		return nil, err
+	}
+	store, ok := q.driver.(RecurringStore)
+	if !ok {
+		return nil, fmt.Errorf("queue driver %T does not support recurring jobs", q.driver)
+	}
+
+	payload, err := MarshalJob(job)
+	if err != nil {
+		return nil, err
+	}
+
+	jobType := jobTypeOf(job)
+	recurring := &RecurringJob{
+		ID:         fmt.Sprintf("%s@%s:%s", jobType, job.GetQueue(), cronExpr),
+		JobType:    jobType,
+		Queue:      job.GetQueue(),
+		Payload:    payload,
+		Cron:       cronExpr,
+		MaxRetries: job.GetMaxRetries(),
+		Timeout:    job.GetTimeout(),
+		CreatedAt:  time.Now(),
+	}
+
+	// 保留已有定义的创建时间和最近运行时间，避免重启后同一分钟内重复推送
+	existing, err := store.ListRecurring()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		if e.ID == recurring.ID {
+			recurring.CreatedAt = e.CreatedAt
+			recurring.LastRunAt = e.LastRunAt
+			break
+		}
+	}
+
+	if err := store.SaveRecurring(recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+// Unschedule 删除周期任务
+func (q *Queue) Unschedule(id string) error {
+	store, ok := q.driver.(RecurringStore)
+	if !ok {
+		return fmt.Errorf("queue driver %T does not support recurring jobs", q.driver)
+	}
+	return store.DeleteRecurring(id)
+}
+
+// EnqueueDueRecurring 推送在 now 所在分钟到期的周期任务，返回推送数量
+// Work 会在后台定期调用，也可以由外部调度器直接调用
+func (q *Queue) EnqueueDueRecurring(now time.Time) (int, error) {
+	store, ok := q.driver.(RecurringStore)
+	if !ok {
+		return 0, nil
+	}
+
+	jobs, err := store.ListRecurring()
+	if err != nil {
+		return 0, err
+	}
+
+	minute := now.Truncate(time.Minute)
+	pushed := 0
+	for _, recurring := range jobs {
+		if !recurring.LastRunAt.Before(minute) {
+			continue
+		}
+		cronExpr, err := schedule.ParseCron(recurring.Cron)
+		if err != nil {
+			q.logger.Error("invalid recurring job cron expression", "recurring_id", recurring.ID, "error", err)
+			continue
+		}
+		if !cronExpr.IsDue(minute) {
+			continue
+		}
+
+		claimed, err := store.ClaimRecurring(recurring.ID, minute)
+		if err != nil {
+			q.logger.Error("failed to claim recurring job", "recurring_id", recurring.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := q.driver.Push(&recurringInstance{recurring: recurring, id: newJobID()}); err != nil {
+			q.logger.Error("failed to push recurring job", "recurring_id", recurring.ID, "error", err)
+			continue
+		}
+		pushed++
+
+		recurring.LastRunAt = minute
+		if err := store.SaveRecurring(recurring); err != nil {
+			q.logger.Error("failed to save recurring job", "recurring_id", recurring.ID, "error", err)
+		}
+	}
+
+	return pushed, nil
+}
+
+// runRecurring 后台推送到期的周期任务，直到队列停止
+func (q *Queue) runRecurring() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(recurringInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := q.EnqueueDueRecurring(now); err != nil {
+				q.logger.Error("failed to enqueue recurring jobs", "error", err)
+			}
+		}
+	}
+}
+
+// newJobID 生成任务 ID，格式与 BaseJob 一致
+func newJobID() string {
+	return fmt.Sprintf("job_%d", time.Now().UnixNano())
+}
+
+// recurringInstance 周期任务推送的任务实例
+// 任务类型沿用原任务，由原任务类型注册的处理器处理
+type recurringInstance struct {
+	recurring *RecurringJob
+	id        string
+}
+
+// Handle 实现 Job 接口，任务由注册的处理器执行，不会调用该方法
+func (j *recurringInstance) Handle() error {
+	return fmt.Errorf("recurring job %s must be handled by a registered handler", j.recurring.JobType)
+}
+
+// GetID 实现 Job 接口
+func (j *recurringInstance) GetID() string { return j.id }
+
+// GetQueue 实现 Job 接口
+func (j *recurringInstance) GetQueue() string { return j.recurring.Queue }
+
+// GetMaxRetries 实现 Job 接口
+func (j *recurringInstance) GetMaxRetries() int { return j.recurring.MaxRetries }
+
+// GetTimeout 实现 Job 接口
+func (j *recurringInstance) GetTimeout() time.Duration { return j.recurring.Timeout }
+
+// JobType 返回原任务类型
+func (j *recurringInstance) JobType() string { return j.recurring.JobType }
+
+// MarshalJSON 输出原任务数据
+func (j *recurringInstance) MarshalJSON() ([]byte, error) {
+	return []byte(j.recurring.Payload), nil
+}
+
+// jobTypeOf 返回任务类型，即处理器注册时使用的名称
+// 优先使用任务自身的 JobType 方法，其次是 RegisterJobType 注册的名称，都没有时使用 %T 类型名
+func jobTypeOf(job Job) string {
+	if typed, ok := job.(interface{ JobType() string }); ok {
+		return typed.JobType()
+	}
+	if name, ok := registeredJobType(job); ok {
+		return name
+	}
+	return fmt.Sprintf("%T", job)
+}
+
+// memoryRecurring 内存周期任务存储，供 MemoryDriver 使用
+type memoryRecurring struct {
+	mu      sync.Mutex
+	jobs    map[string]*RecurringJob
+	claimed map[string]time.Time
+}
+
+// SaveRecurring 实现 RecurringStore 接口
+func (m *memoryRecurring) SaveRecurring(job *RecurringJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.jobs == nil {
+		m.jobs = make(map[string]*RecurringJob)
+	}
+	saved := *job
+	m.jobs[job.ID] = &saved
+	return nil
+}
+
+// ListRecurring 实现 RecurringStore 接口
+func (m *memoryRecurring) ListRecurring() ([]*RecurringJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]*RecurringJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		copied := *job
+		jobs = append(jobs, &copied)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// DeleteRecurring 实现 RecurringStore 接口
+func (m *memoryRecurring) DeleteRecurring(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.jobs, id)
+	delete(m.claimed, id)
+	return nil
+}
+
+// ClaimRecurring 实现 RecurringStore 接口
+func (m *memoryRecurring) ClaimRecurring(id string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.claimed == nil {
+		m.claimed = make(map[string]time.Time)
+	}
+	if last, ok := m.claimed[id]; ok && !at.After(last) {
+		return false, nil
+	}
+	m.claimed[id] = at
+	return true, nil
+}
@@ -4,16 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/clarkgo/clarkgo/pkg/log"
 	"github.com/redis/go-redis/v9"
 )
 
+// DefaultVisibilityTimeout 默认可见性超时，任务在处理中集合停留超过该时间视为工作进程已崩溃
+const DefaultVisibilityTimeout = 10 * time.Minute
+
+// MinReaperInterval 回收间隔的下限，避免可见性超时很短时频繁扫描处理中集合
+const MinReaperInterval = time.Second
+
 // RedisDriver Redis 队列驱动
 type RedisDriver struct {
 	client *redis.Client
 	prefix string
 	ctx    context.Context
+
+	visibilityTimeout time.Duration
+	stopReaper        chan struct{}
+	reaperMu          sync.Mutex
+	logger            log.StructuredLogger
 }
 
 // NewRedisDriver 创建 Redis 驱动
@@ -22,10 +36,168 @@ func NewRedisDriver(client *redis.Client, prefix string) *RedisDriver {
 		prefix = "queue"
 	}
 	return &RedisDriver{
-		client: client,
-		prefix: prefix,
-		ctx:    context.Background(),
+		client:            client,
+		prefix:            prefix,
+		ctx:               context.Background(),
+		visibilityTimeout: DefaultVisibilityTimeout,
+		logger:            log.Default(),
+	}
+}
+
+// SetLogger 设置回收协程使用的日志记录器
+func (d *RedisDriver) SetLogger(logger log.StructuredLogger) *RedisDriver {
+	d.logger = logger
+	return d
+}
+
+// SetVisibilityTimeout 设置可见性超时，应大于任务的最长执行时间，否则仍在执行的任务会被重新入队
+func (d *RedisDriver) SetVisibilityTimeout(timeout time.Duration) *RedisDriver {
+	d.visibilityTimeout = timeout
+	return d
+}
+
+// ReaperInterval 根据可见性超时推算的回收间隔：取超时的一半，不低于 MinReaperInterval
+// 卡住的任务最迟在约 1.5 倍可见性超时后重新入队
+func (d *RedisDriver) ReaperInterval() time.Duration {
+	interval := d.visibilityTimeout / 2
+	if interval < MinReaperInterval {
+		interval = MinReaperInterval
 	}
+	return interval
+}
+
+// StartReaper 启动后台回收协程，每隔 interval 回收 queues 中超过可见性超时的任务
+// 重复调用无效，Close 时停止
+func (d *RedisDriver) StartReaper(queues []string, interval time.Duration) *RedisDriver {
+	d.reaperMu.Lock()
+	defer d.reaperMu.Unlock()
+
+	if d.stopReaper != nil {
+		return d
+	}
+
+	stop := make(chan struct{})
+	d.stopReaper = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				for _, queue := range queues {
+					reaped, err := d.ReapStuckJobs(queue)
+					if err != nil {
+						d.logger.Error("failed to reap stuck jobs", "queue", queue, "reaped", reaped, "error", err)
+					} else if reaped > 0 {
+						d.logger.Warn("reaped stuck jobs", "queue", queue, "reaped", reaped)
+					}
+				}
+			}
+		}
+	}()
+
+	return d
+}
+
+// ReapStuckJobs 回收处理中集合里超过可见性超时的任务，返回回收数量
+// 工作进程在 Pop 之后、Ack 之前崩溃时任务会一直停留在处理中集合；
+// 回收时该次尝试计入 Attempts（Pop 时已累加），未超过最大重试次数则重新入队，否则进入死信队列
+func (d *RedisDriver) ReapStuckJobs(queue string) (int, error) {
+	cutoff := time.Now().Add(-d.visibilityTimeout)
+
+	jobIDs, err := d.client.ZRangeByScore(d.ctx, d.processingKey(queue), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", cutoff.Unix()),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	for _, jobID := range jobIDs {
+		ok, err := d.reapJob(queue, jobID)
+		if err != nil {
+			return reaped, err
+		}
+		if ok {
+			reaped++
+		}
+	}
+
+	return reaped, nil
+}
+
+// reapJob 回收单个任务，返回是否已回收
+// 在 WATCH 任务详情的事务中先读取任务，再在同一个 MULTI 中写回任务、重新入队并移出处理中集合：
+// 回收进程中途崩溃时任务仍留在处理中集合，多个回收进程或 Ack 并发修改任务时事务失败，不会重复入队
+func (d *RedisDriver) reapJob(queue, jobID string) (bool, error) {
+	processingKey := d.processingKey(queue)
+	jobKey := d.jobKey(jobID)
+
+	reaped := false
+	err := d.client.Watch(d.ctx, func(tx *redis.Tx) error {
+		// 已被确认、重试或其他回收进程处理
+		if err := tx.ZScore(d.ctx, processingKey, jobID).Err(); err != nil {
+			if err == redis.Nil {
+				return nil
+			}
+			return err
+		}
+
+		data, err := tx.Get(d.ctx, jobKey).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		var record JobRecord
+		if err == redis.Nil || json.Unmarshal(data, &record) != nil || record.Status != StatusRunning {
+			// 任务详情已过期或不在执行中，只需移出处理中集合
+			_, err := tx.TxPipelined(d.ctx, func(pipe redis.Pipeliner) error {
+				pipe.ZRem(d.ctx, processingKey, jobID)
+				return nil
+			})
+			return err
+		}
+
+		stuckErr := fmt.Errorf("job exceeded visibility timeout of %v", d.visibilityTimeout)
+		now := time.Now()
+		record.Error = stuckErr.Error()
+
+		targetKey := d.queueKey(record.Queue)
+		if record.Attempts >= record.MaxRetries {
+			record.Status = StatusDead
+			record.FailedAt = &now
+			targetKey = d.deadKey()
+		} else {
+			record.Status = StatusPending
+			record.ScheduledAt = now
+		}
+
+		recordData, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(d.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(d.ctx, jobKey, recordData, 7*24*time.Hour)
+			pipe.LPush(d.ctx, targetKey, jobID)
+			pipe.ZRem(d.ctx, processingKey, jobID)
+			return nil
+		})
+		if err == nil {
+			reaped = true
+		}
+		return err
+	}, jobKey)
+
+	if err == redis.TxFailedErr {
+		return false, nil
+	}
+	return reaped, err
 }
 
 // Push 推送任务
@@ -46,7 +218,7 @@ func (d *RedisDriver) PushDelay(job Job, delay time.Duration) error {
 	record := &JobRecord{
 		ID:          job.GetID(),
 		Queue:       job.GetQueue(),
-		JobType:     fmt.Sprintf("%T", job),
+		JobType:     jobTypeOf(job),
 		Payload:     payload,
 		Status:      StatusPending,
 		Attempts:    0,
@@ -351,11 +523,90 @@ func (d *RedisDriver) GetStats(queue string) (map[string]interface{}, error) {
 	return stats, nil
 }
 
-// Close 关闭驱动
+// GetQueueDepth 实现 DepthReporter 接口
+func (d *RedisDriver) GetQueueDepth(queue string) (pending, delayed, processing int64, err error) {
+	pipe := d.client.Pipeline()
+	pendingCmd := pipe.LLen(d.ctx, d.queueKey(queue))
+	delayedCmd := pipe.ZCard(d.ctx, d.delayedKey(queue))
+	processingCmd := pipe.ZCard(d.ctx, d.processingKey(queue))
+	if _, err := pipe.Exec(d.ctx); err != nil && err != redis.Nil {
+		return 0, 0, 0, err
+	}
+	return pendingCmd.Val(), delayedCmd.Val(), processingCmd.Val(), nil
+}
+
+// OldestJobAge 实现 DepthReporter 接口，任务从列表头部推入、尾部取出，尾部即最早的任务
+func (d *RedisDriver) OldestJobAge(queue string) (time.Duration, error) {
+	jobID, err := d.client.LIndex(d.ctx, d.queueKey(queue), -1).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	record, err := d.GetJob(jobID)
+	if err == redis.Nil {
+		return 0, nil // 任务详情已过期
+	}
+	if err != nil {
+		return 0, err
+	}
+	return jobAge(record, time.Now()), nil
+}
+
+// Close 关闭驱动，停止回收协程
 func (d *RedisDriver) Close() error {
+	d.reaperMu.Lock()
+	if d.stopReaper != nil {
+		close(d.stopReaper)
+		d.stopReaper = nil
+	}
+	d.reaperMu.Unlock()
+
 	return nil // Redis 客户端由外部管理
 }
 
+// SaveRecurring 实现 RecurringStore 接口，周期任务定义保存在哈希中，不设置过期时间
+func (d *RedisDriver) SaveRecurring(job *RecurringJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return d.client.HSet(d.ctx, d.recurringKey(), job.ID, data).Err()
+}
+
+// ListRecurring 实现 RecurringStore 接口
+func (d *RedisDriver) ListRecurring() ([]*RecurringJob, error) {
+	values, err := d.client.HGetAll(d.ctx, d.recurringKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*RecurringJob, 0, len(values))
+	for _, value := range values {
+		var job RecurringJob
+		if err := json.Unmarshal([]byte(value), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// DeleteRecurring 实现 RecurringStore 接口
+func (d *RedisDriver) DeleteRecurring(id string) error {
+	return d.client.HDel(d.ctx, d.recurringKey(), id).Err()
+}
+
+// ClaimRecurring 实现 RecurringStore 接口，使用 SETNX 保证多个工作进程只推送一次
+func (d *RedisDriver) ClaimRecurring(id string, at time.Time) (bool, error) {
+	return d.client.SetNX(d.ctx, d.recurringClaimKey(id, at), 1, 2*time.Minute).Result()
+}
+
 // 键名辅助方法
 func (d *RedisDriver) queueKey(queue string) string {
 	return fmt.Sprintf("%s:queue:%s", d.prefix, queue)
@@ -376,3 +627,11 @@ func (d *RedisDriver) jobKey(jobID string) string {
 func (d *RedisDriver) deadKey() string {
 	return fmt.Sprintf("%s:dead", d.prefix)
 }
+
+func (d *RedisDriver) recurringKey() string {
+	return fmt.Sprintf("%s:recurring", d.prefix)
+}
+
+func (d *RedisDriver) recurringClaimKey(id string, at time.Time) string {
+	return fmt.Sprintf("%s:recurring_claim:%s:%d", d.prefix, id, at.Unix())
+}
@@ -0,0 +1,129 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedisDriver(t *testing.T) *RedisDriver {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisDriver(client, "test").SetVisibilityTimeout(time.Minute)
+}
+
+// markStuck 将处理中任务的开始时间改为超过可见性超时之前
+func markStuck(t *testing.T, d *RedisDriver, queue, jobID string) {
+	t.Helper()
+	err := d.client.ZAdd(d.ctx, d.processingKey(queue), redis.Z{
+		Score:  float64(time.Now().Add(-2 * time.Minute).Unix()),
+		Member: jobID,
+	}).Err()
+	if err != nil {
+		t.Fatalf("ZAdd failed: %v", err)
+	}
+}
+
+func TestRedisDriverReapStuckJobs(t *testing.T) {
+	d := newTestRedisDriver(t)
+
+	d.Push(&testJob{BaseJob: BaseJob{ID: "retry", Queue: "default", MaxRetries: 3}})
+	d.Push(&testJob{BaseJob: BaseJob{ID: "dead", Queue: "default", MaxRetries: 1}})
+	for i := 0; i < 2; i++ {
+		if record, err := d.Pop("default", time.Second); err != nil || record == nil {
+			t.Fatalf("Pop failed: %v", err)
+		}
+	}
+
+	// 未超过可见性超时的任务不回收
+	if reaped, err := d.ReapStuckJobs("default"); err != nil || reaped != 0 {
+		t.Fatalf("ReapStuckJobs() = %d, %v, want 0", reaped, err)
+	}
+
+	markStuck(t, d, "default", "retry")
+	markStuck(t, d, "default", "dead")
+	if reaped, err := d.ReapStuckJobs("default"); err != nil || reaped != 2 {
+		t.Fatalf("ReapStuckJobs() = %d, %v, want 2", reaped, err)
+	}
+	if count := d.client.ZCard(d.ctx, d.processingKey("default")).Val(); count != 0 {
+		t.Errorf("expected processing set to be empty, got %d", count)
+	}
+
+	// 未超过最大重试次数的任务重新入队，否则进入死信队列
+	if record, _ := d.GetJob("retry"); record.Status != StatusPending || record.Error == "" {
+		t.Errorf("unexpected retried job %+v", record)
+	}
+	if record, _ := d.GetJob("dead"); record.Status != StatusDead || record.FailedAt == nil {
+		t.Errorf("unexpected dead job %+v", record)
+	}
+	if dead := d.client.LRange(d.ctx, d.deadKey(), 0, -1).Val(); len(dead) != 1 || dead[0] != "dead" {
+		t.Errorf("unexpected dead letter queue %v", dead)
+	}
+
+	// 重复回收不会再次入队
+	if reaped, err := d.ReapStuckJobs("default"); err != nil || reaped != 0 {
+		t.Fatalf("second ReapStuckJobs() = %d, %v, want 0", reaped, err)
+	}
+	if length := d.client.LLen(d.ctx, d.queueKey("default")).Val(); length != 1 {
+		t.Errorf("expected job to be queued once, got %d", length)
+	}
+
+	record, err := d.Pop("default", time.Second)
+	if err != nil || record == nil || record.ID != "retry" || record.Attempts != 2 {
+		t.Fatalf("expected reaped job to be popped again, got %+v, %v", record, err)
+	}
+}
+
+func TestRedisDriverReapSkipsFinishedJobs(t *testing.T) {
+	d := newTestRedisDriver(t)
+
+	// 回收前已被确认的任务只移出处理中集合
+	d.Push(&testJob{BaseJob: BaseJob{ID: "done", Queue: "default", MaxRetries: 3}})
+	if _, err := d.Pop("default", time.Second); err != nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	if err := d.Ack("done"); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	markStuck(t, d, "default", "done")
+
+	// 任务详情已过期的任务同样只移出处理中集合
+	markStuck(t, d, "default", "expired")
+
+	if reaped, err := d.ReapStuckJobs("default"); err != nil || reaped != 0 {
+		t.Fatalf("ReapStuckJobs() = %d, %v, want 0", reaped, err)
+	}
+	if record, _ := d.GetJob("done"); record.Status != StatusCompleted {
+		t.Errorf("expected acked job to stay completed, got %s", record.Status)
+	}
+	if count := d.client.ZCard(d.ctx, d.processingKey("default")).Val(); count != 0 {
+		t.Errorf("expected processing set to be empty, got %d", count)
+	}
+	if length := d.client.LLen(d.ctx, d.queueKey("default")).Val(); length != 0 {
+		t.Errorf("expected no job to be requeued, got %d", length)
+	}
+}
+
+func TestRedisDriverReaperInterval(t *testing.T) {
+	driver := NewRedisDriver(nil, "")
+	if got := driver.ReaperInterval(); got != DefaultVisibilityTimeout/2 {
+		t.Errorf("Expected half the default visibility timeout, got %v", got)
+	}
+
+	driver.SetVisibilityTimeout(90 * time.Second)
+	if got := driver.ReaperInterval(); got != 45*time.Second {
+		t.Errorf("Expected 45s, got %v", got)
+	}
+
+	// 超时很短时不低于下限
+	driver.SetVisibilityTimeout(100 * time.Millisecond)
+	if got := driver.ReaperInterval(); got != MinReaperInterval {
+		t.Errorf("Expected MinReaperInterval, got %v", got)
+	}
+}
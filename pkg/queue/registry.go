@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// 任务类型注册表：名称 -> 工厂函数，Go 类型 -> 名称
+var (
+	jobTypesMu   sync.RWMutex
+	jobFactories = make(map[string]func() Job)
+	jobTypeNames = make(map[reflect.Type]string)
+)
+
+// RegisterJobType 为任务类型注册稳定的名称，通常在 init 中调用
+// 注册后推送该类型的任务时以 name 作为 JobType 保存，而不是 %T 得到的包路径类型名，
+// 类型移动包或重命名后已入队的任务仍能被处理；factory 返回新的零值任务，用于 DecodeJob 反序列化
+// 名称或类型重复注册时 panic
+func RegisterJobType(name string, factory func() Job) {
+	if name == "" || factory == nil {
+		panic("queue: RegisterJobType requires a name and a factory")
+	}
+
+	t := reflect.TypeOf(factory())
+
+	jobTypesMu.Lock()
+	defer jobTypesMu.Unlock()
+
+	if _, exists := jobFactories[name]; exists {
+		panic(fmt.Sprintf("queue: job type %q already registered", name))
+	}
+	if existing, exists := jobTypeNames[t]; exists {
+		panic(fmt.Sprintf("queue: %s already registered as %q", t, existing))
+	}
+	jobFactories[name] = factory
+	jobTypeNames[t] = name
+}
+
+// DecodeJob 按注册的任务类型名称将任务数据还原为具体类型
+func DecodeJob(jobType string, payload []byte) (Job, error) {
+	jobTypesMu.RLock()
+	factory, exists := jobFactories[jobType]
+	jobTypesMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("job type %s not registered", jobType)
+	}
+
+	job := factory()
+	if err := json.Unmarshal(payload, job); err != nil {
+		return nil, fmt.Errorf("failed to decode %s job: %w", jobType, err)
+	}
+	return job, nil
+}
+
+// registeredJobType 返回任务类型注册的名称
+func registeredJobType(job Job) (string, bool) {
+	jobTypesMu.RLock()
+	defer jobTypesMu.RUnlock()
+
+	name, exists := jobTypeNames[reflect.TypeOf(job)]
+	return name, exists
+}
+
+// registeredHandler 为注册了名称但没有处理器的任务类型生成处理器：还原任务后调用其 Handle 方法
+func registeredHandler(jobType string) (ContextJobHandler, bool) {
+	jobTypesMu.RLock()
+	_, exists := jobFactories[jobType]
+	jobTypesMu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	return func(ctx context.Context, payload []byte) error {
+		job, err := DecodeJob(jobType, payload)
+		if err != nil {
+			return err
+		}
+		return job.Handle()
+	}, true
+}
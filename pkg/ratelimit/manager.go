@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"sort"
+	"sync"
+)
+
+// closer 可关闭的限流器（TokenBucket、SlidingWindow 会启动 GC goroutine）
+type closer interface {
+	Close()
+}
+
+// Manager 命名限流器管理器，统一管理多个限流器的生命周期
+type Manager struct {
+	limiters map[string]Limiter
+	mu       sync.RWMutex
+}
+
+// NewManager 创建限流器管理器
+func NewManager() *Manager {
+	return &Manager{
+		limiters: make(map[string]Limiter),
+	}
+}
+
+// Register 注册命名限流器，同名限流器会被关闭并替换
+func (m *Manager) Register(name string, limiter Limiter) *Manager {
+	m.mu.Lock()
+	old, exists := m.limiters[name]
+	m.limiters[name] = limiter
+	m.mu.Unlock()
+
+	if exists && old != limiter {
+		closeLimiter(old)
+	}
+	return m
+}
+
+// Get 获取命名限流器
+func (m *Manager) Get(name string) (Limiter, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	limiter, exists := m.limiters[name]
+	return limiter, exists
+}
+
+// Names 获取所有已注册的限流器名称（已排序）
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.limiters))
+	for name := range m.limiters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Allow 使用命名限流器检查是否允许请求，未注册的限流器不做限制
+func (m *Manager) Allow(name, key string) bool {
+	return m.AllowN(name, key, 1)
+}
+
+// AllowN 使用命名限流器检查是否允许 n 个请求，未注册的限流器不做限制
+func (m *Manager) AllowN(name, key string, n int) bool {
+	limiter, exists := m.Get(name)
+	if !exists {
+		return true
+	}
+	return limiter.AllowN(key, n)
+}
+
+// Remove 移除并关闭命名限流器
+func (m *Manager) Remove(name string) {
+	m.mu.Lock()
+	limiter, exists := m.limiters[name]
+	delete(m.limiters, name)
+	m.mu.Unlock()
+
+	if exists {
+		closeLimiter(limiter)
+	}
+}
+
+// Close 关闭所有限流器并清空注册表
+func (m *Manager) Close() {
+	m.mu.Lock()
+	limiters := m.limiters
+	m.limiters = make(map[string]Limiter)
+	m.mu.Unlock()
+
+	for _, limiter := range limiters {
+		closeLimiter(limiter)
+	}
+}
+
+// closeLimiter 关闭支持 Close 的限流器
+func closeLimiter(limiter Limiter) {
+	if c, ok := limiter.(closer); ok {
+		c.Close()
+	}
+}
@@ -17,17 +17,26 @@ type Limiter interface {
 	Reset(key string)
 }
 
+// DefaultTokenBucketShards 令牌桶默认分片数
+const DefaultTokenBucketShards = 32
+
 // TokenBucket 令牌桶算法实现
+// 键按哈希分布到多个分片，每个分片有独立的锁，降低大量不同键并发访问时的锁竞争
 type TokenBucket struct {
 	rate       int // 每秒生成的令牌数
 	capacity   int // 桶容量
-	buckets    map[string]*bucket
-	mu         sync.RWMutex
+	shards     []*bucketShard
 	gcInterval time.Duration // 垃圾回收间隔
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
 
+// bucketShard 令牌桶分片
+type bucketShard struct {
+	buckets map[string]*bucket
+	mu      sync.RWMutex
+}
+
 type bucket struct {
 	tokens    float64
 	lastCheck time.Time
@@ -36,15 +45,27 @@ type bucket struct {
 
 // NewTokenBucket 创建令牌桶限流器
 func NewTokenBucket(rate, capacity int) *TokenBucket {
+	return NewShardedTokenBucket(rate, capacity, DefaultTokenBucketShards)
+}
+
+// NewShardedTokenBucket 创建指定分片数的令牌桶限流器，shards 小于 1 时使用 1
+func NewShardedTokenBucket(rate, capacity, shards int) *TokenBucket {
+	if shards < 1 {
+		shards = 1
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	tb := &TokenBucket{
 		rate:       rate,
 		capacity:   capacity,
-		buckets:    make(map[string]*bucket),
+		shards:     make([]*bucketShard, shards),
 		gcInterval: 5 * time.Minute,
 		ctx:        ctx,
 		cancel:     cancel,
 	}
+	for i := range tb.shards {
+		tb.shards[i] = &bucketShard{buckets: make(map[string]*bucket)}
+	}
 
 	// 启动垃圾回收
 	go tb.gc()
@@ -52,6 +73,20 @@ func NewTokenBucket(rate, capacity int) *TokenBucket {
 	return tb
 }
 
+// shard 返回键所在的分片（FNV-1a 哈希）
+func (tb *TokenBucket) shard(key string) *bucketShard {
+	if len(tb.shards) == 1 {
+		return tb.shards[0]
+	}
+
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return tb.shards[hash%uint32(len(tb.shards))]
+}
+
 // Allow 检查是否允许请求
 func (tb *TokenBucket) Allow(key string) bool {
 	return tb.AllowN(key, 1)
@@ -59,21 +94,59 @@ func (tb *TokenBucket) Allow(key string) bool {
 
 // AllowN 检查是否允许 n 个请求
 func (tb *TokenBucket) AllowN(key string, n int) bool {
-	tb.mu.RLock()
-	b, exists := tb.buckets[key]
-	tb.mu.RUnlock()
+	ok, _ := tb.take(key, n)
+	return ok
+}
+
+// Wait 阻塞直到获得一个令牌或 ctx 取消，用法同 golang.org/x/time/rate.Limiter.Wait
+func (tb *TokenBucket) Wait(ctx context.Context, key string) error {
+	return tb.WaitN(ctx, key, 1)
+}
+
+// WaitN 阻塞直到获得 n 个令牌或 ctx 取消
+// 等待时间按令牌生成速率计算，不会忙等；n 超过桶容量时永远无法满足，直接返回错误
+func (tb *TokenBucket) WaitN(ctx context.Context, key string, n int) error {
+	if n > tb.capacity {
+		return fmt.Errorf("ratelimit: wait for %d tokens exceeds bucket capacity %d", n, tb.capacity)
+	}
+
+	for {
+		ok, wait := tb.take(key, n)
+		if ok {
+			return nil
+		}
+		if tb.rate <= 0 {
+			return fmt.Errorf("ratelimit: no tokens available and refill rate is %d", tb.rate)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take 尝试取出 n 个令牌，不足时返回令牌补足所需的等待时间
+func (tb *TokenBucket) take(key string, n int) (bool, time.Duration) {
+	shard := tb.shard(key)
+	shard.mu.RLock()
+	b, exists := shard.buckets[key]
+	shard.mu.RUnlock()
 
 	if !exists {
-		tb.mu.Lock()
+		shard.mu.Lock()
 		// 双重检查
-		if b, exists = tb.buckets[key]; !exists {
+		if b, exists = shard.buckets[key]; !exists {
 			b = &bucket{
 				tokens:    float64(tb.capacity),
 				lastCheck: time.Now(),
 			}
-			tb.buckets[key] = b
+			shard.buckets[key] = b
 		}
-		tb.mu.Unlock()
+		shard.mu.Unlock()
 	}
 
 	b.mu.Lock()
@@ -94,17 +167,22 @@ func (tb *TokenBucket) AllowN(key string, n int) bool {
 	// 检查是否有足够的令牌
 	if b.tokens >= float64(n) {
 		b.tokens -= float64(n)
-		return true
+		return true, 0
 	}
 
-	return false
+	if tb.rate <= 0 {
+		return false, 0
+	}
+	missing := float64(n) - b.tokens
+	return false, time.Duration(missing / float64(tb.rate) * float64(time.Second))
 }
 
 // Reset 重置指定键的限制
 func (tb *TokenBucket) Reset(key string) {
-	tb.mu.Lock()
-	defer tb.mu.Unlock()
-	delete(tb.buckets, key)
+	shard := tb.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.buckets, key)
 }
 
 // Close 关闭限流器
@@ -112,6 +190,38 @@ func (tb *TokenBucket) Close() {
 	tb.cancel()
 }
 
+// GetStats 获取统计信息，按补充公式计算当前令牌数但不消耗令牌
+func (tb *TokenBucket) GetStats(key string) map[string]interface{} {
+	shard := tb.shard(key)
+	shard.mu.RLock()
+	b, exists := shard.buckets[key]
+	shard.mu.RUnlock()
+
+	tokens := float64(tb.capacity)
+	if exists {
+		b.mu.Lock()
+		tokens = b.tokens + time.Since(b.lastCheck).Seconds()*float64(tb.rate)
+		b.mu.Unlock()
+
+		if tokens > float64(tb.capacity) {
+			tokens = float64(tb.capacity)
+		}
+	}
+
+	// 估算令牌补满所需时间
+	timeToFull := time.Duration(0)
+	if tb.rate > 0 && tokens < float64(tb.capacity) {
+		timeToFull = time.Duration((float64(tb.capacity) - tokens) / float64(tb.rate) * float64(time.Second))
+	}
+
+	return map[string]interface{}{
+		"tokens":       tokens,
+		"capacity":     tb.capacity,
+		"rate":         tb.rate,
+		"time_to_full": timeToFull.String(),
+	}
+}
+
 // gc 垃圾回收
 func (tb *TokenBucket) gc() {
 	ticker := time.NewTicker(tb.gcInterval)
@@ -122,17 +232,20 @@ func (tb *TokenBucket) gc() {
 		case <-tb.ctx.Done():
 			return
 		case <-ticker.C:
-			tb.mu.Lock()
 			now := time.Now()
-			for key, b := range tb.buckets {
-				b.mu.Lock()
-				// 如果桶超过 10 分钟没有使用，删除它
-				if now.Sub(b.lastCheck) > 10*time.Minute {
-					delete(tb.buckets, key)
+			// 逐个分片回收，避免长时间阻塞所有键
+			for _, shard := range tb.shards {
+				shard.mu.Lock()
+				for key, b := range shard.buckets {
+					b.mu.Lock()
+					// 如果桶超过 10 分钟没有使用，删除它
+					if now.Sub(b.lastCheck) > 10*time.Minute {
+						delete(shard.buckets, key)
+					}
+					b.mu.Unlock()
 				}
-				b.mu.Unlock()
+				shard.mu.Unlock()
 			}
-			tb.mu.Unlock()
 		}
 	}
 }
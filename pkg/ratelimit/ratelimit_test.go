@@ -1,6 +1,9 @@
 package ratelimit
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -89,6 +92,60 @@ func TestTokenBucket_Reset(t *testing.T) {
 	}
 }
 
+func TestTokenBucket_GetStats(t *testing.T) {
+	tb := NewTokenBucket(1, 10)
+	defer tb.Close()
+
+	stats := tb.GetStats("test_user")
+	if stats["tokens"] != float64(10) {
+		t.Errorf("Expected 10 tokens for unknown key, got %v", stats["tokens"])
+	}
+
+	tb.AllowN("test_user", 4)
+
+	stats = tb.GetStats("test_user")
+	tokens := stats["tokens"].(float64)
+	if tokens < 6 || tokens > 6.1 {
+		t.Errorf("Expected about 6 tokens, got %v", tokens)
+	}
+	if stats["capacity"] != 10 || stats["rate"] != 1 {
+		t.Errorf("Unexpected capacity/rate: %v/%v", stats["capacity"], stats["rate"])
+	}
+
+	// 获取统计不应消耗令牌
+	if !tb.AllowN("test_user", 6) {
+		t.Error("GetStats should not consume tokens")
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	tb := NewTokenBucket(20, 1) // 20 tokens/sec, capacity 1
+	defer tb.Close()
+
+	if err := tb.Wait(context.Background(), "test_user"); err != nil {
+		t.Fatalf("Expected immediate token, got %v", err)
+	}
+
+	// Next token is refilled after ~50ms
+	start := time.Now()
+	if err := tb.Wait(context.Background(), "test_user"); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected to wait about 50ms, waited %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tb.Wait(ctx, "test_user"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+
+	if err := tb.WaitN(context.Background(), "test_user", 2); err == nil {
+		t.Error("Expected error when waiting for more tokens than capacity")
+	}
+}
+
 func TestSlidingWindow_Allow(t *testing.T) {
 	sw := NewSlidingWindow(5, 1*time.Second) // 5 requests per second
 
@@ -192,6 +249,39 @@ func TestFixedWindow_AllowN(t *testing.T) {
 	}
 }
 
+func TestManager(t *testing.T) {
+	m := NewManager()
+	defer m.Close()
+
+	m.Register("ip", NewTokenBucket(1, 2)).
+		Register("endpoint", NewFixedWindow(1, time.Second))
+
+	if !m.Allow("ip", "1.2.3.4") || !m.Allow("ip", "1.2.3.4") {
+		t.Error("First 2 requests should be allowed")
+	}
+	if m.Allow("ip", "1.2.3.4") {
+		t.Error("3rd request should be denied")
+	}
+
+	if !m.Allow("endpoint", "GET:/") || m.Allow("endpoint", "GET:/") {
+		t.Error("Endpoint limiter should allow exactly 1 request")
+	}
+
+	if !m.Allow("unknown", "key") {
+		t.Error("Unregistered limiter should not limit")
+	}
+
+	names := m.Names()
+	if len(names) != 2 || names[0] != "endpoint" || names[1] != "ip" {
+		t.Errorf("Unexpected names: %v", names)
+	}
+
+	m.Close()
+	if len(m.Names()) != 0 {
+		t.Error("Close should remove all limiters")
+	}
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	tb := NewTokenBucket(100, 200)
 	sw := NewSlidingWindow(100, 1*time.Second)
@@ -275,3 +365,63 @@ func BenchmarkConcurrentTokenBucket(b *testing.B) {
 		}
 	})
 }
+
+func TestShardedTokenBucket(t *testing.T) {
+	tb := NewShardedTokenBucket(1, 2, 0)
+	defer tb.Close()
+	if len(tb.shards) != 1 {
+		t.Errorf("Expected at least 1 shard, got %d", len(tb.shards))
+	}
+
+	sharded := NewShardedTokenBucket(1, 2, 8)
+	defer sharded.Close()
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user_%d", i)
+		if !sharded.Allow(key) || !sharded.Allow(key) || sharded.Allow(key) {
+			t.Fatalf("Expected exactly 2 requests allowed for %s", key)
+		}
+	}
+
+	used := 0
+	for _, shard := range sharded.shards {
+		if len(shard.buckets) > 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		t.Errorf("Expected keys to be spread across shards, got %d used", used)
+	}
+
+	sharded.Reset("user_1")
+	if !sharded.Allow("user_1") {
+		t.Error("Expected request allowed after reset")
+	}
+}
+
+// benchmarkTokenBucketKeys 并发访问大量不同键，对比分片与单锁的锁竞争
+func benchmarkTokenBucketKeys(b *testing.B, shards int) {
+	tb := NewShardedTokenBucket(10000, 20000, shards)
+	defer tb.Close()
+
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user_%d", i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			tb.Allow(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentTokenBucket_SingleLock(b *testing.B) {
+	benchmarkTokenBucketKeys(b, 1)
+}
+
+func BenchmarkConcurrentTokenBucket_Sharded(b *testing.B) {
+	benchmarkTokenBucketKeys(b, DefaultTokenBucketShards)
+}
@@ -107,3 +107,8 @@ func (c *Client) Publish(ctx context.Context, channel string, message interface{
 func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
 	return c.client.Subscribe(ctx, channels...)
 }
+
+// Raw 获取底层 go-redis 客户端，未连接时返回 nil
+func (c *Client) Raw() *redis.Client {
+	return c.client
+}
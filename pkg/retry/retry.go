@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// 默认重试策略
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+)
+
+// Config 重试策略
+type Config struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       bool
+	OnRetry      func(attempt int, err error, delay time.Duration)
+}
+
+// Option 重试选项
+type Option func(*Config)
+
+// WithMaxAttempts 设置最大尝试次数（包含首次执行）
+func WithMaxAttempts(n int) Option {
+	return func(c *Config) {
+		c.MaxAttempts = n
+	}
+}
+
+// WithBackoff 设置指数退避的初始间隔和最大间隔
+func WithBackoff(initial, maxDelay time.Duration) Option {
+	return func(c *Config) {
+		c.InitialDelay = initial
+		c.MaxDelay = maxDelay
+	}
+}
+
+// WithMultiplier 设置退避倍数，默认 2
+func WithMultiplier(multiplier float64) Option {
+	return func(c *Config) {
+		c.Multiplier = multiplier
+	}
+}
+
+// WithJitter 设置是否为退避间隔添加随机抖动，默认开启
+func WithJitter(jitter bool) Option {
+	return func(c *Config) {
+		c.Jitter = jitter
+	}
+}
+
+// WithOnRetry 设置重试回调，在每次等待重试前调用
+// attempt 为刚失败的尝试序号（从 1 开始）
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *Config) {
+		c.OnRetry = fn
+	}
+}
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent 包装不应重试的错误，Do 遇到后立即返回原始错误
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 检查错误是否被标记为不可重试
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Do 执行 fn，失败时按指数退避重试，直到成功、遇到 Permanent 错误、达到最大次数或 ctx 结束
+// 返回 fn 最后一次的错误（Permanent 错误会被解包）
+func Do(ctx context.Context, fn func() error, opts ...Option) error {
+	config := Config{
+		MaxAttempts:  DefaultMaxAttempts,
+		InitialDelay: DefaultInitialDelay,
+		MaxDelay:     DefaultMaxDelay,
+		Multiplier:   2,
+		Jitter:       true,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if attempt >= config.MaxAttempts {
+			return err
+		}
+
+		delay := config.backoff(attempt)
+		if config.OnRetry != nil {
+			config.OnRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 计算第 attempt 次失败后的等待时间
+// 开启抖动时在 [delay/2, delay] 范围内随机取值
+func (c *Config) backoff(attempt int) time.Duration {
+	delay := float64(c.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= c.Multiplier
+		if c.MaxDelay > 0 && delay >= float64(c.MaxDelay) {
+			delay = float64(c.MaxDelay)
+			break
+		}
+	}
+
+	if c.Jitter && delay > 0 {
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	errTemporary := errors.New("temporary")
+
+	calls := 0
+	var retries []int
+	err := Do(ctx, func() error {
+		calls++
+		if calls < 3 {
+			return errTemporary
+		}
+		return nil
+	},
+		WithMaxAttempts(5),
+		WithBackoff(time.Millisecond, 5*time.Millisecond),
+		WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			retries = append(retries, attempt)
+		}),
+	)
+	if err != nil || calls != 3 {
+		t.Fatalf("Do() = %v after %d calls", err, calls)
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("unexpected OnRetry attempts %v", retries)
+	}
+
+	// 达到最大次数返回最后的错误
+	calls = 0
+	err = Do(ctx, func() error {
+		calls++
+		return errTemporary
+	}, WithMaxAttempts(3), WithBackoff(time.Millisecond, time.Millisecond))
+	if !errors.Is(err, errTemporary) || calls != 3 {
+		t.Errorf("Do() = %v after %d calls, want temporary after 3", err, calls)
+	}
+
+	// Permanent 错误立即停止并解包
+	calls = 0
+	errFatal := errors.New("fatal")
+	err = Do(ctx, func() error {
+		calls++
+		return Permanent(errFatal)
+	}, WithMaxAttempts(3))
+	if err != errFatal || calls != 1 {
+		t.Errorf("Do() = %v after %d calls, want fatal after 1", err, calls)
+	}
+
+	// context 取消时停止等待
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = Do(cancelled, func() error { return errTemporary }, WithBackoff(time.Hour, time.Hour))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	config := Config{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, want := range expected {
+		if got := config.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+
+	config.Jitter = true
+	for i := 0; i < 100; i++ {
+		if got := config.backoff(2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("jittered backoff %v out of range", got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package schedule
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -117,12 +118,25 @@ func (tb *TaskBuilder) Description(desc string) *TaskBuilder {
 	return tb
 }
 
+// CatchUp 调度器启动时补跑停机期间错过的执行（只补跑一次）
+// 需要通过 Scheduler.WithStore 持久化 LastRunAt 才能跨进程重启生效
+func (tb *TaskBuilder) CatchUp() *TaskBuilder {
+	tb.task.CatchUp = true
+	return tb
+}
+
 // Do 设置处理函数并注册任务
 func (tb *TaskBuilder) Do(handler func() error) error {
 	tb.task.Handler = handler
 	return tb.scheduler.AddTask(tb.task)
 }
 
+// DoWithContext 设置带 context 的处理函数并注册任务
+func (tb *TaskBuilder) DoWithContext(handler func(ctx context.Context) error) error {
+	tb.task.ContextHandler = handler
+	return tb.scheduler.AddTask(tb.task)
+}
+
 // Weekdays 工作日执行
 func (tb *TaskBuilder) Weekdays() *TaskBuilder {
 	tb.task.Schedule = "0 0 * * 1-5" // 周一到周五
@@ -2,6 +2,7 @@ package schedule
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -14,12 +15,16 @@ type CronExpression struct {
 	dayOfMonth []int // 1-31
 	month      []int // 1-12
 	dayOfWeek  []int // 0-6 (0 = Sunday)
+
+	// 日期和星期字段是否为通配（以 * 开头），两者都非通配时按“或”匹配
+	dayOfMonthAny bool
+	dayOfWeekAny  bool
 }
 
 // ParseCron 解析 Cron 表达式
 // 格式: "minute hour day month weekday"
 // 例如: "0 8 * * *" 表示每天 8:00
-// 支持: * , - / 语法
+// 支持: * , - / 语法，月份和星期字段支持英文缩写（如 JAN、MON-FRI，不区分大小写）
 func ParseCron(expr string) (*CronExpression, error) {
 	fields := strings.Fields(expr)
 	if len(fields) != 5 {
@@ -30,42 +35,69 @@ func ParseCron(expr string) (*CronExpression, error) {
 	var err error
 
 	// 解析分钟
-	cron.minute, err = parseField(fields[0], 0, 59)
+	cron.minute, err = parseField(fields[0], 0, 59, nil)
 	if err != nil {
 		return nil, fmt.Errorf("invalid minute field: %w", err)
 	}
 
 	// 解析小时
-	cron.hour, err = parseField(fields[1], 0, 23)
+	cron.hour, err = parseField(fields[1], 0, 23, nil)
 	if err != nil {
 		return nil, fmt.Errorf("invalid hour field: %w", err)
 	}
 
 	// 解析日期
-	cron.dayOfMonth, err = parseField(fields[2], 1, 31)
+	cron.dayOfMonth, err = parseField(fields[2], 1, 31, nil)
 	if err != nil {
 		return nil, fmt.Errorf("invalid day field: %w", err)
 	}
 
 	// 解析月份
-	cron.month, err = parseField(fields[3], 1, 12)
+	cron.month, err = parseField(fields[3], 1, 12, monthNames)
 	if err != nil {
 		return nil, fmt.Errorf("invalid month field: %w", err)
 	}
 
 	// 解析星期
-	cron.dayOfWeek, err = parseField(fields[4], 0, 6)
+	cron.dayOfWeek, err = parseField(fields[4], 0, 6, weekdayNames)
 	if err != nil {
 		return nil, fmt.Errorf("invalid weekday field: %w", err)
 	}
 
+	cron.dayOfMonthAny = strings.HasPrefix(fields[2], "*")
+	cron.dayOfWeekAny = strings.HasPrefix(fields[4], "*")
+
 	return cron, nil
 }
 
+// monthNames 月份英文缩写
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+// weekdayNames 星期英文缩写
+var weekdayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// nameRegexp 匹配字段中的英文名称
+var nameRegexp = regexp.MustCompile(`[A-Za-z]+`)
+
 // parseField 解析单个字段
-func parseField(field string, min, max int) ([]int, error) {
+// names 不为空时先将字段中的英文名称替换为对应数字
+func parseField(field string, min, max int, names map[string]int) ([]int, error) {
 	var values []int
 
+	if names != nil {
+		field = nameRegexp.ReplaceAllStringFunc(field, func(name string) string {
+			if value, ok := names[strings.ToUpper(name)]; ok {
+				return strconv.Itoa(value)
+			}
+			return name
+		})
+	}
+
 	// 处理 *
 	if field == "*" {
 		for i := min; i <= max; i++ {
@@ -133,31 +165,74 @@ func parseField(field string, min, max int) ([]int, error) {
 	return values, nil
 }
 
-// Next 计算下次执行时间
+// Next 计算下一次执行时间（晚于 from 的第一个匹配时间），一年内没有匹配时返回零值
+// 月份、日期、小时不匹配时整段跳过，而不是逐分钟扫描
 func (c *CronExpression) Next(from time.Time) time.Time {
-	// 从下一分钟开始
+	// 从下一分钟开始，最多查找一年
 	t := from.Truncate(time.Minute).Add(time.Minute)
-
-	// 最多查找一年
-	maxIterations := 525600 // 一年的分钟数
-	for i := 0; i < maxIterations; i++ {
-		if c.matches(t) {
+	limit := t.AddDate(1, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !contains(c.month, int(t.Month())):
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location()))
+		case !c.dayMatches(t):
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()))
+		case !contains(c.hour, t.Hour()):
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !contains(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
 			return t
 		}
-		t = t.Add(time.Minute)
 	}
 
 	// 如果找不到，返回零值
 	return time.Time{}
 }
 
+// NextN 返回晚于 from 的 n 个执行时间，每次从上一个执行时间继续查找；一年内的匹配不足 n 个时返回已找到的部分
+func (c *CronExpression) NextN(from time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		next := c.Next(from)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+		from = next
+	}
+	return times
+}
+
+// advance 跳转到 candidate，夏令时切换导致 candidate 不晚于 t 时改为前进一分钟，保证查找向前推进
+func advance(t, candidate time.Time) time.Time {
+	if !candidate.After(t) {
+		return t.Add(time.Minute)
+	}
+	return candidate
+}
+
 // matches 检查时间是否匹配 cron 表达式
+// 与 Vixie cron 一致：日期和星期字段都受限时满足其一即可，否则两者都需满足
 func (c *CronExpression) matches(t time.Time) bool {
-	return contains(c.minute, t.Minute()) &&
-		contains(c.hour, t.Hour()) &&
-		contains(c.dayOfMonth, t.Day()) &&
-		contains(c.month, int(t.Month())) &&
-		contains(c.dayOfWeek, int(t.Weekday()))
+	if !contains(c.minute, t.Minute()) ||
+		!contains(c.hour, t.Hour()) ||
+		!contains(c.month, int(t.Month())) {
+		return false
+	}
+
+	return c.dayMatches(t)
+}
+
+// dayMatches 检查日期和星期字段是否匹配
+func (c *CronExpression) dayMatches(t time.Time) bool {
+	dayMatch := contains(c.dayOfMonth, t.Day())
+	weekdayMatch := contains(c.dayOfWeek, int(t.Weekday()))
+	if !c.dayOfMonthAny && !c.dayOfWeekAny {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
 }
 
 // contains 检查切片是否包含指定值
@@ -0,0 +1,38 @@
+package schedule
+
+import (
+	"time"
+)
+
+// 调度事件名称，设置 WithDispatcher 后触发
+const (
+	TaskFailedEventName    = "schedule.task_failed"
+	TaskRecoveredEventName = "schedule.task_recovered"
+)
+
+// TaskFailedEvent 任务执行失败事件，可根据 ConsecutiveFailures 决定是否告警
+type TaskFailedEvent struct {
+	TaskID              string
+	TaskName            string
+	Error               string
+	ConsecutiveFailures int
+	Time                time.Time
+}
+
+// EventName 实现 event.Event 接口
+func (e *TaskFailedEvent) EventName() string {
+	return TaskFailedEventName
+}
+
+// TaskRecoveredEvent 连续失败的任务再次执行成功事件
+type TaskRecoveredEvent struct {
+	TaskID           string
+	TaskName         string
+	PreviousFailures int // 恢复前的连续失败次数
+	Time             time.Time
+}
+
+// EventName 实现 event.Event 接口
+func (e *TaskRecoveredEvent) EventName() string {
+	return TaskRecoveredEventName
+}
@@ -2,25 +2,44 @@ package schedule
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/event"
+	"github.com/clarkgo/clarkgo/pkg/log"
 )
 
 // Task 表示一个调度任务
 type Task struct {
-	ID          string
-	Name        string
-	Schedule    string // Cron 表达式或预定义调度
-	Handler     func() error
-	LastRunAt   time.Time
-	NextRunAt   time.Time
-	RunCount    int
-	FailCount   int
-	IsRunning   bool
-	Description string
-	cronExpr    *CronExpression
-	mu          sync.RWMutex
+	ID       string
+	Name     string
+	Schedule string // Cron 表达式或预定义调度
+	Handler  func() error
+	// ContextHandler 带 context 的处理函数，设置后优先于 Handler
+	// ctx 携带附加了 task_id/task_name 字段的 Logger（log.FromContext）
+	ContextHandler func(ctx context.Context) error
+	LastRunAt      time.Time
+	LastError      string // 最近一次执行的错误，成功时为空
+	NextRunAt      time.Time
+	RunCount       int
+	FailCount      int
+	IsRunning      bool
+	Description    string
+	CatchUp        bool // 调度器启动时若发现停机期间错过了执行，立即补跑一次
+	cronExpr       *CronExpression
+	mu             sync.RWMutex
+
+	// 执行统计，用于 GetTaskDetail
+	totalDuration time.Duration
+	lastDuration  time.Duration
+	lastDrift     time.Duration
+	maxDrift      time.Duration
+	totalDrift    time.Duration
+	driftCount    int
+
+	consecutiveFails int
 }
 
 // Scheduler 任务调度器
@@ -35,17 +54,51 @@ type Scheduler struct {
 	logs       []TaskLog
 	logsMu     sync.RWMutex
 	maxLogSize int
+	logger     log.StructuredLogger
+	dispatcher *event.Dispatcher
+	store      Store
 }
 
 // TaskLog 任务执行日志
 type TaskLog struct {
-	TaskID    string
-	TaskName  string
-	StartTime time.Time
-	EndTime   time.Time
-	Duration  time.Duration
-	Success   bool
-	Error     string
+	TaskID      string
+	TaskName    string
+	ScheduledAt time.Time // 计划执行时间，手动执行时为零值
+	StartTime   time.Time
+	EndTime     time.Time
+	Duration    time.Duration
+	Drift       time.Duration // 实际开始时间与计划时间之差
+	Success     bool
+	Error       string
+	Reason      string // 非常规执行的原因，如 catch_up
+}
+
+// ReasonCatchUp 启动时补跑错过的执行
+const ReasonCatchUp = "catch_up"
+
+// ReasonManual 通过 RunNowAndWait 手动触发执行
+const ReasonManual = "manual"
+
+// ErrAlreadyRunning 任务正在运行中，不会重复执行
+var ErrAlreadyRunning = errors.New("task is already running")
+
+// TaskDetail 任务执行详情
+type TaskDetail struct {
+	ID              string        `json:"id"`
+	Name            string        `json:"name"`
+	Schedule        string        `json:"schedule"`
+	IsRunning       bool          `json:"is_running"`
+	LastRunAt       time.Time     `json:"last_run_at"`
+	NextRunAt       time.Time     `json:"next_run_at"`
+	LastError       string        `json:"last_error,omitempty"`
+	RunCount        int           `json:"run_count"`
+	FailCount       int           `json:"fail_count"`
+	SuccessRate     float64       `json:"success_rate"`
+	LastDuration    time.Duration `json:"last_duration"`
+	AverageDuration time.Duration `json:"average_duration"`
+	LastDrift       time.Duration `json:"last_drift"`
+	AverageDrift    time.Duration `json:"average_drift"`
+	MaxDrift        time.Duration `json:"max_drift"`
 }
 
 // NewScheduler 创建新的调度器
@@ -57,9 +110,30 @@ func NewScheduler() *Scheduler {
 		cancel:     cancel,
 		logs:       make([]TaskLog, 0),
 		maxLogSize: 1000, // 最多保留 1000 条日志
+		logger:     log.Default(),
 	}
 }
 
+// WithDispatcher 设置事件分发器，任务失败时触发 schedule.task_failed 事件，
+// 连续失败的任务恢复成功时触发 schedule.task_recovered 事件
+func (s *Scheduler) WithDispatcher(dispatcher *event.Dispatcher) *Scheduler {
+	s.dispatcher = dispatcher
+	return s
+}
+
+// WithStore 设置任务状态存储，每次运行后保存 LastRunAt（ResultStore 同时保存运行结果），
+// 启动时据此恢复并补跑错过的任务
+func (s *Scheduler) WithStore(store Store) *Scheduler {
+	s.store = store
+	return s
+}
+
+// SetLogger 设置日志记录器
+func (s *Scheduler) SetLogger(logger log.StructuredLogger) *Scheduler {
+	s.logger = logger
+	return s
+}
+
 // AddTask 添加任务
 func (s *Scheduler) AddTask(task *Task) error {
 	s.mu.Lock()
@@ -109,6 +183,54 @@ func (s *Scheduler) GetTask(taskID string) (*Task, error) {
 	return task, nil
 }
 
+// GetTaskDetail 获取任务执行详情，包括平均耗时、成功率和调度漂移
+// 调度漂移为任务实际开始时间减去计划执行时间，持续增大说明调度器处理不过来
+func (s *Scheduler) GetTaskDetail(taskID string) (*TaskDetail, error) {
+	task, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	task.mu.RLock()
+	defer task.mu.RUnlock()
+
+	detail := &TaskDetail{
+		ID:           task.ID,
+		Name:         task.Name,
+		Schedule:     task.Schedule,
+		IsRunning:    task.IsRunning,
+		LastRunAt:    task.LastRunAt,
+		NextRunAt:    task.NextRunAt,
+		LastError:    task.LastError,
+		RunCount:     task.RunCount,
+		FailCount:    task.FailCount,
+		SuccessRate:  calculateSuccessRate(task.RunCount, task.FailCount),
+		LastDuration: task.lastDuration,
+		LastDrift:    task.lastDrift,
+		MaxDrift:     task.maxDrift,
+	}
+	if task.RunCount > 0 {
+		detail.AverageDuration = task.totalDuration / time.Duration(task.RunCount)
+	}
+	if task.driftCount > 0 {
+		detail.AverageDrift = task.totalDrift / time.Duration(task.driftCount)
+	}
+
+	return detail, nil
+}
+
+// UpcomingRuns 返回任务接下来的 n 个计划执行时间，用于界面展示
+func (s *Scheduler) UpcomingRuns(taskID string, n int) ([]time.Time, error) {
+	task, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.cronExpr == nil {
+		return nil, fmt.Errorf("task %s has no schedule", taskID)
+	}
+	return task.cronExpr.NextN(time.Now(), n), nil
+}
+
 // ListTasks 列出所有任务
 func (s *Scheduler) ListTasks() []*Task {
 	s.mu.RLock()
@@ -131,10 +253,86 @@ func (s *Scheduler) Start() {
 	s.isRunning = true
 	s.runningMu.Unlock()
 
+	s.catchUp(time.Now())
+
 	s.ticker = time.NewTicker(time.Second)
 	go s.run()
 }
 
+// LoadState 从存储恢复所有任务的最近运行时间和运行结果，未设置存储时不做任何操作
+// 调度器启动和 RunDue 时会自动调用，schedule:list 等只读取状态的场景可直接调用
+func (s *Scheduler) LoadState() {
+	if s.store == nil {
+		return
+	}
+
+	resultStore, hasResults := s.store.(ResultStore)
+	for _, task := range s.ListTasks() {
+		var (
+			result TaskResult
+			err    error
+		)
+		if hasResults {
+			result, err = resultStore.LastResult(task.Name)
+		} else {
+			result.LastRunAt, err = s.store.LastRunAt(task.Name)
+		}
+		if err != nil {
+			s.logger.Warn("failed to load task state", "task_name", task.Name, "error", err)
+			continue
+		}
+
+		task.mu.Lock()
+		if result.LastRunAt.After(task.LastRunAt) {
+			task.LastRunAt = result.LastRunAt
+			if hasResults {
+				task.LastError = result.Error
+			}
+		}
+		task.mu.Unlock()
+	}
+}
+
+// missedTasks 返回开启了 CatchUp 且在 now 所在分钟之前错过执行的任务，跳过 exclude 中的任务
+// 当前分钟的执行交给调度主循环或 RunDue，没有运行记录的任务不补跑
+func (s *Scheduler) missedTasks(now time.Time, exclude map[*Task]bool) []*Task {
+	var missed []*Task
+	for _, task := range s.ListTasks() {
+		if exclude[task] {
+			continue
+		}
+
+		task.mu.RLock()
+		last := task.LastRunAt
+		cronExpr := task.cronExpr
+		catchUp := task.CatchUp
+		task.mu.RUnlock()
+
+		if !catchUp || cronExpr == nil || last.IsZero() {
+			continue
+		}
+
+		missedAt := cronExpr.Next(last)
+		if missedAt.IsZero() || !missedAt.Before(now.Truncate(time.Minute)) {
+			continue
+		}
+
+		ctx := log.WithTaskID(log.WithContext(s.ctx, s.logger), task.ID, task.Name)
+		log.FromContext(ctx).Info("running missed scheduled task", "reason", ReasonCatchUp, "missed_at", missedAt, "last_run_at", last)
+		missed = append(missed, task)
+	}
+	return missed
+}
+
+// catchUp 从存储恢复任务状态，并补跑开启了 CatchUp 且停机期间错过执行的任务
+// 无论错过多少次都只补跑一次
+func (s *Scheduler) catchUp(now time.Time) {
+	s.LoadState()
+	for _, task := range s.missedTasks(now, nil) {
+		go s.runTask(s.ctx, task, time.Time{}, ReasonCatchUp)
+	}
+}
+
 // Stop 停止调度器
 func (s *Scheduler) Stop() {
 	s.runningMu.Lock()
@@ -181,7 +379,11 @@ func (s *Scheduler) checkAndRunTasks(now time.Time) {
 
 	for _, task := range tasks {
 		if s.shouldRun(task, now) {
-			go s.runTask(task)
+			task.mu.RLock()
+			scheduledAt := task.NextRunAt
+			task.mu.RUnlock()
+
+			go s.runTask(s.ctx, task, scheduledAt, "")
 		}
 	}
 }
@@ -203,39 +405,130 @@ func (s *Scheduler) shouldRun(task *Task, now time.Time) bool {
 	return now.Unix() >= task.NextRunAt.Unix()
 }
 
-// runTask 运行任务
-func (s *Scheduler) runTask(task *Task) {
+// RunDue 同步运行 now 所在分钟到期的所有任务并返回执行日志
+// 适用于由 cron/systemd 每分钟调用一次的场景，无需启动调度器主循环
+// 设置了存储时先恢复任务状态，并补跑开启了 CatchUp 且在之前错过执行的任务
+func (s *Scheduler) RunDue(now time.Time) []TaskLog {
+	minute := now.Truncate(time.Minute)
+
+	s.LoadState()
+
+	s.mu.RLock()
+	due := make(map[*Task]bool)
+	for _, task := range s.tasks {
+		if task.cronExpr != nil && task.cronExpr.IsDue(minute) {
+			due[task] = true
+		}
+	}
+	s.mu.RUnlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		logs []TaskLog
+	)
+	run := func(task *Task, scheduledAt time.Time, reason string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if log, ran, _ := s.runTask(s.ctx, task, scheduledAt, reason); ran {
+				mu.Lock()
+				logs = append(logs, log)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// 本分钟本来就到期的任务只运行一次
+	for _, task := range s.missedTasks(now, due) {
+		run(task, time.Time{}, ReasonCatchUp)
+	}
+	for task := range due {
+		run(task, minute, "")
+	}
+	wg.Wait()
+
+	return logs
+}
+
+// runTask 运行任务，返回执行日志和任务的错误，任务已在运行时 ran 为 false
+// scheduledAt 为计划执行时间，用于计算调度漂移，手动执行时传零值
+// reason 记录非常规执行的原因，常规调度时为空
+// parent 传给 ContextHandler，调度执行时为调度器的 ctx
+func (s *Scheduler) runTask(parent context.Context, task *Task, scheduledAt time.Time, reason string) (taskLog TaskLog, ran bool, err error) {
 	task.mu.Lock()
 	if task.IsRunning {
 		task.mu.Unlock()
-		return
+		return TaskLog{}, false, nil
 	}
 	task.IsRunning = true
 	task.mu.Unlock()
 
-	log := TaskLog{
-		TaskID:    task.ID,
-		TaskName:  task.Name,
-		StartTime: time.Now(),
+	taskLog = TaskLog{
+		TaskID:      task.ID,
+		TaskName:    task.Name,
+		ScheduledAt: scheduledAt,
+		StartTime:   time.Now(),
+		Reason:      reason,
+	}
+	if !scheduledAt.IsZero() {
+		taskLog.Drift = taskLog.StartTime.Sub(scheduledAt)
 	}
 
 	// 运行任务
-	err := task.Handler()
+	ctx := log.WithTaskID(log.WithContext(parent, s.logger), task.ID, task.Name)
+	if task.ContextHandler != nil {
+		err = task.ContextHandler(ctx)
+	} else {
+		err = task.Handler()
+	}
 
-	log.EndTime = time.Now()
-	log.Duration = log.EndTime.Sub(log.StartTime)
+	taskLog.EndTime = time.Now()
+	taskLog.Duration = taskLog.EndTime.Sub(taskLog.StartTime)
 
 	task.mu.Lock()
 	task.IsRunning = false
-	task.LastRunAt = log.StartTime
+	task.LastRunAt = taskLog.StartTime
 	task.RunCount++
+	task.lastDuration = taskLog.Duration
+	task.totalDuration += taskLog.Duration
+	if !scheduledAt.IsZero() {
+		task.lastDrift = taskLog.Drift
+		task.totalDrift += taskLog.Drift
+		task.driftCount++
+		if taskLog.Drift > task.maxDrift {
+			task.maxDrift = taskLog.Drift
+		}
+	}
 
+	var evt event.Event
 	if err != nil {
 		task.FailCount++
-		log.Success = false
-		log.Error = err.Error()
+		task.consecutiveFails++
+		task.LastError = err.Error()
+		taskLog.Success = false
+		taskLog.Error = err.Error()
+		log.FromContext(ctx).Error("scheduled task failed", "error", err, "duration", taskLog.Duration)
+
+		evt = &TaskFailedEvent{
+			TaskID:              task.ID,
+			TaskName:            task.Name,
+			Error:               err.Error(),
+			ConsecutiveFailures: task.consecutiveFails,
+			Time:                taskLog.EndTime,
+		}
 	} else {
-		log.Success = true
+		if task.consecutiveFails > 0 {
+			evt = &TaskRecoveredEvent{
+				TaskID:           task.ID,
+				TaskName:         task.Name,
+				PreviousFailures: task.consecutiveFails,
+				Time:             taskLog.EndTime,
+			}
+		}
+		task.consecutiveFails = 0
+		task.LastError = ""
+		taskLog.Success = true
 	}
 
 	// 计算下次运行时间
@@ -245,7 +538,32 @@ func (s *Scheduler) runTask(task *Task) {
 	task.mu.Unlock()
 
 	// 保存日志
-	s.addLog(log)
+	s.addLog(taskLog)
+
+	if s.store != nil {
+		var err error
+		if resultStore, ok := s.store.(ResultStore); ok {
+			err = resultStore.SaveResult(task.Name, TaskResult{
+				LastRunAt: taskLog.StartTime,
+				Success:   taskLog.Success,
+				Error:     taskLog.Error,
+			})
+		} else {
+			err = s.store.SaveLastRunAt(task.Name, taskLog.StartTime)
+		}
+		if err != nil {
+			log.FromContext(ctx).Warn("failed to save task state", "error", err)
+		}
+	}
+
+	// 在释放任务锁之后分发事件，监听器可以安全地查询任务状态
+	if evt != nil && s.dispatcher != nil {
+		if err := s.dispatcher.DispatchWithContext(ctx, evt); err != nil {
+			log.FromContext(ctx).Warn("schedule event listener failed", "event", evt.EventName(), "error", err)
+		}
+	}
+
+	return taskLog, true, err
 }
 
 // addLog 添加日志
@@ -287,10 +605,25 @@ func (s *Scheduler) RunNow(taskID string) error {
 		return err
 	}
 
-	go s.runTask(task)
+	go s.runTask(s.ctx, task, time.Time{}, "")
 	return nil
 }
 
+// RunNowAndWait 立即运行任务并等待完成，返回本次执行日志
+// ctx 传给 ContextHandler，任务已在运行时返回 ErrAlreadyRunning，任务失败时返回任务的错误
+func (s *Scheduler) RunNowAndWait(ctx context.Context, taskID string) (TaskLog, error) {
+	task, err := s.GetTask(taskID)
+	if err != nil {
+		return TaskLog{}, err
+	}
+
+	taskLog, ran, err := s.runTask(ctx, task, time.Time{}, ReasonManual)
+	if !ran {
+		return TaskLog{}, ErrAlreadyRunning
+	}
+	return taskLog, err
+}
+
 // GetStats 获取统计信息
 func (s *Scheduler) GetStats() map[string]interface{} {
 	s.mu.RLock()
@@ -1,8 +1,19 @@
 package schedule
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/event"
+	"github.com/clarkgo/clarkgo/pkg/log"
 )
 
 func TestParseCron(t *testing.T) {
@@ -17,6 +28,10 @@ func TestParseCron(t *testing.T) {
 		{"every 5 minutes", "*/5 * * * *", false},
 		{"range", "0-30 * * * *", false},
 		{"list", "0,15,30,45 * * * *", false},
+		{"weekday names", "0 9 * * MON-FRI", false},
+		{"month names", "0 0 1 jan,Jul *", false},
+		{"invalid - unknown name", "0 0 * * FOO", true},
+		{"invalid - name in minute field", "MON * * * *", true},
 		{"invalid - too few fields", "* * *", true},
 		{"invalid - too many fields", "* * * * * *", true},
 		{"invalid - out of range", "60 * * * *", true},
@@ -57,6 +72,12 @@ func TestCronNext(t *testing.T) {
 			from: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
 			want: time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC),
 		},
+		{
+			name: "weekdays at 9am",
+			expr: "0 9 * * MON-FRI",
+			from: time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC), // 星期六
+			want: time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+		},
 	}
 
 	for _, tt := range tests {
@@ -74,6 +95,111 @@ func TestCronNext(t *testing.T) {
 	}
 }
 
+func TestCronNextN(t *testing.T) {
+	cron, err := ParseCron("*/20 9-10 * * MON-FRI")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+
+	from := time.Date(2024, 1, 5, 10, 30, 0, 0, time.UTC) // 星期五
+	want := []time.Time{
+		time.Date(2024, 1, 5, 10, 40, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 9, 20, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 9, 40, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC),
+	}
+	got := cron.NextN(from, len(want))
+	if len(got) != len(want) {
+		t.Fatalf("NextN() returned %d times, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("NextN()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// 不存在的日期在一年内没有匹配
+	never, _ := ParseCron("0 0 30 2 *")
+	if runs := never.NextN(from, 3); len(runs) != 0 {
+		t.Errorf("Expected no runs for Feb 30, got %v", runs)
+	}
+
+	// 跨年和闰日
+	leap, _ := ParseCron("0 12 29 2 *")
+	if next := leap.Next(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Next() = %v, want 2024-02-29 12:00", next)
+	}
+}
+
+func TestUpcomingRuns(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.NewTask("hourly").Hourly().Do(func() error { return nil })
+
+	runs, err := scheduler.UpcomingRuns(scheduler.ListTasks()[0].ID, 3)
+	if err != nil || len(runs) != 3 {
+		t.Fatalf("UpcomingRuns() = %v, %v", runs, err)
+	}
+	for i, run := range runs {
+		if run.Minute() != 0 || !run.After(time.Now()) || (i > 0 && run.Sub(runs[i-1]) != time.Hour) {
+			t.Errorf("Unexpected upcoming run %d: %v", i, run)
+		}
+	}
+
+	if _, err := scheduler.UpcomingRuns("missing", 3); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
+
+func TestCronDayOfMonthOrDayOfWeek(t *testing.T) {
+	// 日期和星期都受限时满足其一即可：每月 1 日、15 日以及每个星期一
+	cron, err := ParseCron("0 0 1,15 * MON")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+
+	tests := []struct {
+		day  int
+		want bool
+	}{
+		{1, true},   // 2024-01-01 星期一，同时满足
+		{8, true},   // 星期一
+		{15, true},  // 星期一，且为 15 日
+		{16, false}, // 星期二
+		{22, true},  // 星期一
+		{31, false}, // 星期三
+	}
+	for _, tt := range tests {
+		at := time.Date(2024, 1, tt.day, 0, 0, 0, 0, time.UTC)
+		if got := cron.IsDue(at); got != tt.want {
+			t.Errorf("IsDue(%s) = %v, want %v", at.Format("2006-01-02 Mon"), got, tt.want)
+		}
+	}
+
+	if next := cron.Next(time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Next() = %v, want the following Monday", next)
+	}
+	if next := cron.Next(time.Date(2024, 2, 12, 12, 0, 0, 0, time.UTC)); !next.Equal(time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Next() = %v, want the 15th", next)
+	}
+
+	// 仅一个字段受限时仍要求两者同时满足
+	cron, err = ParseCron("0 0 * * MON")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+	if !cron.IsDue(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) || cron.IsDue(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected weekday-only expression to match Mondays only")
+	}
+	cron, err = ParseCron("0 0 1,15 * *")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+	if cron.IsDue(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected day-of-month-only expression not to match other days")
+	}
+}
+
 func TestScheduler(t *testing.T) {
 	scheduler := NewScheduler()
 
@@ -153,3 +279,334 @@ func TestTaskBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestRunDue(t *testing.T) {
+	scheduler := NewScheduler()
+
+	ran := make(map[string]bool)
+	var mu sync.Mutex
+
+	scheduler.NewTask("hourly").Cron("0 * * * *").Do(func() error {
+		mu.Lock()
+		ran["hourly"] = true
+		mu.Unlock()
+		return nil
+	})
+	scheduler.NewTask("daily").Cron("0 8 * * *").Do(func() error {
+		mu.Lock()
+		ran["daily"] = true
+		mu.Unlock()
+		return errors.New("boom")
+	})
+
+	logs := scheduler.RunDue(time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC))
+
+	if len(logs) != 1 || !ran["hourly"] || ran["daily"] {
+		t.Fatalf("Expected only the hourly task to run, got %v (logs: %d)", ran, len(logs))
+	}
+
+	logs = scheduler.RunDue(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 tasks to run at 08:00, got %d", len(logs))
+	}
+
+	for _, task := range scheduler.ListTasks() {
+		if task.Name == "daily" && task.LastError != "boom" {
+			t.Errorf("Expected LastError 'boom', got '%s'", task.LastError)
+		}
+	}
+}
+
+func TestContextHandlerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	scheduler := NewScheduler().SetLogger(log.NewJSONLogger(&buf, log.LevelInfo))
+
+	scheduler.NewTask("report").EveryMinute().DoWithContext(func(ctx context.Context) error {
+		log.FromContext(ctx).Info("generating report")
+		return nil
+	})
+
+	scheduler.RunDue(time.Now())
+
+	if !strings.Contains(buf.String(), `"task_name":"report"`) || !strings.Contains(buf.String(), `"task_id":`) {
+		t.Errorf("Expected task fields in log output, got %q", buf.String())
+	}
+}
+
+func TestGetTaskDetail(t *testing.T) {
+	scheduler := NewScheduler()
+
+	calls := 0
+	scheduler.NewTask("sync").EveryMinute().Do(func() error {
+		calls++
+		time.Sleep(5 * time.Millisecond)
+		if calls == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	now := time.Now()
+	scheduler.RunDue(now)
+	scheduler.RunDue(now)
+
+	detail, err := scheduler.GetTaskDetail(scheduler.ListTasks()[0].ID)
+	if err != nil {
+		t.Fatalf("GetTaskDetail failed: %v", err)
+	}
+
+	if detail.RunCount != 2 || detail.FailCount != 1 {
+		t.Errorf("Expected 2 runs and 1 failure, got %d/%d", detail.RunCount, detail.FailCount)
+	}
+	if detail.SuccessRate != 50 {
+		t.Errorf("Expected success rate 50, got %v", detail.SuccessRate)
+	}
+	if detail.AverageDuration < 5*time.Millisecond {
+		t.Errorf("Expected average duration >= 5ms, got %v", detail.AverageDuration)
+	}
+	if detail.LastError != "boom" {
+		t.Errorf("Expected LastError 'boom', got '%s'", detail.LastError)
+	}
+
+	// 计划时间为 now 所在分钟的起点，漂移至少为 now 超出整分钟的部分
+	minDrift := now.Sub(now.Truncate(time.Minute))
+	if detail.LastDrift < minDrift || detail.MaxDrift < detail.LastDrift {
+		t.Errorf("Unexpected drift: last %v, max %v, expected >= %v", detail.LastDrift, detail.MaxDrift, minDrift)
+	}
+	if !detail.NextRunAt.After(now) {
+		t.Errorf("Expected next run after %v, got %v", now, detail.NextRunAt)
+	}
+
+	if _, err := scheduler.GetTaskDetail("missing"); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
+
+func TestSchedulerFailureEvents(t *testing.T) {
+	dispatcher := event.NewDispatcher(1)
+	defer dispatcher.Stop()
+	scheduler := NewScheduler().WithDispatcher(dispatcher)
+
+	var (
+		failures  []int
+		recovered *TaskRecoveredEvent
+	)
+	event.On(dispatcher, func(ctx context.Context, e *TaskFailedEvent) error {
+		failures = append(failures, e.ConsecutiveFailures)
+		return nil
+	})
+	event.On(dispatcher, func(ctx context.Context, e *TaskRecoveredEvent) error {
+		recovered = e
+		return nil
+	})
+
+	results := []error{errors.New("boom"), errors.New("boom"), nil, nil}
+	calls := 0
+	scheduler.NewTask("sync").EveryMinute().Do(func() error {
+		err := results[calls]
+		calls++
+		return err
+	})
+
+	now := time.Now()
+	for range results {
+		scheduler.RunDue(now)
+	}
+
+	if len(failures) != 2 || failures[0] != 1 || failures[1] != 2 {
+		t.Errorf("Expected consecutive failures [1 2], got %v", failures)
+	}
+	if recovered == nil || recovered.TaskName != "sync" || recovered.PreviousFailures != 2 {
+		t.Errorf("Expected a single recovery after 2 failures, got %+v", recovered)
+	}
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "schedule.json"))
+	lastRun := time.Now().Add(-3 * time.Hour)
+	for _, name := range []string{"hourly-catch-up", "hourly"} {
+		if err := store.SaveLastRunAt(name, lastRun); err != nil {
+			t.Fatalf("SaveLastRunAt failed: %v", err)
+		}
+	}
+
+	scheduler := NewScheduler().WithStore(NewFileStore(store.path))
+	var mu sync.Mutex
+	ran := map[string]int{}
+	for _, name := range []string{"hourly-catch-up", "hourly", "never-ran"} {
+		name := name
+		builder := scheduler.NewTask(name).Hourly()
+		if name != "hourly" {
+			builder = builder.CatchUp()
+		}
+		builder.Do(func() error {
+			mu.Lock()
+			ran[name]++
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(scheduler.GetLogs("", 10)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if ran["hourly-catch-up"] != 1 || ran["hourly"] != 0 || ran["never-ran"] != 0 {
+		t.Fatalf("expected only the catch-up task with a missed run to run once, got %v", ran)
+	}
+
+	logs := scheduler.GetLogs("", 10)
+	if len(logs) != 1 || logs[0].Reason != ReasonCatchUp || logs[0].TaskName != "hourly-catch-up" {
+		t.Fatalf("expected one catch_up log, got %+v", logs)
+	}
+
+	// 未补跑的任务也从存储恢复 LastRunAt，补跑后保存新的运行时间
+	for _, task := range scheduler.ListTasks() {
+		if task.Name == "hourly" && !task.LastRunAt.Equal(lastRun) {
+			t.Errorf("expected LastRunAt to be restored, got %v", task.LastRunAt)
+		}
+	}
+	saved, err := NewFileStore(store.path).LastRunAt("hourly-catch-up")
+	if err != nil || !saved.After(lastRun) {
+		t.Errorf("expected catch-up run to be persisted, got %v (%v)", saved, err)
+	}
+}
+
+func TestRunDueCatchUpAndResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	store := NewFileStore(path)
+	for _, name := range []string{"hourly-catch-up", "half-hourly"} {
+		if err := store.SaveLastRunAt(name, now.Add(-3*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scheduler := NewScheduler().WithStore(NewFileStore(path))
+	var mu sync.Mutex
+	ran := map[string]int{}
+	record := func(name string, err error) func() error {
+		return func() error {
+			mu.Lock()
+			ran[name]++
+			mu.Unlock()
+			return err
+		}
+	}
+	scheduler.NewTask("hourly-catch-up").Hourly().CatchUp().Do(record("hourly-catch-up", errors.New("boom")))
+	// 本分钟到期的任务即使错过了执行也只运行一次
+	scheduler.NewTask("half-hourly").Cron("30 * * * *").CatchUp().Do(record("half-hourly", nil))
+
+	logs := scheduler.RunDue(now)
+	if len(logs) != 2 || ran["hourly-catch-up"] != 1 || ran["half-hourly"] != 1 {
+		t.Fatalf("Expected the missed task to be caught up once, got %v (logs: %+v)", ran, logs)
+	}
+	for _, taskLog := range logs {
+		if (taskLog.TaskName == "hourly-catch-up") != (taskLog.Reason == ReasonCatchUp) {
+			t.Errorf("Unexpected reason for %s: %q", taskLog.TaskName, taskLog.Reason)
+		}
+	}
+
+	// 运行结果保存到存储，新的调度器实例（如 schedule:list）可以读取
+	result, err := NewFileStore(path).LastResult("hourly-catch-up")
+	if err != nil || result.Success || result.Error != "boom" || result.LastRunAt.IsZero() {
+		t.Errorf("Expected failed result to be persisted, got %+v (%v)", result, err)
+	}
+
+	listing := NewScheduler().WithStore(NewFileStore(path))
+	listing.NewTask("hourly-catch-up").Hourly().Do(record("unused", nil))
+	listing.NewTask("half-hourly").Cron("30 * * * *").Do(record("unused", nil))
+	listing.LoadState()
+	for _, task := range listing.ListTasks() {
+		if task.LastRunAt.IsZero() {
+			t.Errorf("Expected LastRunAt to be restored for %s", task.Name)
+		}
+		if wantErr := map[string]string{"hourly-catch-up": "boom"}[task.Name]; task.LastError != wantErr {
+			t.Errorf("Expected LastError %q for %s, got %q", wantErr, task.Name, task.LastError)
+		}
+	}
+}
+
+func TestFileStoreLegacyFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+	if err := os.WriteFile(path, []byte(`{"backup": "2024-01-01T02:00:00Z"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileStore(path)
+	at, err := store.LastRunAt("backup")
+	if err != nil || !at.Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected legacy LastRunAt to be read, got %v (%v)", at, err)
+	}
+
+	// 只更新运行时间时保留已记录的结果
+	if err := store.SaveResult("backup", TaskResult{LastRunAt: at, Error: "disk full"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveLastRunAt("backup", at.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewFileStore(path).LastResult("backup")
+	if err != nil || result.Error != "disk full" || !result.LastRunAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("Unexpected result after reload: %+v (%v)", result, err)
+	}
+}
+
+func TestRunNowAndWait(t *testing.T) {
+	scheduler := NewScheduler()
+
+	type ctxKey struct{}
+	release := make(chan struct{})
+	started := make(chan struct{})
+	errBoom := errors.New("boom")
+	fail := false
+	scheduler.NewTask("sync").EveryMinute().DoWithContext(func(ctx context.Context) error {
+		if ctx.Value(ctxKey{}) == "caller" {
+			close(started)
+			<-release
+		}
+		if fail {
+			return fmt.Errorf("sync failed: %w", errBoom)
+		}
+		return nil
+	})
+	taskID := scheduler.ListTasks()[0].ID
+
+	taskLog, err := scheduler.RunNowAndWait(context.Background(), taskID)
+	if err != nil || !taskLog.Success || taskLog.Reason != ReasonManual {
+		t.Fatalf("Expected successful manual run, got %+v, %v", taskLog, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := scheduler.RunNowAndWait(context.WithValue(context.Background(), ctxKey{}, "caller"), taskID)
+		done <- err
+	}()
+	<-started
+
+	if _, err := scheduler.RunNowAndWait(context.Background(), taskID); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("Expected ErrAlreadyRunning while task runs, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("Expected first run to succeed, got %v", err)
+	}
+
+	fail = true
+	taskLog, err = scheduler.RunNowAndWait(context.Background(), taskID)
+	// 返回任务的原始错误，调用方可以用 errors.Is 判断
+	if !errors.Is(err, errBoom) || taskLog.Success || taskLog.Error != "sync failed: boom" {
+		t.Errorf("Expected task error to be returned, got %+v, %v", taskLog, err)
+	}
+
+	if _, err := scheduler.RunNowAndWait(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store 任务运行状态存储，用于在进程重启后恢复 LastRunAt
+// 以任务名称为键，任务 ID 在每次启动时可能重新生成
+type Store interface {
+	// LastRunAt 返回任务最近一次运行时间，没有记录时返回零值
+	LastRunAt(taskName string) (time.Time, error)
+	// SaveLastRunAt 保存任务最近一次运行时间
+	SaveLastRunAt(taskName string, at time.Time) error
+}
+
+// TaskResult 任务最近一次运行的结果
+type TaskResult struct {
+	LastRunAt time.Time `json:"last_run_at"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ResultStore 同时保存运行结果的存储，Store 实现该接口时调度器用 SaveResult 代替 SaveLastRunAt，
+// schedule:list 等不运行任务的进程可据此展示最近一次的运行结果
+type ResultStore interface {
+	Store
+	// LastResult 返回任务最近一次的运行结果，没有记录时返回零值
+	LastResult(taskName string) (TaskResult, error)
+	// SaveResult 保存任务最近一次的运行结果
+	SaveResult(taskName string, result TaskResult) error
+}
+
+// FileStore 基于 JSON 文件的任务状态存储，实现 ResultStore
+type FileStore struct {
+	path    string
+	mu      sync.Mutex
+	loaded  bool
+	results map[string]TaskResult
+}
+
+// NewFileStore 创建文件存储，文件不存在时在首次保存时创建
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		path:    path,
+		results: make(map[string]TaskResult),
+	}
+}
+
+// LastRunAt 实现 Store 接口
+func (f *FileStore) LastRunAt(taskName string) (time.Time, error) {
+	result, err := f.LastResult(taskName)
+	return result.LastRunAt, err
+}
+
+// SaveLastRunAt 实现 Store 接口，保留已记录的运行结果
+func (f *FileStore) SaveLastRunAt(taskName string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return err
+	}
+	result := f.results[taskName]
+	result.LastRunAt = at
+	f.results[taskName] = result
+	return f.save()
+}
+
+// LastResult 实现 ResultStore 接口
+func (f *FileStore) LastResult(taskName string) (TaskResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return TaskResult{}, err
+	}
+	return f.results[taskName], nil
+}
+
+// SaveResult 实现 ResultStore 接口
+func (f *FileStore) SaveResult(taskName string, result TaskResult) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return err
+	}
+	f.results[taskName] = result
+	return f.save()
+}
+
+// save 写入状态文件，调用方需持有锁
+func (f *FileStore) save() error {
+	data, err := json.MarshalIndent(f.results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to write schedule store %s: %w", f.path, err)
+	}
+
+	// 先写临时文件再重命名，避免写入中断导致状态文件损坏
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedule store %s: %w", f.path, err)
+	}
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write schedule store %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// load 首次访问时读取状态文件，调用方需持有锁
+// 兼容只记录运行时间的旧格式（任务名到时间字符串的映射）
+func (f *FileStore) load() error {
+	if f.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read schedule store %s: %w", f.path, err)
+	}
+	if len(data) > 0 {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to parse schedule store %s: %w", f.path, err)
+		}
+		for name, value := range raw {
+			var result TaskResult
+			if bytes.HasPrefix(bytes.TrimSpace(value), []byte(`"`)) {
+				err = json.Unmarshal(value, &result.LastRunAt)
+			} else {
+				err = json.Unmarshal(value, &result)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to parse schedule store %s: %w", f.path, err)
+			}
+			f.results[name] = result
+		}
+	}
+
+	f.loaded = true
+	return nil
+}
@@ -0,0 +1,67 @@
+package web3
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/clarkgo/clarkgo/pkg/cache"
+)
+
+// AddressBook 地址簿，为链上地址添加标签
+// 标签存储在缓存中且永不过期，使用共享缓存驱动时多个实例可共享标签
+type AddressBook struct {
+	store *cache.Cache
+}
+
+// NewAddressBook 创建地址簿，store 为 nil 时使用内存缓存
+func NewAddressBook(store *cache.Cache) *AddressBook {
+	if store == nil {
+		store = cache.NewCache(cache.NewMemoryDriver())
+	}
+	return &AddressBook{
+		store: store.Namespace("addressbook"),
+	}
+}
+
+// Add 为地址添加标签，已存在时覆盖
+func (b *AddressBook) Add(chain Chain, address, label string) error {
+	if err := ValidateAddress(chain, address); err != nil {
+		return err
+	}
+	return b.store.Set(addressKey(chain, address), label, 0)
+}
+
+// Label 获取地址的标签
+func (b *AddressBook) Label(chain Chain, address string) (string, bool) {
+	label, err := b.store.GetString(addressKey(chain, address))
+	if err != nil {
+		return "", false
+	}
+	return label, true
+}
+
+// Remove 删除地址的标签
+func (b *AddressBook) Remove(chain Chain, address string) error {
+	return b.store.Delete(addressKey(chain, address))
+}
+
+// addressKey 生成存储键，EVM 地址不区分大小写
+func addressKey(chain Chain, address string) string {
+	if chain == Ethereum || chain == BSC {
+		address = strings.ToLower(address)
+	}
+	return string(chain) + cache.NamespaceSeparator + address
+}
+
+var (
+	globalAddressBook *AddressBook
+	addressBookOnce   sync.Once
+)
+
+// GetAddressBook 获取全局地址簿
+func GetAddressBook() *AddressBook {
+	addressBookOnce.Do(func() {
+		globalAddressBook = NewAddressBook(nil)
+	})
+	return globalAddressBook
+}
@@ -0,0 +1,43 @@
+package web3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// base58Alphabet Bitcoin 和 Solana 使用的 Base58 字符集（不含 0、O、I、l）
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Decode 解码 Base58 字符串，开头的每个 '1' 对应一个前导零字节
+func base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty base58 string")
+	}
+
+	// 以大端字节序逐位累加：result = result*58 + digit
+	var result []byte
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+
+		carry := digit
+		for j := len(result) - 1; j >= 0; j-- {
+			carry += int(result[j]) * 58
+			result[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			result = append([]byte{byte(carry)}, result...)
+			carry >>= 8
+		}
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), result...), nil
+}
@@ -0,0 +1,161 @@
+package web3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bitcoin 主网地址版本和前缀
+const (
+	btcP2PKHVersion = 0x00 // 1 开头的 P2PKH 地址
+	btcP2SHVersion  = 0x05 // 3 开头的 P2SH 地址
+	btcBech32HRP    = "bc" // bc1 开头的 SegWit 地址
+)
+
+// bech32 校验和常量，见 BIP173（见证版本 0）和 BIP350（见证版本 1 及以上）
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// bech32Charset bech32 编码字符集
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// validateBitcoinAddress 验证 Bitcoin 主网地址及其校验和
+func validateBitcoinAddress(address string) error {
+	if strings.HasPrefix(strings.ToLower(address), btcBech32HRP+"1") {
+		return validateBech32Address(address)
+	}
+	return validateBase58Address(address)
+}
+
+// validateBase58Address 验证 Legacy（P2PKH）和 P2SH 地址：Base58Check 解码后为版本号 + 20 字节哈希 + 4 字节校验和
+func validateBase58Address(address string) error {
+	decoded, err := base58Decode(address)
+	if err != nil {
+		return fmt.Errorf("invalid bitcoin address: %w", err)
+	}
+	if len(decoded) != 25 {
+		return errors.New("invalid bitcoin address length")
+	}
+
+	payload, checksum := decoded[:21], decoded[21:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return errors.New("invalid bitcoin address checksum")
+	}
+
+	if version := payload[0]; version != btcP2PKHVersion && version != btcP2SHVersion {
+		return fmt.Errorf("unsupported bitcoin address version 0x%02x (not a mainnet address)", version)
+	}
+	return nil
+}
+
+// validateBech32Address 验证 SegWit 地址：见证版本 0 使用 bech32，1 及以上使用 bech32m
+func validateBech32Address(address string) error {
+	if len(address) > 90 {
+		return errors.New("invalid bitcoin address length")
+	}
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return errors.New("invalid bitcoin address: mixed case")
+	}
+	address = strings.ToLower(address)
+
+	sep := strings.LastIndexByte(address, '1')
+	hrp, data := address[:sep], address[sep+1:]
+	if hrp != btcBech32HRP {
+		return fmt.Errorf("invalid bitcoin address prefix %q (not a mainnet address)", hrp)
+	}
+	if len(data) < 6 {
+		return errors.New("invalid bitcoin address: missing checksum")
+	}
+
+	values := make([]byte, len(data))
+	for i := 0; i < len(data); i++ {
+		v := strings.IndexByte(bech32Charset, data[i])
+		if v < 0 {
+			return fmt.Errorf("invalid bech32 character %q", data[i])
+		}
+		values[i] = byte(v)
+	}
+
+	checksum := bech32Polymod(append(bech32HRPExpand(hrp), values...))
+	values = values[:len(values)-6]
+	if len(values) == 0 {
+		return errors.New("invalid bitcoin address: missing witness version")
+	}
+
+	version := values[0]
+	switch {
+	case version == 0 && checksum != bech32Const:
+		return errors.New("invalid bitcoin address checksum")
+	case version > 0 && checksum != bech32mConst:
+		return errors.New("invalid bitcoin address checksum")
+	case version > 16:
+		return fmt.Errorf("invalid witness version %d", version)
+	}
+
+	program, err := convertBits(values[1:], 5, 8)
+	if err != nil {
+		return fmt.Errorf("invalid bitcoin address: %w", err)
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return errors.New("invalid witness program length")
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return errors.New("invalid witness program length for version 0")
+	}
+	return nil
+}
+
+// bech32Polymod 计算 bech32 校验多项式
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand 展开人类可读部分用于校验和计算
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// convertBits 在不同位宽之间转换，解码时不允许非零填充位
+func convertBits(data []byte, fromBits, toBits uint) ([]byte, error) {
+	var acc, bits uint
+	maxValue := uint(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits))
+	for _, v := range data {
+		acc = acc<<fromBits | uint(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+	if bits >= fromBits || (acc<<(toBits-bits))&maxValue != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return result, nil
+}
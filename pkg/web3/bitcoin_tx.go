@@ -0,0 +1,322 @@
+package web3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Bitcoin 交易体积估算（单位 vbyte），输出按 P2WPKH 计算，输入按锁定脚本类型计算
+const (
+	btcTxOverheadVSize = 11
+	btcOutputVSize     = 31
+
+	btcP2PKHInputVSize      = 148
+	btcP2SHP2WPKHInputVSize = 91
+	btcP2WPKHInputVSize     = 68
+	btcP2TRInputVSize       = 58
+
+	// btcDustLimit 找零低于该值（聪）时并入手续费
+	btcDustLimit = 546
+	// satoshiPerBTC 1 BTC = 10^8 聪
+	satoshiPerBTC = 1e8
+)
+
+// ErrInsufficientFunds 可用 UTXO 不足以支付金额和手续费
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// UTXO 未花费的交易输出
+type UTXO struct {
+	TxID          string `json:"txid"`
+	Vout          int    `json:"vout"`
+	Address       string `json:"address"`
+	Amount        int64  `json:"amount"` // 聪
+	Confirmations int    `json:"confirmations"`
+	ScriptPubKey  string `json:"script_pub_key"` // 锁定脚本（十六进制），用于估算输入体积
+}
+
+// BitcoinTxRequest 构建交易请求
+type BitcoinTxRequest struct {
+	// From 可花费的地址，为空时使用钱包中的全部 UTXO
+	From []string
+	// To 收款地址
+	To string
+	// Amount 转账金额（聪）
+	Amount int64
+	// ChangeAddress 找零地址，为空时使用第一个被选中的 UTXO 地址
+	ChangeAddress string
+	// FeeRate 手续费率（sat/vB），为 0 时通过 EstimateFeeRate 估算
+	FeeRate float64
+	// ConfTarget 手续费估算的目标确认区块数，默认 6
+	ConfTarget int
+	// MinConfirmations UTXO 最少确认数，默认 1
+	MinConfirmations int
+	// PrivateKeys WIF 私钥，为空时使用节点钱包签名
+	PrivateKeys []string
+}
+
+// SignedBitcoinTx 已签名的交易
+type SignedBitcoinTx struct {
+	Hex     string  `json:"hex"`
+	Fee     int64   `json:"fee"`      // 聪
+	FeeRate float64 `json:"fee_rate"` // sat/vB
+	VSize   int     `json:"vsize"`    // 估算体积
+	Inputs  []UTXO  `json:"inputs"`
+	Change  int64   `json:"change"` // 聪
+}
+
+// EstimateFeeRate 通过 estimatesmartfee 估算在 confTarget 个区块内确认的手续费率（sat/vB）
+func (c *BitcoinClient) EstimateFeeRate(ctx context.Context, confTarget int) (float64, error) {
+	if confTarget <= 0 {
+		confTarget = 6
+	}
+
+	result, err := c.call(ctx, "estimatesmartfee", []interface{}{confTarget})
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		FeeRate float64  `json:"feerate"` // BTC/kvB
+		Errors  []string `json:"errors"`
+		Blocks  int      `json:"blocks"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return 0, fmt.Errorf("failed to parse fee estimate: %w", err)
+	}
+
+	if resp.FeeRate <= 0 {
+		if len(resp.Errors) > 0 {
+			return 0, fmt.Errorf("fee estimation unavailable: %s", resp.Errors[0])
+		}
+		return 0, errors.New("fee estimation unavailable")
+	}
+
+	// BTC/kvB 转换为 sat/vB
+	return resp.FeeRate * satoshiPerBTC / 1000, nil
+}
+
+// ListUnspent 获取钱包中指定地址可花费的 UTXO，跳过钱包无法签名（spendable 为 false）的输出
+func (c *BitcoinClient) ListUnspent(ctx context.Context, minConf int, addresses []string) ([]UTXO, error) {
+	params := []interface{}{minConf, 9999999}
+	if len(addresses) > 0 {
+		params = append(params, addresses)
+	}
+
+	result, err := c.call(ctx, "listunspent", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []struct {
+		TxID          string  `json:"txid"`
+		Vout          int     `json:"vout"`
+		Address       string  `json:"address"`
+		Amount        float64 `json:"amount"`
+		Confirmations int     `json:"confirmations"`
+		ScriptPubKey  string  `json:"scriptPubKey"`
+		Spendable     bool    `json:"spendable"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse unspent outputs: %w", err)
+	}
+
+	utxos := make([]UTXO, 0, len(resp))
+	for _, item := range resp {
+		if !item.Spendable {
+			continue
+		}
+		utxos = append(utxos, UTXO{
+			TxID:          item.TxID,
+			Vout:          item.Vout,
+			Address:       item.Address,
+			Amount:        int64(math.Round(item.Amount * satoshiPerBTC)),
+			Confirmations: item.Confirmations,
+			ScriptPubKey:  item.ScriptPubKey,
+		})
+	}
+
+	return utxos, nil
+}
+
+// BuildAndSignTx 选择 UTXO、按估算体积 × 手续费率计算手续费，构建并签名交易
+// 返回的 Hex 可直接通过 BroadcastTx 广播
+func (c *BitcoinClient) BuildAndSignTx(ctx context.Context, req *BitcoinTxRequest) (*SignedBitcoinTx, error) {
+	if err := ValidateAddress(Bitcoin, req.To); err != nil {
+		return nil, err
+	}
+	if req.Amount <= btcDustLimit {
+		return nil, fmt.Errorf("amount %d is below dust limit", req.Amount)
+	}
+
+	feeRate := req.FeeRate
+	if feeRate <= 0 {
+		var err error
+		if feeRate, err = c.EstimateFeeRate(ctx, req.ConfTarget); err != nil {
+			return nil, err
+		}
+	}
+
+	minConf := req.MinConfirmations
+	if minConf <= 0 {
+		minConf = 1
+	}
+
+	utxos, err := c.ListUnspent(ctx, minConf, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := selectUTXOs(utxos, req.Amount, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	changeAddress := req.ChangeAddress
+	if changeAddress == "" {
+		changeAddress = signed.Inputs[0].Address
+	}
+
+	inputs := make([]map[string]interface{}, 0, len(signed.Inputs))
+	for _, utxo := range signed.Inputs {
+		inputs = append(inputs, map[string]interface{}{"txid": utxo.TxID, "vout": utxo.Vout})
+	}
+
+	outputs := map[string]interface{}{req.To: satoshiToBTC(req.Amount)}
+	if signed.Change > 0 {
+		if changeAddress == req.To {
+			return nil, errors.New("change address must differ from recipient")
+		}
+		outputs[changeAddress] = satoshiToBTC(signed.Change)
+	}
+
+	result, err := c.call(ctx, "createrawtransaction", []interface{}{inputs, outputs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	var rawHex string
+	if err := json.Unmarshal(result, &rawHex); err != nil {
+		return nil, fmt.Errorf("failed to parse raw transaction: %w", err)
+	}
+
+	if len(req.PrivateKeys) > 0 {
+		result, err = c.call(ctx, "signrawtransactionwithkey", []interface{}{rawHex, req.PrivateKeys})
+	} else {
+		result, err = c.call(ctx, "signrawtransactionwithwallet", []interface{}{rawHex})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	var signResp struct {
+		Hex      string `json:"hex"`
+		Complete bool   `json:"complete"`
+		Errors   []struct {
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(result, &signResp); err != nil {
+		return nil, fmt.Errorf("failed to parse signed transaction: %w", err)
+	}
+	if !signResp.Complete {
+		if len(signResp.Errors) > 0 {
+			return nil, fmt.Errorf("transaction signing incomplete: %s", signResp.Errors[0].Error)
+		}
+		return nil, errors.New("transaction signing incomplete")
+	}
+
+	signed.Hex = signResp.Hex
+	return signed, nil
+}
+
+// BroadcastTx 广播已签名的原始交易，返回交易 ID
+func (c *BitcoinClient) BroadcastTx(ctx context.Context, rawHex string) (string, error) {
+	result, err := c.call(ctx, "sendrawtransaction", []interface{}{rawHex})
+	if err != nil {
+		return "", err
+	}
+
+	var txid string
+	if err := json.Unmarshal(result, &txid); err != nil {
+		return "", fmt.Errorf("failed to parse transaction id: %w", err)
+	}
+
+	return txid, nil
+}
+
+// selectUTXOs 按金额从大到小选择 UTXO，直到覆盖转账金额和手续费
+// 每加入一个输入后先按带找零计算，找零不足粉尘阈值时再按无找零的手续费判断，
+// 无找零时剩余部分并入手续费
+func selectUTXOs(utxos []UTXO, amount int64, feeRate float64) (*SignedBitcoinTx, error) {
+	sorted := append([]UTXO{}, utxos...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Amount > sorted[j].Amount
+	})
+
+	var selected []UTXO
+	var total int64
+	for _, utxo := range sorted {
+		selected = append(selected, utxo)
+		total += utxo.Amount
+
+		vsize := estimateVSize(selected, 2)
+		fee := int64(math.Ceil(float64(vsize) * feeRate))
+		if change := total - amount - fee; change >= btcDustLimit {
+			return &SignedBitcoinTx{
+				Fee:     fee,
+				FeeRate: feeRate,
+				VSize:   vsize,
+				Inputs:  selected,
+				Change:  change,
+			}, nil
+		}
+
+		vsize = estimateVSize(selected, 1)
+		fee = int64(math.Ceil(float64(vsize) * feeRate))
+		if total >= amount+fee {
+			return &SignedBitcoinTx{
+				Fee:     total - amount,
+				FeeRate: feeRate,
+				VSize:   vsize,
+				Inputs:  selected,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: have %d sat, need %d sat plus fee", ErrInsufficientFunds, total, amount)
+}
+
+// estimateVSize 估算交易体积
+func estimateVSize(inputs []UTXO, outputs int) int {
+	vsize := btcTxOverheadVSize + outputs*btcOutputVSize
+	for _, utxo := range inputs {
+		vsize += inputVSize(utxo.ScriptPubKey)
+	}
+	return vsize
+}
+
+// inputVSize 按锁定脚本类型估算输入体积，无法识别的脚本按最大的 P2PKH 计算
+func inputVSize(scriptPubKey string) int {
+	script := strings.ToLower(scriptPubKey)
+	switch {
+	case len(script) == 44 && strings.HasPrefix(script, "0014"):
+		return btcP2WPKHInputVSize
+	case len(script) == 68 && strings.HasPrefix(script, "5120"):
+		return btcP2TRInputVSize
+	case len(script) == 46 && strings.HasPrefix(script, "a914") && strings.HasSuffix(script, "87"):
+		// 钱包中的 P2SH 输出通常为嵌套 SegWit（P2SH-P2WPKH）
+		return btcP2SHP2WPKHInputVSize
+	default:
+		return btcP2PKHInputVSize
+	}
+}
+
+// satoshiToBTC 聪转换为 BTC 金额，使用 json.Number 避免浮点误差
+func satoshiToBTC(sats int64) json.Number {
+	return json.Number(fmt.Sprintf("%d.%08d", sats/satoshiPerBTC, sats%satoshiPerBTC))
+}
@@ -10,18 +10,51 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
+// CoinbaseAuthMode Coinbase 鉴权模式
+type CoinbaseAuthMode string
+
+const (
+	// CoinbaseAuthLegacy 旧版 Exchange API，CB-ACCESS-* HMAC 签名
+	CoinbaseAuthLegacy CoinbaseAuthMode = "legacy"
+	// CoinbaseAuthAdvancedTrade Advanced Trade v3 API，ES256 JWT 签名
+	CoinbaseAuthAdvancedTrade CoinbaseAuthMode = "advanced"
+)
+
 // CoinbaseClient Coinbase Exchange API 客户端
 type CoinbaseClient struct {
+	credMu     sync.RWMutex // 保护 apiKey/apiSecret，支持运行时轮换
 	apiKey     string
 	apiSecret  string
 	baseURL    string
+	authMode   CoinbaseAuthMode
 	httpClient *http.Client
 }
 
+// CoinbaseOption Coinbase 客户端选项
+type CoinbaseOption func(*CoinbaseClient)
+
+// WithCoinbaseAdvancedTrade 使用 Advanced Trade v3 API
+// 此时 apiKey 为 CDP 密钥名称（organizations/{org_id}/apiKeys/{key_id}），apiSecret 为 EC 私钥 PEM
+func WithCoinbaseAdvancedTrade() CoinbaseOption {
+	return func(c *CoinbaseClient) {
+		c.authMode = CoinbaseAuthAdvancedTrade
+		c.baseURL = "https://api.coinbase.com"
+	}
+}
+
+// WithCoinbaseBaseURL 设置 API 地址（需在 WithCoinbaseAdvancedTrade 之后应用）
+func WithCoinbaseBaseURL(baseURL string) CoinbaseOption {
+	return func(c *CoinbaseClient) {
+		c.baseURL = baseURL
+	}
+}
+
 // CoinbaseAccount 账户信息
 type CoinbaseAccount struct {
 	ID        string `json:"id"`
@@ -68,25 +101,55 @@ type CoinbaseOrder struct {
 	FilledSize    string `json:"filled_size"`
 	ExecutedValue string `json:"executed_value"`
 	Status        string `json:"status"`
+	DoneReason    string `json:"done_reason"` // status 为 done 时的原因：filled 或 canceled
 	Settled       bool   `json:"settled"`
 }
 
-// NewCoinbaseClient 创建 Coinbase 客户端
-func NewCoinbaseClient(apiKey, apiSecret string) *CoinbaseClient {
-	return &CoinbaseClient{
+// NewCoinbaseClient 创建 Coinbase 客户端，默认使用旧版 Exchange API
+func NewCoinbaseClient(apiKey, apiSecret string, opts ...CoinbaseOption) *CoinbaseClient {
+	c := &CoinbaseClient{
 		apiKey:    apiKey,
 		apiSecret: apiSecret,
 		baseURL:   "https://api.exchange.coinbase.com",
+		authMode:  CoinbaseAuthLegacy,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// AuthMode 获取鉴权模式
+func (c *CoinbaseClient) AuthMode() CoinbaseAuthMode {
+	return c.authMode
+}
+
+// SetCredentials 轮换 API 凭证，之后发出的请求使用新凭证，可在运行中调用
+func (c *CoinbaseClient) SetCredentials(apiKey, apiSecret string) {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+
+	c.apiKey = apiKey
+	c.apiSecret = apiSecret
+}
+
+// credentials 返回当前凭证，保证 key 和 secret 属于同一组
+func (c *CoinbaseClient) credentials() (apiKey, apiSecret string) {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+
+	return c.apiKey, c.apiSecret
 }
 
 // generateSignature 生成签名
-func (c *CoinbaseClient) generateSignature(timestamp, method, requestPath, body string) string {
+func (c *CoinbaseClient) generateSignature(apiSecret, timestamp, method, requestPath, body string) string {
 	message := timestamp + method + requestPath + body
-	h := hmac.New(sha256.New, []byte(c.apiSecret))
+	h := hmac.New(sha256.New, []byte(apiSecret))
 	h.Write([]byte(message))
 	return hex.EncodeToString(h.Sum(nil))
 }
@@ -94,8 +157,6 @@ func (c *CoinbaseClient) generateSignature(timestamp, method, requestPath, body
 // request 发送请求
 func (c *CoinbaseClient) request(ctx context.Context, method, path string, body string) ([]byte, error) {
 	url := c.baseURL + path
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	signature := c.generateSignature(timestamp, method, path, body)
 
 	var reqBody io.Reader
 	if body != "" {
@@ -108,9 +169,19 @@ func (c *CoinbaseClient) request(ctx context.Context, method, path string, body
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("CB-ACCESS-KEY", c.apiKey)
-	req.Header.Set("CB-ACCESS-SIGN", signature)
-	req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		token, err := c.generateJWT(method, path)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		apiKey, apiSecret := c.credentials()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("CB-ACCESS-KEY", apiKey)
+		req.Header.Set("CB-ACCESS-SIGN", c.generateSignature(apiSecret, timestamp, method, path, body))
+		req.Header.Set("CB-ACCESS-TIMESTAMP", timestamp)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -124,7 +195,7 @@ func (c *CoinbaseClient) request(ctx context.Context, method, path string, body
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("coinbase API error: %s", string(data))
+		return nil, &APIError{Exchange: Coinbase, StatusCode: resp.StatusCode, Message: string(data)}
 	}
 
 	return data, nil
@@ -132,6 +203,10 @@ func (c *CoinbaseClient) request(ctx context.Context, method, path string, body
 
 // GetAccounts 获取账户列表
 func (c *CoinbaseClient) GetAccounts(ctx context.Context) ([]CoinbaseAccount, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedAccounts(ctx)
+	}
+
 	data, err := c.request(ctx, "GET", "/accounts", "")
 	if err != nil {
 		return nil, err
@@ -147,6 +222,10 @@ func (c *CoinbaseClient) GetAccounts(ctx context.Context) ([]CoinbaseAccount, er
 
 // GetAccount 获取指定账户信息
 func (c *CoinbaseClient) GetAccount(ctx context.Context, accountID string) (*CoinbaseAccount, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedAccount(ctx, accountID)
+	}
+
 	data, err := c.request(ctx, "GET", "/accounts/"+accountID, "")
 	if err != nil {
 		return nil, err
@@ -162,6 +241,10 @@ func (c *CoinbaseClient) GetAccount(ctx context.Context, accountID string) (*Coi
 
 // GetProducts 获取交易对列表
 func (c *CoinbaseClient) GetProducts(ctx context.Context) ([]CoinbaseProduct, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedProducts(ctx)
+	}
+
 	data, err := c.request(ctx, "GET", "/products", "")
 	if err != nil {
 		return nil, err
@@ -177,6 +260,10 @@ func (c *CoinbaseClient) GetProducts(ctx context.Context) ([]CoinbaseProduct, er
 
 // GetTicker 获取行情
 func (c *CoinbaseClient) GetTicker(ctx context.Context, productID string) (*CoinbaseTicker, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedTicker(ctx, productID)
+	}
+
 	data, err := c.request(ctx, "GET", "/products/"+productID+"/ticker", "")
 	if err != nil {
 		return nil, err
@@ -190,8 +277,99 @@ func (c *CoinbaseClient) GetTicker(ctx context.Context, productID string) (*Coin
 	return &ticker, nil
 }
 
+// GetOrderBook 获取订单簿
+// level 1 只返回买一和卖一，level 2 返回按价位聚合的深度（Orders 为该价位挂单数），
+// level 3 返回逐笔订单（OrderID 为订单 ID，需要 API 密钥）；Advanced Trade 模式不支持 level 3
+func (c *CoinbaseClient) GetOrderBook(ctx context.Context, productID string, level int) (*OrderBook, error) {
+	if level < 1 || level > 3 {
+		return nil, fmt.Errorf("unsupported order book level %d", level)
+	}
+
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedOrderBook(ctx, productID, level)
+	}
+
+	data, err := c.request(ctx, "GET", fmt.Sprintf("/products/%s/book?level=%d", productID, level), "")
+	if err != nil {
+		return nil, err
+	}
+
+	// level 1/2 每个价位为 [price, size, num_orders]，level 3 为 [price, size, order_id]
+	var resp struct {
+		Sequence json.Number         `json:"sequence"`
+		Time     string              `json:"time"`
+		Bids     [][]json.RawMessage `json:"bids"`
+		Asks     [][]json.RawMessage `json:"asks"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseCoinbaseBookLevels(resp.Bids, level)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseCoinbaseBookLevels(resp.Asks, level)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &OrderBook{
+		Pair:     productID,
+		Sequence: resp.Sequence.String(),
+		Bids:     bids,
+		Asks:     asks,
+	}
+	if resp.Time != "" {
+		if book.Time, err = time.Parse(time.RFC3339Nano, resp.Time); err != nil {
+			return nil, fmt.Errorf("invalid order book time %q: %w", resp.Time, err)
+		}
+	}
+
+	return book, nil
+}
+
+// parseCoinbaseBookLevels 解析 Coinbase 订单簿价位，level 1 只保留第一档
+func parseCoinbaseBookLevels(rows [][]json.RawMessage, level int) ([]OrderBookLevel, error) {
+	if level == 1 && len(rows) > 1 {
+		rows = rows[:1]
+	}
+
+	levels := make([]OrderBookLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("invalid order book level %s", row)
+		}
+
+		var entry OrderBookLevel
+		if err := json.Unmarshal(row[0], &entry.Price); err != nil {
+			return nil, fmt.Errorf("invalid order book price %s: %w", row[0], err)
+		}
+		if err := json.Unmarshal(row[1], &entry.Size); err != nil {
+			return nil, fmt.Errorf("invalid order book size %s: %w", row[1], err)
+		}
+		if len(row) > 2 {
+			var err error
+			if level == 3 {
+				err = json.Unmarshal(row[2], &entry.OrderID)
+			} else {
+				err = json.Unmarshal(row[2], &entry.Orders)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid order book entry %s: %w", row[2], err)
+			}
+		}
+		levels = append(levels, entry)
+	}
+	return levels, nil
+}
+
 // GetOrders 获取订单列表
 func (c *CoinbaseClient) GetOrders(ctx context.Context, status string) ([]CoinbaseOrder, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedOrders(ctx, status)
+	}
+
 	path := "/orders"
 	if status != "" {
 		path += "?status=" + status
@@ -210,8 +388,74 @@ func (c *CoinbaseClient) GetOrders(ctx context.Context, status string) ([]Coinba
 	return orders, nil
 }
 
+// GetOrder 获取订单详情
+func (c *CoinbaseClient) GetOrder(ctx context.Context, orderID string) (*CoinbaseOrder, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedOrder(ctx, orderID)
+	}
+
+	data, err := c.request(ctx, "GET", "/orders/"+orderID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var order CoinbaseOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// GetOrderStatus 实现 OrderStatusGetter 接口
+func (c *CoinbaseClient) GetOrderStatus(ctx context.Context, orderID string) (*OrderResult, error) {
+	order, err := c.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var status OrderStatus
+	switch order.Status {
+	case "filled":
+		status = OrderStatusFilled
+	case "done":
+		// 旧版 API 成交和取消都是 done，通过 done_reason 区分
+		status = OrderStatusFilled
+		if order.DoneReason == "canceled" {
+			status = OrderStatusCancelled
+		}
+	case "cancelled", "canceled", "expired":
+		status = OrderStatusCancelled
+	case "rejected", "failed":
+		status = OrderStatusRejected
+	default:
+		status = OrderStatusOpen
+	}
+
+	price := averagePrice(order.ExecutedValue, order.FilledSize)
+	if price == "" {
+		price = order.Price
+	}
+
+	return &OrderResult{
+		Exchange:   Coinbase,
+		OrderID:    order.ID,
+		Pair:       order.ProductID,
+		Side:       order.Side,
+		Status:     status,
+		Size:       order.Size,
+		FilledSize: order.FilledSize,
+		Price:      price,
+		Fee:        order.FillFees,
+	}, nil
+}
+
 // PlaceOrder 下单
 func (c *CoinbaseClient) PlaceOrder(ctx context.Context, productID, side, orderType, size, price string) (*CoinbaseOrder, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.placeAdvancedOrder(ctx, productID, side, orderType, size, price)
+	}
+
 	orderData := map[string]interface{}{
 		"product_id": productID,
 		"side":       side,
@@ -243,6 +487,10 @@ func (c *CoinbaseClient) PlaceOrder(ctx context.Context, productID, side, orderT
 
 // CancelOrder 取消订单
 func (c *CoinbaseClient) CancelOrder(ctx context.Context, orderID string) error {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.cancelAdvancedOrder(ctx, orderID)
+	}
+
 	_, err := c.request(ctx, "DELETE", "/orders/"+orderID, "")
 	return err
 }
@@ -286,3 +534,149 @@ func (c *CoinbaseClient) GetBalances(ctx context.Context) (map[string]string, er
 
 	return balances, nil
 }
+
+// coinbaseGranularities K 线周期对应的秒数（旧版 API）及枚举名（Advanced Trade）
+var coinbaseGranularities = map[string]struct {
+	seconds int
+	name    string
+}{
+	"1m":  {60, "ONE_MINUTE"},
+	"5m":  {300, "FIVE_MINUTE"},
+	"15m": {900, "FIFTEEN_MINUTE"},
+	"1h":  {3600, "ONE_HOUR"},
+	"6h":  {21600, "SIX_HOUR"},
+	"1d":  {86400, "ONE_DAY"},
+}
+
+const (
+	// coinbaseCandlesLimit 旧版 K 线接口单次最多返回的根数
+	coinbaseCandlesLimit = 300
+	// coinbaseAdvancedCandlesLimit Advanced Trade K 线接口单次最多返回的根数
+	coinbaseAdvancedCandlesLimit = 350
+)
+
+// coinbaseFillsLimit 成交记录每页条数
+const coinbaseFillsLimit = 100
+
+// GetTradeHistory 获取成交记录（/fills），按 trade_id 游标向更早的记录翻页
+func (c *CoinbaseClient) GetTradeHistory(ctx context.Context, pair string, start, end time.Time) ([]Trade, error) {
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return c.getAdvancedTradeHistory(ctx, pair, start, end)
+	}
+
+	var trades []Trade
+	after := ""
+	for {
+		path := fmt.Sprintf("/fills?product_id=%s&limit=%d&start_date=%s&end_date=%s",
+			url.QueryEscape(pair),
+			coinbaseFillsLimit,
+			url.QueryEscape(start.UTC().Format(time.RFC3339)),
+			url.QueryEscape(end.UTC().Format(time.RFC3339)),
+		)
+		if after != "" {
+			path += "&after=" + after
+		}
+
+		data, err := c.request(ctx, "GET", path, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var fills []struct {
+			TradeID   int64     `json:"trade_id"`
+			ProductID string    `json:"product_id"`
+			Price     string    `json:"price"`
+			Size      string    `json:"size"`
+			Fee       string    `json:"fee"`
+			Side      string    `json:"side"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		if err := json.Unmarshal(data, &fills); err != nil {
+			return nil, err
+		}
+
+		for _, fill := range fills {
+			if fill.CreatedAt.Before(start) || fill.CreatedAt.After(end) {
+				continue
+			}
+			trades = append(trades, Trade{
+				ID:    strconv.FormatInt(fill.TradeID, 10),
+				Pair:  fill.ProductID,
+				Side:  fill.Side,
+				Price: fill.Price,
+				Size:  fill.Size,
+				Fee:   fill.Fee,
+				Time:  fill.CreatedAt.UTC(),
+			})
+		}
+
+		// 结果按时间倒序返回，最后一条早于 start 时不再翻页
+		if len(fills) < coinbaseFillsLimit || fills[len(fills)-1].CreatedAt.Before(start) {
+			break
+		}
+		after = strconv.FormatInt(fills[len(fills)-1].TradeID, 10)
+	}
+
+	return sortTrades(trades), nil
+}
+
+// GetCandles 获取 K 线数据，超过单次上限（旧版 300 根，Advanced Trade 350 根）时按时间窗口分批请求
+func (c *CoinbaseClient) GetCandles(ctx context.Context, pair string, interval string, start, end time.Time) ([]Candle, error) {
+	granularity, ok := coinbaseGranularities[interval]
+	if !ok {
+		return nil, unsupportedInterval(Coinbase, interval)
+	}
+
+	step := time.Duration(granularity.seconds) * time.Second
+	if c.authMode == CoinbaseAuthAdvancedTrade {
+		return fetchCandleWindows(start, end, step, coinbaseAdvancedCandlesLimit, func(start, end time.Time) ([]Candle, error) {
+			return c.getAdvancedCandles(ctx, pair, granularity.name, start, end)
+		})
+	}
+
+	return fetchCandleWindows(start, end, step, coinbaseCandlesLimit, func(start, end time.Time) ([]Candle, error) {
+		return c.getCandles(ctx, pair, granularity.seconds, start, end)
+	})
+}
+
+// getCandles 通过旧版接口获取单个时间窗口内的 K 线
+func (c *CoinbaseClient) getCandles(ctx context.Context, pair string, granularity int, start, end time.Time) ([]Candle, error) {
+	path := fmt.Sprintf("/products/%s/candles?granularity=%d&start=%s&end=%s",
+		pair,
+		granularity,
+		url.QueryEscape(start.UTC().Format(time.RFC3339)),
+		url.QueryEscape(end.UTC().Format(time.RFC3339)),
+	)
+
+	data, err := c.request(ctx, "GET", path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// 每根 K 线为 [time, low, high, open, close, volume]，使用 json.Number 保留原始精度
+	var rows [][]json.Number
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	candles := make([]Candle, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 6 {
+			continue
+		}
+		ts, err := row[0].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid candle time %q: %w", row[0], err)
+		}
+		candles = append(candles, Candle{
+			Time:   time.Unix(ts, 0).UTC(),
+			Low:    row[1].String(),
+			High:   row[2].String(),
+			Open:   row[3].String(),
+			Close:  row[4].String(),
+			Volume: row[5].String(),
+		})
+	}
+
+	return candles, nil
+}
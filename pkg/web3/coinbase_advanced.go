@@ -0,0 +1,593 @@
+package web3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/web3/decimal"
+)
+
+// Coinbase Advanced Trade v3 API 路径前缀
+const coinbaseAdvancedPrefix = "/api/v3/brokerage"
+
+// coinbaseJWTExpiry JWT 有效期（Coinbase 要求不超过 2 分钟）
+const coinbaseJWTExpiry = 2 * time.Minute
+
+// coinbaseMoney Advanced Trade 金额字段
+type coinbaseMoney struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// coinbaseAdvancedAccount Advanced Trade 账户
+type coinbaseAdvancedAccount struct {
+	UUID             string        `json:"uuid"`
+	Currency         string        `json:"currency"`
+	AvailableBalance coinbaseMoney `json:"available_balance"`
+	Hold             coinbaseMoney `json:"hold"`
+}
+
+// coinbaseAdvancedProduct Advanced Trade 交易对
+type coinbaseAdvancedProduct struct {
+	ProductID      string `json:"product_id"`
+	Price          string `json:"price"`
+	BaseCurrency   string `json:"base_currency_id"`
+	QuoteCurrency  string `json:"quote_currency_id"`
+	BaseIncrement  string `json:"base_increment"`
+	QuoteIncrement string `json:"quote_increment"`
+	DisplayName    string `json:"display_name"`
+	Status         string `json:"status"`
+	Volume24h      string `json:"volume_24h"`
+}
+
+// coinbaseAdvancedOrder Advanced Trade 订单
+type coinbaseAdvancedOrder struct {
+	OrderID            string `json:"order_id"`
+	ProductID          string `json:"product_id"`
+	Side               string `json:"side"`
+	Status             string `json:"status"`
+	TimeInForce        string `json:"time_in_force"`
+	CreatedTime        string `json:"created_time"`
+	FilledSize         string `json:"filled_size"`
+	AverageFilledPrice string `json:"average_filled_price"`
+	TotalFees          string `json:"total_fees"`
+	FilledValue        string `json:"filled_value"`
+	OrderType          string `json:"order_type"`
+	Settled            bool   `json:"settled"`
+}
+
+// toAccount 转换为通用账户结构
+func (a coinbaseAdvancedAccount) toAccount() CoinbaseAccount {
+	return CoinbaseAccount{
+		ID:        a.UUID,
+		Currency:  a.Currency,
+		Balance:   addDecimalStrings(a.AvailableBalance.Value, a.Hold.Value),
+		Available: a.AvailableBalance.Value,
+		Hold:      a.Hold.Value,
+	}
+}
+
+// toOrder 转换为通用订单结构
+func (o coinbaseAdvancedOrder) toOrder() CoinbaseOrder {
+	return CoinbaseOrder{
+		ID:            o.OrderID,
+		ProductID:     o.ProductID,
+		Side:          strings.ToLower(o.Side),
+		Type:          strings.ToLower(o.OrderType),
+		TimeInForce:   o.TimeInForce,
+		CreatedAt:     o.CreatedTime,
+		FillFees:      o.TotalFees,
+		FilledSize:    o.FilledSize,
+		ExecutedValue: o.FilledValue,
+		Price:         o.AverageFilledPrice,
+		Status:        strings.ToLower(o.Status),
+		Settled:       o.Settled,
+	}
+}
+
+// generateJWT 生成 Advanced Trade 请求使用的 ES256 JWT
+func (c *CoinbaseClient) generateJWT(method, path string) (string, error) {
+	apiKey, apiSecret := c.credentials()
+	key, err := parseCoinbasePrivateKey(apiSecret)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid coinbase base URL: %w", err)
+	}
+
+	// uri 声明不包含查询参数
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	now := time.Now().Unix()
+	header := map[string]interface{}{
+		"alg":   "ES256",
+		"typ":   "JWT",
+		"kid":   apiKey,
+		"nonce": hex.EncodeToString(nonce),
+	}
+	claims := map[string]interface{}{
+		"sub": apiKey,
+		"iss": "cdp",
+		"nbf": now,
+		"exp": now + int64(coinbaseJWTExpiry/time.Second),
+		"uri": method + " " + u.Host + path,
+	}
+
+	return signES256(key, header, claims)
+}
+
+// signES256 使用 ECDSA P-256 签名 JWT
+func signES256(key *ecdsa.PrivateKey, header, claims map[string]interface{}) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS 签名为定长的 r || s
+	size := (key.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	r.FillBytes(signature[:size])
+	s.FillBytes(signature[size:])
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseCoinbasePrivateKey 解析 PEM 格式的 EC 私钥（支持 SEC1 和 PKCS#8）
+// 环境变量中的私钥常以字面量 \n 换行，这里一并处理
+func parseCoinbasePrivateKey(secret string) (*ecdsa.PrivateKey, error) {
+	secret = strings.ReplaceAll(secret, `\n`, "\n")
+
+	block, _ := pem.Decode([]byte(secret))
+	if block == nil {
+		return nil, errors.New("coinbase: invalid PEM private key")
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("coinbase: failed to parse private key: %w", err)
+	}
+
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("coinbase: private key is not an ECDSA key")
+	}
+	return key, nil
+}
+
+// getAdvancedAccounts 获取账户列表（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedAccounts(ctx context.Context) ([]CoinbaseAccount, error) {
+	var accounts []CoinbaseAccount
+	cursor := ""
+
+	for {
+		path := coinbaseAdvancedPrefix + "/accounts?limit=250"
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+
+		data, err := c.request(ctx, "GET", path, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Accounts []coinbaseAdvancedAccount `json:"accounts"`
+			HasNext  bool                      `json:"has_next"`
+			Cursor   string                    `json:"cursor"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, account := range resp.Accounts {
+			accounts = append(accounts, account.toAccount())
+		}
+
+		if !resp.HasNext || resp.Cursor == "" {
+			return accounts, nil
+		}
+		cursor = resp.Cursor
+	}
+}
+
+// getAdvancedAccount 获取指定账户信息（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedAccount(ctx context.Context, accountID string) (*CoinbaseAccount, error) {
+	data, err := c.request(ctx, "GET", coinbaseAdvancedPrefix+"/accounts/"+accountID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Account coinbaseAdvancedAccount `json:"account"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	account := resp.Account.toAccount()
+	return &account, nil
+}
+
+// getAdvancedProducts 获取交易对列表（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedProducts(ctx context.Context) ([]CoinbaseProduct, error) {
+	data, err := c.request(ctx, "GET", coinbaseAdvancedPrefix+"/products", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Products []coinbaseAdvancedProduct `json:"products"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	products := make([]CoinbaseProduct, 0, len(resp.Products))
+	for _, p := range resp.Products {
+		products = append(products, CoinbaseProduct{
+			ID:             p.ProductID,
+			BaseCurrency:   p.BaseCurrency,
+			QuoteCurrency:  p.QuoteCurrency,
+			BaseIncrement:  p.BaseIncrement,
+			QuoteIncrement: p.QuoteIncrement,
+			DisplayName:    p.DisplayName,
+			Status:         p.Status,
+		})
+	}
+	return products, nil
+}
+
+// getAdvancedTicker 获取行情（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedTicker(ctx context.Context, productID string) (*CoinbaseTicker, error) {
+	data, err := c.request(ctx, "GET", coinbaseAdvancedPrefix+"/products/"+productID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var product coinbaseAdvancedProduct
+	if err := json.Unmarshal(data, &product); err != nil {
+		return nil, err
+	}
+
+	return &CoinbaseTicker{
+		Price:  product.Price,
+		Volume: product.Volume24h,
+		Time:   time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// getAdvancedOrders 获取订单列表（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedOrders(ctx context.Context, status string) ([]CoinbaseOrder, error) {
+	path := coinbaseAdvancedPrefix + "/orders/historical/batch"
+	if status != "" {
+		path += "?order_status=" + url.QueryEscape(strings.ToUpper(status))
+	}
+
+	data, err := c.request(ctx, "GET", path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Orders []coinbaseAdvancedOrder `json:"orders"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	orders := make([]CoinbaseOrder, 0, len(resp.Orders))
+	for _, order := range resp.Orders {
+		orders = append(orders, order.toOrder())
+	}
+	return orders, nil
+}
+
+// getAdvancedOrder 获取订单详情（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedOrder(ctx context.Context, orderID string) (*CoinbaseOrder, error) {
+	data, err := c.request(ctx, "GET", coinbaseAdvancedPrefix+"/orders/historical/"+url.PathEscape(orderID), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Order coinbaseAdvancedOrder `json:"order"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	order := resp.Order.toOrder()
+	return &order, nil
+}
+
+// placeAdvancedOrder 下单（Advanced Trade）
+func (c *CoinbaseClient) placeAdvancedOrder(ctx context.Context, productID, side, orderType, size, price string) (*CoinbaseOrder, error) {
+	clientOrderID := make([]byte, 16)
+	if _, err := rand.Read(clientOrderID); err != nil {
+		return nil, err
+	}
+
+	var configuration map[string]interface{}
+	if orderType == "limit" && price != "" {
+		configuration = map[string]interface{}{
+			"limit_limit_gtc": map[string]string{
+				"base_size":   size,
+				"limit_price": price,
+			},
+		}
+	} else {
+		configuration = map[string]interface{}{
+			"market_market_ioc": map[string]string{
+				"base_size": size,
+			},
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"client_order_id":     hex.EncodeToString(clientOrderID),
+		"product_id":          productID,
+		"side":                strings.ToUpper(side),
+		"order_configuration": configuration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.request(ctx, "POST", coinbaseAdvancedPrefix+"/orders", string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Success         bool `json:"success"`
+		SuccessResponse struct {
+			OrderID string `json:"order_id"`
+		} `json:"success_response"`
+		ErrorResponse struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		} `json:"error_response"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("coinbase order rejected: %s %s", resp.ErrorResponse.Error, resp.ErrorResponse.Message)
+	}
+
+	return &CoinbaseOrder{
+		ID:        resp.SuccessResponse.OrderID,
+		ProductID: productID,
+		Side:      strings.ToLower(side),
+		Type:      orderType,
+		Size:      size,
+		Price:     price,
+		Status:    "pending",
+	}, nil
+}
+
+// cancelAdvancedOrder 取消订单（Advanced Trade）
+func (c *CoinbaseClient) cancelAdvancedOrder(ctx context.Context, orderID string) error {
+	body, err := json.Marshal(map[string][]string{"order_ids": {orderID}})
+	if err != nil {
+		return err
+	}
+
+	data, err := c.request(ctx, "POST", coinbaseAdvancedPrefix+"/orders/batch_cancel", string(body))
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Results []struct {
+			Success       bool   `json:"success"`
+			FailureReason string `json:"failure_reason"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Results) > 0 && !resp.Results[0].Success {
+		return fmt.Errorf("coinbase cancel failed: %s", resp.Results[0].FailureReason)
+	}
+	return nil
+}
+
+// getAdvancedTradeHistory 获取成交记录（Advanced Trade），按 cursor 翻页
+func (c *CoinbaseClient) getAdvancedTradeHistory(ctx context.Context, productID string, start, end time.Time) ([]Trade, error) {
+	var trades []Trade
+	cursor := ""
+	for {
+		path := fmt.Sprintf("%s/orders/historical/fills?product_ids=%s&start_sequence_timestamp=%s&end_sequence_timestamp=%s&limit=%d",
+			coinbaseAdvancedPrefix,
+			url.QueryEscape(productID),
+			url.QueryEscape(start.UTC().Format(time.RFC3339)),
+			url.QueryEscape(end.UTC().Format(time.RFC3339)),
+			coinbaseFillsLimit,
+		)
+		if cursor != "" {
+			path += "&cursor=" + url.QueryEscape(cursor)
+		}
+
+		data, err := c.request(ctx, "GET", path, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Fills []struct {
+				TradeID    string    `json:"trade_id"`
+				ProductID  string    `json:"product_id"`
+				Price      string    `json:"price"`
+				Size       string    `json:"size"`
+				Commission string    `json:"commission"`
+				Side       string    `json:"side"`
+				TradeTime  time.Time `json:"trade_time"`
+			} `json:"fills"`
+			Cursor string `json:"cursor"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, fill := range resp.Fills {
+			trades = append(trades, Trade{
+				ID:    fill.TradeID,
+				Pair:  fill.ProductID,
+				Side:  strings.ToLower(fill.Side),
+				Price: fill.Price,
+				Size:  fill.Size,
+				Fee:   fill.Commission,
+				Time:  fill.TradeTime.UTC(),
+			})
+		}
+
+		if resp.Cursor == "" || len(resp.Fills) == 0 {
+			break
+		}
+		cursor = resp.Cursor
+	}
+
+	return sortTrades(trades), nil
+}
+
+// getAdvancedCandles 获取 K 线数据（Advanced Trade）
+func (c *CoinbaseClient) getAdvancedCandles(ctx context.Context, productID, granularity string, start, end time.Time) ([]Candle, error) {
+	path := fmt.Sprintf("%s/products/%s/candles?start=%d&end=%d&granularity=%s",
+		coinbaseAdvancedPrefix, productID, start.Unix(), end.Unix(), granularity)
+
+	data, err := c.request(ctx, "GET", path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Candles []struct {
+			Start  string `json:"start"`
+			Low    string `json:"low"`
+			High   string `json:"high"`
+			Open   string `json:"open"`
+			Close  string `json:"close"`
+			Volume string `json:"volume"`
+		} `json:"candles"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	candles := make([]Candle, 0, len(resp.Candles))
+	for _, row := range resp.Candles {
+		ts, err := strconv.ParseInt(row.Start, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid candle start %q: %w", row.Start, err)
+		}
+		candles = append(candles, Candle{
+			Time:   time.Unix(ts, 0).UTC(),
+			Open:   row.Open,
+			High:   row.High,
+			Low:    row.Low,
+			Close:  row.Close,
+			Volume: row.Volume,
+		})
+	}
+
+	return sortCandles(candles), nil
+}
+
+// getAdvancedOrderBook 获取订单簿（Advanced Trade），level 1 只请求一档，level 2 返回全部聚合深度
+func (c *CoinbaseClient) getAdvancedOrderBook(ctx context.Context, productID string, level int) (*OrderBook, error) {
+	if level == 3 {
+		return nil, errors.New("order book level 3 is not supported by Advanced Trade")
+	}
+
+	path := coinbaseAdvancedPrefix + "/product_book?product_id=" + url.QueryEscape(productID)
+	if level == 1 {
+		path += "&limit=1"
+	}
+
+	data, err := c.request(ctx, "GET", path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		PriceBook struct {
+			ProductID string           `json:"product_id"`
+			Bids      []OrderBookLevel `json:"bids"`
+			Asks      []OrderBookLevel `json:"asks"`
+			Time      time.Time        `json:"time"`
+		} `json:"pricebook"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	return &OrderBook{
+		Pair: productID,
+		Time: resp.PriceBook.Time,
+		Bids: resp.PriceBook.Bids,
+		Asks: resp.PriceBook.Asks,
+	}, nil
+}
+
+// addDecimalStrings 将两个十进制字符串精确相加，解析失败时返回 a
+func addDecimalStrings(a, b string) string {
+	x, err := decimal.ParseAmount(a)
+	if err != nil {
+		return a
+	}
+	y, err := decimal.ParseAmount(b)
+	if err != nil {
+		return a
+	}
+
+	places := 0
+	for _, v := range []string{a, b} {
+		if i := strings.IndexByte(v, '.'); i >= 0 && len(v)-i-1 > places {
+			places = len(v) - i - 1
+		}
+	}
+	return x.Add(y).StringFixed(places)
+}
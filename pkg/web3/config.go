@@ -23,6 +23,7 @@ type Config struct {
 	// Exchanges
 	CoinbaseAPIKey    string
 	CoinbaseAPISecret string
+	CoinbaseAuthMode  string // legacy（默认）或 advanced
 	KuCoinAPIKey      string
 	KuCoinAPISecret   string
 	KuCoinPassphrase  string
@@ -48,6 +49,7 @@ func LoadConfig() *Config {
 			SolanaRPC:         getEnv("WEB3_SOLANA_RPC", "https://api.mainnet-beta.solana.com"),
 			CoinbaseAPIKey:    getEnv("EXCHANGE_COINBASE_API_KEY", ""),
 			CoinbaseAPISecret: getEnv("EXCHANGE_COINBASE_API_SECRET", ""),
+			CoinbaseAuthMode:  getEnv("EXCHANGE_COINBASE_AUTH_MODE", string(CoinbaseAuthLegacy)),
 			KuCoinAPIKey:      getEnv("EXCHANGE_KUCOIN_API_KEY", ""),
 			KuCoinAPISecret:   getEnv("EXCHANGE_KUCOIN_API_SECRET", ""),
 			KuCoinPassphrase:  getEnv("EXCHANGE_KUCOIN_PASSPHRASE", ""),
@@ -114,7 +116,11 @@ func InitializeClients() error {
 	exchangeManager := GetExchangeManager()
 
 	if cfg.CoinbaseAPIKey != "" && cfg.CoinbaseAPISecret != "" {
-		coinbaseClient := NewCoinbaseClient(cfg.CoinbaseAPIKey, cfg.CoinbaseAPISecret)
+		var opts []CoinbaseOption
+		if CoinbaseAuthMode(cfg.CoinbaseAuthMode) == CoinbaseAuthAdvancedTrade {
+			opts = append(opts, WithCoinbaseAdvancedTrade())
+		}
+		coinbaseClient := NewCoinbaseClient(cfg.CoinbaseAPIKey, cfg.CoinbaseAPISecret, opts...)
 		exchangeManager.RegisterExchange(Coinbase, coinbaseClient)
 	}
 
@@ -0,0 +1,180 @@
+package decimal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// DefaultPrecision String 输出的最大小数位数
+const DefaultPrecision = 8
+
+// Amount 精确的十进制数值，用于余额和价格运算，避免 float64 的精度损失
+// 零值表示 0，所有运算返回新值，不修改接收者
+type Amount struct {
+	r *big.Rat
+}
+
+// Zero 数值 0
+var Zero = Amount{}
+
+// ParseAmount 解析十进制字符串，支持 "1.5"、"-0.001"、"1e-8" 等格式
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	// big.Rat 也接受 "1/3" 形式的分数，余额和价格不应出现这种格式
+	if s == "" || strings.Contains(s, "/") {
+		return Amount{}, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount: %q", s)
+	}
+	return Amount{r: r}, nil
+}
+
+// MustParseAmount 解析十进制字符串，格式错误时 panic，仅用于常量
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// NewFromInt 从整数创建
+func NewFromInt(v int64) Amount {
+	return Amount{r: new(big.Rat).SetInt64(v)}
+}
+
+// NewFromBaseUnits 从最小单位数量创建，如 wei（decimals=18）、lamports（decimals=9）
+func NewFromBaseUnits(v *big.Int, decimals int) Amount {
+	return Amount{r: new(big.Rat).SetInt(v)}.Shift(-decimals)
+}
+
+// rat 返回内部值，零值时返回 0
+func (a Amount) rat() *big.Rat {
+	if a.r == nil {
+		return new(big.Rat)
+	}
+	return a.r
+}
+
+// Add 加法
+func (a Amount) Add(b Amount) Amount {
+	return Amount{r: new(big.Rat).Add(a.rat(), b.rat())}
+}
+
+// Sub 减法
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{r: new(big.Rat).Sub(a.rat(), b.rat())}
+}
+
+// Mul 乘法
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{r: new(big.Rat).Mul(a.rat(), b.rat())}
+}
+
+// Quo 除法，b 为 0 时 panic，调用方需先检查 IsZero
+func (a Amount) Quo(b Amount) Amount {
+	return Amount{r: new(big.Rat).Quo(a.rat(), b.rat())}
+}
+
+// Shift 乘以 10 的 places 次方，places 为负数时相当于除以 10 的 -places 次方
+func (a Amount) Shift(places int) Amount {
+	if places == 0 {
+		return a
+	}
+
+	exp := places
+	if exp < 0 {
+		exp = -exp
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	if places > 0 {
+		return Amount{r: new(big.Rat).Mul(a.rat(), scale)}
+	}
+	return Amount{r: new(big.Rat).Quo(a.rat(), scale)}
+}
+
+// Cmp 比较大小，a < b 返回 -1，相等返回 0，a > b 返回 1
+func (a Amount) Cmp(b Amount) int {
+	return a.rat().Cmp(b.rat())
+}
+
+// Sign 返回符号：负数 -1，零 0，正数 1
+func (a Amount) Sign() int {
+	return a.rat().Sign()
+}
+
+// IsZero 是否为 0
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// String 格式化为最多 DefaultPrecision 位小数的字符串，去除末尾多余的 0
+func (a Amount) String() string {
+	return a.Format(DefaultPrecision)
+}
+
+// Format 格式化为最多 places 位小数的字符串（四舍五入），去除末尾多余的 0
+func (a Amount) Format(places int) string {
+	s := a.StringFixed(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// StringFixed 格式化为固定 places 位小数的字符串（四舍五入），保留末尾的 0
+func (a Amount) StringFixed(places int) string {
+	if places < 0 {
+		places = 0
+	}
+	return a.rat().FloatString(places)
+}
+
+// MarshalJSON 实现 json.Marshaler，输出为字符串以免 JSON 数字丢失精度
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，接受字符串或数字
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*a = Amount{}
+		return nil
+	}
+
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Median 计算中位数，values 为空时返回 0，不修改 values 的顺序
+func Median(values []Amount) Amount {
+	if len(values) == 0 {
+		return Amount{}
+	}
+
+	sorted := make([]Amount, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return sorted[mid-1].Add(sorted[mid]).Quo(NewFromInt(2))
+}
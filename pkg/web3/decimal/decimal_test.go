@@ -0,0 +1,122 @@
+package decimal
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1.5", "1.5", false},
+		{" -0.001 ", "-0.001", false},
+		{"1e-8", "0.00000001", false},
+		{"100", "100", false},
+		{"0.000000001", "0", false}, // 超出默认精度
+		{"", "", true},
+		{"abc", "", true},
+		{"1/3", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParseAmount(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	// float64 中 0.1 + 0.2 != 0.3
+	sum := MustParseAmount("0.1").Add(MustParseAmount("0.2"))
+	if sum.Cmp(MustParseAmount("0.3")) != 0 {
+		t.Errorf("expected 0.1 + 0.2 = 0.3, got %s", sum)
+	}
+
+	balance := MustParseAmount("123456789.123456789")
+	price := MustParseAmount("61234.56")
+	if got := balance.Mul(price).Format(18); got != "7559822160987.66215342784" {
+		t.Errorf("unexpected product %s", got)
+	}
+
+	if got := MustParseAmount("10").Quo(MustParseAmount("3")).Format(4); got != "3.3333" {
+		t.Errorf("unexpected quotient %s", got)
+	}
+	if got := MustParseAmount("2").Sub(MustParseAmount("2.5")); got.Sign() != -1 || got.String() != "-0.5" {
+		t.Errorf("unexpected difference %s", got)
+	}
+	if !Zero.IsZero() || Zero.String() != "0" || Zero.Add(NewFromInt(1)).String() != "1" {
+		t.Error("expected zero value to behave as 0")
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	a := MustParseAmount("1.23456789")
+	if got := a.Format(2); got != "1.23" {
+		t.Errorf("Format(2) = %s", got)
+	}
+	if got := a.Format(4); got != "1.2346" {
+		t.Errorf("Format(4) = %s", got)
+	}
+	if got := MustParseAmount("1.5").StringFixed(4); got != "1.5000" {
+		t.Errorf("StringFixed(4) = %s", got)
+	}
+	if got := MustParseAmount("-0.0000000001").String(); got != "0" {
+		t.Errorf("expected negative rounding to 0, got %s", got)
+	}
+}
+
+func TestNewFromBaseUnits(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1500000000000000000", 10)
+	if got := NewFromBaseUnits(wei, 18).String(); got != "1.5" {
+		t.Errorf("expected 1.5 ETH, got %s", got)
+	}
+	if got := MustParseAmount("1.5").Shift(9).String(); got != "1500000000" {
+		t.Errorf("expected 1500000000 lamports, got %s", got)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	values := []Amount{MustParseAmount("3"), MustParseAmount("1"), MustParseAmount("2"), MustParseAmount("10")}
+	if got := Median(values).String(); got != "2.5" {
+		t.Errorf("expected median 2.5, got %s", got)
+	}
+	if values[0].String() != "3" {
+		t.Error("expected Median not to reorder input")
+	}
+	if got := Median(values[:3]).String(); got != "2" {
+		t.Errorf("expected median 2, got %s", got)
+	}
+	if !Median(nil).IsZero() {
+		t.Error("expected median of empty slice to be 0")
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Price Amount `json:"price"`
+		Size  Amount `json:"size"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":"61234.56","size":0.25}`), &v); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if v.Price.String() != "61234.56" || v.Size.String() != "0.25" {
+		t.Errorf("unexpected values %s %s", v.Price, v.Size)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"price":"61234.56","size":"0.25"}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
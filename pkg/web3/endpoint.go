@@ -0,0 +1,216 @@
+package web3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultEndpointFailureThreshold 连续失败多少次后暂时跳过该节点
+	DefaultEndpointFailureThreshold = 3
+	// DefaultEndpointCooldown 被跳过的节点多久后重新尝试
+	DefaultEndpointCooldown = 30 * time.Second
+	// DefaultEndpointTimeout 单个节点等待响应头的超时时间，超时后切换到下一个节点
+	DefaultEndpointTimeout = 10 * time.Second
+)
+
+// EndpointStatus 节点健康状态
+type EndpointStatus struct {
+	URL       string    `json:"url"`
+	Active    bool      `json:"active"`
+	Failures  int       `json:"failures"` // 连续失败次数
+	DownUntil time.Time `json:"down_until,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// endpoint 单个 RPC 节点
+type endpoint struct {
+	url       *url.URL
+	failures  int
+	downUntil time.Time
+	lastError string
+}
+
+// EndpointPool 多个 RPC 节点组成的连接池，实现 http.RoundTripper
+// 请求发往当前活跃节点，连接失败、超时或返回 5xx/429 时依次切换到下一个节点；
+// 连续失败达到阈值的节点在冷却期内被跳过，所有节点都不可用时仍会逐个尝试
+type EndpointPool struct {
+	mu               sync.Mutex
+	endpoints        []*endpoint
+	active           int
+	transport        http.RoundTripper
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+// NewEndpointPool 创建节点池，只支持 http/https 节点
+func NewEndpointPool(urls []string) (*EndpointPool, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("at least one rpc endpoint is required")
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid rpc endpoint %q: only http(s) endpoints are supported", raw)
+		}
+		endpoints = append(endpoints, &endpoint{url: u})
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = DefaultEndpointTimeout
+
+	return &EndpointPool{
+		endpoints:        endpoints,
+		transport:        transport,
+		failureThreshold: DefaultEndpointFailureThreshold,
+		cooldown:         DefaultEndpointCooldown,
+	}, nil
+}
+
+// SetFailureThreshold 设置连续失败阈值和冷却时间
+func (p *EndpointPool) SetFailureThreshold(threshold int, cooldown time.Duration) *EndpointPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failureThreshold = threshold
+	p.cooldown = cooldown
+	return p
+}
+
+// Active 返回当前活跃节点的 URL
+func (p *EndpointPool) Active() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.endpoints[p.active].url.String()
+}
+
+// Status 返回所有节点的健康状态
+func (p *EndpointPool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = EndpointStatus{
+			URL:       e.url.String(),
+			Active:    i == p.active,
+			Failures:  e.failures,
+			DownUntil: e.downUntil,
+			LastError: e.lastError,
+		}
+	}
+	return statuses
+}
+
+// RoundTrip 实现 http.RoundTripper，按健康状态依次尝试各节点
+func (p *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	// 请求体需要在切换节点时重新发送
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	var lastErr error
+	order := p.attemptOrder()
+	for i, index := range order {
+		attempt := req.Clone(req.Context())
+		attempt.URL = p.endpointURL(index)
+		attempt.Host = attempt.URL.Host
+		if body != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(body))
+			attempt.ContentLength = int64(len(body))
+		}
+
+		resp, err := p.transport.RoundTrip(attempt)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			p.markSuccess(index)
+			return resp, nil
+		}
+
+		if err == nil {
+			err = fmt.Errorf("rpc endpoint returned status %d", resp.StatusCode)
+			// 最后一个节点的错误响应原样返回，由调用方解析
+			if i == len(order)-1 {
+				p.markFailure(index, err)
+				return resp, nil
+			}
+			resp.Body.Close()
+		}
+		p.markFailure(index, err)
+		lastErr = err
+
+		// 调用方已取消时不再尝试其他节点
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attemptOrder 返回本次请求尝试节点的顺序：从活跃节点开始，冷却中的节点排在最后
+func (p *EndpointPool) attemptOrder() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	healthy := make([]int, 0, len(p.endpoints))
+	var down []int
+	for i := range p.endpoints {
+		index := (p.active + i) % len(p.endpoints)
+		if now.Before(p.endpoints[index].downUntil) {
+			down = append(down, index)
+		} else {
+			healthy = append(healthy, index)
+		}
+	}
+	return append(healthy, down...)
+}
+
+// endpointURL 返回节点 URL 的副本
+func (p *EndpointPool) endpointURL(index int) *url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	u := *p.endpoints[index].url
+	return &u
+}
+
+// markSuccess 记录节点请求成功，并将其设为活跃节点
+func (p *EndpointPool) markSuccess(index int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.endpoints[index]
+	e.failures = 0
+	e.downUntil = time.Time{}
+	e.lastError = ""
+	p.active = index
+}
+
+// markFailure 记录节点请求失败，连续失败达到阈值时进入冷却
+func (p *EndpointPool) markFailure(index int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.endpoints[index]
+	e.failures++
+	e.lastError = err.Error()
+	if p.failureThreshold > 0 && e.failures >= p.failureThreshold {
+		e.downUntil = time.Now().Add(p.cooldown)
+	}
+}
@@ -0,0 +1,170 @@
+package web3
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ENSRegistryAddress ENS 注册表合约地址（以太坊主网）
+const ENSRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// ErrENSNotFound ENS 名称未设置解析
+var ErrENSNotFound = errors.New("ens name not found")
+
+var (
+	ensResolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	ensAddrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	ensNameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+)
+
+// ResolveENS 将 ENS 名称（如 vitalik.eth）解析为地址
+func (c *EthereumClient) ResolveENS(ctx context.Context, name string) (string, error) {
+	node := ENSNamehash(name)
+
+	resolver, err := c.ensResolver(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.ethCall(ctx, resolver, append(append([]byte{}, ensAddrSelector...), node[:]...))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve ens name %s: %w", name, err)
+	}
+
+	address := common.BytesToAddress(result)
+	if len(result) < 32 || address == (common.Address{}) {
+		return "", fmt.Errorf("%w: %s", ErrENSNotFound, name)
+	}
+
+	return address.Hex(), nil
+}
+
+// ReverseResolve 反向解析地址的 ENS 主名称
+// 会正向解析结果名称并校验其指向该地址，防止伪造的反向记录
+func (c *EthereumClient) ReverseResolve(ctx context.Context, address string) (string, error) {
+	if err := ValidateAddress(c.chain, address); err != nil {
+		return "", err
+	}
+
+	addr := common.HexToAddress(address)
+	node := ENSNamehash(strings.ToLower(addr.Hex()[2:]) + ".addr.reverse")
+
+	resolver, err := c.ensResolver(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.ethCall(ctx, resolver, append(append([]byte{}, ensNameSelector...), node[:]...))
+	if err != nil {
+		return "", fmt.Errorf("failed to reverse resolve %s: %w", address, err)
+	}
+
+	name, err := decodeABIString(result)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: %s", ErrENSNotFound, address)
+	}
+
+	resolved, err := c.ResolveENS(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if common.HexToAddress(resolved) != addr {
+		return "", fmt.Errorf("ens name %s does not resolve back to %s", name, address)
+	}
+
+	return name, nil
+}
+
+// resolveAddress 输入为 ENS 名称时解析为地址，否则原样返回
+func (c *EthereumClient) resolveAddress(ctx context.Context, input string) (string, error) {
+	if strings.HasPrefix(input, "0x") || !strings.Contains(input, ".") {
+		return input, nil
+	}
+	return c.ResolveENS(ctx, input)
+}
+
+// ensResolver 获取节点的解析器合约地址
+func (c *EthereumClient) ensResolver(ctx context.Context, node common.Hash) (string, error) {
+	if c.chain != Ethereum {
+		return "", fmt.Errorf("ens is not supported on chain: %s", c.chain)
+	}
+
+	result, err := c.ethCall(ctx, ENSRegistryAddress, append(append([]byte{}, ensResolverSelector...), node[:]...))
+	if err != nil {
+		return "", fmt.Errorf("failed to get ens resolver: %w", err)
+	}
+
+	resolver := common.BytesToAddress(result)
+	if len(result) < 32 || resolver == (common.Address{}) {
+		return "", ErrENSNotFound
+	}
+
+	return resolver.Hex(), nil
+}
+
+// ethCall 调用合约只读方法，返回原始返回数据
+func (c *EthereumClient) ethCall(ctx context.Context, to string, data []byte) ([]byte, error) {
+	msg := map[string]interface{}{
+		"to":   to,
+		"data": "0x" + common.Bytes2Hex(data),
+	}
+
+	var result string
+	if err := c.rpc.CallContext(ctx, &result, "eth_call", msg, "latest"); err != nil {
+		return nil, err
+	}
+
+	return common.FromHex(result), nil
+}
+
+// ENSNamehash 计算 ENS 名称的 namehash（EIP-137）
+// 名称仅做小写处理，未实现完整的 UTS-46 规范化
+func ENSNamehash(name string) common.Hash {
+	var node common.Hash
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node[:], labelHash))
+	}
+
+	return node
+}
+
+// decodeABIString 解码 ABI 编码的 string 返回值
+func decodeABIString(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	if len(data) < 64 {
+		return "", errors.New("invalid abi string: data too short")
+	}
+
+	// 偏移和长度来自 RPC 响应，先与剩余长度比较再相加，避免恶意值溢出
+	size := uint64(len(data))
+	offset := binary.BigEndian.Uint64(data[24:32])
+	if offset > size-32 {
+		return "", errors.New("invalid abi string: offset out of range")
+	}
+
+	length := binary.BigEndian.Uint64(data[offset+24 : offset+32])
+	start := offset + 32
+	if length > size-start {
+		return "", errors.New("invalid abi string: length out of range")
+	}
+
+	return string(data[start : start+length]), nil
+}
@@ -2,20 +2,32 @@ package web3
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"strings"
+	"sync"
 
+	geth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // EthereumClient Ethereum/BSC 客户端
 type EthereumClient struct {
-	client *ethclient.Client
-	rpc    *rpc.Client
-	chain  Chain
+	client    *ethclient.Client
+	rpc       *rpc.Client
+	chain     Chain
+	rpcURL    string
+	endpoints *EndpointPool // 多节点时非空
+
+	privateKey *ecdsa.PrivateKey // 签名私钥，未设置时不能发送交易
+	keyMu      sync.RWMutex
 }
 
 // NewEthereumClient 创建 Ethereum 客户端
@@ -39,11 +51,57 @@ func newEVMClient(rpcURL string, chain Chain) (*EthereumClient, error) {
 		client: client,
 		rpc:    rpcClient,
 		chain:  chain,
+		rpcURL: rpcURL,
 	}, nil
 }
 
-// GetBalance 获取地址余额
+// NewEthereumClientPool 创建使用多个 RPC 节点的 Ethereum 客户端，节点失败或超时时自动切换
+// 只支持 http/https 节点
+func NewEthereumClientPool(urls []string) (*EthereumClient, error) {
+	return newEVMClientPool(urls, Ethereum)
+}
+
+func newEVMClientPool(urls []string, chain Chain) (*EthereumClient, error) {
+	pool, err := NewEndpointPool(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求发往第一个节点，由节点池在传输层改写为当前活跃节点
+	rpcClient, err := rpc.DialOptions(context.Background(), urls[0], rpc.WithHTTPClient(&http.Client{Transport: pool}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", chain, err)
+	}
+
+	return &EthereumClient{
+		client:    ethclient.NewClient(rpcClient),
+		rpc:       rpcClient,
+		chain:     chain,
+		rpcURL:    urls[0],
+		endpoints: pool,
+	}, nil
+}
+
+// ActiveEndpoint 返回当前使用的 RPC 节点
+func (c *EthereumClient) ActiveEndpoint() string {
+	if c.endpoints != nil {
+		return c.endpoints.Active()
+	}
+	return c.rpcURL
+}
+
+// Endpoints 返回节点池，单节点客户端返回 nil
+func (c *EthereumClient) Endpoints() *EndpointPool {
+	return c.endpoints
+}
+
+// GetBalance 获取地址余额，address 也可以是 ENS 名称
 func (c *EthereumClient) GetBalance(ctx context.Context, address string) (string, error) {
+	address, err := c.resolveAddress(ctx, address)
+	if err != nil {
+		return "", err
+	}
+
 	if err := ValidateAddress(c.chain, address); err != nil {
 		return "", err
 	}
@@ -144,12 +202,139 @@ func (c *EthereumClient) GetTransaction(ctx context.Context, txHash string) (*Tr
 }
 
 // SendTransaction 发送交易
+// 需要先通过 SetPrivateKey 设置签名私钥
 func (c *EthereumClient) SendTransaction(ctx context.Context, tx *TransactionRequest) (string, error) {
-	// Note: This is a placeholder. Actual implementation requires:
-	// 1. Private key for signing
-	// 2. Proper transaction construction
-	// 3. Gas estimation
-	return "", fmt.Errorf("sendTransaction not implemented: requires private key integration")
+	_, raw, err := c.SignTransaction(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	return c.SendRawTransaction(ctx, raw)
+}
+
+// SetPrivateKey 设置签名私钥，私钥无效时保留原私钥并返回错误
+func (c *EthereumClient) SetPrivateKey(privateKeyHex string) error {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	c.privateKey = privateKey
+	return nil
+}
+
+// SignTransaction 使用私钥签名交易，返回交易哈希和 RLP 编码的原始交易
+// Value 和 GasPrice 为 wei（十进制或 0x 十六进制），未指定 GasLimit、GasPrice 或 Nonce 时从节点获取
+func (c *EthereumClient) SignTransaction(ctx context.Context, tx *TransactionRequest) (string, []byte, error) {
+	c.keyMu.RLock()
+	privateKey := c.privateKey
+	c.keyMu.RUnlock()
+	if privateKey == nil {
+		return "", nil, errors.New("private key not configured")
+	}
+
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	if tx.From != "" && !strings.EqualFold(tx.From, from.Hex()) {
+		return "", nil, fmt.Errorf("from address %s does not match private key address %s", tx.From, from.Hex())
+	}
+
+	toAddress, err := c.resolveAddress(ctx, tx.To)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := ValidateAddress(c.chain, toAddress); err != nil {
+		return "", nil, err
+	}
+	to := common.HexToAddress(toAddress)
+
+	value, err := parseWei(tx.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid value: %w", err)
+	}
+	data := common.FromHex(tx.Data)
+
+	gasPrice, err := parseWei(tx.GasPrice)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid gas price: %w", err)
+	}
+	if gasPrice.Sign() == 0 {
+		if gasPrice, err = c.client.SuggestGasPrice(ctx); err != nil {
+			return "", nil, fmt.Errorf("failed to get gas price: %w", err)
+		}
+	}
+
+	gasLimit := tx.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = c.client.EstimateGas(ctx, geth.CallMsg{From: from, To: &to, Value: value, Data: data})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+	}
+
+	nonce := tx.Nonce
+	if nonce == 0 {
+		if nonce, err = c.client.PendingNonceAt(ctx, from); err != nil {
+			return "", nil, fmt.Errorf("failed to get transaction count: %w", err)
+		}
+	}
+
+	chainID, err := c.client.ChainID(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get chain id: %w", err)
+	}
+
+	signed, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gasLimit,
+		To:       &to,
+		Value:    value,
+		Data:     data,
+	}), types.LatestSignerForChainID(chainID), privateKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return "", nil, err
+	}
+	return signed.Hash().Hex(), raw, nil
+}
+
+// SendRawTransaction 广播已签名的原始交易
+func (c *EthereumClient) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return "", fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	if err := c.client.SendTransaction(ctx, &tx); err != nil {
+		return "", err
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// parseWei 解析 wei 数量，只接受十进制或 0x 十六进制，空字符串为 0
+// 不接受符号、下划线以及 0b、0o 等其他进制前缀
+func parseWei(value string) (*big.Int, error) {
+	if value == "" {
+		return new(big.Int), nil
+	}
+
+	digits, base := value, 10
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		digits, base = value[2:], 16
+	}
+	if digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, fmt.Errorf("invalid wei amount %q", value)
+	}
+
+	wei, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("invalid wei amount %q", value)
+	}
+	return wei, nil
 }
 
 // GetChain 获取链类型
@@ -204,8 +389,13 @@ func (c *EthereumClient) EstimateGas(ctx context.Context, from, to, data string,
 	return gas.Uint64(), nil
 }
 
-// GetTransactionCount 获取地址的交易计数（nonce）
+// GetTransactionCount 获取地址的交易计数（nonce），address 也可以是 ENS 名称
 func (c *EthereumClient) GetTransactionCount(ctx context.Context, address string) (uint64, error) {
+	address, err := c.resolveAddress(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+
 	if err := ValidateAddress(c.chain, address); err != nil {
 		return 0, err
 	}
@@ -0,0 +1,33 @@
+package web3
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// validateEthereumAddress 验证 Ethereum/BSC 地址：0x 开头的 40 位十六进制
+// 大小写混合的地址按 EIP-55 校验和验证，全小写或全大写的地址不携带校验信息，跳过校验
+func validateEthereumAddress(address string) error {
+	if len(address) != 42 || address[:2] != "0x" || !common.IsHexAddress(address) {
+		return errors.New("invalid ethereum address format")
+	}
+
+	hex := address[2:]
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+	if common.HexToAddress(address).Hex() != address {
+		return errors.New("invalid ethereum address checksum (EIP-55)")
+	}
+	return nil
+}
+
+// ToChecksumAddress 将 Ethereum/BSC 地址转换为 EIP-55 校验和格式，如 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed
+func ToChecksumAddress(address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", errors.New("invalid ethereum address format")
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
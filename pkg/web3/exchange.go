@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
+	"time"
 )
 
 // Exchange 交易所类型
@@ -26,11 +29,134 @@ type ExchangeClient interface {
 
 	// GetPrice 获取价格
 	GetPrice(ctx context.Context, pair string) (string, error)
+
+	// GetCandles 获取 K 线数据，interval 为 1m/5m/15m/1h/6h/1d 等，结果按时间升序
+	GetCandles(ctx context.Context, pair string, interval string, start, end time.Time) ([]Candle, error)
+
+	// GetTradeHistory 获取 [start, end] 内的成交记录，自动翻页，结果按时间升序
+	GetTradeHistory(ctx context.Context, pair string, start, end time.Time) ([]Trade, error)
+}
+
+// Candle K 线数据
+type Candle struct {
+	Time   time.Time `json:"time"` // 开盘时间
+	Open   string    `json:"open"`
+	High   string    `json:"high"`
+	Low    string    `json:"low"`
+	Close  string    `json:"close"`
+	Volume string    `json:"volume"`
+}
+
+// Trade 成交记录
+type Trade struct {
+	ID    string    `json:"id"`
+	Pair  string    `json:"pair"`
+	Side  string    `json:"side"` // buy 或 sell
+	Price string    `json:"price"`
+	Size  string    `json:"size"`
+	Fee   string    `json:"fee"`
+	Time  time.Time `json:"time"`
+}
+
+// OrderBookLevel 订单簿价位
+type OrderBookLevel struct {
+	Price   string `json:"price"`
+	Size    string `json:"size"`
+	Orders  int    `json:"orders,omitempty"`   // 该价位的挂单数，交易所提供时填充
+	OrderID string `json:"order_id,omitempty"` // 逐笔订单簿（如 Coinbase level 3）中的订单 ID
+}
+
+// OrderBook 订单簿，Bids 按价格从高到低，Asks 按价格从低到高
+type OrderBook struct {
+	Pair     string           `json:"pair"`
+	Sequence string           `json:"sequence,omitempty"` // 交易所的订单簿序号，用于与增量推送对齐
+	Time     time.Time        `json:"time"`
+	Bids     []OrderBookLevel `json:"bids"`
+	Asks     []OrderBookLevel `json:"asks"`
+}
+
+// APIError 交易所接口返回的错误
+type APIError struct {
+	Exchange   Exchange
+	StatusCode int    // HTTP 状态码
+	Message    string // 交易所返回的错误信息
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error: %s", e.Exchange, e.Message)
+}
+
+// Temporary 是否为可重试的暂时错误（超时、限流或服务端错误）
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError
+}
+
+// isPermanentAPIError 判断是否为不可重试的交易所错误，如订单不存在或认证失败
+func isPermanentAPIError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && !apiErr.Temporary()
+}
+
+// sortTrades 按成交时间升序排列成交记录
+func sortTrades(trades []Trade) []Trade {
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].Time.Before(trades[j].Time)
+	})
+	return trades
+}
+
+// sortCandles 按开盘时间升序排列 K 线
+func sortCandles(candles []Candle) []Candle {
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Time.Before(candles[j].Time)
+	})
+	return candles
+}
+
+// fetchCandleWindows 按单次请求最多 limit 根 K 线将 [start, end] 切分为多个窗口依次请求，
+// 合并后按开盘时间去重并升序排列
+func fetchCandleWindows(start, end time.Time, step time.Duration, limit int, fetch func(start, end time.Time) ([]Candle, error)) ([]Candle, error) {
+	span := step * time.Duration(limit-1)
+	seen := make(map[int64]bool)
+	var candles []Candle
+
+	for windowStart := start; !windowStart.After(end); {
+		windowEnd := windowStart.Add(span)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		batch, err := fetch(windowStart, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, candle := range batch {
+			key := candle.Time.Unix()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			candles = append(candles, candle)
+		}
+
+		windowStart = windowEnd.Add(step)
+	}
+
+	return sortCandles(candles), nil
+}
+
+// unsupportedInterval 不支持的 K 线周期错误
+func unsupportedInterval(exchange Exchange, interval string) error {
+	return fmt.Errorf("%s: unsupported candle interval %q", exchange, interval)
 }
 
 // ExchangeManager 交易所管理器
 type ExchangeManager struct {
 	exchanges map[Exchange]ExchangeClient
+	timeouts  map[Exchange]time.Duration
 	mu        sync.RWMutex
 }
 
@@ -68,12 +194,46 @@ func (m *ExchangeManager) GetExchange(exchange Exchange) (ExchangeClient, error)
 	return client, nil
 }
 
+// SetExchangeTimeout 设置交易所调用的默认超时，仅在调用方 context 没有截止时间时生效
+// 未设置时使用 DefaultClientTimeout，timeout <= 0 表示不添加超时
+func (m *ExchangeManager) SetExchangeTimeout(exchange Exchange, timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timeouts == nil {
+		m.timeouts = make(map[Exchange]time.Duration)
+	}
+	m.timeouts[exchange] = timeout
+}
+
+// exchangeContext 调用方 context 没有截止时间时添加该交易所的默认超时
+func (m *ExchangeManager) exchangeContext(ctx context.Context, exchange Exchange) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	m.mu.RLock()
+	timeout, ok := m.timeouts[exchange]
+	m.mu.RUnlock()
+
+	if !ok {
+		timeout = DefaultClientTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // GetBalance 获取余额
 func (m *ExchangeManager) GetBalance(ctx context.Context, exchange Exchange, currency string) (string, error) {
 	client, err := m.GetExchange(exchange)
 	if err != nil {
 		return "", err
 	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
 	return client.GetBalance(ctx, currency)
 }
 
@@ -83,6 +243,9 @@ func (m *ExchangeManager) GetBalances(ctx context.Context, exchange Exchange) (m
 	if err != nil {
 		return nil, err
 	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
 	return client.GetBalances(ctx)
 }
 
@@ -92,9 +255,36 @@ func (m *ExchangeManager) GetPrice(ctx context.Context, exchange Exchange, pair
 	if err != nil {
 		return "", err
 	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
 	return client.GetPrice(ctx, pair)
 }
 
+// GetCandles 获取 K 线数据
+func (m *ExchangeManager) GetCandles(ctx context.Context, exchange Exchange, pair, interval string, start, end time.Time) ([]Candle, error) {
+	client, err := m.GetExchange(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
+	return client.GetCandles(ctx, pair, interval, start, end)
+}
+
+// GetTradeHistory 获取成交记录
+func (m *ExchangeManager) GetTradeHistory(ctx context.Context, exchange Exchange, pair string, start, end time.Time) ([]Trade, error) {
+	client, err := m.GetExchange(exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
+	return client.GetTradeHistory(ctx, pair, start, end)
+}
+
 // GetSupportedExchanges 获取支持的交易所
 func (m *ExchangeManager) GetSupportedExchanges() []Exchange {
 	m.mu.RLock()
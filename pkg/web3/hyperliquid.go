@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -20,6 +21,7 @@ import (
 // HyperliquidClient Hyperliquid 去中心化交易所客户端
 type HyperliquidClient struct {
 	baseURL    string
+	credMu     sync.RWMutex // 保护 privateKey/address，支持运行时轮换
 	privateKey *ecdsa.PrivateKey
 	address    string
 	httpClient *http.Client
@@ -27,27 +29,9 @@ type HyperliquidClient struct {
 
 // NewHyperliquidClient 创建 Hyperliquid 客户端
 func NewHyperliquidClient(privateKeyHex string) (*HyperliquidClient, error) {
-	var privateKey *ecdsa.PrivateKey
-	var address string
-
-	if privateKeyHex != "" {
-		// 移除可能的 0x 前缀
-		privateKeyHex = strings.TrimPrefix(privateKeyHex, "0x")
-
-		// 解析私钥
-		key, err := crypto.HexToECDSA(privateKeyHex)
-		if err != nil {
-			return nil, fmt.Errorf("invalid private key: %w", err)
-		}
-		privateKey = key
-
-		// 从私钥派生地址
-		publicKey := privateKey.Public()
-		publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-		if !ok {
-			return nil, fmt.Errorf("error casting public key to ECDSA")
-		}
-		address = crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
+	privateKey, address, err := parseHyperliquidKey(privateKeyHex)
+	if err != nil {
+		return nil, err
 	}
 
 	return &HyperliquidClient{
@@ -60,9 +44,64 @@ func NewHyperliquidClient(privateKeyHex string) (*HyperliquidClient, error) {
 	}, nil
 }
 
+// parseHyperliquidKey 解析私钥并派生钱包地址，私钥为空时返回 nil
+func parseHyperliquidKey(privateKeyHex string) (*ecdsa.PrivateKey, string, error) {
+	if privateKeyHex == "" {
+		return nil, "", nil
+	}
+
+	// 移除可能的 0x 前缀
+	privateKeyHex = strings.TrimPrefix(privateKeyHex, "0x")
+
+	// 解析私钥
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid private key: %w", err)
+	}
+
+	// 从私钥派生地址
+	publicKey := privateKey.Public()
+	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, "", fmt.Errorf("error casting public key to ECDSA")
+	}
+	return privateKey, crypto.PubkeyToAddress(*publicKeyECDSA).Hex(), nil
+}
+
+// SetPrivateKey 轮换签名私钥，钱包地址随之更新，私钥无效时保留原凭证并返回错误
+func (h *HyperliquidClient) SetPrivateKey(privateKeyHex string) error {
+	privateKey, address, err := parseHyperliquidKey(privateKeyHex)
+	if err != nil {
+		return err
+	}
+
+	h.credMu.Lock()
+	defer h.credMu.Unlock()
+
+	h.privateKey = privateKey
+	h.address = address
+	return nil
+}
+
+// walletAddress 返回当前钱包地址
+func (h *HyperliquidClient) walletAddress() string {
+	h.credMu.RLock()
+	defer h.credMu.RUnlock()
+
+	return h.address
+}
+
+// signingKey 返回当前签名私钥
+func (h *HyperliquidClient) signingKey() *ecdsa.PrivateKey {
+	h.credMu.RLock()
+	defer h.credMu.RUnlock()
+
+	return h.privateKey
+}
+
 // GetBalance 获取余额
 func (h *HyperliquidClient) GetBalance(ctx context.Context, currency string) (string, error) {
-	if h.address == "" {
+	if h.walletAddress() == "" {
 		return "", fmt.Errorf("wallet address not configured")
 	}
 
@@ -98,14 +137,15 @@ func (h *HyperliquidClient) GetBalance(ctx context.Context, currency string) (st
 
 // GetBalances 获取所有余额
 func (h *HyperliquidClient) GetBalances(ctx context.Context) (map[string]string, error) {
-	if h.address == "" {
+	address := h.walletAddress()
+	if address == "" {
 		return nil, fmt.Errorf("wallet address not configured")
 	}
 
 	// 构建请求
 	reqBody := map[string]interface{}{
 		"type": "clearinghouseState",
-		"user": h.address,
+		"user": address,
 	}
 
 	respData, err := h.makeRequest(ctx, "/info", reqBody)
@@ -182,6 +222,136 @@ func (h *HyperliquidClient) GetPrice(ctx context.Context, pair string) (string,
 	return "", fmt.Errorf("price not found for %s", pair)
 }
 
+// hyperliquidIntervals 支持的 K 线周期
+var hyperliquidIntervals = map[string]bool{
+	"1m": true, "3m": true, "5m": true, "15m": true, "30m": true,
+	"1h": true, "2h": true, "4h": true, "8h": true, "12h": true,
+	"1d": true, "3d": true, "1w": true, "1M": true,
+}
+
+// GetCandles 获取 K 线数据（candleSnapshot）
+func (h *HyperliquidClient) GetCandles(ctx context.Context, pair string, interval string, start, end time.Time) ([]Candle, error) {
+	if !hyperliquidIntervals[interval] {
+		return nil, unsupportedInterval(Hyperliquid, interval)
+	}
+
+	coin := strings.Split(pair, "-")[0]
+
+	reqBody := map[string]interface{}{
+		"type": "candleSnapshot",
+		"req": map[string]interface{}{
+			"coin":      coin,
+			"interval":  interval,
+			"startTime": start.UnixMilli(),
+			"endTime":   end.UnixMilli(),
+		},
+	}
+
+	respData, err := h.makeRequest(ctx, "/info", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		T int64  `json:"t"`
+		O string `json:"o"`
+		H string `json:"h"`
+		L string `json:"l"`
+		C string `json:"c"`
+		V string `json:"v"`
+	}
+	if err := json.Unmarshal(respData, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	candles := make([]Candle, 0, len(rows))
+	for _, row := range rows {
+		candles = append(candles, Candle{
+			Time:   time.UnixMilli(row.T).UTC(),
+			Open:   row.O,
+			High:   row.H,
+			Low:    row.L,
+			Close:  row.C,
+			Volume: row.V,
+		})
+	}
+
+	return sortCandles(candles), nil
+}
+
+// hyperliquidFillsLimit userFillsByTime 单次最多返回的成交条数
+const hyperliquidFillsLimit = 2000
+
+// GetTradeHistory 获取成交记录（userFillsByTime），按最后一条成交时间翻页
+func (h *HyperliquidClient) GetTradeHistory(ctx context.Context, pair string, start, end time.Time) ([]Trade, error) {
+	address := h.walletAddress()
+	if address == "" {
+		return nil, fmt.Errorf("wallet address not configured")
+	}
+
+	coin := strings.Split(pair, "-")[0]
+
+	var trades []Trade
+	from := start.UnixMilli()
+	for {
+		reqBody := map[string]interface{}{
+			"type":      "userFillsByTime",
+			"user":      address,
+			"startTime": from,
+			"endTime":   end.UnixMilli(),
+		}
+
+		respData, err := h.makeRequest(ctx, "/info", reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []struct {
+			Coin string `json:"coin"`
+			Px   string `json:"px"`
+			Sz   string `json:"sz"`
+			Side string `json:"side"` // B 为买入，A 为卖出
+			Time int64  `json:"time"`
+			Fee  string `json:"fee"`
+			Tid  int64  `json:"tid"`
+		}
+		if err := json.Unmarshal(respData, &rows); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		last := from
+		for _, row := range rows {
+			if row.Time > last {
+				last = row.Time
+			}
+			if row.Coin != coin {
+				continue
+			}
+
+			side := "sell"
+			if row.Side == "B" {
+				side = "buy"
+			}
+			trades = append(trades, Trade{
+				ID:    strconv.FormatInt(row.Tid, 10),
+				Pair:  pair,
+				Side:  side,
+				Price: row.Px,
+				Size:  row.Sz,
+				Fee:   row.Fee,
+				Time:  time.UnixMilli(row.Time).UTC(),
+			})
+		}
+
+		if len(rows) < hyperliquidFillsLimit {
+			break
+		}
+		from = last + 1
+	}
+
+	return sortTrades(trades), nil
+}
+
 // Position 持仓信息
 type Position struct {
 	Coin          string `json:"coin"`
@@ -195,13 +365,14 @@ type Position struct {
 
 // GetPositions 获取当前持仓
 func (h *HyperliquidClient) GetPositions(ctx context.Context) ([]Position, error) {
-	if h.address == "" {
+	address := h.walletAddress()
+	if address == "" {
 		return nil, fmt.Errorf("wallet address not configured")
 	}
 
 	reqBody := map[string]interface{}{
 		"type": "clearinghouseState",
-		"user": h.address,
+		"user": address,
 	}
 
 	respData, err := h.makeRequest(ctx, "/info", reqBody)
@@ -297,7 +468,7 @@ type OrderRequest struct {
 
 // PlaceOrder 下单（需要私钥）
 func (h *HyperliquidClient) PlaceOrder(ctx context.Context, order OrderRequest) (string, error) {
-	if h.privateKey == nil {
+	if h.signingKey() == nil {
 		return "", fmt.Errorf("private key not configured, cannot place orders")
 	}
 
@@ -381,7 +552,7 @@ func (h *HyperliquidClient) PlaceOrder(ctx context.Context, order OrderRequest)
 
 // CancelOrder 取消订单（需要私钥）
 func (h *HyperliquidClient) CancelOrder(ctx context.Context, coin string, oid int64) error {
-	if h.privateKey == nil {
+	if h.signingKey() == nil {
 		return fmt.Errorf("private key not configured, cannot cancel orders")
 	}
 
@@ -468,8 +639,12 @@ func (h *HyperliquidClient) signAction(action map[string]interface{}) (map[strin
 	}
 
 	// 简化的签名实现（实际应该使用完整的 EIP-712）
+	privateKey := h.signingKey()
+	if privateKey == nil {
+		return nil, fmt.Errorf("private key not configured")
+	}
 	hash := crypto.Keccak256Hash(actionJSON)
-	signature, err := crypto.Sign(hash.Bytes(), h.privateKey)
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign: %w", err)
 	}
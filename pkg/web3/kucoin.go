@@ -10,12 +10,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"time"
 )
 
 // KuCoinClient KuCoin Exchange API 客户端
 type KuCoinClient struct {
+	credMu     sync.RWMutex // 保护 apiKey/apiSecret/passphrase，支持运行时轮换
 	apiKey     string
 	apiSecret  string
 	passphrase string
@@ -121,27 +124,46 @@ func NewKuCoinClient(apiKey, apiSecret, passphrase string) *KuCoinClient {
 	}
 }
 
+// SetCredentials 轮换 API 凭证，之后发出的请求使用新凭证，可在运行中调用
+func (k *KuCoinClient) SetCredentials(apiKey, apiSecret, passphrase string) {
+	k.credMu.Lock()
+	defer k.credMu.Unlock()
+
+	k.apiKey = apiKey
+	k.apiSecret = apiSecret
+	k.passphrase = passphrase
+}
+
+// credentials 返回当前凭证，保证三者属于同一组
+func (k *KuCoinClient) credentials() (apiKey, apiSecret, passphrase string) {
+	k.credMu.RLock()
+	defer k.credMu.RUnlock()
+
+	return k.apiKey, k.apiSecret, k.passphrase
+}
+
 // generateSignature 生成签名
-func (k *KuCoinClient) generateSignature(timestamp, method, endpoint, body string) string {
+func (k *KuCoinClient) generateSignature(apiSecret, timestamp, method, endpoint, body string) string {
 	strToSign := timestamp + method + endpoint + body
-	h := hmac.New(sha256.New, []byte(k.apiSecret))
+	h := hmac.New(sha256.New, []byte(apiSecret))
 	h.Write([]byte(strToSign))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
 // generatePassphrase 生成加密的 passphrase
-func (k *KuCoinClient) generatePassphrase() string {
-	h := hmac.New(sha256.New, []byte(k.apiSecret))
-	h.Write([]byte(k.passphrase))
+func (k *KuCoinClient) generatePassphrase(apiSecret, passphrase string) string {
+	h := hmac.New(sha256.New, []byte(apiSecret))
+	h.Write([]byte(passphrase))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
 
 // request 发送请求
 func (k *KuCoinClient) request(ctx context.Context, method, endpoint string, body string) ([]byte, error) {
 	url := k.baseURL + endpoint
+	apiKey, apiSecret, passphrase := k.credentials()
 	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	signature := k.generateSignature(timestamp, method, endpoint, body)
-	passphrase := k.generatePassphrase()
+	signature := k.generateSignature(apiSecret, timestamp, method, endpoint, body)
+	signedPassphrase := k.generatePassphrase(apiSecret, passphrase)
 
 	var reqBody io.Reader
 	if body != "" {
@@ -154,10 +176,10 @@ func (k *KuCoinClient) request(ctx context.Context, method, endpoint string, bod
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("KC-API-KEY", k.apiKey)
+	req.Header.Set("KC-API-KEY", apiKey)
 	req.Header.Set("KC-API-SIGN", signature)
 	req.Header.Set("KC-API-TIMESTAMP", timestamp)
-	req.Header.Set("KC-API-PASSPHRASE", passphrase)
+	req.Header.Set("KC-API-PASSPHRASE", signedPassphrase)
 	req.Header.Set("KC-API-KEY-VERSION", "2")
 
 	resp, err := k.httpClient.Do(req)
@@ -173,11 +195,14 @@ func (k *KuCoinClient) request(ctx context.Context, method, endpoint string, bod
 
 	var apiResp KuCoinResponse
 	if err := json.Unmarshal(data, &apiResp); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, &APIError{Exchange: KuCoin, StatusCode: resp.StatusCode, Message: string(data)}
+		}
 		return nil, err
 	}
 
 	if apiResp.Code != "200000" {
-		return nil, fmt.Errorf("kucoin API error: %s - %s", apiResp.Code, apiResp.Msg)
+		return nil, &APIError{Exchange: KuCoin, StatusCode: resp.StatusCode, Message: apiResp.Code + " - " + apiResp.Msg}
 	}
 
 	return apiResp.Data, nil
@@ -311,6 +336,40 @@ func (k *KuCoinClient) GetOrder(ctx context.Context, orderID string) (*KuCoinOrd
 	return &order, nil
 }
 
+// GetOrderStatus 实现 OrderStatusGetter 接口
+func (k *KuCoinClient) GetOrderStatus(ctx context.Context, orderID string) (*OrderResult, error) {
+	order, err := k.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 非活跃订单：被取消时 cancelExist 为 true，否则为完全成交
+	status := OrderStatusOpen
+	if !order.IsActive {
+		status = OrderStatusFilled
+		if order.CancelExist {
+			status = OrderStatusCancelled
+		}
+	}
+
+	price := averagePrice(order.DealFunds, order.DealSize)
+	if price == "" {
+		price = order.Price
+	}
+
+	return &OrderResult{
+		Exchange:   KuCoin,
+		OrderID:    order.ID,
+		Pair:       order.Symbol,
+		Side:       order.Side,
+		Status:     status,
+		Size:       order.Size,
+		FilledSize: order.DealSize,
+		Price:      price,
+		Fee:        order.Fee,
+	}, nil
+}
+
 // GetOrders 获取订单列表
 func (k *KuCoinClient) GetOrders(ctx context.Context, status string) ([]KuCoinOrder, error) {
 	endpoint := "/api/v1/orders"
@@ -380,3 +439,206 @@ func (k *KuCoinClient) GetPrice(ctx context.Context, symbol string) (string, err
 	}
 	return ticker.Last, nil
 }
+
+// kucoinCandleTypes K 线周期对应的 KuCoin type 参数及秒数
+var kucoinCandleTypes = map[string]struct {
+	name    string
+	seconds int
+}{
+	"1m":  {"1min", 60},
+	"3m":  {"3min", 180},
+	"5m":  {"5min", 300},
+	"15m": {"15min", 900},
+	"30m": {"30min", 1800},
+	"1h":  {"1hour", 3600},
+	"2h":  {"2hour", 7200},
+	"4h":  {"4hour", 14400},
+	"6h":  {"6hour", 21600},
+	"8h":  {"8hour", 28800},
+	"12h": {"12hour", 43200},
+	"1d":  {"1day", 86400},
+	"1w":  {"1week", 604800},
+}
+
+const (
+	// kucoinFillsWindow 成交记录单次查询的最大时间跨度
+	kucoinFillsWindow = 7 * 24 * time.Hour
+	// kucoinCandlesLimit K 线接口单次最多返回的根数
+	kucoinCandlesLimit = 1500
+	// kucoinFillsPageSize 成交记录每页条数
+	kucoinFillsPageSize = 500
+)
+
+// GetTradeHistory 获取成交记录（/api/v1/fills）
+// 接口单次最多查询 7 天，按 7 天拆分时间范围后逐页获取
+func (k *KuCoinClient) GetTradeHistory(ctx context.Context, symbol string, start, end time.Time) ([]Trade, error) {
+	var trades []Trade
+	for windowStart := start; windowStart.Before(end); {
+		windowEnd := windowStart.Add(kucoinFillsWindow)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		for page := 1; ; page++ {
+			endpoint := fmt.Sprintf("/api/v1/fills?symbol=%s&startAt=%d&endAt=%d&currentPage=%d&pageSize=%d",
+				url.QueryEscape(symbol), windowStart.UnixMilli(), windowEnd.UnixMilli(), page, kucoinFillsPageSize)
+
+			data, err := k.request(ctx, "GET", endpoint, "")
+			if err != nil {
+				return nil, err
+			}
+
+			var resp struct {
+				TotalPage int `json:"totalPage"`
+				Items     []struct {
+					TradeID   string `json:"tradeId"`
+					Symbol    string `json:"symbol"`
+					Side      string `json:"side"`
+					Price     string `json:"price"`
+					Size      string `json:"size"`
+					Fee       string `json:"fee"`
+					CreatedAt int64  `json:"createdAt"`
+				} `json:"items"`
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return nil, err
+			}
+
+			for _, item := range resp.Items {
+				trades = append(trades, Trade{
+					ID:    item.TradeID,
+					Pair:  item.Symbol,
+					Side:  item.Side,
+					Price: item.Price,
+					Size:  item.Size,
+					Fee:   item.Fee,
+					Time:  time.UnixMilli(item.CreatedAt).UTC(),
+				})
+			}
+
+			if page >= resp.TotalPage {
+				break
+			}
+		}
+
+		windowStart = windowEnd
+	}
+
+	return sortTrades(trades), nil
+}
+
+// GetCandles 获取 K 线数据，超过单次上限（1500 根）时按时间窗口分批请求
+func (k *KuCoinClient) GetCandles(ctx context.Context, symbol string, interval string, start, end time.Time) ([]Candle, error) {
+	candleType, ok := kucoinCandleTypes[interval]
+	if !ok {
+		return nil, unsupportedInterval(KuCoin, interval)
+	}
+
+	step := time.Duration(candleType.seconds) * time.Second
+	return fetchCandleWindows(start, end, step, kucoinCandlesLimit, func(start, end time.Time) ([]Candle, error) {
+		return k.getCandles(ctx, symbol, candleType.name, start, end)
+	})
+}
+
+// getCandles 获取单个时间窗口内的 K 线
+func (k *KuCoinClient) getCandles(ctx context.Context, symbol, candleType string, start, end time.Time) ([]Candle, error) {
+	endpoint := fmt.Sprintf("/api/v1/market/candles?type=%s&symbol=%s&startAt=%d&endAt=%d",
+		candleType, symbol, start.Unix(), end.Unix())
+
+	data, err := k.request(ctx, "GET", endpoint, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// 每根 K 线为 [time, open, close, high, low, volume, turnover]
+	var rows [][]string
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	candles := make([]Candle, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 6 {
+			continue
+		}
+		ts, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid candle time %q: %w", row[0], err)
+		}
+		candles = append(candles, Candle{
+			Time:   time.Unix(ts, 0).UTC(),
+			Open:   row[1],
+			Close:  row[2],
+			High:   row[3],
+			Low:    row[4],
+			Volume: row[5],
+		})
+	}
+
+	return candles, nil
+}
+
+// GetOrderBook 获取订单簿，depth 为每侧的价位数
+// depth 为 1-20 和 21-100 时分别使用 level2_20 和 level2_100 部分深度接口并截取前 depth 档，
+// depth <= 0 或大于 100 时使用需要签名的全量深度接口（/api/v3/market/orderbook/level2）
+func (k *KuCoinClient) GetOrderBook(ctx context.Context, symbol string, depth int) (*OrderBook, error) {
+	var endpoint string
+	switch {
+	case depth > 0 && depth <= 20:
+		endpoint = "/api/v1/market/orderbook/level2_20?symbol=" + symbol
+	case depth > 20 && depth <= 100:
+		endpoint = "/api/v1/market/orderbook/level2_100?symbol=" + symbol
+	default:
+		endpoint = "/api/v3/market/orderbook/level2?symbol=" + symbol
+		depth = 0
+	}
+
+	data, err := k.request(ctx, "GET", endpoint, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// 每个价位为 [price, size]
+	var resp struct {
+		Sequence string     `json:"sequence"`
+		Time     int64      `json:"time"`
+		Bids     [][]string `json:"bids"`
+		Asks     [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	bids, err := parseOrderBookLevels(resp.Bids, depth)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parseOrderBookLevels(resp.Asks, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderBook{
+		Pair:     symbol,
+		Sequence: resp.Sequence,
+		Time:     time.UnixMilli(resp.Time).UTC(),
+		Bids:     bids,
+		Asks:     asks,
+	}, nil
+}
+
+// parseOrderBookLevels 解析 [price, size] 价位数组，limit > 0 时只保留前 limit 档
+func parseOrderBookLevels(rows [][]string, limit int) ([]OrderBookLevel, error) {
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	levels := make([]OrderBookLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("invalid order book level %v", row)
+		}
+		levels = append(levels, OrderBookLevel{Price: row[0], Size: row[1]})
+	}
+	return levels, nil
+}
@@ -0,0 +1,216 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MockCall 模拟客户端记录的一次调用
+type MockCall struct {
+	Method string
+	Args   []interface{} // 除 ctx 外的参数
+}
+
+// mockRecorder 记录模拟客户端的调用并按方法注入错误
+type mockRecorder struct {
+	recMu sync.Mutex
+	calls []MockCall
+	errs  map[string]error // 方法名 -> 注入的错误
+}
+
+// Calls 返回按顺序记录的调用
+func (r *mockRecorder) Calls() []MockCall {
+	r.recMu.Lock()
+	defer r.recMu.Unlock()
+	return append([]MockCall(nil), r.calls...)
+}
+
+// CallCount 返回方法被调用的次数
+func (r *mockRecorder) CallCount(method string) int {
+	r.recMu.Lock()
+	defer r.recMu.Unlock()
+
+	count := 0
+	for _, call := range r.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// setError 设置方法注入的错误，err 为 nil 时清除
+func (r *mockRecorder) setError(method string, err error) {
+	r.recMu.Lock()
+	defer r.recMu.Unlock()
+
+	if err == nil {
+		delete(r.errs, method)
+		return
+	}
+	if r.errs == nil {
+		r.errs = make(map[string]error)
+	}
+	r.errs[method] = err
+}
+
+// record 记录调用并返回方法注入的错误
+func (r *mockRecorder) record(method string, args ...interface{}) error {
+	r.recMu.Lock()
+	defer r.recMu.Unlock()
+
+	r.calls = append(r.calls, MockCall{Method: method, Args: args})
+	return r.errs[method]
+}
+
+// MockClient 可编程的链客户端，实现 Client 和 NonceFetcher，
+// 用于测试依赖 Manager 的代码（如 GetWalletInfo、MultiChainAddress.GetAllBalances）而不连接节点。
+// 所有方法并发安全，调用按顺序记录
+// 例如:
+//
+//	client := web3.NewMockClient(web3.Ethereum).
+//		WithBalance("0x742D35CC6634c0532925A3b844BC9E7595F0BEb0", "1.5").
+//		WithNonce("0x742D35CC6634c0532925A3b844BC9E7595F0BEb0", 7)
+//	web3.GetManager().RegisterClient(web3.Ethereum, client)
+type MockClient struct {
+	mu           sync.Mutex
+	chain        Chain
+	balances     map[string]string
+	nonces       map[string]uint64
+	transactions map[string]*Transaction
+	blockNumber  uint64
+	sent         int
+
+	mockRecorder
+}
+
+// NewMockClient 创建指定链的模拟客户端
+func NewMockClient(chain Chain) *MockClient {
+	return &MockClient{
+		chain:        chain,
+		balances:     make(map[string]string),
+		nonces:       make(map[string]uint64),
+		transactions: make(map[string]*Transaction),
+	}
+}
+
+// WithBalance 设置地址余额，未设置的地址余额为 "0"
+func (m *MockClient) WithBalance(address, balance string) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances[address] = balance
+	return m
+}
+
+// WithNonce 设置地址的 nonce（交易数）
+func (m *MockClient) WithNonce(address string, nonce uint64) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonces[address] = nonce
+	return m
+}
+
+// WithTransaction 设置交易，GetTransaction 按 Hash 返回，未设置的交易返回错误
+func (m *MockClient) WithTransaction(tx Transaction) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions[tx.Hash] = &tx
+	return m
+}
+
+// WithBlockNumber 设置最新区块高度
+func (m *MockClient) WithBlockNumber(number uint64) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blockNumber = number
+	return m
+}
+
+// WithError 使方法（如 "GetBalance"）返回 err，err 为 nil 时恢复正常响应
+func (m *MockClient) WithError(method string, err error) *MockClient {
+	m.setError(method, err)
+	return m
+}
+
+// GetBalance 实现 Client 接口
+func (m *MockClient) GetBalance(ctx context.Context, address string) (string, error) {
+	if err := m.record("GetBalance", address); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if balance, ok := m.balances[address]; ok {
+		return balance, nil
+	}
+	return "0", nil
+}
+
+// GetBlockNumber 实现 Client 接口
+func (m *MockClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	if err := m.record("GetBlockNumber"); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.blockNumber, nil
+}
+
+// GetTransaction 实现 Client 接口，返回交易的副本
+func (m *MockClient) GetTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	if err := m.record("GetTransaction", txHash); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx, ok := m.transactions[txHash]
+	if !ok {
+		return nil, fmt.Errorf("transaction %s not found", txHash)
+	}
+	result := *tx
+	return &result, nil
+}
+
+// SendTransaction 实现 Client 接口，生成交易哈希并保存为 pending 交易
+func (m *MockClient) SendTransaction(ctx context.Context, tx *TransactionRequest) (string, error) {
+	if err := m.record("SendTransaction", tx); err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent++
+	hash := fmt.Sprintf("mock-tx-%d", m.sent)
+	m.transactions[hash] = &Transaction{
+		Hash:   hash,
+		From:   tx.From,
+		To:     tx.To,
+		Value:  tx.Value,
+		Status: "pending",
+	}
+	return hash, nil
+}
+
+// GetTransactionCount 实现 NonceFetcher 接口
+func (m *MockClient) GetTransactionCount(ctx context.Context, address string) (uint64, error) {
+	if err := m.record("GetTransactionCount", address); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nonces[address], nil
+}
+
+// GetChain 实现 Client 接口
+func (m *MockClient) GetChain() Chain {
+	return m.chain
+}
+
+// Close 实现 Client 接口
+func (m *MockClient) Close() error {
+	return m.record("Close")
+}
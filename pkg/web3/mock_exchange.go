@@ -0,0 +1,306 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MockExchangeClient 可编程的交易所客户端，实现 ExchangeClient 和 OrderStatusGetter，
+// 并提供订单簿、下单和撤单，用于测试依赖交易所的代码而不访问真实 API。所有方法并发安全，调用按顺序记录
+// 例如:
+//
+//	client := web3.NewMockExchangeClient().
+//		WithBalance("BTC", "0.5").
+//		WithPrice("BTC-USD", "60000").
+//		WithOrderBook(web3.OrderBook{Pair: "BTC-USD", Bids: bids, Asks: asks}).
+//		WithPlaceOrderStatus(web3.OrderStatusFilled).
+//		WithError("GetBalances", errors.New("rate limited"))
+//	manager.RegisterExchange(web3.Coinbase, client)
+type MockExchangeClient struct {
+	mu          sync.Mutex
+	balances    map[string]string
+	prices      map[string]string
+	candles     map[string][]Candle
+	trades      map[string][]Trade
+	orders      map[string]*OrderResult
+	orderBooks  map[string]*OrderBook
+	placeStatus OrderStatus // PlaceOrder 创建的订单状态
+	nextOrderID int
+
+	mockRecorder
+}
+
+// NewMockExchangeClient 创建没有任何数据的模拟交易所客户端
+func NewMockExchangeClient() *MockExchangeClient {
+	return &MockExchangeClient{
+		balances:    make(map[string]string),
+		prices:      make(map[string]string),
+		candles:     make(map[string][]Candle),
+		trades:      make(map[string][]Trade),
+		orders:      make(map[string]*OrderResult),
+		orderBooks:  make(map[string]*OrderBook),
+		placeStatus: OrderStatusOpen,
+	}
+}
+
+// WithBalance 设置币种余额
+func (m *MockExchangeClient) WithBalance(currency, balance string) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances[currency] = balance
+	return m
+}
+
+// WithBalances 替换全部余额
+func (m *MockExchangeClient) WithBalances(balances map[string]string) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances = copyBalances(balances)
+	return m
+}
+
+// WithPrice 设置交易对价格，未设置的交易对 GetPrice 返回错误
+func (m *MockExchangeClient) WithPrice(pair, price string) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prices[pair] = price
+	return m
+}
+
+// WithCandles 设置交易对的 K 线，GetCandles 按时间范围过滤，不区分 interval
+func (m *MockExchangeClient) WithCandles(pair string, candles []Candle) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.candles[pair] = append([]Candle(nil), candles...)
+	return m
+}
+
+// WithTrades 设置交易对的成交记录，GetTradeHistory 按时间范围过滤
+func (m *MockExchangeClient) WithTrades(pair string, trades []Trade) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.trades[pair] = append([]Trade(nil), trades...)
+	return m
+}
+
+// WithOrder 设置订单状态，GetOrderStatus 按 OrderID 返回
+func (m *MockExchangeClient) WithOrder(order OrderResult) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orders[order.OrderID] = &order
+	return m
+}
+
+// WithOrderBook 设置交易对的订单簿，按 book.Pair 保存，GetOrderBook 按 depth 截取每侧价位
+func (m *MockExchangeClient) WithOrderBook(book OrderBook) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orderBooks[book.Pair] = copyOrderBook(&book, 0)
+	return m
+}
+
+// WithPlaceOrderStatus 设置 PlaceOrder 创建的订单状态，默认为 OrderStatusOpen；
+// 设置为 OrderStatusFilled 时订单按下单数量全部成交，可配合 WaitForFill 测试
+func (m *MockExchangeClient) WithPlaceOrderStatus(status OrderStatus) *MockExchangeClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.placeStatus = status
+	return m
+}
+
+// WithError 使方法（如 "GetBalances"）返回 err，err 为 nil 时恢复正常响应
+func (m *MockExchangeClient) WithError(method string, err error) *MockExchangeClient {
+	m.setError(method, err)
+	return m
+}
+
+// GetBalance 实现 ExchangeClient 接口，未设置的币种余额为 "0"
+func (m *MockExchangeClient) GetBalance(ctx context.Context, currency string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetBalance", currency); err != nil {
+		return "", err
+	}
+	if balance, ok := m.balances[currency]; ok {
+		return balance, nil
+	}
+	return "0", nil
+}
+
+// GetBalances 实现 ExchangeClient 接口，返回余额的副本
+func (m *MockExchangeClient) GetBalances(ctx context.Context) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetBalances"); err != nil {
+		return nil, err
+	}
+	return copyBalances(m.balances), nil
+}
+
+// GetPrice 实现 ExchangeClient 接口
+func (m *MockExchangeClient) GetPrice(ctx context.Context, pair string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetPrice", pair); err != nil {
+		return "", err
+	}
+	price, ok := m.prices[pair]
+	if !ok {
+		return "", fmt.Errorf("no price for %s", pair)
+	}
+	return price, nil
+}
+
+// GetCandles 实现 ExchangeClient 接口
+func (m *MockExchangeClient) GetCandles(ctx context.Context, pair string, interval string, start, end time.Time) ([]Candle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetCandles", pair, interval, start, end); err != nil {
+		return nil, err
+	}
+	var candles []Candle
+	for _, candle := range m.candles[pair] {
+		if !candle.Time.Before(start) && !candle.Time.After(end) {
+			candles = append(candles, candle)
+		}
+	}
+	return candles, nil
+}
+
+// GetTradeHistory 实现 ExchangeClient 接口
+func (m *MockExchangeClient) GetTradeHistory(ctx context.Context, pair string, start, end time.Time) ([]Trade, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetTradeHistory", pair, start, end); err != nil {
+		return nil, err
+	}
+	var trades []Trade
+	for _, trade := range m.trades[pair] {
+		if !trade.Time.Before(start) && !trade.Time.After(end) {
+			trades = append(trades, trade)
+		}
+	}
+	return sortTrades(trades), nil
+}
+
+// GetOrderStatus 实现 OrderStatusGetter 接口
+func (m *MockExchangeClient) GetOrderStatus(ctx context.Context, orderID string) (*OrderResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetOrderStatus", orderID); err != nil {
+		return nil, err
+	}
+	order, ok := m.orders[orderID]
+	if !ok {
+		return nil, mockOrderNotFound(orderID)
+	}
+	result := *order
+	return &result, nil
+}
+
+// GetOrderBook 获取订单簿，depth <= 0 时返回全部价位，未设置的交易对返回错误
+func (m *MockExchangeClient) GetOrderBook(ctx context.Context, pair string, depth int) (*OrderBook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("GetOrderBook", pair, depth); err != nil {
+		return nil, err
+	}
+	book, ok := m.orderBooks[pair]
+	if !ok {
+		return nil, fmt.Errorf("no order book for %s", pair)
+	}
+	return copyOrderBook(book, depth), nil
+}
+
+// PlaceOrder 下单，创建 ID 为 mock-order-N 的订单，状态由 WithPlaceOrderStatus 决定，
+// 之后可通过 GetOrderStatus 查询、CancelOrder 撤销
+func (m *MockExchangeClient) PlaceOrder(ctx context.Context, pair, side, orderType, size, price string) (*OrderResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("PlaceOrder", pair, side, orderType, size, price); err != nil {
+		return nil, err
+	}
+
+	m.nextOrderID++
+	order := &OrderResult{
+		Exchange:   "mock",
+		OrderID:    fmt.Sprintf("mock-order-%d", m.nextOrderID),
+		Pair:       pair,
+		Side:       side,
+		Status:     m.placeStatus,
+		Size:       size,
+		FilledSize: "0",
+		Price:      price,
+		Fee:        "0",
+	}
+	if order.Status == OrderStatusFilled {
+		order.FilledSize = size
+		if order.Price == "" {
+			order.Price = m.prices[pair]
+		}
+	}
+	m.orders[order.OrderID] = order
+
+	result := *order
+	return &result, nil
+}
+
+// CancelOrder 撤销未结束的订单，订单不存在或已结束时返回错误
+func (m *MockExchangeClient) CancelOrder(ctx context.Context, orderID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.record("CancelOrder", orderID); err != nil {
+		return err
+	}
+	order, ok := m.orders[orderID]
+	if !ok {
+		return mockOrderNotFound(orderID)
+	}
+	if order.Status.IsFinal() {
+		return fmt.Errorf("order %s is already %s", orderID, order.Status)
+	}
+	order.Status = OrderStatusCancelled
+	return nil
+}
+
+// mockOrderNotFound 与真实交易所一致返回 404，WaitForFill 不会继续轮询
+func mockOrderNotFound(orderID string) error {
+	return &APIError{Exchange: "mock", StatusCode: http.StatusNotFound, Message: fmt.Sprintf("order %s not found", orderID)}
+}
+
+// copyOrderBook 复制订单簿，depth > 0 时每侧最多保留 depth 个价位
+func copyOrderBook(book *OrderBook, depth int) *OrderBook {
+	copied := *book
+	copied.Bids = copyLevels(book.Bids, depth)
+	copied.Asks = copyLevels(book.Asks, depth)
+	return &copied
+}
+
+// copyLevels 复制价位列表，depth > 0 时最多保留 depth 个
+func copyLevels(levels []OrderBookLevel, depth int) []OrderBookLevel {
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return append([]OrderBookLevel(nil), levels...)
+}
+
+// copyBalances 复制余额映射，避免调用方修改内部状态
+func copyBalances(balances map[string]string) map[string]string {
+	copied := make(map[string]string, len(balances))
+	for currency, balance := range balances {
+		copied[currency] = balance
+	}
+	return copied
+}
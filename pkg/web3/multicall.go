@@ -0,0 +1,161 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Multicall3Address Multicall3 合约地址，在主流 EVM 链上地址相同
+const Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+var multicallAggregate3Selector = crypto.Keccak256([]byte("aggregate3((address,bool,bytes)[])"))[:4]
+
+// Call 合约只读调用
+type Call struct {
+	// Target 合约地址
+	Target string
+	// Data 调用数据（方法选择器 + ABI 编码参数）
+	Data []byte
+	// AllowFailure 为 true 时该调用失败不影响其他调用，对应结果为 nil
+	AllowFailure bool
+}
+
+// Multicall 通过 Multicall3 合约在一次 eth_call 中执行多个只读调用
+// 返回结果与 calls 一一对应，为各调用的原始返回数据
+func (c *EthereumClient) Multicall(ctx context.Context, calls []Call) ([][]byte, error) {
+	if len(calls) == 0 {
+		return [][]byte{}, nil
+	}
+
+	for _, call := range calls {
+		if err := ValidateAddress(c.chain, call.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := c.ethCall(ctx, Multicall3Address, encodeAggregate3(calls))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute multicall: %w", err)
+	}
+
+	return decodeAggregate3(result, len(calls))
+}
+
+// encodeAggregate3 ABI 编码 aggregate3((address,bool,bytes)[]) 调用
+func encodeAggregate3(calls []Call) []byte {
+	// 每个元组: target, allowFailure, bytes 偏移, bytes 长度, 数据（按 32 字节对齐）
+	tuples := make([][]byte, len(calls))
+	for i, call := range calls {
+		tuple := make([]byte, 0, 128+padLen(len(call.Data)))
+		tuple = append(tuple, common.LeftPadBytes(common.HexToAddress(call.Target).Bytes(), 32)...)
+		tuple = append(tuple, abiBool(call.AllowFailure)...)
+		tuple = append(tuple, abiUint(96)...)
+		tuple = append(tuple, abiUint(len(call.Data))...)
+		tuple = append(tuple, common.RightPadBytes(call.Data, padLen(len(call.Data)))...)
+		tuples[i] = tuple
+	}
+
+	data := append([]byte{}, multicallAggregate3Selector...)
+	data = append(data, abiUint(32)...)
+	data = append(data, abiUint(len(calls))...)
+
+	// 元组偏移相对于偏移表起始位置
+	offset := 32 * len(calls)
+	for _, tuple := range tuples {
+		data = append(data, abiUint(offset)...)
+		offset += len(tuple)
+	}
+	for _, tuple := range tuples {
+		data = append(data, tuple...)
+	}
+
+	return data
+}
+
+// decodeAggregate3 解码 aggregate3 返回的 (bool success, bytes returnData)[]
+// 失败的调用对应结果为 nil
+func decodeAggregate3(data []byte, expected int) ([][]byte, error) {
+	arrayOffset, err := abiReadUint(data, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := abiReadUint(data, arrayOffset)
+	if err != nil {
+		return nil, err
+	}
+	if count != expected {
+		return nil, fmt.Errorf("multicall returned %d results, expected %d", count, expected)
+	}
+
+	base := arrayOffset + 32
+	results := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		tupleOffset, err := abiReadUint(data, base+32*i)
+		if err != nil {
+			return nil, err
+		}
+		tupleStart := base + tupleOffset
+
+		success, err := abiReadUint(data, tupleStart)
+		if err != nil {
+			return nil, err
+		}
+		if success == 0 {
+			continue
+		}
+
+		bytesOffset, err := abiReadUint(data, tupleStart+32)
+		if err != nil {
+			return nil, err
+		}
+		length, err := abiReadUint(data, tupleStart+bytesOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		start := tupleStart + bytesOffset + 32
+		if start+length > len(data) {
+			return nil, errors.New("invalid multicall result: data out of range")
+		}
+		results[i] = append([]byte{}, data[start:start+length]...)
+	}
+
+	return results, nil
+}
+
+// abiUint ABI 编码无符号整数
+func abiUint(v int) []byte {
+	return common.LeftPadBytes(big.NewInt(int64(v)).Bytes(), 32)
+}
+
+// abiBool ABI 编码布尔值
+func abiBool(v bool) []byte {
+	if v {
+		return abiUint(1)
+	}
+	return abiUint(0)
+}
+
+// abiReadUint 读取 offset 处的 32 字节无符号整数
+func abiReadUint(data []byte, offset int) (int, error) {
+	if offset < 0 || offset+32 > len(data) {
+		return 0, errors.New("invalid abi data: offset out of range")
+	}
+
+	v := new(big.Int).SetBytes(data[offset : offset+32])
+	if !v.IsInt64() || v.Int64() > int64(len(data)) {
+		return 0, errors.New("invalid abi data: value out of range")
+	}
+	return int(v.Int64()), nil
+}
+
+// padLen 计算按 32 字节对齐后的长度
+func padLen(n int) int {
+	return (n + 31) / 32 * 32
+}
@@ -0,0 +1,102 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/web3/decimal"
+)
+
+// OrderStatus 统一的订单状态
+type OrderStatus string
+
+const (
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusFilled    OrderStatus = "filled"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRejected  OrderStatus = "rejected"
+)
+
+// IsFinal 订单是否已结束（成交、取消或被拒绝）
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusFilled || s == OrderStatusCancelled || s == OrderStatusRejected
+}
+
+// OrderResult 统一的订单查询结果
+type OrderResult struct {
+	Exchange   Exchange    `json:"exchange"`
+	OrderID    string      `json:"order_id"`
+	Pair       string      `json:"pair"`
+	Side       string      `json:"side"`
+	Status     OrderStatus `json:"status"`
+	Size       string      `json:"size"`
+	FilledSize string      `json:"filled_size"`
+	Price      string      `json:"price"` // 成交均价，未成交时为下单价格
+	Fee        string      `json:"fee"`
+}
+
+// OrderStatusGetter 支持查询订单状态的交易所客户端
+type OrderStatusGetter interface {
+	GetOrderStatus(ctx context.Context, orderID string) (*OrderResult, error)
+}
+
+// WaitForFill 轮询订单直到成交、取消或被拒绝，返回最终状态
+// 超时、限流等暂时错误继续轮询，订单不存在、认证失败等错误立即返回；
+// ctx 结束时返回最近一次查询到的状态，错误中包含最近一次查询失败的原因
+func (m *ExchangeManager) WaitForFill(ctx context.Context, exchange Exchange, orderID string, poll time.Duration) (*OrderResult, error) {
+	client, err := m.GetExchange(exchange)
+	if err != nil {
+		return nil, err
+	}
+	getter, ok := client.(OrderStatusGetter)
+	if !ok {
+		return nil, fmt.Errorf("exchange %s does not support order status queries", exchange)
+	}
+	if poll <= 0 {
+		poll = time.Second
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	var last *OrderResult
+	var lastErr error
+	for {
+		callCtx, cancel := m.exchangeContext(ctx, exchange)
+		result, err := getter.GetOrderStatus(callCtx, orderID)
+		cancel()
+		if err == nil {
+			last, lastErr = result, nil
+			if result.Status.IsFinal() {
+				return result, nil
+			}
+		} else if isPermanentAPIError(err) {
+			return last, fmt.Errorf("failed to get order %s: %w", orderID, err)
+		} else {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return last, fmt.Errorf("stopped waiting for order %s: %w (last error: %w)", orderID, ctx.Err(), lastErr)
+			}
+			return last, fmt.Errorf("stopped waiting for order %s: %w", orderID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// averagePrice 根据成交金额和成交数量计算成交均价，无法计算时返回空字符串
+func averagePrice(funds, size string) string {
+	f, err := decimal.ParseAmount(funds)
+	if err != nil {
+		return ""
+	}
+	s, err := decimal.ParseAmount(size)
+	if err != nil || s.IsZero() {
+		return ""
+	}
+	return f.Quo(s).String()
+}
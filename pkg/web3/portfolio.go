@@ -0,0 +1,346 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/clarkgo/clarkgo/pkg/web3/decimal"
+)
+
+// portfolioConcurrency 估值时并发查询价格的最大数量
+const portfolioConcurrency = 8
+
+// ValuedBalance 按计价币种估值后的余额
+type ValuedBalance struct {
+	Asset    string `json:"asset"`
+	Balance  string `json:"balance"`
+	Price    string `json:"price"`
+	Value    string `json:"value"`
+	NoMarket bool   `json:"no_market,omitempty"` // 没有对应 quote 的交易对，估值按 0 计
+	Error    string `json:"error,omitempty"`
+}
+
+// GetPortfolioValue 获取交易所全部持仓按 quote（如 USD、USDT）计价的估值及总额
+// 交易对由 NormalizeSymbol 按交易所格式构造，价格查询并发执行；无法获取价格的资产估值为 0 并标记 NoMarket
+func (m *ExchangeManager) GetPortfolioValue(ctx context.Context, exchange Exchange, quote string) (map[string]ValuedBalance, string, error) {
+	client, err := m.GetExchange(exchange)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := m.exchangeContext(ctx, exchange)
+	defer cancel()
+
+	balances, err := client.GetBalances(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		results = make(map[string]ValuedBalance, len(balances))
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, portfolioConcurrency)
+	)
+
+	for asset, balance := range balances {
+		amount, err := decimal.ParseAmount(balance)
+		if err != nil || amount.IsZero() || strings.EqualFold(asset, quote) {
+			// 零余额、无法解析的余额或计价币种本身无需查询价格
+			valued := ValuedBalance{Asset: asset, Balance: balance, Price: "1", Value: balance}
+			if err != nil {
+				valued.Price, valued.Value, valued.NoMarket = "0", "0", true
+			} else if amount.IsZero() {
+				valued.Value = "0"
+			}
+			mu.Lock()
+			results[asset] = valued
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(asset, balance string, amount decimal.Amount) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			valued := ValuedBalance{Asset: asset, Balance: balance, Price: "0", Value: "0"}
+
+			price, err := client.GetPrice(ctx, NormalizeSymbol(exchange, asset, quote))
+			if err != nil {
+				valued.NoMarket = true
+				valued.Error = err.Error()
+			} else if p, err := decimal.ParseAmount(price); err == nil {
+				valued.Price = price
+				valued.Value = amount.Mul(p).String()
+			} else {
+				valued.NoMarket = true
+			}
+
+			mu.Lock()
+			results[asset] = valued
+			mu.Unlock()
+		}(asset, balance, amount)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, "", err
+	}
+
+	total := decimal.Zero
+	for _, valued := range results {
+		if value, err := decimal.ParseAmount(valued.Value); err == nil {
+			total = total.Add(value)
+		}
+	}
+
+	return results, total.String(), nil
+}
+
+// nativeAsset 链原生资产及 GetBalance 返回值的小数位数
+type nativeAsset struct {
+	Symbol   string
+	Decimals int
+}
+
+// chainNativeAssets 各链原生资产，Bitcoin 的 GetBalance 已以 BTC 为单位
+var chainNativeAssets = map[Chain]nativeAsset{
+	Bitcoin:  {Symbol: "BTC", Decimals: 0},
+	Ethereum: {Symbol: "ETH", Decimals: 18},
+	BSC:      {Symbol: "BNB", Decimals: 18},
+	Solana:   {Symbol: "SOL", Decimals: 9},
+}
+
+// 净值来源类型
+const (
+	SourceChain    = "chain"
+	SourceExchange = "exchange"
+)
+
+// NetWorthItem 净值明细，每个来源的每种资产一条
+type NetWorthItem struct {
+	Source  string `json:"source"` // 链或交易所名称
+	Type    string `json:"type"`   // chain 或 exchange
+	Asset   string `json:"asset"`
+	Balance string `json:"balance"`
+	Price   string `json:"price"`
+	Value   string `json:"value"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NetWorth 链上钱包与交易所资产按 quote 计价的净值
+type NetWorth struct {
+	Quote   string         `json:"quote"`
+	Total   string         `json:"total"`
+	Items   []NetWorthItem `json:"items"`
+	Partial bool           `json:"partial"` // 部分来源查询失败或资产无法估值
+}
+
+// GetNetWorth 并发查询 addresses 中已注册客户端的链上余额和所有交易所的余额，按 quote 计价汇总
+// 价格取所有交易所对应交易对报价的中位数；查询失败的来源或无法估值的资产记录错误并计为 0，
+// 只有没有任何可查询的来源时返回错误
+func GetNetWorth(ctx context.Context, addresses MultiChainAddress, quote string) (NetWorth, error) {
+	return getNetWorth(ctx, GetManager(), GetExchangeManager(), addresses, quote)
+}
+
+// getNetWorth GetNetWorth 的实现，便于注入管理器
+func getNetWorth(ctx context.Context, chains *Manager, exchanges *ExchangeManager, addresses MultiChainAddress, quote string) (NetWorth, error) {
+	quote = strings.ToUpper(quote)
+	result := NetWorth{Quote: quote, Total: "0", Items: []NetWorthItem{}}
+
+	var (
+		items   []NetWorthItem
+		sources int
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+	)
+	collect := func(collected ...NetWorthItem) {
+		mu.Lock()
+		items = append(items, collected...)
+		mu.Unlock()
+	}
+
+	walletAddresses := map[Chain]string{
+		Bitcoin:  addresses.Bitcoin,
+		Ethereum: addresses.Ethereum,
+		BSC:      addresses.BSC,
+		Solana:   addresses.Solana,
+	}
+	for chain, address := range walletAddresses {
+		client, err := chains.GetClient(chain)
+		if address == "" || err != nil {
+			continue
+		}
+
+		sources++
+		wg.Add(1)
+		go func(chain Chain, client Client, address string) {
+			defer wg.Done()
+
+			native := chainNativeAssets[chain]
+			item := NetWorthItem{Source: string(chain), Type: SourceChain, Asset: native.Symbol, Balance: "0"}
+
+			callCtx, cancel := chains.clientContext(ctx, chain)
+			defer cancel()
+
+			balance, err := client.GetBalance(callCtx, address)
+			if err != nil {
+				item.Error = err.Error()
+			} else if amount, err := decimal.ParseAmount(balance); err == nil {
+				item.Balance = amount.Shift(-native.Decimals).String()
+			} else {
+				item.Error = "invalid balance: " + balance
+			}
+			collect(item)
+		}(chain, client, address)
+	}
+
+	exchangeClients := make(map[Exchange]ExchangeClient)
+	for _, exchange := range exchanges.GetSupportedExchanges() {
+		if client, err := exchanges.GetExchange(exchange); err == nil {
+			exchangeClients[exchange] = client
+		}
+	}
+	for exchange, client := range exchangeClients {
+		sources++
+		wg.Add(1)
+		go func(exchange Exchange, client ExchangeClient) {
+			defer wg.Done()
+
+			callCtx, cancel := exchanges.exchangeContext(ctx, exchange)
+			defer cancel()
+
+			balances, err := client.GetBalances(callCtx)
+			if err != nil {
+				collect(NetWorthItem{Source: string(exchange), Type: SourceExchange, Balance: "0", Error: err.Error()})
+				return
+			}
+
+			var exchangeItems []NetWorthItem
+			for asset, balance := range balances {
+				if amount, err := decimal.ParseAmount(balance); err == nil && amount.IsZero() {
+					continue
+				}
+				exchangeItems = append(exchangeItems, NetWorthItem{
+					Source:  string(exchange),
+					Type:    SourceExchange,
+					Asset:   strings.ToUpper(asset),
+					Balance: balance,
+				})
+			}
+			collect(exchangeItems...)
+		}(exchange, client)
+	}
+
+	wg.Wait()
+
+	if sources == 0 {
+		return result, errors.New("no chain clients or exchanges available")
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	prices := aggregatePrices(ctx, exchanges, exchangeClients, items, quote)
+
+	total := decimal.Zero
+	for i := range items {
+		item := &items[i]
+		item.Price, item.Value = "0", "0"
+		if item.Error != "" {
+			result.Partial = true
+			continue
+		}
+
+		amount, err := decimal.ParseAmount(item.Balance)
+		if err != nil {
+			item.Error = "invalid balance: " + item.Balance
+			result.Partial = true
+			continue
+		}
+
+		price, ok := prices[item.Asset]
+		if !ok {
+			item.Error = "no market for " + item.Asset + "-" + quote
+			result.Partial = true
+			continue
+		}
+
+		value := amount.Mul(price)
+		item.Price = price.String()
+		item.Value = value.String()
+		total = total.Add(value)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		if items[i].Source != items[j].Source {
+			return items[i].Source < items[j].Source
+		}
+		return items[i].Asset < items[j].Asset
+	})
+
+	result.Items = items
+	result.Total = total.String()
+	return result, nil
+}
+
+// aggregatePrices 并发查询 items 中各资产在所有交易所的报价，取中位数
+// 资产与 quote 相同时价格为 1；没有任何报价的资产不出现在结果中
+func aggregatePrices(ctx context.Context, manager *ExchangeManager, exchanges map[Exchange]ExchangeClient, items []NetWorthItem, quote string) map[string]decimal.Amount {
+	prices := map[string]decimal.Amount{quote: decimal.NewFromInt(1)}
+	quotes := make(map[string][]decimal.Amount)
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, portfolioConcurrency)
+	)
+
+	queried := make(map[string]bool)
+	for _, item := range items {
+		if item.Error != "" || item.Asset == quote || queried[item.Asset] {
+			continue
+		}
+		queried[item.Asset] = true
+
+		for exchange, client := range exchanges {
+			wg.Add(1)
+			go func(asset string, exchange Exchange, client ExchangeClient) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				callCtx, cancel := manager.exchangeContext(ctx, exchange)
+				defer cancel()
+
+				price, err := client.GetPrice(callCtx, NormalizeSymbol(exchange, asset, quote))
+				if err != nil {
+					return
+				}
+				if p, err := decimal.ParseAmount(price); err == nil && p.Sign() > 0 {
+					mu.Lock()
+					quotes[asset] = append(quotes[asset], p)
+					mu.Unlock()
+				}
+			}(item.Asset, exchange, client)
+		}
+	}
+
+	wg.Wait()
+
+	for asset, assetQuotes := range quotes {
+		prices[asset] = decimal.Median(assetQuotes)
+	}
+	return prices
+}
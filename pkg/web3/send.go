@@ -0,0 +1,157 @@
+package web3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// RawTransactionSender 支持先签名再广播的客户端
+// 签名后即可得到交易哈希，重试时据此判断交易是否已进入内存池，避免重复广播
+type RawTransactionSender interface {
+	// SignTransaction 签名交易，返回交易哈希和签名后的原始交易
+	SignTransaction(ctx context.Context, tx *TransactionRequest) (string, []byte, error)
+
+	// SendRawTransaction 广播已签名的交易
+	SendRawTransaction(ctx context.Context, raw []byte) (string, error)
+}
+
+// NonceFetcher 支持查询账户 nonce 的客户端
+type NonceFetcher interface {
+	GetTransactionCount(ctx context.Context, address string) (uint64, error)
+}
+
+// sendRetryBackoff 重试间隔基数，第 n 次重试等待 n 倍
+var sendRetryBackoff = 500 * time.Millisecond
+
+// SendTransactionWithRetry 发送交易，RPC 超时或 nonce 过低时重试
+//
+// 客户端实现 RawTransactionSender 时，每次广播前先签名得到交易哈希：
+// 超时后重试前会查询该哈希，若节点已知该交易则直接返回，不会重复广播；
+// 节点返回 "already known" 时同样视为成功。nonce 过低时先查询已签名交易的哈希，
+// 节点已知该交易（之前超时的广播已上链）则直接返回，否则通过 NonceFetcher 重新获取 nonce 并重新签名。
+//
+// 客户端未实现 RawTransactionSender 时无法得知之前的广播是否已上链，不做任何重试。
+// EthereumClient 在通过 SetPrivateKey 设置私钥后实现本地签名。
+func (m *Manager) SendTransactionWithRetry(ctx context.Context, chain Chain, tx *TransactionRequest, maxAttempts int) (string, error) {
+	client, err := m.GetClient(chain)
+	if err != nil {
+		return "", err
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	request := *tx
+	rawSender, canSignRaw := client.(RawTransactionSender)
+
+	// 已签名交易的哈希和原始数据，nonce 不变时重试复用同一笔签名交易
+	var signedHash string
+	var signedRaw []byte
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", fmt.Errorf("send transaction cancelled after %d attempts: %w", attempt-1, lastErr)
+			case <-time.After(time.Duration(attempt-1) * sendRetryBackoff):
+			}
+		}
+
+		var hash string
+		if canSignRaw {
+			if signedHash != "" && isTransactionKnown(ctx, client, signedHash) {
+				return signedHash, nil
+			}
+
+			if signedHash == "" {
+				signedHash, signedRaw, err = rawSender.SignTransaction(ctx, &request)
+				if err != nil {
+					return "", fmt.Errorf("failed to sign transaction: %w", err)
+				}
+			}
+
+			hash, err = rawSender.SendRawTransaction(ctx, signedRaw)
+			if err != nil && isAlreadyKnownError(err) {
+				return signedHash, nil
+			}
+		} else {
+			hash, err = client.SendTransaction(ctx, &request)
+		}
+
+		if err == nil {
+			return hash, nil
+		}
+		lastErr = err
+
+		switch {
+		case canSignRaw && isNonceTooLowError(err):
+			// 之前超时的广播可能已上链，导致同一笔交易 nonce 过低
+			if isTransactionKnown(ctx, client, signedHash) {
+				return signedHash, nil
+			}
+			nonce, nonceErr := refetchNonce(ctx, client, request.From)
+			if nonceErr != nil {
+				return "", fmt.Errorf("%w (failed to refetch nonce: %v)", err, nonceErr)
+			}
+			request.Nonce = nonce
+			signedHash, signedRaw = "", nil
+		case canSignRaw && isTransientRPCError(ctx, err):
+			// 保留已签名交易，下一次尝试前先确认是否已被节点接收
+		default:
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("send transaction failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// refetchNonce 重新获取账户 nonce
+func refetchNonce(ctx context.Context, client Client, address string) (uint64, error) {
+	fetcher, ok := client.(NonceFetcher)
+	if !ok {
+		return 0, fmt.Errorf("client for chain %s cannot fetch nonce", client.GetChain())
+	}
+	return fetcher.GetTransactionCount(ctx, address)
+}
+
+// isTransactionKnown 检查交易是否已被节点接收（内存池或已上链）
+func isTransactionKnown(ctx context.Context, client Client, hash string) bool {
+	tx, err := client.GetTransaction(ctx, hash)
+	return err == nil && tx != nil
+}
+
+// isTransientRPCError 判断是否为可重试的 RPC 超时错误，调用方 context 结束时不重试
+func isTransientRPCError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out")
+}
+
+// isNonceTooLowError 判断是否为 nonce 过低错误
+func isNonceTooLowError(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isAlreadyKnownError 判断是否为交易已存在错误
+func isAlreadyKnownError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "known transaction") ||
+		strings.Contains(msg, "already been processed")
+}
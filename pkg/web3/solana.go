@@ -14,6 +14,7 @@ import (
 type SolanaClient struct {
 	rpcURL     string
 	httpClient *http.Client
+	endpoints  *EndpointPool // 多节点时非空
 }
 
 // SolanaRPCRequest Solana RPC 请求
@@ -42,6 +43,32 @@ func NewSolanaClient(rpcURL string) *SolanaClient {
 	}
 }
 
+// NewSolanaClientPool 创建使用多个 RPC 节点的 Solana 客户端，节点失败或超时时自动切换
+func NewSolanaClientPool(urls []string) (*SolanaClient, error) {
+	pool, err := NewEndpointPool(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewSolanaClient(urls[0])
+	client.httpClient.Transport = pool
+	client.endpoints = pool
+	return client, nil
+}
+
+// ActiveEndpoint 返回当前使用的 RPC 节点
+func (c *SolanaClient) ActiveEndpoint() string {
+	if c.endpoints != nil {
+		return c.endpoints.Active()
+	}
+	return c.rpcURL
+}
+
+// Endpoints 返回节点池，单节点客户端返回 nil
+func (c *SolanaClient) Endpoints() *EndpointPool {
+	return c.endpoints
+}
+
 // call RPC 调用
 func (c *SolanaClient) call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
 	req := SolanaRPCRequest{
@@ -310,6 +337,70 @@ func (c *SolanaClient) GetAccountInfo(ctx context.Context, address string) (map[
 	return info, nil
 }
 
+// AccountInfo Solana 账户信息
+type AccountInfo struct {
+	Address    string          `json:"address"`
+	Lamports   uint64          `json:"lamports"`
+	Owner      string          `json:"owner"`
+	Executable bool            `json:"executable"`
+	RentEpoch  uint64          `json:"rentEpoch"`
+	Space      uint64          `json:"space"`
+	Data       json.RawMessage `json:"data"` // jsonParsed 编码的账户数据，无法解析的程序账户为 [base64, "base64"]
+}
+
+// solanaMaxMultipleAccounts getMultipleAccounts 单次请求的最大账户数
+const solanaMaxMultipleAccounts = 100
+
+// GetMultipleAccounts 批量获取账户信息，结果与 addresses 顺序一致，不存在的账户为 nil
+// 超过单次请求上限时自动分批
+func (c *SolanaClient) GetMultipleAccounts(ctx context.Context, addresses []string) ([]*AccountInfo, error) {
+	for _, address := range addresses {
+		if err := ValidateAddress(Solana, address); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts := make([]*AccountInfo, 0, len(addresses))
+	for start := 0; start < len(addresses); start += solanaMaxMultipleAccounts {
+		end := start + solanaMaxMultipleAccounts
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+		batch := addresses[start:end]
+
+		params := []interface{}{
+			batch,
+			map[string]interface{}{
+				"encoding": "jsonParsed",
+			},
+		}
+
+		result, err := c.call(ctx, "getMultipleAccounts", params)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			Value []*AccountInfo `json:"value"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse accounts: %w", err)
+		}
+		if len(resp.Value) != len(batch) {
+			return nil, fmt.Errorf("expected %d accounts, got %d", len(batch), len(resp.Value))
+		}
+
+		for i, account := range resp.Value {
+			if account != nil {
+				account.Address = batch[i]
+			}
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
+
 // GetTokenBalance 获取 SPL Token 余额
 func (c *SolanaClient) GetTokenBalance(ctx context.Context, tokenAccount string) (string, error) {
 	result, err := c.call(ctx, "getTokenAccountBalance", []interface{}{tokenAccount})
@@ -330,3 +421,106 @@ func (c *SolanaClient) GetTokenBalance(ctx context.Context, tokenAccount string)
 
 	return resp.Value.UIAmountString, nil
 }
+
+// SignatureInfo 地址相关交易签名信息
+type SignatureInfo struct {
+	Signature          string `json:"signature"`
+	Slot               uint64 `json:"slot"`
+	BlockTime          int64  `json:"blockTime"`
+	ConfirmationStatus string `json:"confirmationStatus"`
+	Memo               string `json:"memo"`
+	Success            bool   `json:"success"`
+	Error              string `json:"error,omitempty"`
+}
+
+// GetSignaturesForAddress 获取地址的交易签名历史（按时间倒序）
+// limit 为 0 时使用节点默认值（最大 1000），before 为分页游标，传入上一页最后一个签名
+func (c *SolanaClient) GetSignaturesForAddress(ctx context.Context, address string, limit int, before string) ([]SignatureInfo, error) {
+	if err := ValidateAddress(Solana, address); err != nil {
+		return nil, err
+	}
+
+	options := map[string]interface{}{}
+	if limit > 0 {
+		options["limit"] = limit
+	}
+	if before != "" {
+		options["before"] = before
+	}
+
+	result, err := c.call(ctx, "getSignaturesForAddress", []interface{}{address, options})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []struct {
+		Signature          string          `json:"signature"`
+		Slot               uint64          `json:"slot"`
+		BlockTime          *int64          `json:"blockTime"`
+		ConfirmationStatus string          `json:"confirmationStatus"`
+		Memo               *string         `json:"memo"`
+		Err                json.RawMessage `json:"err"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse signatures: %w", err)
+	}
+
+	signatures := make([]SignatureInfo, 0, len(resp))
+	for _, item := range resp {
+		info := SignatureInfo{
+			Signature:          item.Signature,
+			Slot:               item.Slot,
+			ConfirmationStatus: item.ConfirmationStatus,
+			Success:            len(item.Err) == 0 || string(item.Err) == "null",
+		}
+		if item.BlockTime != nil {
+			info.BlockTime = *item.BlockTime
+		}
+		if item.Memo != nil {
+			info.Memo = *item.Memo
+		}
+		if !info.Success {
+			info.Error = string(item.Err)
+		}
+		signatures = append(signatures, info)
+	}
+
+	return signatures, nil
+}
+
+// Solana 交易确认级别
+const (
+	CommitmentProcessed = "processed"
+	CommitmentConfirmed = "confirmed"
+	CommitmentFinalized = "finalized"
+)
+
+// GetSignatureStatus 获取交易签名的确认级别（processed/confirmed/finalized），节点未知该交易时返回空字符串
+func (c *SolanaClient) GetSignatureStatus(ctx context.Context, signature string) (string, error) {
+	params := []interface{}{
+		[]string{signature},
+		map[string]interface{}{
+			"searchTransactionHistory": true,
+		},
+	}
+
+	result, err := c.call(ctx, "getSignatureStatuses", params)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Value []*struct {
+			Slot               uint64 `json:"slot"`
+			ConfirmationStatus string `json:"confirmationStatus"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return "", fmt.Errorf("failed to parse signature status: %w", err)
+	}
+
+	if len(resp.Value) == 0 || resp.Value[0] == nil {
+		return "", nil
+	}
+	return resp.Value[0].ConfirmationStatus, nil
+}
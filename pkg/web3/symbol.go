@@ -0,0 +1,58 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// hyperliquidQuote Hyperliquid 永续合约的计价币种，交易对只使用基础币种名称
+const hyperliquidQuote = "USD"
+
+// knownQuotes 解析无分隔符交易对（如 BTCUSDT）时识别的计价币种，较长的在前
+var knownQuotes = []string{"USDT", "USDC", "BUSD", "DAI", "USD", "EUR", "GBP", "BTC", "ETH"}
+
+// NormalizeSymbol 将基础币种和计价币种转换为交易所的交易对格式
+// Coinbase、KuCoin 为 BTC-USD 形式，Hyperliquid 只使用基础币种 BTC，未知交易所使用 BTC-USD 形式
+func NormalizeSymbol(exchange Exchange, base, quote string) string {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+
+	switch exchange {
+	case Hyperliquid:
+		return base
+	default:
+		return base + "-" + quote
+	}
+}
+
+// ParseSymbol 将交易所的交易对解析为基础币种和计价币种，是 NormalizeSymbol 的逆操作
+// 支持 -、/、_ 分隔符以及 BTCUSDT 这类无分隔符格式，Hyperliquid 的计价币种固定为 USD
+func ParseSymbol(exchange Exchange, symbol string) (base, quote string, err error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", "", fmt.Errorf("%s: empty symbol", exchange)
+	}
+
+	if exchange == Hyperliquid && !strings.ContainsAny(symbol, "-/_") {
+		return symbol, hyperliquidQuote, nil
+	}
+
+	for _, sep := range []string{"-", "/", "_"} {
+		if parts := strings.Split(symbol, sep); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			return parts[0], parts[1], nil
+		}
+	}
+
+	for _, q := range knownQuotes {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return strings.TrimSuffix(symbol, q), q, nil
+		}
+	}
+	return "", "", fmt.Errorf("%s: cannot parse symbol %q", exchange, symbol)
+}
+
+// GetPairPrice 按基础币种和计价币种获取价格，自动转换为交易所的交易对格式
+func (m *ExchangeManager) GetPairPrice(ctx context.Context, exchange Exchange, base, quote string) (string, error) {
+	return m.GetPrice(ctx, exchange, NormalizeSymbol(exchange, base, quote))
+}
@@ -144,6 +144,7 @@ func (m *MultiChainAddress) GetAllBalances(ctx context.Context) (map[Chain]strin
 // WalletInfo 钱包信息
 type WalletInfo struct {
 	Address string                 `json:"address"`
+	Label   string                 `json:"label,omitempty"`
 	Chain   Chain                  `json:"chain"`
 	Balance string                 `json:"balance"`
 	Nonce   uint64                 `json:"nonce,omitempty"`
@@ -166,6 +167,9 @@ func GetWalletInfo(ctx context.Context, chain Chain, address string) (*WalletInf
 		Balance: balance,
 		Extra:   make(map[string]interface{}),
 	}
+	if label, ok := GetAddressBook().Label(chain, address); ok {
+		info.Label = label
+	}
 
 	// Get additional info based on chain
 	client, err := manager.GetClient(chain)
@@ -175,8 +179,8 @@ func GetWalletInfo(ctx context.Context, chain Chain, address string) (*WalletInf
 
 	switch chain {
 	case Ethereum, BSC:
-		if ethClient, ok := client.(*EthereumClient); ok {
-			if nonce, err := ethClient.GetTransactionCount(ctx, address); err == nil {
+		if fetcher, ok := client.(NonceFetcher); ok {
+			if nonce, err := fetcher.GetTransactionCount(ctx, address); err == nil {
 				info.Nonce = nonce
 				info.TxCount = int(nonce)
 			}
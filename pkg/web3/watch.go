@@ -0,0 +1,208 @@
+package web3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/web3/decimal"
+)
+
+// SignatureStatusFetcher 通过确认级别判断交易是否确认的客户端（Solana）
+type SignatureStatusFetcher interface {
+	GetSignatureStatus(ctx context.Context, signature string) (string, error)
+}
+
+// watchPollInterval 交易确认轮询间隔
+var watchPollInterval = 5 * time.Second
+
+// WatchTransaction 轮询交易直到达到 confirmations 个确认，然后调用 onConfirm
+// 阻塞直到交易确认或 ctx 结束，通常在单独的 goroutine 中调用
+//
+// EVM 链和 Bitcoin 的确认数为最新区块高度减去交易所在区块高度加一；
+// Solana 使用确认级别：confirmations <= 1 时要求 confirmed，否则要求 finalized。
+// 交易尚未被节点发现或仍在内存池时继续轮询。
+func (m *Manager) WatchTransaction(ctx context.Context, chain Chain, txHash string, confirmations int, onConfirm func(*Transaction)) error {
+	client, err := m.GetClient(chain)
+	if err != nil {
+		return err
+	}
+	if confirmations <= 0 {
+		confirmations = 1
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		tx, err := checkConfirmed(ctx, client, txHash, confirmations)
+		if err != nil {
+			return err
+		}
+		if tx != nil {
+			if onConfirm != nil {
+				onConfirm(tx)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped watching transaction %s: %w", txHash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkConfirmed 检查交易是否已达到确认数，未确认时返回 nil
+// 交易查询和区块高度查询的错误视为暂时错误，只有调用方 ctx 结束时返回错误
+func checkConfirmed(ctx context.Context, client Client, txHash string, confirmations int) (*Transaction, error) {
+	if statusFetcher, ok := client.(SignatureStatusFetcher); ok {
+		status, err := statusFetcher.GetSignatureStatus(ctx, txHash)
+		if err != nil || !commitmentReached(status, confirmations) {
+			return nil, ctx.Err()
+		}
+	}
+
+	tx, err := client.GetTransaction(ctx, txHash)
+	if err != nil || tx == nil || tx.Status == "pending" {
+		return nil, ctx.Err()
+	}
+
+	if _, ok := client.(SignatureStatusFetcher); ok {
+		return tx, nil
+	}
+
+	depth, err := confirmationDepth(ctx, client, tx)
+	if err != nil || depth < confirmations {
+		return nil, ctx.Err()
+	}
+	return tx, nil
+}
+
+// confirmationDepth 计算交易的确认数，优先使用节点返回的确认数（Bitcoin）
+func confirmationDepth(ctx context.Context, client Client, tx *Transaction) (int, error) {
+	if confirmations, ok := tx.Extra["confirmations"].(int); ok {
+		return confirmations, nil
+	}
+	if tx.BlockNumber == 0 {
+		return 0, nil
+	}
+
+	current, err := client.GetBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if current < tx.BlockNumber {
+		return 0, nil
+	}
+	return int(current-tx.BlockNumber) + 1, nil
+}
+
+// commitmentReached 判断 Solana 确认级别是否满足要求
+func commitmentReached(status string, confirmations int) bool {
+	switch status {
+	case CommitmentFinalized:
+		return true
+	case CommitmentConfirmed:
+		return confirmations <= 1
+	default:
+		return false
+	}
+}
+
+// BalanceChange 交易所余额变化，新出现或消失的币种另一侧余额为 "0"
+type BalanceChange struct {
+	Currency string
+	Old      string
+	New      string
+}
+
+// WatchBalances 按 interval 轮询交易所余额，与上一次快照比较后将每个变化的币种发送到返回的通道
+// 首次查询作为基准，不产生变化；首次查询失败时直接返回错误。
+// 之后的查询错误视为暂时错误，下一次成功的查询仍与最近一次成功的快照比较。
+// ctx 结束时停止轮询并关闭通道
+func (m *ExchangeManager) WatchBalances(ctx context.Context, exchange Exchange, interval time.Duration) (<-chan BalanceChange, error) {
+	if interval <= 0 {
+		interval = watchPollInterval
+	}
+
+	previous, err := m.GetBalances(ctx, exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan BalanceChange)
+	go func() {
+		defer close(changes)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := m.GetBalances(ctx, exchange)
+			if err != nil {
+				continue
+			}
+
+			for _, change := range diffBalances(previous, current) {
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+
+	return changes, nil
+}
+
+// diffBalances 比较两次余额快照，按币种排序返回变化
+// 余额按数值比较（"1.50" 与 "1.5" 相同），缺失的币种视为 "0"
+func diffBalances(previous, current map[string]string) []BalanceChange {
+	currencies := make([]string, 0, len(previous)+len(current))
+	for currency := range previous {
+		currencies = append(currencies, currency)
+	}
+	for currency := range current {
+		if _, exists := previous[currency]; !exists {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	var changes []BalanceChange
+	for _, currency := range currencies {
+		old, ok := previous[currency]
+		if !ok {
+			old = "0"
+		}
+		balance, ok := current[currency]
+		if !ok {
+			balance = "0"
+		}
+		if !balanceEqual(old, balance) {
+			changes = append(changes, BalanceChange{Currency: currency, Old: old, New: balance})
+		}
+	}
+	return changes
+}
+
+// balanceEqual 按数值比较余额，无法解析时按字符串比较
+func balanceEqual(a, b string) bool {
+	x, errA := decimal.ParseAmount(a)
+	y, errB := decimal.ParseAmount(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return x.Cmp(y) == 0
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // Chain 区块链类型
@@ -68,10 +69,14 @@ type TransactionRequest struct {
 
 // Manager Web3 管理器
 type Manager struct {
-	clients map[Chain]Client
-	mu      sync.RWMutex
+	clients  map[Chain]Client
+	timeouts map[Chain]time.Duration
+	mu       sync.RWMutex
 }
 
+// DefaultClientTimeout 调用方 context 没有截止时间时，单次客户端调用的默认超时
+const DefaultClientTimeout = 30 * time.Second
+
 var (
 	globalManager *Manager
 	once          sync.Once
@@ -106,12 +111,46 @@ func (m *Manager) GetClient(chain Chain) (Client, error) {
 	return client, nil
 }
 
+// SetClientTimeout 设置链客户端调用的默认超时，仅在调用方 context 没有截止时间时生效
+// 未设置时使用 DefaultClientTimeout，timeout <= 0 表示不添加超时
+func (m *Manager) SetClientTimeout(chain Chain, timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.timeouts == nil {
+		m.timeouts = make(map[Chain]time.Duration)
+	}
+	m.timeouts[chain] = timeout
+}
+
+// clientContext 调用方 context 没有截止时间时添加该链的默认超时
+func (m *Manager) clientContext(ctx context.Context, chain Chain) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	m.mu.RLock()
+	timeout, ok := m.timeouts[chain]
+	m.mu.RUnlock()
+
+	if !ok {
+		timeout = DefaultClientTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // GetBalance 获取余额
 func (m *Manager) GetBalance(ctx context.Context, chain Chain, address string) (string, error) {
 	client, err := m.GetClient(chain)
 	if err != nil {
 		return "", err
 	}
+
+	ctx, cancel := m.clientContext(ctx, chain)
+	defer cancel()
 	return client.GetBalance(ctx, address)
 }
 
@@ -121,6 +160,9 @@ func (m *Manager) GetTransaction(ctx context.Context, chain Chain, txHash string
 	if err != nil {
 		return nil, err
 	}
+
+	ctx, cancel := m.clientContext(ctx, chain)
+	defer cancel()
 	return client.GetTransaction(ctx, txHash)
 }
 
@@ -130,6 +172,9 @@ func (m *Manager) SendTransaction(ctx context.Context, chain Chain, tx *Transact
 	if err != nil {
 		return "", err
 	}
+
+	ctx, cancel := m.clientContext(ctx, chain)
+	defer cancel()
 	return client.SendTransaction(ctx, tx)
 }
 
@@ -171,21 +216,21 @@ func ValidateAddress(chain Chain, address string) error {
 
 	switch chain {
 	case Bitcoin:
-		// Bitcoin 地址验证（简化版）
-		// Legacy (1...), SegWit (3...), Bech32 (bc1...)
-		if len(address) < 26 || len(address) > 90 {
-			return errors.New("invalid bitcoin address length")
-		}
+		// Legacy (1...) 和 P2SH (3...) 校验 Base58Check，SegWit (bc1...) 校验 bech32/bech32m，只接受主网地址
+		return validateBitcoinAddress(address)
 	case Ethereum, BSC:
-		// Ethereum/BSC 地址验证
-		if len(address) != 42 || address[:2] != "0x" {
-			return errors.New("invalid ethereum address format")
-		}
+		// Ethereum/BSC 地址验证，大小写混合时校验 EIP-55 校验和
+		return validateEthereumAddress(address)
 	case Solana:
-		// Solana 地址验证（Base58）
+		// Solana 地址为 32 字节公钥的 Base58 编码
 		if len(address) < 32 || len(address) > 44 {
 			return errors.New("invalid solana address length")
 		}
+		if decoded, err := base58Decode(address); err != nil {
+			return fmt.Errorf("invalid solana address: %w", err)
+		} else if len(decoded) != 32 {
+			return errors.New("invalid solana address: must decode to 32 bytes")
+		}
 	default:
 		return fmt.Errorf("unsupported chain: %s", chain)
 	}
@@ -209,9 +254,15 @@ func ValidateTxHash(chain Chain, txHash string) error {
 			return errors.New("invalid ethereum transaction hash format")
 		}
 	case Solana:
+		// Solana 交易签名为 64 字节的 Base58 编码
 		if len(txHash) < 80 || len(txHash) > 90 {
 			return errors.New("invalid solana transaction hash length")
 		}
+		if decoded, err := base58Decode(txHash); err != nil {
+			return fmt.Errorf("invalid solana transaction hash: %w", err)
+		} else if len(decoded) != 64 {
+			return errors.New("invalid solana transaction hash: must decode to 64 bytes")
+		}
 	default:
 		return fmt.Errorf("unsupported chain: %s", chain)
 	}
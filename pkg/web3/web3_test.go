@@ -1,9 +1,32 @@
 package web3
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func TestManager(t *testing.T) {
@@ -29,11 +52,15 @@ func TestValidateAddress(t *testing.T) {
 	}{
 		// Ethereum
 		{Ethereum, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb", false}, // missing last character
-		{Ethereum, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", true},
-		{Ethereum, "742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", false}, // missing 0x
+		{Ethereum, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0", true},
+		{Ethereum, "742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", false},   // missing 0x
+		{Ethereum, "0x742d35cc6634c0532925a3b844bc9e7595f0beb0", true},  // all lower case skips checksum
+		{Ethereum, "0x742D35CC6634C0532925A3B844BC9E7595F0BEB0", true},  // all upper case skips checksum
+		{Ethereum, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", false}, // bad EIP-55 checksum
+		{Ethereum, "0x742d35cc6634c0532925a3b844bc9e7595f0beg0", false}, // not hex
 
 		// BSC (same format as Ethereum)
-		{BSC, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", true},
+		{BSC, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0", true},
 
 		// Bitcoin
 		{Bitcoin, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
@@ -42,7 +69,12 @@ func TestValidateAddress(t *testing.T) {
 
 		// Solana
 		{Solana, "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeK", true},
+		{Solana, "11111111111111111111111111111111", true}, // system program
 		{Solana, "invalid", false},
+		{Solana, "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJe0", false}, // '0' is not base58
+		{Solana, "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeI", false}, // 'I' is not base58
+		{Solana, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0", false},
+		{Solana, "zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz", false}, // decodes to 33 bytes
 	}
 
 	for _, tt := range tests {
@@ -56,6 +88,65 @@ func TestValidateAddress(t *testing.T) {
 	}
 }
 
+func TestValidateBitcoinAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		// Legacy / P2SH (Base58Check)
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false}, // checksum mismatch
+		{"1InvalidButRightLengthxxxxxxxxxxxx", false}, // 'I' is not base58
+		{"mfWyW5fc9NUj75YAnFgoRLrjxgLDn2MMth", false}, // testnet P2PKH
+		{"12D2adLM3UKy4Z4giRbReR6gjWuvHUqB", false},   // truncated
+
+		// SegWit (bech32 / bech32m)
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", true},
+		{"BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ", true},
+		{"bc1qqqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0szrtjt7", true},  // P2WSH
+		{"bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", true},  // taproot
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdr", false},                     // checksum mismatch
+		{"bc1Qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", false},                     // mixed case
+		{"tb1qqqqsyqcyq5rqwzqfpg9scrgwpugpzysnl25zw8", false},                     // testnet
+		{"bc1qqqqsyqcyq5rqwzqfpg9scrgwpugpzysnqslask", false},                     // v0 with bech32m checksum
+		{"bc1pqqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0sagmhkq", false}, // v1 with bech32 checksum
+		{"bc1qqqqsyqcyq5rqwzqfpg9scrgwpuk7nx3h", false},                           // 16-byte v0 program
+	}
+
+	for _, tt := range tests {
+		err := ValidateAddress(Bitcoin, tt.address)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateAddress(bitcoin, %s) expected valid, got error: %v", tt.address, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ValidateAddress(bitcoin, %s) expected invalid, got no error", tt.address)
+		}
+	}
+}
+
+func TestToChecksumAddress(t *testing.T) {
+	// EIP-55 测试向量
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		got, err := ToChecksumAddress(strings.ToLower(want))
+		if err != nil || got != want {
+			t.Errorf("ToChecksumAddress(%s) = %s, %v; want %s", strings.ToLower(want), got, err, want)
+		}
+		if err := ValidateAddress(Ethereum, want); err != nil {
+			t.Errorf("ValidateAddress(%s) expected valid, got %v", want, err)
+		}
+	}
+
+	if _, err := ToChecksumAddress("0x1234"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
 func TestValidateTxHash(t *testing.T) {
 	tests := []struct {
 		chain  Chain
@@ -70,6 +161,12 @@ func TestValidateTxHash(t *testing.T) {
 		// Bitcoin (64 hex characters)
 		{Bitcoin, "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098", true},
 		{Bitcoin, "invalid", false},
+
+		// Solana (64-byte signature, Base58)
+		{Solana, "31RMWYUY89wmcrGNzzvCcU6mZHH5PP4cRyNErF6bjNDgpufJPGXxLQizVoPXajooXcgf6szskqvy5LvhJBGb6VeA", true},
+		{Solana, "31RMWYUY89wmcrGNzzvCcU6mZHH5PP4cRyNErF6bjNDgpufJPGXxLQizVoPXajooXcgf6szskqvy5LvhJBGb6Ve0", false}, // '0' is not base58
+		{Solana, "31RMWYUY89wmcrGNzzvCcU6mZHH5PP4cRyNErF6bjNDgpufJPGXxLQizVoPXajooXcgf6szskqvy5LvhJBGb6VeO", false}, // 'O' is not base58
+		{Solana, "JntszNrtTp3V7rU7ddKzeZbVq3hMvHkFnbFkuULbUP9onh395pCfdTiAHxFSMGdJK3rnDrur7nihKRZ2dL", false},       // 60 bytes
 	}
 
 	for _, tt := range tests {
@@ -104,8 +201,8 @@ func TestMultiChainAddress(t *testing.T) {
 	// This is a unit test that doesn't require actual blockchain connections
 	addr := MultiChainAddress{
 		Bitcoin:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
-		Ethereum: "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0",
-		BSC:      "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0",
+		Ethereum: "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0",
+		BSC:      "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0",
 		Solana:   "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeK",
 	}
 
@@ -127,10 +224,37 @@ func TestMultiChainAddress(t *testing.T) {
 	}
 }
 
+func TestAddressBook(t *testing.T) {
+	book := NewAddressBook(nil)
+
+	address := "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0"
+	if err := book.Add(Ethereum, address, "treasury"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if label, ok := book.Label(Ethereum, strings.ToLower(address)); !ok || label != "treasury" {
+		t.Errorf("Expected label 'treasury' for lower-cased address, got %q (%v)", label, ok)
+	}
+	if _, ok := book.Label(BSC, address); ok {
+		t.Error("Expected labels to be scoped per chain")
+	}
+
+	if err := book.Add(Ethereum, "0x123", "bad"); err == nil {
+		t.Error("Expected invalid address to be rejected")
+	}
+
+	if err := book.Remove(Ethereum, address); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, ok := book.Label(Ethereum, address); ok {
+		t.Error("Expected label to be removed")
+	}
+}
+
 func TestTransaction(t *testing.T) {
 	tx := &Transaction{
 		Hash:        "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-		From:        "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0",
+		From:        "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0",
 		To:          "0x1234567890abcdef1234567890abcdef12345678",
 		Value:       "1000000000000000000",
 		BlockNumber: 12345678,
@@ -152,7 +276,7 @@ func TestTransaction(t *testing.T) {
 
 func TestWalletInfo(t *testing.T) {
 	info := &WalletInfo{
-		Address: "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0",
+		Address: "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0",
 		Chain:   Ethereum,
 		Balance: "1000000000000000000",
 		Nonce:   5,
@@ -238,3 +362,1602 @@ func TestSolanaClientIntegration(t *testing.T) {
 		t.Logf("Solana version: %v", version)
 	}
 }
+
+func TestCoinbaseAdvancedTradeJWT(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	client := NewCoinbaseClient("organizations/org/apiKeys/key", secret, WithCoinbaseAdvancedTrade())
+	if client.AuthMode() != CoinbaseAuthAdvancedTrade {
+		t.Fatalf("expected advanced auth mode, got %s", client.AuthMode())
+	}
+
+	token, err := client.generateJWT("GET", "/api/v3/brokerage/accounts?limit=250")
+	if err != nil {
+		t.Fatalf("generateJWT error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT parts, got %d", len(parts))
+	}
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]interface{}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims["uri"] != "GET api.coinbase.com/api/v3/brokerage/accounts" {
+		t.Errorf("unexpected uri claim: %v", claims["uri"])
+	}
+	if claims["sub"] != "organizations/org/apiKeys/key" || claims["iss"] != "cdp" {
+		t.Errorf("unexpected claims: %v", claims)
+	}
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	if len(signature) != 64 {
+		t.Fatalf("expected 64-byte signature, got %d", len(signature))
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(&key.PublicKey, hash[:], r, s) {
+		t.Error("JWT signature verification failed")
+	}
+
+	// 旧版模式保持默认
+	if NewCoinbaseClient("key", "secret").AuthMode() != CoinbaseAuthLegacy {
+		t.Error("expected legacy auth mode by default")
+	}
+}
+
+// fakeExchange 测试用交易所客户端
+type fakeExchange struct {
+	balances map[string]string
+	prices   map[string]string
+}
+
+func (f *fakeExchange) GetBalance(ctx context.Context, currency string) (string, error) {
+	return f.balances[currency], nil
+}
+
+func (f *fakeExchange) GetBalances(ctx context.Context) (map[string]string, error) {
+	return f.balances, nil
+}
+
+func (f *fakeExchange) GetPrice(ctx context.Context, pair string) (string, error) {
+	price, ok := f.prices[pair]
+	if !ok {
+		return "", fmt.Errorf("no market for %s", pair)
+	}
+	return price, nil
+}
+
+func (f *fakeExchange) GetCandles(ctx context.Context, pair, interval string, start, end time.Time) ([]Candle, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetTradeHistory(ctx context.Context, pair string, start, end time.Time) ([]Trade, error) {
+	return nil, nil
+}
+
+func TestGetPortfolioValue(t *testing.T) {
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(Coinbase, &fakeExchange{
+		balances: map[string]string{"BTC": "0.5", "ETH": "2", "USD": "100.25", "DUST": "10", "SOL": "0"},
+		prices:   map[string]string{"BTC-USD": "60000", "ETH-USD": "3000.5"},
+	})
+
+	values, total, err := manager.GetPortfolioValue(context.Background(), Coinbase, "USD")
+	if err != nil {
+		t.Fatalf("GetPortfolioValue error: %v", err)
+	}
+
+	if total != "36101.25" {
+		t.Errorf("expected total 36101.25, got %s", total)
+	}
+	if values["ETH"].Value != "6001" {
+		t.Errorf("expected ETH value 6001, got %s", values["ETH"].Value)
+	}
+	if !values["DUST"].NoMarket || values["DUST"].Value != "0" {
+		t.Errorf("expected DUST to be flagged with no market, got %+v", values["DUST"])
+	}
+	if values["SOL"].NoMarket || values["SOL"].Value != "0" {
+		t.Errorf("expected zero SOL balance valued at 0, got %+v", values["SOL"])
+	}
+
+	// Hyperliquid 的交易对只使用基础币种名称
+	manager.RegisterExchange(Hyperliquid, &fakeExchange{
+		balances: map[string]string{"BTC": "0.5", "USD": "10"},
+		prices:   map[string]string{"BTC": "60000"},
+	})
+	values, total, err = manager.GetPortfolioValue(context.Background(), Hyperliquid, "usd")
+	if err != nil {
+		t.Fatalf("GetPortfolioValue error: %v", err)
+	}
+	if total != "30010" || values["BTC"].NoMarket {
+		t.Errorf("expected Hyperliquid pair format, got total %s %+v", total, values["BTC"])
+	}
+}
+
+func TestSolanaGetSignaturesForAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SolanaRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "getSignaturesForAddress" {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		options, _ := req.Params[1].(map[string]interface{})
+		if options["before"] != "cursor" || options["limit"] != float64(2) {
+			t.Errorf("unexpected options %v", options)
+		}
+
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":[
+			{"signature":"sig1","slot":100,"blockTime":1700000000,"confirmationStatus":"finalized","memo":null,"err":null},
+			{"signature":"sig2","slot":99,"blockTime":null,"confirmationStatus":"confirmed","memo":"hi","err":{"InstructionError":[0,"Custom"]}}
+		]}`)
+	}))
+	defer server.Close()
+
+	client := NewSolanaClient(server.URL)
+	address := "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+
+	signatures, err := client.GetSignaturesForAddress(context.Background(), address, 2, "cursor")
+	if err != nil {
+		t.Fatalf("GetSignaturesForAddress failed: %v", err)
+	}
+	if len(signatures) != 2 {
+		t.Fatalf("expected 2 signatures, got %d", len(signatures))
+	}
+	if !signatures[0].Success || signatures[0].BlockTime != 1700000000 {
+		t.Errorf("unexpected first signature: %+v", signatures[0])
+	}
+	if signatures[1].Success || signatures[1].Error == "" || signatures[1].Memo != "hi" {
+		t.Errorf("unexpected second signature: %+v", signatures[1])
+	}
+}
+
+func TestENSNamehash(t *testing.T) {
+	tests := map[string]string{
+		"":        "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"eth":     "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth": "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+	}
+
+	for name, want := range tests {
+		if got := ENSNamehash(name).Hex(); got != want {
+			t.Errorf("ENSNamehash(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestDecodeABIString(t *testing.T) {
+	data := make([]byte, 96)
+	data[31] = 32
+	data[63] = 11
+	copy(data[64:], "vitalik.eth")
+
+	name, err := decodeABIString(data)
+	if err != nil || name != "vitalik.eth" {
+		t.Errorf("decodeABIString() = %q, %v", name, err)
+	}
+
+	if _, err := decodeABIString(data[:40]); err == nil {
+		t.Error("expected error for truncated data")
+	}
+
+	// 接近 uint64 上限的偏移和长度相加会溢出，必须返回错误而不是 panic
+	hostile := func(offset, length uint64) []byte {
+		b := append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(b[24:32], offset)
+		binary.BigEndian.PutUint64(b[56:64], length)
+		return b
+	}
+	tests := map[string][]byte{
+		"offset overflow":  hostile(math.MaxUint64-16, 11),
+		"offset too large": hostile(80, 11),
+		"length overflow":  hostile(32, math.MaxUint64-16),
+		"length too large": hostile(32, 33),
+	}
+	for name, b := range tests {
+		if _, err := decodeABIString(b); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestMulticallEncoding(t *testing.T) {
+	target := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	calls := []Call{
+		{Target: target, Data: []byte{0x31, 0x3c, 0xe5, 0x67}},
+		{Target: target, Data: []byte{0x95, 0xd8, 0x9b, 0x41}, AllowFailure: true},
+	}
+
+	data := encodeAggregate3(calls)
+	if !bytes.Equal(data[:4], multicallAggregate3Selector) {
+		t.Fatalf("unexpected selector %x", data[:4])
+	}
+	// 选择器 + 数组偏移 + 长度 + 2 个元组偏移 + 2 个元组（各 5 个字）
+	if len(data) != 4+32*4+2*160 {
+		t.Fatalf("unexpected encoded length %d", len(data))
+	}
+	if count, _ := abiReadUint(data[4:], 32); count != 2 {
+		t.Errorf("expected 2 calls, got %d", count)
+	}
+
+	// 构造返回值: 第一个调用成功返回 0xdeadbeef，第二个调用失败
+	var result []byte
+	result = append(result, abiUint(32)...)
+	result = append(result, abiUint(2)...)
+	result = append(result, abiUint(64)...)
+	result = append(result, abiUint(192)...)
+	result = append(result, abiBool(true)...)
+	result = append(result, abiUint(64)...)
+	result = append(result, abiUint(4)...)
+	result = append(result, append([]byte{0xde, 0xad, 0xbe, 0xef}, make([]byte, 28)...)...)
+	result = append(result, abiBool(false)...)
+	result = append(result, abiUint(64)...)
+	result = append(result, abiUint(0)...)
+
+	decoded, err := decodeAggregate3(result, 2)
+	if err != nil {
+		t.Fatalf("decodeAggregate3 failed: %v", err)
+	}
+	if !bytes.Equal(decoded[0], []byte{0xde, 0xad, 0xbe, 0xef}) {
+		t.Errorf("unexpected first result %x", decoded[0])
+	}
+	if decoded[1] != nil {
+		t.Errorf("expected nil for failed call, got %x", decoded[1])
+	}
+
+	if _, err := decodeAggregate3(result, 3); err == nil {
+		t.Error("expected error for result count mismatch")
+	}
+}
+
+// fakeSendClient 可控制广播结果的测试客户端
+type fakeSendClient struct {
+	sendErrors []error
+	known      map[string]bool
+	sends      int
+	nonce      uint64
+}
+
+func (c *fakeSendClient) GetBalance(ctx context.Context, address string) (string, error) {
+	return "0", nil
+}
+
+func (c *fakeSendClient) GetBlockNumber(ctx context.Context) (uint64, error) { return 0, nil }
+
+func (c *fakeSendClient) GetTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	if c.known[txHash] {
+		return &Transaction{Hash: txHash, Status: "pending"}, nil
+	}
+	return nil, fmt.Errorf("transaction %s not found", txHash)
+}
+
+func (c *fakeSendClient) SendTransaction(ctx context.Context, tx *TransactionRequest) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (c *fakeSendClient) GetChain() Chain { return Ethereum }
+
+func (c *fakeSendClient) Close() error { return nil }
+
+func (c *fakeSendClient) SignTransaction(ctx context.Context, tx *TransactionRequest) (string, []byte, error) {
+	hash := fmt.Sprintf("0xhash%d", tx.Nonce)
+	return hash, []byte(hash), nil
+}
+
+func (c *fakeSendClient) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	c.sends++
+	if len(c.sendErrors) > 0 {
+		err := c.sendErrors[0]
+		c.sendErrors = c.sendErrors[1:]
+		if err != nil {
+			// 超时的广播实际上已被节点接收
+			if !isNonceTooLowError(err) {
+				c.known[string(raw)] = true
+			}
+			return "", err
+		}
+	}
+	return string(raw), nil
+}
+
+func (c *fakeSendClient) GetTransactionCount(ctx context.Context, address string) (uint64, error) {
+	return c.nonce, nil
+}
+
+func TestSendTransactionWithRetry(t *testing.T) {
+	sendRetryBackoff = time.Millisecond
+	manager := &Manager{clients: make(map[Chain]Client)}
+	ctx := context.Background()
+
+	// 超时后交易已在内存池中，不重复广播
+	client := &fakeSendClient{
+		sendErrors: []error{fmt.Errorf("i/o timeout")},
+		known:      map[string]bool{},
+	}
+	manager.RegisterClient(Ethereum, client)
+
+	hash, err := manager.SendTransactionWithRetry(ctx, Ethereum, &TransactionRequest{Nonce: 1}, 3)
+	if err != nil || hash != "0xhash1" {
+		t.Fatalf("SendTransactionWithRetry() = %q, %v", hash, err)
+	}
+	if client.sends != 1 {
+		t.Errorf("expected 1 broadcast, got %d", client.sends)
+	}
+
+	// nonce 过低时重新获取 nonce 并重新签名
+	client = &fakeSendClient{
+		sendErrors: []error{fmt.Errorf("nonce too low")},
+		known:      map[string]bool{},
+		nonce:      7,
+	}
+	manager.RegisterClient(Ethereum, client)
+
+	hash, err = manager.SendTransactionWithRetry(ctx, Ethereum, &TransactionRequest{Nonce: 1}, 3)
+	if err != nil || hash != "0xhash7" {
+		t.Fatalf("SendTransactionWithRetry() = %q, %v", hash, err)
+	}
+	if client.sends != 2 {
+		t.Errorf("expected 2 broadcasts, got %d", client.sends)
+	}
+
+	// nonce 过低但原交易已上链时直接返回原哈希，不重新签名
+	client = &fakeSendClient{
+		sendErrors: []error{fmt.Errorf("nonce too low")},
+		known:      map[string]bool{"0xhash1": true},
+		nonce:      7,
+	}
+	manager.RegisterClient(Ethereum, client)
+
+	hash, err = manager.SendTransactionWithRetry(ctx, Ethereum, &TransactionRequest{Nonce: 1}, 3)
+	if err != nil || hash != "0xhash1" {
+		t.Fatalf("SendTransactionWithRetry() = %q, %v", hash, err)
+	}
+	if client.sends != 1 {
+		t.Errorf("expected 1 broadcast, got %d", client.sends)
+	}
+
+	// 不支持本地签名的客户端 nonce 过低时不重试
+	plain := &plainSendClient{Client: &fakeSendClient{known: map[string]bool{}}, err: fmt.Errorf("nonce too low")}
+	manager.RegisterClient(Ethereum, plain)
+
+	if _, err := manager.SendTransactionWithRetry(ctx, Ethereum, &TransactionRequest{Nonce: 1}, 3); err == nil {
+		t.Fatal("expected nonce too low error")
+	}
+	if plain.sends != 1 {
+		t.Errorf("expected 1 send without retry, got %d", plain.sends)
+	}
+}
+
+func TestParseWei(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "0"},
+		{"1000000000000000000", "1000000000000000000"},
+		{"0x4a817c800", "20000000000"},
+		{"0X4A817C800", "20000000000"},
+		{"0755", "755"},
+	}
+	for _, tt := range tests {
+		wei, err := parseWei(tt.value)
+		if err != nil || wei.String() != tt.want {
+			t.Errorf("parseWei(%q) = %v, %v, want %s", tt.value, wei, err, tt.want)
+		}
+	}
+
+	// 只接受十进制和 0x 十六进制
+	for _, value := range []string{"-1", "+1", "0x", "0x-1", "0b101", "0o17", "1_000", "1.5", "abc"} {
+		if _, err := parseWei(value); err == nil {
+			t.Errorf("parseWei(%q) expected error", value)
+		}
+	}
+}
+
+func TestEthereumSignAndSendRawTransaction(t *testing.T) {
+	var broadcast string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []string        `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result string
+		switch req.Method {
+		case "eth_chainId":
+			result = `"0x1"`
+		case "eth_sendRawTransaction":
+			broadcast = req.Params[0]
+			result = `"0x` + strings.Repeat("0", 64) + `"`
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	client, err := NewEthereumClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	request := &TransactionRequest{
+		To:       "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+		Value:    "1000000000000000000",
+		GasLimit: 21000,
+		GasPrice: "20000000000",
+		Nonce:    3,
+	}
+	if _, _, err := client.SignTransaction(context.Background(), request); err == nil {
+		t.Fatal("expected error without private key")
+	}
+
+	if err := client.SetPrivateKey("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"); err != nil {
+		t.Fatalf("SetPrivateKey failed: %v", err)
+	}
+	hash, raw, err := client.SignTransaction(context.Background(), request)
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("invalid raw transaction: %v", err)
+	}
+	if tx.Hash().Hex() != hash || tx.Nonce() != 3 || tx.Gas() != 21000 || tx.Value().String() != request.Value {
+		t.Errorf("unexpected signed transaction %s nonce=%d gas=%d value=%s", hash, tx.Nonce(), tx.Gas(), tx.Value())
+	}
+
+	if _, err := client.SendRawTransaction(context.Background(), raw); err != nil {
+		t.Fatalf("SendRawTransaction failed: %v", err)
+	}
+	if broadcast != "0x"+hex.EncodeToString(raw) {
+		t.Errorf("unexpected broadcast %s", broadcast)
+	}
+
+	request.From = "0x0000000000000000000000000000000000000001"
+	if _, _, err := client.SignTransaction(context.Background(), request); err == nil {
+		t.Error("expected error for mismatched from address")
+	}
+}
+
+// plainSendClient 只实现 Client 接口、不支持本地签名的测试客户端
+type plainSendClient struct {
+	Client
+	err   error
+	sends int
+}
+
+func (c *plainSendClient) SendTransaction(ctx context.Context, tx *TransactionRequest) (string, error) {
+	c.sends++
+	return "", c.err
+}
+
+func TestBitcoinBuildAndSignTx(t *testing.T) {
+	var outputs map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result string
+		switch req.Method {
+		case "estimatesmartfee":
+			result = `{"feerate":0.00010000,"blocks":6}`
+		case "listunspent":
+			result = `[
+				{"txid":"aa","vout":0,"address":"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq","amount":0.00050000,"confirmations":10,"scriptPubKey":"0014e8df018c7e326cc253faac7e46cdc51e68542c42","spendable":true},
+				{"txid":"bb","vout":1,"address":"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq","amount":0.00200000,"confirmations":3,"scriptPubKey":"0014e8df018c7e326cc253faac7e46cdc51e68542c42","spendable":true},
+				{"txid":"cc","vout":0,"address":"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4","amount":1.00000000,"confirmations":50,"scriptPubKey":"0014751e76e8199196d454941c45d1b3a323f1433bd6","spendable":false}
+			]`
+		case "createrawtransaction":
+			json.Unmarshal(req.Params[1], &outputs)
+			result = `"0200raw"`
+		case "signrawtransactionwithwallet":
+			result = `{"hex":"0200signed","complete":true}`
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		fmt.Fprintf(w, `{"result":%s,"error":null,"id":"clarkgo"}`, result)
+	}))
+	defer server.Close()
+
+	client := NewBitcoinClient(server.URL, "")
+	if rate, err := client.EstimateFeeRate(context.Background(), 6); err != nil || rate != 10 {
+		t.Fatalf("EstimateFeeRate() = %v, %v", rate, err)
+	}
+
+	tx, err := client.BuildAndSignTx(context.Background(), &BitcoinTxRequest{
+		To:     "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		Amount: 100000,
+	})
+	if err != nil {
+		t.Fatalf("BuildAndSignTx failed: %v", err)
+	}
+
+	// 选择最大的 UTXO，vsize = 11 + 68 + 2*31 = 141，手续费 1410 聪
+	if tx.Hex != "0200signed" || len(tx.Inputs) != 1 || tx.Inputs[0].TxID != "bb" {
+		t.Errorf("unexpected tx %+v", tx)
+	}
+	if tx.Fee != 1410 || tx.Change != 200000-100000-1410 {
+		t.Errorf("unexpected fee %d / change %d", tx.Fee, tx.Change)
+	}
+	if outputs["bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"] != 0.001 {
+		t.Errorf("unexpected outputs %v", outputs)
+	}
+
+	if _, err := selectUTXOs(tx.Inputs, 300000, 10); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	// 单个输入足以支付无找零的手续费时不再加入输入：vsize = 11 + 68 + 31 = 110
+	p2wpkh := "0014e8df018c7e326cc253faac7e46cdc51e68542c42"
+	noChange, err := selectUTXOs([]UTXO{
+		{TxID: "big", Amount: 101200, ScriptPubKey: p2wpkh},
+		{TxID: "small", Amount: 5000, ScriptPubKey: p2wpkh},
+	}, 100000, 10)
+	if err != nil {
+		t.Fatalf("selectUTXOs failed: %v", err)
+	}
+	if len(noChange.Inputs) != 1 || noChange.Change != 0 || noChange.Fee != 1200 || noChange.VSize != 110 {
+		t.Errorf("unexpected no-change selection %+v", noChange)
+	}
+
+	// P2PKH 输入按 148 vB 计算：vsize = 11 + 148 + 2*31 = 221
+	legacy, err := selectUTXOs([]UTXO{{TxID: "legacy", Amount: 200000, ScriptPubKey: "76a91489abcdefabbaabbaabbaabbaabbaabbaabbaabba88ac"}}, 100000, 10)
+	if err != nil {
+		t.Fatalf("selectUTXOs failed: %v", err)
+	}
+	if legacy.VSize != 221 || legacy.Fee != 2210 {
+		t.Errorf("expected P2PKH input sizing, got vsize %d fee %d", legacy.VSize, legacy.Fee)
+	}
+	if got := satoshiToBTC(123456789); got != "1.23456789" {
+		t.Errorf("satoshiToBTC() = %s", got)
+	}
+}
+
+// fakeWatchClient 每次查询区块高度时出一个新块的测试客户端
+type fakeWatchClient struct {
+	fakeSendClient
+	block   uint64
+	txBlock uint64
+}
+
+func (c *fakeWatchClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	c.block++
+	return c.block, nil
+}
+
+func (c *fakeWatchClient) GetTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	if c.block < c.txBlock {
+		return &Transaction{Hash: txHash, Status: "pending"}, nil
+	}
+	return &Transaction{Hash: txHash, Status: "success", BlockNumber: c.txBlock}, nil
+}
+
+func TestWatchTransaction(t *testing.T) {
+	watchPollInterval = time.Millisecond
+	manager := &Manager{clients: make(map[Chain]Client)}
+	client := &fakeWatchClient{block: 10, txBlock: 10}
+	manager.RegisterClient(Ethereum, client)
+
+	var confirmed *Transaction
+	err := manager.WatchTransaction(context.Background(), Ethereum, "0xabc", 3, func(tx *Transaction) {
+		confirmed = tx
+	})
+	if err != nil {
+		t.Fatalf("WatchTransaction failed: %v", err)
+	}
+	if confirmed == nil || confirmed.Hash != "0xabc" {
+		t.Fatalf("expected confirmation callback, got %+v", confirmed)
+	}
+	if client.block != 12 {
+		t.Errorf("expected confirmation at block 12, got %d", client.block)
+	}
+
+	// 交易一直未上链时随 ctx 结束
+	manager.RegisterClient(Ethereum, &fakeWatchClient{txBlock: 1 << 40})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = manager.WatchTransaction(ctx, Ethereum, "0xabc", 1, func(tx *Transaction) {
+		t.Error("unexpected confirmation")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestWatchSolanaTransaction(t *testing.T) {
+	watchPollInterval = time.Millisecond
+	signature := strings.Repeat("5", 88)
+
+	statuses := []string{"null", `{"slot":100,"confirmationStatus":"confirmed"}`, `{"slot":100,"confirmationStatus":"finalized"}`}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SolanaRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		switch req.Method {
+		case "getSignatureStatuses":
+			status := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				status = statuses[polls]
+			}
+			polls++
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":101},"value":[%s]}}`, status)
+		case "getTransaction":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"slot":100,"blockTime":1700000000,"meta":{"err":null,"fee":5000},"transaction":{"message":{"accountKeys":[]},"signatures":[]}}}`)
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+	}))
+	defer server.Close()
+
+	manager := &Manager{clients: make(map[Chain]Client)}
+	manager.RegisterClient(Solana, NewSolanaClient(server.URL))
+
+	var confirmed *Transaction
+	err := manager.WatchTransaction(context.Background(), Solana, signature, 32, func(tx *Transaction) {
+		confirmed = tx
+	})
+	if err != nil {
+		t.Fatalf("WatchTransaction failed: %v", err)
+	}
+	if confirmed == nil || confirmed.Status != "success" || confirmed.BlockNumber != 100 {
+		t.Errorf("unexpected confirmed transaction: %+v", confirmed)
+	}
+	if polls != 3 {
+		t.Errorf("expected finalized on third poll, got %d polls", polls)
+	}
+}
+
+// fakeBalanceExchange 按顺序返回余额快照的交易所客户端，nil 快照表示查询失败
+type fakeBalanceExchange struct {
+	fakeExchange
+	mu        sync.Mutex
+	snapshots []map[string]string
+}
+
+func (f *fakeBalanceExchange) GetBalances(ctx context.Context) (map[string]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snapshot := f.snapshots[0]
+	if len(f.snapshots) > 1 {
+		f.snapshots = f.snapshots[1:]
+	}
+	if snapshot == nil {
+		return nil, errors.New("exchange unavailable")
+	}
+	return snapshot, nil
+}
+
+func TestWatchBalances(t *testing.T) {
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(Coinbase, &fakeBalanceExchange{snapshots: []map[string]string{
+		{"BTC": "1.5", "ETH": "2"},
+		{"BTC": "1.50", "ETH": "2"}, // 数值未变化
+		nil,                         // 暂时错误
+		{"BTC": "1.25", "SOL": "10"},
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes, err := manager.WatchBalances(ctx, Coinbase, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchBalances failed: %v", err)
+	}
+
+	expected := []BalanceChange{
+		{Currency: "BTC", Old: "1.50", New: "1.25"},
+		{Currency: "ETH", Old: "2", New: "0"},
+		{Currency: "SOL", Old: "0", New: "10"},
+	}
+	for _, want := range expected {
+		select {
+		case got := <-changes:
+			if got != want {
+				t.Errorf("expected %+v, got %+v", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %+v", want)
+		}
+	}
+
+	// 余额不再变化，取消后通道关闭
+	cancel()
+	for change := range changes {
+		t.Errorf("unexpected change %+v", change)
+	}
+
+	if _, err := manager.WatchBalances(context.Background(), KuCoin, time.Millisecond); err == nil {
+		t.Error("expected error for unregistered exchange")
+	}
+}
+
+// fakeBalanceClient 返回固定余额的测试链客户端
+type fakeBalanceClient struct {
+	fakeSendClient
+	balance string
+	err     error
+}
+
+func (c *fakeBalanceClient) GetBalance(ctx context.Context, address string) (string, error) {
+	return c.balance, c.err
+}
+
+func TestGetNetWorth(t *testing.T) {
+	chains := &Manager{clients: make(map[Chain]Client)}
+	chains.RegisterClient(Ethereum, &fakeBalanceClient{balance: "1500000000000000000"}) // 1.5 ETH
+	chains.RegisterClient(Solana, &fakeBalanceClient{err: errors.New("rpc unavailable")})
+
+	exchanges := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	exchanges.RegisterExchange(Coinbase, &fakeExchange{
+		balances: map[string]string{"BTC": "0.1", "USD": "50", "DUST": "3", "ETH": "0"},
+		prices:   map[string]string{"BTC-USD": "60000", "ETH-USD": "3000"},
+	})
+	exchanges.RegisterExchange(KuCoin, &fakeExchange{
+		balances: map[string]string{},
+		prices:   map[string]string{"BTC-USD": "62000", "ETH-USD": "3100"},
+	})
+	exchanges.RegisterExchange(Hyperliquid, &fakeExchange{
+		balances: map[string]string{},
+		prices:   map[string]string{"BTC": "63000", "ETH": "3200"},
+	})
+
+	addresses := MultiChainAddress{
+		Ethereum: "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0",
+		Solana:   "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeK",
+		Bitcoin:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", // 未注册客户端，跳过
+	}
+
+	worth, err := getNetWorth(context.Background(), chains, exchanges, addresses, "usd")
+	if err != nil {
+		t.Fatalf("getNetWorth error: %v", err)
+	}
+
+	// 价格为三个交易所报价的中位数：1.5 ETH × 3100 + 0.1 BTC × 62000 + 50 USD
+	if worth.Total != "10900" {
+		t.Errorf("expected total 10900, got %s", worth.Total)
+	}
+	if !worth.Partial {
+		t.Error("expected partial result when a source fails")
+	}
+
+	byKey := make(map[string]NetWorthItem)
+	for _, item := range worth.Items {
+		byKey[item.Source+"/"+item.Asset] = item
+	}
+	if item := byKey["ethereum/ETH"]; item.Balance != "1.5" || item.Price != "3100" || item.Value != "4650" {
+		t.Errorf("unexpected ethereum item: %+v", item)
+	}
+	if item := byKey["solana/SOL"]; item.Error == "" || item.Value != "0" {
+		t.Errorf("expected solana error item, got %+v", item)
+	}
+	if item := byKey["coinbase/DUST"]; item.Error == "" || item.Value != "0" {
+		t.Errorf("expected DUST without market, got %+v", item)
+	}
+	if _, ok := byKey["coinbase/ETH"]; ok {
+		t.Error("expected zero balances to be skipped")
+	}
+
+	empty := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	if _, err := getNetWorth(context.Background(), &Manager{clients: make(map[Chain]Client)}, empty, addresses, "USD"); err == nil {
+		t.Error("expected error without any sources")
+	}
+}
+
+// blockingClient 阻塞直到 context 结束的测试客户端
+type blockingClient struct {
+	fakeSendClient
+}
+
+func (c *blockingClient) GetBalance(ctx context.Context, address string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestManagerClientTimeout(t *testing.T) {
+	manager := &Manager{clients: make(map[Chain]Client)}
+	manager.RegisterClient(Ethereum, &blockingClient{})
+	manager.SetClientTimeout(Ethereum, 10*time.Millisecond)
+
+	// 没有截止时间时使用客户端默认超时
+	_, err := manager.GetBalance(context.Background(), Ethereum, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected default timeout, got %v", err)
+	}
+
+	// 调用方 context 的取消直接传递给客户端
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	manager.SetClientTimeout(Ethereum, 0)
+	_, err = manager.GetBalance(ctx, Ethereum, "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected caller cancellation, got %v", err)
+	}
+}
+
+func TestWaitForFill(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/orders/order-1":
+		case "/api/v1/orders/busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"code":"500000","msg":"service busy"}`)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		// 前两次查询仍在挂单，之后完全成交
+		active := atomic.AddInt32(&polls, 1) <= 2
+		fmt.Fprintf(w, `{"code":"200000","data":{"id":"order-1","symbol":"BTC-USDT","side":"buy","price":"0","size":"0.5","dealFunds":"30500","dealSize":"0.5","fee":"30.5","isActive":%t,"cancelExist":false}}`, active)
+	}))
+	defer server.Close()
+
+	kucoin := NewKuCoinClient("key", "secret", "passphrase")
+	kucoin.baseURL = server.URL
+
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(KuCoin, kucoin)
+
+	result, err := manager.WaitForFill(context.Background(), KuCoin, "order-1", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForFill error: %v", err)
+	}
+	if result.Status != OrderStatusFilled || result.Price != "61000" || result.FilledSize != "0.5" {
+		t.Errorf("unexpected order result: %+v", result)
+	}
+	if atomic.LoadInt32(&polls) != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+
+	// 不支持订单查询的交易所
+	manager.RegisterExchange(Coinbase, &fakeExchange{})
+	if _, err := manager.WaitForFill(context.Background(), Coinbase, "order-1", time.Millisecond); err == nil {
+		t.Error("expected error for exchange without order status support")
+	}
+
+	// 订单一直未成交时随 ctx 结束返回最近状态
+	atomic.StoreInt32(&polls, -100)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err = manager.WaitForFill(ctx, KuCoin, "order-1", 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || result == nil || result.Status != OrderStatusOpen {
+		t.Errorf("expected open order with deadline error, got %+v, %v", result, err)
+	}
+
+	// 订单不存在时立即返回，不等待 ctx 结束
+	var apiErr *APIError
+	start := time.Now()
+	_, err = manager.WaitForFill(context.Background(), KuCoin, "missing", time.Second)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected permanent error to stop polling immediately, took %v", elapsed)
+	}
+
+	// 暂时错误继续轮询，ctx 结束时错误中保留最近一次失败原因
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = manager.WaitForFill(ctx, KuCoin, "busy", 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected deadline error wrapping the last API error, got %v", err)
+	}
+}
+
+func TestCoinbaseOrderStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders/filled":
+			fmt.Fprint(w, `{"id":"filled","product_id":"ETH-USD","side":"sell","size":"2","filled_size":"2","executed_value":"6100","fill_fees":"6.1","status":"done","done_reason":"filled"}`)
+		case "/orders/canceled":
+			fmt.Fprint(w, `{"id":"canceled","product_id":"ETH-USD","side":"buy","price":"2900","size":"1","filled_size":"0","status":"done","done_reason":"canceled"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewCoinbaseClient("key", base64.StdEncoding.EncodeToString([]byte("secret")))
+	client.baseURL = server.URL
+
+	result, err := client.GetOrderStatus(context.Background(), "filled")
+	if err != nil {
+		t.Fatalf("GetOrderStatus error: %v", err)
+	}
+	if result.Status != OrderStatusFilled || result.Price != "3050" || result.Fee != "6.1" {
+		t.Errorf("unexpected filled order: %+v", result)
+	}
+
+	result, err = client.GetOrderStatus(context.Background(), "canceled")
+	if err != nil {
+		t.Fatalf("GetOrderStatus error: %v", err)
+	}
+	if result.Status != OrderStatusCancelled || result.Price != "2900" {
+		t.Errorf("unexpected canceled order: %+v", result)
+	}
+}
+
+func TestKuCoinTradeHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+
+	var mu sync.Mutex
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		windows = append(windows, q.Get("startAt")+"-"+q.Get("endAt")+"#"+q.Get("currentPage"))
+		mu.Unlock()
+
+		// 第一个 7 天窗口有两页，第二个窗口一页
+		startAt, _ := strconv.ParseInt(q.Get("startAt"), 10, 64)
+		page := q.Get("currentPage")
+		totalPage := 1
+		if startAt == start.UnixMilli() {
+			totalPage = 2
+		}
+		createdAt := startAt + 1000
+		if page == "2" {
+			createdAt = startAt
+		}
+		fmt.Fprintf(w, `{"code":"200000","data":{"currentPage":%s,"totalPage":%d,"items":[{"tradeId":"t-%d-%s","symbol":"BTC-USDT","side":"buy","price":"42000","size":"0.01","fee":"0.42","createdAt":%d}]}}`,
+			page, totalPage, startAt, page, createdAt)
+	}))
+	defer server.Close()
+
+	client := NewKuCoinClient("key", "secret", "passphrase")
+	client.baseURL = server.URL
+
+	trades, err := client.GetTradeHistory(context.Background(), "BTC-USDT", start, end)
+	if err != nil {
+		t.Fatalf("GetTradeHistory error: %v", err)
+	}
+
+	split := start.Add(7 * 24 * time.Hour).UnixMilli()
+	want := []string{
+		fmt.Sprintf("%d-%d#1", start.UnixMilli(), split),
+		fmt.Sprintf("%d-%d#2", start.UnixMilli(), split),
+		fmt.Sprintf("%d-%d#1", split, end.UnixMilli()),
+	}
+	if strings.Join(windows, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected requests: %v, want %v", windows, want)
+	}
+
+	if len(trades) != 3 {
+		t.Fatalf("expected 3 trades, got %d", len(trades))
+	}
+	for i := 1; i < len(trades); i++ {
+		if trades[i].Time.Before(trades[i-1].Time) {
+			t.Errorf("expected trades sorted by time, got %v", trades)
+		}
+	}
+	if trades[0].ID != fmt.Sprintf("t-%d-2", start.UnixMilli()) || trades[0].Side != "buy" || trades[0].Fee != "0.42" {
+		t.Errorf("unexpected first trade: %+v", trades[0])
+	}
+}
+
+func TestSymbolNormalization(t *testing.T) {
+	if got := NormalizeSymbol(KuCoin, "btc", "usdt"); got != "BTC-USDT" {
+		t.Errorf("expected BTC-USDT, got %s", got)
+	}
+	if got := NormalizeSymbol(Hyperliquid, "eth", "usd"); got != "ETH" {
+		t.Errorf("expected ETH, got %s", got)
+	}
+
+	tests := []struct {
+		exchange    Exchange
+		symbol      string
+		base, quote string
+	}{
+		{Coinbase, "BTC-USD", "BTC", "USD"},
+		{KuCoin, "eth-usdt", "ETH", "USDT"},
+		{Hyperliquid, "SOL", "SOL", "USD"},
+		{Exchange("binance"), "BTCUSDT", "BTC", "USDT"},
+		{Exchange("binance"), "ETH/BTC", "ETH", "BTC"},
+	}
+	for _, tt := range tests {
+		base, quote, err := ParseSymbol(tt.exchange, tt.symbol)
+		if err != nil || base != tt.base || quote != tt.quote {
+			t.Errorf("ParseSymbol(%s, %s) = %s, %s, %v", tt.exchange, tt.symbol, base, quote, err)
+		}
+	}
+	if _, _, err := ParseSymbol(Exchange("binance"), "XYZ"); err == nil {
+		t.Error("expected unparseable symbol to fail")
+	}
+
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(Hyperliquid, &fakeExchange{prices: map[string]string{"BTC": "60000"}})
+	if price, err := manager.GetPairPrice(context.Background(), Hyperliquid, "btc", "usd"); err != nil || price != "60000" {
+		t.Errorf("expected 60000, got %s (%v)", price, err)
+	}
+}
+
+func TestSolanaClientPoolFailover(t *testing.T) {
+	var broken int32
+	brokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&broken, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer brokenServer.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SolanaRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "getBlockHeight" {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":42}`)
+	}))
+	defer healthy.Close()
+
+	if _, err := NewSolanaClientPool(nil); err == nil {
+		t.Error("expected empty endpoint list to fail")
+	}
+
+	client, err := NewSolanaClientPool([]string{brokenServer.URL, healthy.URL})
+	if err != nil {
+		t.Fatalf("NewSolanaClientPool error: %v", err)
+	}
+	client.Endpoints().SetFailureThreshold(2, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		height, err := client.GetBlockHeight(context.Background())
+		if err != nil || height != 42 {
+			t.Fatalf("expected height 42 via failover, got %d (%v)", height, err)
+		}
+	}
+	if client.ActiveEndpoint() != healthy.URL {
+		t.Errorf("expected active endpoint %s, got %s", healthy.URL, client.ActiveEndpoint())
+	}
+
+	// 活跃节点已切换，失败节点只在第一次请求时被尝试
+	if got := atomic.LoadInt32(&broken); got != 1 {
+		t.Errorf("expected broken endpoint to be tried once, got %d", got)
+	}
+
+	// 活跃节点失败后切换回其他节点，连续失败达到阈值后进入冷却
+	healthy.Close()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetBlockHeight(context.Background()); err == nil {
+			t.Fatal("expected error when all endpoints fail")
+		}
+	}
+	status := client.Endpoints().Status()
+	if status[0].Failures != 3 || status[0].DownUntil.IsZero() || status[1].DownUntil.IsZero() {
+		t.Errorf("expected both endpoints to be marked down, got %+v", status)
+	}
+}
+
+func TestExchangeCredentialRotation(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("CB-ACCESS-KEY") + r.Header.Get("KC-API-KEY")
+		keys = append(keys, key)
+		if r.Header.Get("KC-API-KEY") != "" {
+			fmt.Fprint(w, `{"code":"200000","data":[]}`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	coinbase := NewCoinbaseClient("old-key", "old-secret", WithCoinbaseBaseURL(server.URL))
+	coinbase.GetBalances(context.Background())
+	coinbase.SetCredentials("new-key", "new-secret")
+	coinbase.GetBalances(context.Background())
+
+	kucoin := NewKuCoinClient("old-kc", "secret", "passphrase")
+	kucoin.baseURL = server.URL
+	kucoin.GetBalances(context.Background())
+	kucoin.SetCredentials("new-kc", "secret2", "passphrase2")
+	kucoin.GetBalances(context.Background())
+
+	want := []string{"old-key", "new-key", "old-kc", "new-kc"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("expected keys %v, got %v", want, keys)
+	}
+
+	hl, _ := NewHyperliquidClient("")
+	if err := hl.SetPrivateKey("not-hex"); err == nil {
+		t.Error("expected invalid private key to be rejected")
+	}
+}
+
+func TestSolanaGetMultipleAccounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SolanaRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "getMultipleAccounts" || len(req.Params[0].([]interface{})) != 2 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[
+			null,
+			{"lamports":2039280,"owner":"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA","executable":false,"rentEpoch":18446744073709551615,"space":165,
+			 "data":{"program":"spl-token","parsed":{"type":"account"}}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	client := NewSolanaClient(server.URL)
+	addresses := []string{"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"}
+
+	if _, err := client.GetMultipleAccounts(context.Background(), []string{addresses[0], "invalid"}); err == nil {
+		t.Error("expected invalid address to be rejected")
+	}
+
+	accounts, err := client.GetMultipleAccounts(context.Background(), addresses)
+	if err != nil {
+		t.Fatalf("GetMultipleAccounts failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0] != nil {
+		t.Fatalf("expected missing first account, got %+v", accounts)
+	}
+	if accounts[1].Address != addresses[1] || accounts[1].Lamports != 2039280 || accounts[1].Space != 165 {
+		t.Errorf("unexpected account %+v", accounts[1])
+	}
+	if !strings.Contains(string(accounts[1].Data), "spl-token") {
+		t.Errorf("expected parsed data, got %s", accounts[1].Data)
+	}
+}
+
+func TestMockExchangeClient(t *testing.T) {
+	var _ ExchangeClient = (*MockExchangeClient)(nil)
+	var _ OrderStatusGetter = (*MockExchangeClient)(nil)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := NewMockExchangeClient().
+		WithBalance("BTC", "0.5").
+		WithPrice("BTC-USD", "60000").
+		WithTrades("BTC-USD", []Trade{
+			{ID: "2", Time: start.Add(2 * time.Hour)},
+			{ID: "1", Time: start.Add(time.Hour)},
+			{ID: "0", Time: start.Add(-time.Hour)},
+		}).
+		WithOrder(OrderResult{OrderID: "order-1", Status: OrderStatusFilled})
+
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(Coinbase, mock)
+	ctx := context.Background()
+
+	if balance, _ := manager.GetBalance(ctx, Coinbase, "BTC"); balance != "0.5" {
+		t.Errorf("expected BTC balance 0.5, got %s", balance)
+	}
+	if balance, _ := mock.GetBalance(ctx, "ETH"); balance != "0" {
+		t.Errorf("expected unset balance 0, got %s", balance)
+	}
+	if _, err := mock.GetPrice(ctx, "ETH-USD"); err == nil {
+		t.Error("expected error for unknown pair")
+	}
+
+	trades, _ := mock.GetTradeHistory(ctx, "BTC-USD", start, start.Add(3*time.Hour))
+	if len(trades) != 2 || trades[0].ID != "1" || trades[1].ID != "2" {
+		t.Errorf("expected trades in range sorted by time, got %+v", trades)
+	}
+
+	result, err := manager.WaitForFill(ctx, Coinbase, "order-1", time.Millisecond)
+	if err != nil || result.Status != OrderStatusFilled {
+		t.Errorf("expected filled order, got %+v, %v", result, err)
+	}
+
+	// 注入错误，清除后恢复
+	errLimited := errors.New("rate limited")
+	mock.WithError("GetBalances", errLimited)
+	if _, err := manager.GetBalances(ctx, Coinbase); !errors.Is(err, errLimited) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+	mock.WithError("GetBalances", nil)
+	balances, err := manager.GetBalances(ctx, Coinbase)
+	if err != nil || balances["BTC"] != "0.5" {
+		t.Errorf("expected balances after clearing error, got %v, %v", balances, err)
+	}
+
+	if mock.CallCount("GetBalances") != 2 || mock.CallCount("GetPrice") != 1 {
+		t.Errorf("unexpected call counts: %+v", mock.Calls())
+	}
+	if calls := mock.Calls(); calls[0].Method != "GetBalance" || calls[0].Args[0] != "BTC" {
+		t.Errorf("expected first call GetBalance(BTC), got %+v", calls[0])
+	}
+}
+
+func TestMockExchangeClientOrders(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockExchangeClient().
+		WithPrice("BTC-USD", "60000").
+		WithOrderBook(OrderBook{
+			Pair: "BTC-USD",
+			Bids: []OrderBookLevel{{Price: "59990", Size: "1"}, {Price: "59980", Size: "2"}},
+			Asks: []OrderBookLevel{{Price: "60010", Size: "1.5"}},
+		})
+
+	book, err := mock.GetOrderBook(ctx, "BTC-USD", 1)
+	if err != nil || len(book.Bids) != 1 || book.Bids[0].Price != "59990" || len(book.Asks) != 1 {
+		t.Fatalf("expected order book trimmed to depth 1, got %+v, %v", book, err)
+	}
+	book.Bids[0].Price = "0"
+	if full, _ := mock.GetOrderBook(ctx, "BTC-USD", 0); len(full.Bids) != 2 || full.Bids[0].Price != "59990" {
+		t.Errorf("expected full order book unaffected by caller changes, got %+v", full)
+	}
+	if _, err := mock.GetOrderBook(ctx, "ETH-USD", 10); err == nil {
+		t.Error("expected error for pair without order book")
+	}
+
+	// 默认下单后挂单，可撤销，撤销后不能再次撤销
+	order, err := mock.PlaceOrder(ctx, "BTC-USD", "buy", "limit", "0.1", "59000")
+	if err != nil || order.OrderID != "mock-order-1" || order.Status != OrderStatusOpen {
+		t.Fatalf("expected open order, got %+v, %v", order, err)
+	}
+	if err := mock.CancelOrder(ctx, order.OrderID); err != nil {
+		t.Fatalf("CancelOrder error: %v", err)
+	}
+	if status, _ := mock.GetOrderStatus(ctx, order.OrderID); status.Status != OrderStatusCancelled {
+		t.Errorf("expected cancelled order, got %+v", status)
+	}
+	if err := mock.CancelOrder(ctx, order.OrderID); err == nil {
+		t.Error("expected error cancelling a finished order")
+	}
+
+	// 设置为立即成交时市价单按当前价格全部成交，WaitForFill 立即返回
+	manager := &ExchangeManager{exchanges: make(map[Exchange]ExchangeClient)}
+	manager.RegisterExchange(Coinbase, mock.WithPlaceOrderStatus(OrderStatusFilled))
+	order, _ = mock.PlaceOrder(ctx, "BTC-USD", "sell", "market", "0.2", "")
+	result, err := manager.WaitForFill(ctx, Coinbase, order.OrderID, time.Millisecond)
+	if err != nil || result.Status != OrderStatusFilled || result.FilledSize != "0.2" || result.Price != "60000" {
+		t.Errorf("expected filled market order, got %+v, %v", result, err)
+	}
+
+	// 未知订单与真实交易所一致返回 404，WaitForFill 不再轮询
+	var apiErr *APIError
+	if err := mock.CancelOrder(ctx, "missing"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := manager.WaitForFill(ctx, Coinbase, "missing", time.Millisecond); !errors.As(err, &apiErr) {
+		t.Errorf("expected WaitForFill to stop on unknown order, got %v", err)
+	}
+
+	// 注入错误
+	errRejected := errors.New("insufficient funds")
+	mock.WithError("PlaceOrder", errRejected)
+	if _, err := mock.PlaceOrder(ctx, "BTC-USD", "buy", "market", "10", ""); !errors.Is(err, errRejected) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+	if mock.CallCount("PlaceOrder") != 3 || mock.CallCount("CancelOrder") != 3 {
+		t.Errorf("unexpected call counts: %+v", mock.Calls())
+	}
+}
+
+func TestMockClient(t *testing.T) {
+	var _ Client = (*MockClient)(nil)
+	var _ NonceFetcher = (*MockClient)(nil)
+
+	address := "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0"
+	eth := NewMockClient(Ethereum).
+		WithBalance(address, "1.5").
+		WithNonce(address, 7).
+		WithTransaction(Transaction{Hash: "0xabc", Status: "success", BlockNumber: 10})
+	sol := NewMockClient(Solana).WithError("GetBalance", errors.New("node unavailable"))
+
+	// GetWalletInfo 和 GetAllBalances 使用全局 Manager，测试结束后移除注册的客户端
+	manager := GetManager()
+	manager.RegisterClient(Ethereum, eth)
+	manager.RegisterClient(Solana, sol)
+	t.Cleanup(func() {
+		manager.mu.Lock()
+		delete(manager.clients, Ethereum)
+		delete(manager.clients, Solana)
+		manager.mu.Unlock()
+	})
+	ctx := context.Background()
+
+	info, err := GetWalletInfo(ctx, Ethereum, address)
+	if err != nil {
+		t.Fatalf("GetWalletInfo failed: %v", err)
+	}
+	if info.Balance != "1.5" || info.Nonce != 7 || info.TxCount != 7 {
+		t.Errorf("unexpected wallet info: %+v", info)
+	}
+
+	balances, err := (&MultiChainAddress{Ethereum: address, Solana: "7EqQdEULxWcraVx3mXKFjc84LhCkMGZCkRuDpvcMwJeK"}).GetAllBalances(ctx)
+	if err != nil {
+		t.Fatalf("GetAllBalances failed: %v", err)
+	}
+	if len(balances) != 1 || balances[Ethereum] != "1.5" {
+		t.Errorf("expected only the Ethereum balance, got %v", balances)
+	}
+
+	hash, err := eth.SendTransaction(ctx, &TransactionRequest{From: address, To: address, Value: "1"})
+	if err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	if tx, err := eth.GetTransaction(ctx, hash); err != nil || tx.Status != "pending" {
+		t.Errorf("expected sent transaction to be pending, got %+v, %v", tx, err)
+	}
+	if tx, _ := eth.GetTransaction(ctx, "0xabc"); tx.BlockNumber != 10 {
+		t.Errorf("expected canned transaction, got %+v", tx)
+	}
+
+	if eth.CallCount("GetBalance") != 2 || sol.CallCount("GetBalance") != 1 {
+		t.Errorf("unexpected calls: %+v / %+v", eth.Calls(), sol.Calls())
+	}
+	if calls := eth.Calls(); calls[1].Method != "GetTransactionCount" || calls[1].Args[0] != address {
+		t.Errorf("expected GetTransactionCount(%s) to be recorded, got %+v", address, calls[1])
+	}
+}
+
+func TestKuCoinGetOrderBook(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Header.Get("KC-API-SIGN") == "" {
+			t.Errorf("expected signed request for %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"code":"200000","data":{"sequence":"3262786978","time":1550653727731,`+
+			`"bids":[["6500.12","0.45"],["6500.11","0.41"],["6500.10","1"]],`+
+			`"asks":[["6500.16","0.57"],["6500.17","0.2"]]}}`)
+	}))
+	defer server.Close()
+
+	client := NewKuCoinClient("key", "secret", "passphrase")
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	book, err := client.GetOrderBook(ctx, "BTC-USDT", 2)
+	if err != nil {
+		t.Fatalf("GetOrderBook error: %v", err)
+	}
+	if len(book.Bids) != 2 || len(book.Asks) != 2 {
+		t.Errorf("expected 2 levels per side, got %d bids and %d asks", len(book.Bids), len(book.Asks))
+	}
+	if book.Bids[0] != (OrderBookLevel{Price: "6500.12", Size: "0.45"}) || book.Asks[0].Price != "6500.16" {
+		t.Errorf("unexpected top of book: %+v / %+v", book.Bids[0], book.Asks[0])
+	}
+	if book.Pair != "BTC-USDT" || book.Sequence != "3262786978" || book.Time.UnixMilli() != 1550653727731 {
+		t.Errorf("unexpected order book metadata: %+v", book)
+	}
+
+	if _, err := client.GetOrderBook(ctx, "BTC-USDT", 50); err != nil {
+		t.Fatal(err)
+	}
+	full, err := client.GetOrderBook(ctx, "BTC-USDT", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full.Bids) != 3 {
+		t.Errorf("expected full depth to keep all levels, got %d", len(full.Bids))
+	}
+
+	want := []string{
+		"/api/v1/market/orderbook/level2_20",
+		"/api/v1/market/orderbook/level2_100",
+		"/api/v3/market/orderbook/level2",
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected endpoints: %v", paths)
+	}
+}
+
+func TestCoinbaseGetOrderBook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/products/BTC-USD/book" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("level") {
+		case "1":
+			fmt.Fprint(w, `{"sequence":13051505638,"time":"2024-05-01T12:00:00.123456Z",`+
+				`"bids":[["64000.01","0.5",3]],"asks":[["64000.02","1.2",5]]}`)
+		case "2":
+			fmt.Fprint(w, `{"sequence":13051505639,"time":"2024-05-01T12:00:01Z",`+
+				`"bids":[["64000.01","0.5",3],["64000","2",12]],"asks":[["64000.02","1.2",5]]}`)
+		case "3":
+			fmt.Fprint(w, `{"sequence":13051505640,"bids":[["64000.01","0.2","b7a2-1"],["64000.01","0.3","b7a2-2"]],"asks":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewCoinbaseClient("key", "secret")
+	client.baseURL = server.URL
+	ctx := context.Background()
+
+	top, err := client.GetOrderBook(ctx, "BTC-USD", 1)
+	if err != nil {
+		t.Fatalf("GetOrderBook level 1 error: %v", err)
+	}
+	if len(top.Bids) != 1 || top.Bids[0] != (OrderBookLevel{Price: "64000.01", Size: "0.5", Orders: 3}) {
+		t.Errorf("unexpected best bid: %+v", top.Bids)
+	}
+	if len(top.Asks) != 1 || top.Asks[0].Price != "64000.02" {
+		t.Errorf("unexpected best ask: %+v", top.Asks)
+	}
+	if top.Pair != "BTC-USD" || top.Sequence != "13051505638" || top.Time.Nanosecond() != 123456000 {
+		t.Errorf("unexpected order book metadata: %+v", top)
+	}
+
+	book, err := client.GetOrderBook(ctx, "BTC-USD", 2)
+	if err != nil {
+		t.Fatalf("GetOrderBook level 2 error: %v", err)
+	}
+	if len(book.Bids) != 2 || book.Bids[1] != (OrderBookLevel{Price: "64000", Size: "2", Orders: 12}) {
+		t.Errorf("unexpected aggregated bids: %+v", book.Bids)
+	}
+
+	full, err := client.GetOrderBook(ctx, "BTC-USD", 3)
+	if err != nil {
+		t.Fatalf("GetOrderBook level 3 error: %v", err)
+	}
+	if len(full.Bids) != 2 || full.Bids[1].OrderID != "b7a2-2" || full.Bids[1].Orders != 0 || len(full.Asks) != 0 {
+		t.Errorf("unexpected level 3 book: %+v", full)
+	}
+	if !full.Time.IsZero() {
+		t.Errorf("expected zero time when omitted, got %v", full.Time)
+	}
+
+	if _, err := client.GetOrderBook(ctx, "BTC-USD", 4); err == nil {
+		t.Error("expected error for unsupported level")
+	}
+}
+
+func TestCoinbaseGetCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(449 * time.Minute)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if r.URL.Path != "/products/BTC-USD/candles" || q.Get("granularity") != "60" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		from, _ := time.Parse(time.RFC3339, q.Get("start"))
+		to, _ := time.Parse(time.RFC3339, q.Get("end"))
+
+		// 旧版接口按时间倒序返回 [time, low, high, open, close, volume]
+		var rows []string
+		for ts := to; !ts.Before(from); ts = ts.Add(-time.Minute) {
+			rows = append(rows, fmt.Sprintf("[%d,42000.01,42100.5,42010,42050.25,1.10000000000000000001]", ts.Unix()))
+		}
+		fmt.Fprint(w, "["+strings.Join(rows, ",")+"]")
+	}))
+	defer server.Close()
+
+	client := NewCoinbaseClient("key", base64.StdEncoding.EncodeToString([]byte("secret")))
+	client.baseURL = server.URL
+
+	candles, err := client.GetCandles(context.Background(), "BTC-USD", "1m", start, end)
+	if err != nil {
+		t.Fatalf("GetCandles error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected range to be split into 2 requests, got %d", requests)
+	}
+	if len(candles) != 450 {
+		t.Fatalf("expected 450 candles, got %d", len(candles))
+	}
+	if !candles[0].Time.Equal(start) || !candles[449].Time.Equal(end) {
+		t.Errorf("expected candles sorted from %v to %v, got %v to %v", start, end, candles[0].Time, candles[449].Time)
+	}
+	want := Candle{Time: start, Open: "42010", High: "42100.5", Low: "42000.01", Close: "42050.25", Volume: "1.10000000000000000001"}
+	if candles[0] != want {
+		t.Errorf("unexpected candle: %+v", candles[0])
+	}
+
+	if _, err := client.GetCandles(context.Background(), "BTC-USD", "3m", start, end); err == nil {
+		t.Error("expected error for unsupported interval")
+	}
+}
+
+func TestKuCoinGetCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(1999 * time.Minute)
+
+	var windows []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/v1/market/candles" || q.Get("type") != "1min" || q.Get("symbol") != "BTC-USDT" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		windows = append(windows, q.Get("startAt")+"-"+q.Get("endAt"))
+		from, _ := strconv.ParseInt(q.Get("startAt"), 10, 64)
+		to, _ := strconv.ParseInt(q.Get("endAt"), 10, 64)
+
+		// 按时间倒序返回 [time, open, close, high, low, volume, turnover]
+		var rows []string
+		for ts := to; ts >= from; ts -= 60 {
+			rows = append(rows, fmt.Sprintf(`["%d","42010","42050.25","42100.5","42000.01","0.5","21025"]`, ts))
+		}
+		fmt.Fprint(w, `{"code":"200000","data":[`+strings.Join(rows, ",")+`]}`)
+	}))
+	defer server.Close()
+
+	client := NewKuCoinClient("key", "secret", "passphrase")
+	client.baseURL = server.URL
+
+	candles, err := client.GetCandles(context.Background(), "BTC-USDT", "1m", start, end)
+	if err != nil {
+		t.Fatalf("GetCandles error: %v", err)
+	}
+
+	split := start.Add(1499 * time.Minute)
+	want := []string{
+		fmt.Sprintf("%d-%d", start.Unix(), split.Unix()),
+		fmt.Sprintf("%d-%d", split.Add(time.Minute).Unix(), end.Unix()),
+	}
+	if strings.Join(windows, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected windows: %v, want %v", windows, want)
+	}
+	if len(candles) != 2000 {
+		t.Fatalf("expected 2000 candles, got %d", len(candles))
+	}
+	if !candles[0].Time.Equal(start) || !candles[1999].Time.Equal(end) {
+		t.Errorf("expected candles sorted from %v to %v, got %v to %v", start, end, candles[0].Time, candles[1999].Time)
+	}
+	if c := candles[0]; c.Open != "42010" || c.Close != "42050.25" || c.High != "42100.5" || c.Low != "42000.01" || c.Volume != "0.5" {
+		t.Errorf("unexpected candle: %+v", c)
+	}
+}
+
+func TestHyperliquidGetCandles(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Type string `json:"type"`
+			Req  struct {
+				Coin      string `json:"coin"`
+				Interval  string `json:"interval"`
+				StartTime int64  `json:"startTime"`
+				EndTime   int64  `json:"endTime"`
+			} `json:"req"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if r.URL.Path != "/info" || body.Type != "candleSnapshot" || body.Req.Coin != "BTC" || body.Req.Interval != "1h" ||
+			body.Req.StartTime != start.UnixMilli() || body.Req.EndTime != end.UnixMilli() {
+			t.Errorf("unexpected request %s %+v", r.URL.Path, body)
+		}
+		fmt.Fprintf(w, `[{"t":%d,"o":"42050","h":"42300","l":"42000","c":"42200","v":"12.5"},`+
+			`{"t":%d,"o":"42000","h":"42100","l":"41900","c":"42050","v":"10.25"}]`,
+			end.UnixMilli(), start.UnixMilli())
+	}))
+	defer server.Close()
+
+	client, _ := NewHyperliquidClient("")
+	client.baseURL = server.URL
+
+	candles, err := client.GetCandles(context.Background(), "BTC-USD", "1h", start, end)
+	if err != nil {
+		t.Fatalf("GetCandles error: %v", err)
+	}
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(candles))
+	}
+	want := Candle{Time: start, Open: "42000", High: "42100", Low: "41900", Close: "42050", Volume: "10.25"}
+	if candles[0] != want || !candles[1].Time.Equal(end) {
+		t.Errorf("unexpected candles: %+v", candles)
+	}
+
+	if _, err := client.GetCandles(context.Background(), "BTC-USD", "7m", start, end); err == nil {
+		t.Error("expected error for unsupported interval")
+	}
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTolerance 默认允许的签名时间戳偏差
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrMissingSignature 缺少签名
+	ErrMissingSignature = errors.New("webhook signature missing")
+	// ErrInvalidSignature 签名格式错误或不匹配
+	ErrInvalidSignature = errors.New("webhook signature invalid")
+	// ErrSignatureExpired 签名时间戳超出允许范围
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
+)
+
+const (
+	// SignatureHeader 签名请求头，格式为 "t=<unix 时间戳>,v1=<hex HMAC-SHA256>"
+	SignatureHeader = "X-Signature"
+	// TimestampHeader 时间戳请求头
+	TimestampHeader = "X-Webhook-Timestamp"
+	// EventHeader 事件名称请求头
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader 投递 ID 请求头，重试时保持不变，可用于接收方去重
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// Sign 生成签名请求头的值
+// 签名内容为 "<时间戳>.<请求体>"，时间戳参与签名以防止重放
+func Sign(secret []byte, body []byte, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeSignature(secret, ts, body)
+}
+
+// computeSignature 计算 HMAC-SHA256 签名
+func computeSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature 校验签名请求头，使用常量时间比较
+// 请求头可以包含多个 v1 签名（例如轮换密钥期间），任意一个匹配即通过
+func VerifySignature(secret []byte, body []byte, signatureHeader string) error {
+	_, err := verify(secret, body, signatureHeader)
+	return err
+}
+
+// VerifySignatureWithTolerance 校验签名，并要求签名时间戳与当前时间的偏差不超过 tolerance
+// tolerance <= 0 时不校验时间戳
+func VerifySignatureWithTolerance(secret []byte, body []byte, signatureHeader string, tolerance time.Duration) error {
+	timestamp, err := verify(secret, body, signatureHeader)
+	if err != nil {
+		return err
+	}
+
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(timestamp, 0))
+		if diff > tolerance || diff < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return nil
+}
+
+// verify 校验签名并返回签名中的时间戳
+func verify(secret []byte, body []byte, signatureHeader string) (int64, error) {
+	if signatureHeader == "" {
+		return 0, ErrMissingSignature
+	}
+
+	var ts string
+	var signatures []string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return 0, ErrInvalidSignature
+	}
+
+	expected := []byte(computeSignature(secret, ts, body))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return timestamp, nil
+		}
+	}
+	return 0, ErrInvalidSignature
+}
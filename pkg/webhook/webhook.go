@@ -0,0 +1,329 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/log"
+	"github.com/clarkgo/clarkgo/pkg/queue"
+)
+
+// DeliveryJobType 投递任务在队列中的任务类型
+const DeliveryJobType = "webhook.delivery"
+
+func init() {
+	queue.RegisterJobType(DeliveryJobType, func() queue.Job { return &DeliveryJob{} })
+}
+
+// Payload 投递的 JSON 请求体
+type Payload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// ErrUnknownEndpoint 投递任务引用的端点未在投递器中登记
+var ErrUnknownEndpoint = errors.New("unknown webhook endpoint")
+
+// Endpoint webhook 端点
+type Endpoint struct {
+	ID     string
+	URL    string
+	Secret string
+}
+
+// EndpointID 根据 URL 生成端点 ID，不同进程中登记同一 URL 得到相同的 ID
+func EndpointID(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// DeliveryJob webhook 投递任务
+// 任务只保存端点 ID，签名密钥在投递时从投递器登记的端点中解析，不会写入队列
+type DeliveryJob struct {
+	queue.BaseJob
+	DeliveryID string          `json:"delivery_id"`
+	EventName  string          `json:"event_name"`
+	EndpointID string          `json:"endpoint_id"`
+	URL        string          `json:"url"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// Handle 实现 queue.Job 接口，使用全局投递器投递
+func (j *DeliveryJob) Handle() error {
+	return GetDeliverer().Deliver(context.Background(), j)
+}
+
+// Delivery 投递记录
+type Delivery struct {
+	ID          string
+	EventName   string
+	URL         string
+	Attempt     int
+	StatusCode  int
+	Success     bool
+	Error       string
+	Duration    time.Duration
+	DeliveredAt time.Time
+}
+
+// Deliverer webhook 投递器
+// 设置队列后投递任务进入队列执行，失败时由队列按最大重试次数重试；
+// 未设置队列时在调用方协程中直接投递一次
+type Deliverer struct {
+	httpClient *http.Client
+	queue      *queue.Queue
+	queueName  string
+	maxRetries int
+	timeout    time.Duration
+	logger     log.StructuredLogger
+
+	endpoints map[string]Endpoint
+
+	deliveries    []Delivery
+	maxDeliveries int
+	mu            sync.RWMutex
+}
+
+// NewDeliverer 创建 webhook 投递器
+func NewDeliverer() *Deliverer {
+	return &Deliverer{
+		httpClient:    &http.Client{Timeout: 10 * time.Second},
+		queueName:     "webhooks",
+		maxRetries:    5,
+		timeout:       30 * time.Second,
+		logger:        log.Default(),
+		endpoints:     make(map[string]Endpoint),
+		deliveries:    make([]Delivery, 0),
+		maxDeliveries: 1000,
+	}
+}
+
+// SetQueue 设置重试使用的队列，并在队列上注册投递任务处理器
+// 工作进程需要监听 SetQueueName 指定的队列（默认 webhooks）
+func (d *Deliverer) SetQueue(q *queue.Queue) *Deliverer {
+	d.queue = q
+	q.RegisterWithContext(DeliveryJobType, d.handleJob)
+	return d
+}
+
+// SetQueueName 设置投递任务所在的队列名称
+func (d *Deliverer) SetQueueName(name string) *Deliverer {
+	d.queueName = name
+	return d
+}
+
+// SetMaxRetries 设置最大重试次数
+func (d *Deliverer) SetMaxRetries(maxRetries int) *Deliverer {
+	d.maxRetries = maxRetries
+	return d
+}
+
+// SetHTTPClient 设置 HTTP 客户端
+func (d *Deliverer) SetHTTPClient(client *http.Client) *Deliverer {
+	d.httpClient = client
+	return d
+}
+
+// SetLogger 设置日志记录器
+func (d *Deliverer) SetLogger(logger log.StructuredLogger) *Deliverer {
+	d.logger = logger
+	return d
+}
+
+// RegisterEndpoint 登记端点并返回端点 ID，重复登记同一 URL 时更新密钥
+// 队列工作进程需要登记与发送方相同的端点，才能在投递时解析密钥
+func (d *Deliverer) RegisterEndpoint(url, secret string) string {
+	id := EndpointID(url)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.endpoints[id] = Endpoint{ID: id, URL: url, Secret: secret}
+	return id
+}
+
+// Endpoint 获取已登记的端点
+func (d *Deliverer) Endpoint(id string) (Endpoint, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	endpoint, ok := d.endpoints[id]
+	return endpoint, ok
+}
+
+// Send 将事件数据封装为 Payload 并投递到 url，url 会被登记为端点
+func (d *Deliverer) Send(ctx context.Context, eventName string, data interface{}, url, secret string) error {
+	endpointID := d.RegisterEndpoint(url, secret)
+	id := newDeliveryID()
+	body, err := json.Marshal(Payload{
+		ID:        id,
+		Event:     eventName,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	job := &DeliveryJob{
+		BaseJob: queue.BaseJob{
+			ID:         "webhook_" + id,
+			Queue:      d.queueName,
+			MaxRetries: d.maxRetries,
+			Timeout:    d.timeout,
+			CreatedAt:  time.Now(),
+		},
+		DeliveryID: id,
+		EventName:  eventName,
+		EndpointID: endpointID,
+		URL:        url,
+		Body:       body,
+	}
+
+	if d.queue == nil {
+		return d.Deliver(ctx, job)
+	}
+	return d.queue.Push(job)
+}
+
+// Deliver 投递一次，响应状态码非 2xx 时返回错误
+func (d *Deliverer) Deliver(ctx context.Context, job *DeliveryJob) error {
+	start := time.Now()
+	delivery := Delivery{
+		ID:          job.DeliveryID,
+		EventName:   job.EventName,
+		URL:         job.URL,
+		DeliveredAt: start,
+	}
+
+	statusCode, err := d.post(ctx, job)
+	delivery.StatusCode = statusCode
+	delivery.Duration = time.Since(start)
+	delivery.Success = err == nil
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	d.record(&delivery)
+
+	logger := d.logger.With("delivery_id", job.DeliveryID, "event", job.EventName, "url", job.URL)
+	if err != nil {
+		logger.Warn("webhook delivery failed", "attempt", delivery.Attempt, "status", statusCode, "error", err)
+		return err
+	}
+
+	logger.Debug("webhook delivered", "attempt", delivery.Attempt, "status", statusCode, "duration", delivery.Duration)
+	return nil
+}
+
+// post 解析端点密钥并发送签名请求
+func (d *Deliverer) post(ctx context.Context, job *DeliveryJob) (int, error) {
+	endpointID := job.EndpointID
+	if endpointID == "" {
+		endpointID = EndpointID(job.URL)
+	}
+	endpoint, ok := d.Endpoint(endpointID)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownEndpoint, endpointID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign([]byte(endpoint.Secret), job.Body, now))
+	req.Header.Set(TimestampHeader, fmt.Sprintf("%d", now.Unix()))
+	req.Header.Set(EventHeader, job.EventName)
+	req.Header.Set(DeliveryHeader, job.DeliveryID)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// handleJob 队列任务处理器
+func (d *Deliverer) handleJob(ctx context.Context, payload []byte) error {
+	var job DeliveryJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("failed to unmarshal webhook job: %w", err)
+	}
+	if job.URL == "" {
+		return errors.New("webhook job has no url")
+	}
+	return d.Deliver(ctx, &job)
+}
+
+// record 记录投递结果，并根据同一投递 ID 的历史记录计算尝试次数
+func (d *Deliverer) record(delivery *Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delivery.Attempt = 1
+	for _, previous := range d.deliveries {
+		if previous.ID == delivery.ID {
+			delivery.Attempt++
+		}
+	}
+
+	d.deliveries = append(d.deliveries, *delivery)
+	if len(d.deliveries) > d.maxDeliveries {
+		d.deliveries = d.deliveries[len(d.deliveries)-d.maxDeliveries:]
+	}
+}
+
+// GetDeliveries 获取最近的投递记录，limit <= 0 时返回全部
+func (d *Deliverer) GetDeliveries(limit int) []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	start := 0
+	if limit > 0 && len(d.deliveries) > limit {
+		start = len(d.deliveries) - limit
+	}
+
+	result := make([]Delivery, len(d.deliveries)-start)
+	copy(result, d.deliveries[start:])
+	return result
+}
+
+// newDeliveryID 生成随机投递 ID
+func newDeliveryID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+var (
+	globalDeliverer *Deliverer
+	once            sync.Once
+)
+
+// GetDeliverer 获取全局投递器
+func GetDeliverer() *Deliverer {
+	once.Do(func() {
+		globalDeliverer = NewDeliverer()
+	})
+	return globalDeliverer
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clarkgo/clarkgo/pkg/queue"
+)
+
+func TestDelivererSend(t *testing.T) {
+	secret := "s3cret"
+	status := http.StatusInternalServerError
+
+	var received Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+
+		signature := r.Header.Get(SignatureHeader)
+		ts := strings.TrimPrefix(strings.Split(signature, ",")[0], "t=")
+		if signature != "t="+ts+",v1="+computeSignature([]byte(secret), ts, body) {
+			t.Errorf("invalid signature header %q", signature)
+		}
+		if r.Header.Get(EventHeader) != "order.paid" {
+			t.Errorf("unexpected event header %q", r.Header.Get(EventHeader))
+		}
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	deliverer := NewDeliverer()
+
+	// 未设置队列时直接投递，非 2xx 返回错误
+	err := deliverer.Send(context.Background(), "order.paid", map[string]string{"order_id": "1"}, server.URL, secret)
+	if err == nil {
+		t.Fatal("expected error for 500 response")
+	}
+	if received.Event != "order.paid" || received.ID == "" {
+		t.Errorf("unexpected payload %+v", received)
+	}
+
+	status = http.StatusOK
+	if err := deliverer.Send(context.Background(), "order.paid", nil, server.URL, secret); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	deliveries := deliverer.GetDeliveries(0)
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].Success || deliveries[0].StatusCode != 500 {
+		t.Errorf("unexpected first delivery %+v", deliveries[0])
+	}
+	if !deliveries[1].Success || deliveries[1].StatusCode != 200 {
+		t.Errorf("unexpected second delivery %+v", deliveries[1])
+	}
+}
+
+func TestDelivererRetryAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	deliverer := NewDeliverer()
+	endpointID := deliverer.RegisterEndpoint(server.URL, "secret")
+	job := &DeliveryJob{DeliveryID: "d1", EventName: "test", EndpointID: endpointID, URL: server.URL, Body: []byte(`{}`)}
+	payload, _ := json.Marshal(job)
+
+	// 队列重试时再次调用处理器，尝试次数递增
+	for i := 0; i < 3; i++ {
+		if err := deliverer.handleJob(context.Background(), payload); err == nil {
+			t.Fatal("expected delivery error")
+		}
+	}
+
+	deliveries := deliverer.GetDeliveries(1)
+	if len(deliveries) != 1 || deliveries[0].Attempt != 3 {
+		t.Errorf("expected attempt 3, got %+v", deliveries)
+	}
+}
+
+func TestDeliveryJobResolvesSecret(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+	}))
+	defer server.Close()
+
+	driver := queue.NewMemoryDriver()
+	sender := NewDeliverer().SetQueue(queue.NewQueue(driver))
+	if err := sender.Send(context.Background(), "order.paid", nil, server.URL, "old-secret"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	record, err := driver.Pop("webhooks", time.Second)
+	if err != nil || record == nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	payload := []byte(record.Payload)
+	if strings.Contains(string(payload), "old-secret") {
+		t.Errorf("expected secret not to be serialized, got %s", payload)
+	}
+
+	// 工作进程未登记端点时投递失败
+	worker := NewDeliverer()
+	if err := worker.handleJob(context.Background(), payload); !errors.Is(err, ErrUnknownEndpoint) {
+		t.Fatalf("expected ErrUnknownEndpoint, got %v", err)
+	}
+
+	// 投递时使用当前登记的密钥
+	worker.RegisterEndpoint(server.URL, "new-secret")
+	if err := worker.handleJob(context.Background(), payload); err != nil {
+		t.Fatalf("handleJob failed: %v", err)
+	}
+	var job DeliveryJob
+	json.Unmarshal(payload, &job)
+	ts := strings.TrimPrefix(strings.Split(signature, ",")[0], "t=")
+	if signature != "t="+ts+",v1="+computeSignature([]byte("new-secret"), ts, job.Body) {
+		t.Errorf("expected signature with the current secret, got %q", signature)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("s3cret")
+	body := []byte(`{"event":"order.paid"}`)
+	header := Sign(secret, body, time.Now())
+
+	if err := VerifySignature(secret, body, header); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := VerifySignature([]byte("other"), body, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if err := VerifySignature(secret, []byte(`{}`), header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+	if err := VerifySignature(secret, body, ""); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("expected ErrMissingSignature, got %v", err)
+	}
+
+	old := Sign(secret, body, time.Now().Add(-time.Hour))
+	if err := VerifySignatureWithTolerance(secret, body, old, DefaultTolerance); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("expected ErrSignatureExpired, got %v", err)
+	}
+	if err := VerifySignatureWithTolerance(secret, body, old, 0); err != nil {
+		t.Errorf("expected tolerance 0 to skip timestamp check, got %v", err)
+	}
+}
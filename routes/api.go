@@ -1,13 +1,12 @@
 package routes
 
 import (
-	"fmt"
-
 	controllers "github.com/clarkgo/clarkgo/app/Http/Controllers"
 	middleware "github.com/clarkgo/clarkgo/app/Http/Middleware"
 	"github.com/clarkgo/clarkgo/config"
 	"github.com/clarkgo/clarkgo/internal/app/adapters"
 	"github.com/clarkgo/clarkgo/pkg/framework"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
 )
 
 func APIRoutes(app *framework.Application) {
@@ -17,10 +16,9 @@ func APIRoutes(app *framework.Application) {
 	manager, err := config.LoadAIManager()
 	if err != nil {
 		// 如果AI配置加载失败，记录错误但不影响其他路由
-		fmt.Printf("Warning: Failed to load AI manager: %v\n", err)
-		fmt.Println("AI routes will not be available.")
+		hlog.Warnf("Failed to load AI manager: %v, AI routes will not be available", err)
 	} else {
-		fmt.Printf("AI manager loaded successfully with %d clients\n", len(manager.ListClients()))
+		hlog.Infof("AI manager loaded successfully with %d clients", len(manager.ListClients()))
 	}
 
 	var aiController *controllers.AIController
@@ -31,8 +29,7 @@ func APIRoutes(app *framework.Application) {
 	// 创建邮件控制器
 	mailController, err := controllers.NewMailController()
 	if err != nil {
-		fmt.Printf("Warning: Failed to create mail controller: %v\n", err)
-		fmt.Println("Mail routes will not be available.")
+		hlog.Warnf("Failed to create mail controller: %v, mail routes will not be available", err)
 	}
 
 	// 创建CMS控制器
@@ -47,10 +44,10 @@ func APIRoutes(app *framework.Application) {
 	seoController := controllers.NewSEOController("http://localhost:8888")
 
 	// 创建Web3控制器
-	web3Controller := &controllers.Web3Controller{}
+	web3Controller := controllers.NewWeb3Controller(app.Container)
 
 	// 创建Exchange控制器
-	exchangeController := &controllers.ExchangeController{}
+	exchangeController := controllers.NewExchangeController(app.Container)
 
 	app.RegisterRoutes(func(r *framework.Router) {
 		// SEO 路由（公开）
@@ -64,7 +61,7 @@ func APIRoutes(app *framework.Application) {
 
 		// AI 路由
 		if aiController != nil {
-			fmt.Println("Registering AI routes...")
+			hlog.Info("Registering AI routes...")
 			r.POST("/api/ai/chat", adapters.HertzToFramework(aiController.Chat))
 			r.POST("/api/ai/completion", adapters.HertzToFramework(aiController.Completion))
 			r.POST("/api/ai/embedding", adapters.HertzToFramework(aiController.Embedding))
@@ -72,7 +69,7 @@ func APIRoutes(app *framework.Application) {
 
 		// 邮件 API 路由
 		if mailController != nil {
-			fmt.Println("Registering mail routes...")
+			hlog.Info("Registering mail routes...")
 			r.POST("/api/mail/send", adapters.HertzToFramework(mailController.SendMail))
 			r.POST("/api/mail/send-template", adapters.HertzToFramework(mailController.SendTemplate))
 			r.POST("/api/mail/send-bulk", adapters.HertzToFramework(mailController.SendBulkMail))
@@ -82,7 +79,7 @@ func APIRoutes(app *framework.Application) {
 		}
 
 		// CMS 公开路由（只读）
-		fmt.Println("Registering CMS routes...")
+		hlog.Info("Registering CMS routes...")
 		r.GET("/api/posts", adapters.HertzToFramework(postController.List))
 		r.GET("/api/posts/:id", adapters.HertzToFramework(postController.Get))
 		r.GET("/api/categories", adapters.HertzToFramework(categoryController.List))
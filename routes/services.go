@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/clarkgo/clarkgo/pkg/framework"
+	"github.com/clarkgo/clarkgo/pkg/web3"
+)
+
+// Services 注册控制器依赖的共享服务
+func Services(app *framework.Application) {
+	app.Container.
+		Singleton("web3", func(c *framework.Container) (interface{}, error) {
+			return web3.GetManager(), nil
+		}).
+		Singleton("exchange", func(c *framework.Container) (interface{}, error) {
+			return web3.GetExchangeManager(), nil
+		})
+}
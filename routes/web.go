@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/clarkgo/clarkgo/pkg/framework"
+	"github.com/clarkgo/clarkgo/pkg/swagger"
+)
+
+// Register 注册应用的全部路由（不启动服务器，可用于 route:list 等命令）
+func Register(app *framework.Application) {
+	// 注册共享服务
+	Services(app)
+
+	// 注册API路由
+	APIRoutes(app)
+
+	// 注册其他路由
+	WebRoutes(app)
+}
+
+// WebRoutes 注册 Web 及基础路由
+func WebRoutes(app *framework.Application) {
+	app.RegisterRoutes(func(router *framework.Router) {
+		// Swagger文档路由 - 访问 http://localhost:8888/swagger/index.html
+		router.GET("/swagger/*any", swagger.SwaggerHandler())
+
+		// 基本API路由
+		api := router.Group("/api")
+		{
+			api.GET("/ping", func(ctx context.Context, c *framework.RequestContext) {
+				c.JSON(200, map[string]interface{}{
+					"message": "pong",
+				})
+			})
+		}
+
+		// Web路由
+		router.GET("/", func(ctx context.Context, c *framework.RequestContext) {
+			c.String(200, "Welcome to ClarkGo with AI capabilities! See /doc/ai.md for AI integration guide.")
+		})
+	})
+
+	// 注册静态文件目录
+	app.Static("/public", app.GetPublicPath())
+}
@@ -10,8 +10,8 @@ import (
 
 func TestDatabaseConnection(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 
 	assert.NotNil(t, app.DB)
 
@@ -25,8 +25,8 @@ func TestDatabaseConnection(t *testing.T) {
 
 func TestRedisConnection(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 
 	// 只有当Redis启用时才测试
 	if app.Redis != nil {
@@ -18,8 +18,8 @@ func TestApplicationCreation(t *testing.T) {
 
 func TestApplicationBoot(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 
 	assert.NotNil(t, app.Server)
 	assert.NotNil(t, app.Router)
@@ -29,8 +29,8 @@ func TestApplicationBoot(t *testing.T) {
 
 func TestApplicationRun(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 
 	// 测试运行不会panic
 	assert.NotPanics(t, func() {
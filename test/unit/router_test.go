@@ -9,8 +9,8 @@ import (
 
 func TestRouterRegistration(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 	
 	app.RegisterRoutes(func(r *framework.Router) {
 		r.GET("/test", func(c *app.RequestContext) {
@@ -24,8 +24,8 @@ func TestRouterRegistration(t *testing.T) {
 
 func TestMiddlewareRegistration(t *testing.T) {
 	app := framework.NewApplication().
-		SetConfigPath("../../config").
-		Boot()
+		SetConfigPath("../../config")
+	assert.NoError(t, app.Boot())
 	
 	middlewareCalled := false
 	app.RegisterMiddleware(func(c *app.RequestContext) {